@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+)
+
+const analyticsDateLayout = "2006-01-02"
+
+type AnalyticsHandler struct {
+	analyticsUseCase *usecase.AnalyticsUseCase
+}
+
+func NewAnalyticsHandler(analyticsUseCase *usecase.AnalyticsUseCase) *AnalyticsHandler {
+	return &AnalyticsHandler{analyticsUseCase: analyticsUseCase}
+}
+
+// GetCourseAnalytics возвращает статистику курса по студентам, записавшимся с ?from= по ?to= (YYYY-MM-DD, включительно),
+// ?interval=day|week|month - шаг графика записей
+func (h *AnalyticsHandler) GetCourseAnalytics(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	filter := models.AnalyticsFilter{CourseID: courseID, Interval: c.Query("interval")}
+	if value := c.Query("from"); value != "" {
+		from, err := time.Parse(analyticsDateLayout, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date"})
+			return
+		}
+		filter.From = &from
+	}
+	if value := c.Query("to"); value != "" {
+		to, err := time.Parse(analyticsDateLayout, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date"})
+			return
+		}
+		to = to.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+
+	analytics, err := h.analyticsUseCase.GetCourseAnalytics(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), filter)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
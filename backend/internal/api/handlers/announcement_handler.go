@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+type AnnouncementHandler struct {
+	announcementUseCase *usecase.AnnouncementUseCase
+}
+
+func NewAnnouncementHandler(announcementUseCase *usecase.AnnouncementUseCase) *AnnouncementHandler {
+	return &AnnouncementHandler{announcementUseCase: announcementUseCase}
+}
+
+// announcementParams разбирает ID курса и объявления из пути
+func announcementParams(c *gin.Context) (int, int, bool) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return 0, 0, false
+	}
+	announcementID, err := strconv.Atoi(c.Param("announcement_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid announcement ID"})
+		return 0, 0, false
+	}
+	return courseID, announcementID, true
+}
+
+// CreateAnnouncement публикует объявление курса
+func (h *AnnouncementHandler) CreateAnnouncement(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	var request dto.AnnouncementRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	announcement, err := h.announcementUseCase.CreateAnnouncement(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, announcement)
+}
+
+// GetCourseAnnouncements возвращает объявления курса
+func (h *AnnouncementHandler) GetCourseAnnouncements(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	announcements, err := h.announcementUseCase.GetCourseAnnouncements(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"announcements": announcements})
+}
+
+// GetAnnouncement возвращает объявление курса
+func (h *AnnouncementHandler) GetAnnouncement(c *gin.Context) {
+	courseID, announcementID, ok := announcementParams(c)
+	if !ok {
+		return
+	}
+
+	announcement, err := h.announcementUseCase.GetAnnouncement(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, announcementID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, announcement)
+}
+
+// UpdateAnnouncement изменяет объявление
+func (h *AnnouncementHandler) UpdateAnnouncement(c *gin.Context) {
+	courseID, announcementID, ok := announcementParams(c)
+	if !ok {
+		return
+	}
+
+	var request dto.AnnouncementRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	announcement, err := h.announcementUseCase.UpdateAnnouncement(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, announcementID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, announcement)
+}
+
+// DeleteAnnouncement удаляет объявление
+func (h *AnnouncementHandler) DeleteAnnouncement(c *gin.Context) {
+	courseID, announcementID, ok := announcementParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.announcementUseCase.DeleteAnnouncement(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, announcementID); err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Announcement deleted successfully"})
+}
+
+// MarkRead отмечает объявление прочитанным
+func (h *AnnouncementHandler) MarkRead(c *gin.Context) {
+	courseID, announcementID, ok := announcementParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.announcementUseCase.MarkRead(c.Request.Context(), c.GetInt("userID"), courseID, announcementID); err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Announcement marked as read"})
+}
+
+// GetReads возвращает отметки о прочтении объявления
+func (h *AnnouncementHandler) GetReads(c *gin.Context) {
+	courseID, announcementID, ok := announcementParams(c)
+	if !ok {
+		return
+	}
+
+	reads, err := h.announcementUseCase.GetReads(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, announcementID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reads": reads})
+}
+
+// GetFeed возвращает ленту объявлений текущего пользователя, ?unread=true - только непрочитанные
+func (h *AnnouncementHandler) GetFeed(c *gin.Context) {
+	unreadOnly := c.Query("unread") == "true"
+
+	announcements, err := h.announcementUseCase.GetFeed(c.Request.Context(), c.GetInt("userID"), unreadOnly)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"announcements": announcements})
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+type AssignmentHandler struct {
+	assignmentUseCase *usecase.AssignmentUseCase
+}
+
+func NewAssignmentHandler(assignmentUseCase *usecase.AssignmentUseCase) *AssignmentHandler {
+	return &AssignmentHandler{assignmentUseCase: assignmentUseCase}
+}
+
+func submissionParams(c *gin.Context) (int, int, int, bool) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return 0, 0, 0, false
+	}
+	submissionID, err := strconv.Atoi(c.Param("submission_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+		return 0, 0, 0, false
+	}
+	return courseID, lessonID, submissionID, true
+}
+
+// GetAssignment возвращает задание урока с рубрикой
+func (h *AssignmentHandler) GetAssignment(c *gin.Context) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return
+	}
+
+	assignment, err := h.assignmentUseCase.GetAssignment(c.Request.Context(), courseID, lessonID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, assignment)
+}
+
+// SetAssignment создаёт или заменяет задание урока
+func (h *AssignmentHandler) SetAssignment(c *gin.Context) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return
+	}
+
+	var request dto.AssignmentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assignment, err := h.assignmentUseCase.SetAssignment(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, assignment)
+}
+
+// DeleteAssignment удаляет задание урока вместе со сданными работами
+func (h *AssignmentHandler) DeleteAssignment(c *gin.Context) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.assignmentUseCase.DeleteAssignment(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID); err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Assignment deleted"})
+}
+
+// Submit сдаёт работу файлом в поле "file" multipart-формы; comment - необязательное поле формы
+func (h *AssignmentHandler) Submit(c *gin.Context) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return
+	}
+
+	var request dto.SubmitAssignmentRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+		return
+	}
+	defer f.Close()
+
+	submission, err := h.assignmentUseCase.Submit(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID, &request, file.Filename, file.Size, f)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, submission)
+}
+
+// ListSubmissions возвращает попытки по заданию; команда курса может отфильтровать студента через ?user_id=
+func (h *AssignmentHandler) ListSubmissions(c *gin.Context) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return
+	}
+
+	var studentID *int
+	if value := c.Query("user_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+		studentID = &id
+	}
+
+	submissions, err := h.assignmentUseCase.ListSubmissions(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID, studentID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"submissions": submissions})
+}
+
+// DownloadSubmission отдаёт файл попытки автору или команде курса
+func (h *AssignmentHandler) DownloadSubmission(c *gin.Context) {
+	courseID, lessonID, submissionID, ok := submissionParams(c)
+	if !ok {
+		return
+	}
+
+	submission, content, err := h.assignmentUseCase.DownloadSubmission(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID, submissionID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+	defer content.Close()
+
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", submission.Filename))
+	c.DataFromReader(http.StatusOK, submission.Size, submission.ContentType, content, nil)
+}
+
+// GradeSubmission выставляет оценку попытке
+func (h *AssignmentHandler) GradeSubmission(c *gin.Context) {
+	courseID, lessonID, submissionID, ok := submissionParams(c)
+	if !ok {
+		return
+	}
+
+	var request dto.GradeSubmissionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	submission, err := h.assignmentUseCase.GradeSubmission(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID, submissionID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, submission)
+}
+
+// GradingQueue возвращает работы курса, ожидающие проверки
+func (h *AssignmentHandler) GradingQueue(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	submissions, err := h.assignmentUseCase.GradingQueue(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"submissions": submissions})
+}
@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
 	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
 	"gitlab.com/w0ikid/study-platform/internal/dto"
 )
@@ -32,16 +35,38 @@ func (h *CourseHandler) CreateCourse(c *gin.Context) {
 
 	ctx := c.Request.Context()
 
+	level := models.CourseLevelBeginner
+	if request.Level != "" {
+		var ok bool
+		if level, ok = models.CourseLevelFromName(request.Level); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "level must be one of beginner, intermediate, advanced"})
+			return
+		}
+	}
+
+	status := models.CourseStatusActive
+	if request.Status != "" {
+		status = request.Status
+	}
+
 	input := usecase.CreateCourseInput{
 		Name:        request.Name,
 		Description: request.Description,
 		TeacherID:   teacherID,
-		Status: 	 "active",
+		Status: 	 status,
 		ImageURL: 	 request.ImageUrl,
+		CategoryID:      request.CategoryID,
+		Level:           level,
+		DurationMinutes: request.DurationMinutes,
+		Language:        request.Language,
 	}
 	
 	course, err := h.courseUseCase.CreateCourse(ctx, input)
 	if err != nil {
+		if strings.Contains(err.Error(), "category not found") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -52,6 +77,11 @@ func (h *CourseHandler) CreateCourse(c *gin.Context) {
 		"description": course.Description,
 		"teacher_id":  course.TeacherID,
 		"image_url":   course.ImageUrl,
+		"category_id":      course.CategoryID,
+		"level":            course.Level,
+		"level_name":       models.CourseLevelName(course.Level),
+		"duration_minutes": course.DurationMinutes,
+		"language":         course.Language,
 	})
 }
 
@@ -75,12 +105,61 @@ func (h *CourseHandler) GetCourse(c *gin.Context) {
 		"name":        course.Name,
 		"description": course.Description,
 		"teacher_id":  course.TeacherID,
+		"image_url":        course.ImageUrl,
+		"category_id":      course.CategoryID,
+		"level":            course.Level,
+		"level_name":       models.CourseLevelName(course.Level),
+		"duration_minutes": course.DurationMinutes,
+		"language":         course.Language,
+		"tags":             course.Tags,
 	})
 }
 
-// GetAllCourses обрабатывает получение всех курсов
+// GetAllCourses обрабатывает получение каталога курсов.
+// Query: q, category_id, tag (можно несколько), level, language, min_duration, max_duration, sort=rating
 func (h *CourseHandler) GetAllCourses(c *gin.Context) {
-	courses, err := h.courseUseCase.GetAllCourses(c.Request.Context())
+	filter := models.CourseFilter{
+		Search:   strings.TrimSpace(c.Query("q")),
+		Tags:     c.QueryArray("tag"),
+		Language: c.Query("language"),
+		Sort:     c.Query("sort"),
+	}
+	if filter.Sort != "" && filter.Sort != models.CourseSortRating {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be rating"})
+		return
+	}
+
+	var err error
+	if value := c.Query("category_id"); value != "" {
+		if filter.CategoryID, err = strconv.Atoi(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category_id"})
+			return
+		}
+	}
+	if value := c.Query("level"); value != "" {
+		level, ok := models.CourseLevelFromName(value)
+		if !ok {
+			if level, err = strconv.Atoi(value); err != nil || models.CourseLevelName(level) == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "level must be one of beginner, intermediate, advanced"})
+				return
+			}
+		}
+		filter.Level = &level
+	}
+	if value := c.Query("min_duration"); value != "" {
+		if filter.MinDuration, err = strconv.Atoi(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid min_duration"})
+			return
+		}
+	}
+	if value := c.Query("max_duration"); value != "" {
+		if filter.MaxDuration, err = strconv.Atoi(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid max_duration"})
+			return
+		}
+	}
+
+	courses, err := h.courseUseCase.GetCatalog(c.Request.Context(), filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -98,12 +177,287 @@ func (h *CourseHandler) Delete(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
+	userID := c.GetInt("userID")
+	userRole := c.GetString("userRole")
 
-	err = h.courseUseCase.DeleteCourse(ctx, id)
+	err = h.courseUseCase.DeleteCourse(ctx, userID, userRole, id)
 	if err != nil {
+		if errors.Is(err, usecase.ErrPermissionDenied) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
 		return
 	}
 
 	c.JSON(http.StatusNoContent, nil)
-}
\ No newline at end of file
+}
+
+// UpdateCourse обновляет описание и метаданные курса
+func (h *CourseHandler) UpdateCourse(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	var request dto.CreateCourseRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	userRole := c.GetString("userRole")
+
+	course, err := h.courseUseCase.UpdateCourseDetails(c.Request.Context(), userID, userRole, id, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, course)
+}
+
+// AddTags добавляет теги курсу
+func (h *CourseHandler) AddTags(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	var request dto.CourseTagsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	userRole := c.GetString("userRole")
+
+	course, err := h.courseUseCase.AddTags(c.Request.Context(), userID, userRole, id, request.Tags)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tags": course.Tags})
+}
+
+// RemoveTag удаляет тег курса
+func (h *CourseHandler) RemoveTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	userRole := c.GetString("userRole")
+
+	if err := h.courseUseCase.RemoveTag(c.Request.Context(), userID, userRole, id, c.Param("tag")); err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tag removed successfully"})
+}
+
+// GetTags возвращает все теги каталога с количеством курсов
+func (h *CourseHandler) GetTags(c *gin.Context) {
+	tags, err := h.courseUseCase.GetAllTags(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
+// GetPrerequisites возвращает пререквизиты курса и минимальный уровень студента
+func (h *CourseHandler) GetPrerequisites(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	prerequisites, err := h.courseUseCase.GetPrerequisites(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, prerequisites)
+}
+
+// SetPrerequisites заменяет пререквизиты курса
+func (h *CourseHandler) SetPrerequisites(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	var request dto.SetPrerequisitesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	userRole := c.GetString("userRole")
+
+	prerequisites, err := h.courseUseCase.SetPrerequisites(c.Request.Context(), userID, userRole, id, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, prerequisites)
+}
+
+// GetStaff возвращает команду курса
+func (h *CourseHandler) GetStaff(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	userRole := c.GetString("userRole")
+
+	staff, err := h.courseUseCase.GetStaff(c.Request.Context(), userID, userRole, id)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"staff": staff})
+}
+
+// AddStaff добавляет соавтора или ассистента в команду курса
+func (h *CourseHandler) AddStaff(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	var request dto.CourseStaffRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	userRole := c.GetString("userRole")
+
+	member, err := h.courseUseCase.AddStaff(c.Request.Context(), userID, userRole, id, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, member)
+}
+
+// RemoveStaff исключает пользователя из команды курса
+func (h *CourseHandler) RemoveStaff(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+	staffUserID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	userRole := c.GetString("userRole")
+
+	if err := h.courseUseCase.RemoveStaff(c.Request.Context(), userID, userRole, id, staffUserID); err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Staff member removed successfully"})
+}
+
+// TransferOwnership передаёт курс другому преподавателю
+func (h *CourseHandler) TransferOwnership(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	var request dto.TransferOwnershipRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	userRole := c.GetString("userRole")
+
+	course, err := h.courseUseCase.TransferOwnership(c.Request.Context(), userID, userRole, id, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, course)
+}
+
+// GetRoster возвращает студентов курса с их прогрессом
+func (h *CourseHandler) GetRoster(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	userRole := c.GetString("userRole")
+
+	roster, err := h.courseUseCase.GetRoster(c.Request.Context(), userID, userRole, id)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"students": roster})
+}
+
+// CloneCourse копирует курс с уроками в новый черновик текущего пользователя
+func (h *CourseHandler) CloneCourse(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	var request dto.CloneCourseRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	userID := c.GetInt("userID")
+	userRole := c.GetString("userRole")
+
+	course, err := h.courseUseCase.CloneCourse(c.Request.Context(), userID, userRole, id, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, course)
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+)
+
+// максимальный размер пакета курса при импорте
+const maxCoursePackageSize = 100 << 20
+
+type CoursePackageHandler struct {
+	coursePackageUseCase *usecase.CoursePackageUseCase
+}
+
+func NewCoursePackageHandler(coursePackageUseCase *usecase.CoursePackageUseCase) *CoursePackageHandler {
+	return &CoursePackageHandler{
+		coursePackageUseCase: coursePackageUseCase,
+	}
+}
+
+// ExportCourse отдаёт ZIP-пакет курса
+func (h *CoursePackageHandler) ExportCourse(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	userRole := c.GetString("userRole")
+
+	data, err := h.coursePackageUseCase.ExportCourse(c.Request.Context(), userID, userRole, courseID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="course-%d.zip"`, courseID))
+	c.Data(http.StatusOK, "application/zip", data)
+}
+
+// ImportCourse создаёт курс из ZIP-пакета. Файл передаётся полем "file"
+// multipart-формы либо телом запроса с Content-Type application/zip.
+// Query: dry_run=true - только проверка пакета.
+func (h *CoursePackageHandler) ImportCourse(c *gin.Context) {
+	dryRun := c.Query("dry_run") == "true"
+
+	var reader io.Reader
+	if file, err := c.FormFile("file"); err == nil {
+		if file.Size > maxCoursePackageSize {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Course package is too large"})
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+			return
+		}
+		defer f.Close()
+		reader = f
+	} else {
+		reader = http.MaxBytesReader(c.Writer, c.Request.Body, maxCoursePackageSize)
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read course package"})
+		return
+	}
+
+	report, err := h.coursePackageUseCase.ImportCourse(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), bytes.NewReader(data), int64(len(data)), dryRun)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	switch {
+	case len(report.Errors) > 0:
+		c.JSON(http.StatusUnprocessableEntity, report)
+	case report.DryRun:
+		c.JSON(http.StatusOK, report)
+	default:
+		c.JSON(http.StatusCreated, report)
+	}
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+type CourseVersionHandler struct {
+	versionUseCase *usecase.CourseVersionUseCase
+}
+
+func NewCourseVersionHandler(versionUseCase *usecase.CourseVersionUseCase) *CourseVersionHandler {
+	return &CourseVersionHandler{versionUseCase: versionUseCase}
+}
+
+// PublishVersion публикует текущий черновик курса как новую версию
+func (h *CourseVersionHandler) PublishVersion(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	// тело необязательно: заметки к версии можно не указывать
+	var request dto.PublishVersionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	version, err := h.versionUseCase.PublishVersion(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, version)
+}
+
+// GetVersions возвращает историю версий курса
+func (h *CourseVersionHandler) GetVersions(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	versions, err := h.versionUseCase.GetVersions(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"versions": versions})
+}
+
+// GetVersion возвращает версию курса с уроками
+func (h *CourseVersionHandler) GetVersion(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+	versionID, err := strconv.Atoi(c.Param("version_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid version ID"})
+		return
+	}
+
+	version, err := h.versionUseCase.GetVersion(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, versionID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, version)
+}
+
+// GetStudentVersion возвращает версию курса текущего студента и доступное обновление
+func (h *CourseVersionHandler) GetStudentVersion(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	info, err := h.versionUseCase.GetStudentVersion(c.Request.Context(), c.GetInt("userID"), courseID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, info)
+}
+
+// UpgradeVersion переводит текущего студента на последнюю версию курса
+func (h *CourseVersionHandler) UpgradeVersion(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	info, err := h.versionUseCase.UpgradeVersion(c.Request.Context(), c.GetInt("userID"), courseID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, info)
+}
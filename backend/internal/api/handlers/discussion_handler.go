@@ -0,0 +1,311 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+type DiscussionHandler struct {
+	discussionUseCase *usecase.DiscussionUseCase
+}
+
+func NewDiscussionHandler(discussionUseCase *usecase.DiscussionUseCase) *DiscussionHandler {
+	return &DiscussionHandler{discussionUseCase: discussionUseCase}
+}
+
+// threadParams разбирает ID курса и темы из пути
+func threadParams(c *gin.Context) (int, int, bool) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return 0, 0, false
+	}
+	threadID, err := strconv.Atoi(c.Param("thread_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid thread ID"})
+		return 0, 0, false
+	}
+	return courseID, threadID, true
+}
+
+// postParams разбирает ID курса, темы и ответа из пути
+func postParams(c *gin.Context) (int, int, int, bool) {
+	courseID, threadID, ok := threadParams(c)
+	if !ok {
+		return 0, 0, 0, false
+	}
+	postID, err := strconv.Atoi(c.Param("post_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return 0, 0, 0, false
+	}
+	return courseID, threadID, postID, true
+}
+
+// GetThreads возвращает темы курса, ?lesson_id= - только темы урока
+func (h *DiscussionHandler) GetThreads(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	var lessonID *int
+	if value := c.Query("lesson_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lesson ID"})
+			return
+		}
+		lessonID = &id
+	}
+
+	threads, err := h.discussionUseCase.GetThreads(c.Request.Context(), c.GetInt("userID"), courseID, lessonID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"threads": threads})
+}
+
+// CreateThread открывает тему обсуждения
+func (h *DiscussionHandler) CreateThread(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	var request dto.DiscussionThreadRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	thread, err := h.discussionUseCase.CreateThread(c.Request.Context(), c.GetInt("userID"), courseID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, thread)
+}
+
+// GetThread возвращает тему с ответами
+func (h *DiscussionHandler) GetThread(c *gin.Context) {
+	courseID, threadID, ok := threadParams(c)
+	if !ok {
+		return
+	}
+
+	thread, err := h.discussionUseCase.GetThread(c.Request.Context(), c.GetInt("userID"), courseID, threadID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, thread)
+}
+
+// UpdateThread изменяет тему
+func (h *DiscussionHandler) UpdateThread(c *gin.Context) {
+	courseID, threadID, ok := threadParams(c)
+	if !ok {
+		return
+	}
+
+	var request dto.DiscussionThreadRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	thread, err := h.discussionUseCase.UpdateThread(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, threadID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, thread)
+}
+
+// DeleteThread удаляет тему
+func (h *DiscussionHandler) DeleteThread(c *gin.Context) {
+	courseID, threadID, ok := threadParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.discussionUseCase.DeleteThread(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, threadID); err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Thread deleted successfully"})
+}
+
+// GetThreadHistory возвращает историю правок темы
+func (h *DiscussionHandler) GetThreadHistory(c *gin.Context) {
+	courseID, threadID, ok := threadParams(c)
+	if !ok {
+		return
+	}
+
+	edits, err := h.discussionUseCase.GetThreadHistory(c.Request.Context(), c.GetInt("userID"), courseID, threadID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"edits": edits})
+}
+
+// SetAcceptedAnswer отмечает принятый ответ темы
+func (h *DiscussionHandler) SetAcceptedAnswer(c *gin.Context) {
+	courseID, threadID, ok := threadParams(c)
+	if !ok {
+		return
+	}
+
+	var request dto.AcceptedAnswerRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	thread, err := h.discussionUseCase.SetAcceptedAnswer(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, threadID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, thread)
+}
+
+// UpvoteThread голосует за тему
+func (h *DiscussionHandler) UpvoteThread(c *gin.Context) {
+	h.voteThread(c, true)
+}
+
+// RemoveThreadUpvote снимает голос за тему
+func (h *DiscussionHandler) RemoveThreadUpvote(c *gin.Context) {
+	h.voteThread(c, false)
+}
+
+func (h *DiscussionHandler) voteThread(c *gin.Context, upvote bool) {
+	courseID, threadID, ok := threadParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.discussionUseCase.VoteThread(c.Request.Context(), c.GetInt("userID"), courseID, threadID, upvote); err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"upvoted": upvote})
+}
+
+// CreatePost отвечает в теме
+func (h *DiscussionHandler) CreatePost(c *gin.Context) {
+	courseID, threadID, ok := threadParams(c)
+	if !ok {
+		return
+	}
+
+	var request dto.DiscussionPostRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	post, err := h.discussionUseCase.CreatePost(c.Request.Context(), c.GetInt("userID"), courseID, threadID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, post)
+}
+
+// UpdatePost изменяет ответ
+func (h *DiscussionHandler) UpdatePost(c *gin.Context) {
+	courseID, threadID, postID, ok := postParams(c)
+	if !ok {
+		return
+	}
+
+	var request dto.DiscussionPostRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	post, err := h.discussionUseCase.UpdatePost(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, threadID, postID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, post)
+}
+
+// DeletePost удаляет ответ
+func (h *DiscussionHandler) DeletePost(c *gin.Context) {
+	courseID, threadID, postID, ok := postParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.discussionUseCase.DeletePost(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, threadID, postID); err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Post deleted successfully"})
+}
+
+// GetPostHistory возвращает историю правок ответа
+func (h *DiscussionHandler) GetPostHistory(c *gin.Context) {
+	courseID, threadID, postID, ok := postParams(c)
+	if !ok {
+		return
+	}
+
+	edits, err := h.discussionUseCase.GetPostHistory(c.Request.Context(), c.GetInt("userID"), courseID, threadID, postID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"edits": edits})
+}
+
+// UpvotePost голосует за ответ
+func (h *DiscussionHandler) UpvotePost(c *gin.Context) {
+	h.votePost(c, true)
+}
+
+// RemovePostUpvote снимает голос за ответ
+func (h *DiscussionHandler) RemovePostUpvote(c *gin.Context) {
+	h.votePost(c, false)
+}
+
+func (h *DiscussionHandler) votePost(c *gin.Context, upvote bool) {
+	courseID, threadID, postID, ok := postParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.discussionUseCase.VotePost(c.Request.Context(), c.GetInt("userID"), courseID, threadID, postID, upvote); err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"upvoted": upvote})
+}
@@ -1,11 +1,14 @@
 package handlers
 
 import (
+    "errors"
     "net/http"
     "strconv"
 
     "github.com/gin-gonic/gin"
+    "gitlab.com/w0ikid/study-platform/internal/domain/models"
     "gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+    "gitlab.com/w0ikid/study-platform/internal/dto"
 )
 
 type EnrollmentHandler struct {
@@ -31,15 +34,143 @@ func (h *EnrollmentHandler) CreateEnrollment(c *gin.Context) {
         return
     }
 
-    err = h.enrollmentUseCase.EnrollStudent(ctx, userID, courseID)
+    result, err := h.enrollmentUseCase.JoinCourse(ctx, userID, courseID)
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        var prerequisitesErr *usecase.PrerequisitesNotMetError
+        if errors.As(err, &prerequisitesErr) {
+            respondPrerequisitesNotMet(c, prerequisitesErr)
+            return
+        }
+        c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+        return
+    }
+
+    if result.Status == models.EnrollmentResultWaitlisted {
+        c.JSON(http.StatusAccepted, gin.H{
+            "message":  "Course is full, you have been added to the waitlist",
+            "status":   result.Status,
+            "waitlist": result.Waitlist,
+        })
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message":    "Enrollment created successfully",
+        "status":     result.Status,
+        "enrollment": result.Enrollment,
+    })
+}
+
+// DropCourse отписывает текущего пользователя от курса
+func (h *EnrollmentHandler) DropCourse(c *gin.Context) {
+    courseID, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+        return
+    }
+
+    if err := h.enrollmentUseCase.DropCourse(c.Request.Context(), c.GetInt("userID"), courseID); err != nil {
+        c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "You have left the course"})
+}
+
+// GetWaitlist возвращает лист ожидания курса
+func (h *EnrollmentHandler) GetWaitlist(c *gin.Context) {
+    courseID, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+        return
+    }
+
+    waitlist, err := h.enrollmentUseCase.GetWaitlist(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID)
+    if err != nil {
+        c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"waitlist": waitlist})
+}
+
+// LeaveWaitlist убирает текущего пользователя из листа ожидания
+func (h *EnrollmentHandler) LeaveWaitlist(c *gin.Context) {
+    courseID, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+        return
+    }
+
+    if err := h.enrollmentUseCase.LeaveWaitlist(c.Request.Context(), c.GetInt("userID"), courseID); err != nil {
+        c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "You have left the waitlist"})
+}
+
+// UpdateSchedule задаёт даты курса, окно записи и лимит мест
+func (h *EnrollmentHandler) UpdateSchedule(c *gin.Context) {
+    courseID, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+        return
+    }
+
+    var request dto.CourseScheduleRequest
+    if err := c.ShouldBindJSON(&request); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    course, err := h.enrollmentUseCase.UpdateSchedule(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, &request)
+    if err != nil {
+        c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusOK, course)
+}
+
+// AdminEnroll зачисляет студента на курс от имени администратора, при необходимости в обход пререквизитов
+func (h *EnrollmentHandler) AdminEnroll(c *gin.Context) {
+    courseID, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+        return
+    }
+
+    var request dto.AdminEnrollRequest
+    if err := c.ShouldBindJSON(&request); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    err = h.enrollmentUseCase.AdminEnrollStudent(c.Request.Context(), request.UserID, courseID, request.OverridePrerequisites)
+    if err != nil {
+        var prerequisitesErr *usecase.PrerequisitesNotMetError
+        if errors.As(err, &prerequisitesErr) {
+            respondPrerequisitesNotMet(c, prerequisitesErr)
+            return
+        }
+        c.JSON(statusFromError(err), gin.H{"error": err.Error()})
         return
     }
 
     c.JSON(http.StatusCreated, gin.H{"message": "Enrollment created successfully"})
 }
 
+// respondPrerequisitesNotMet сообщает, каких пререквизитов не хватает студенту
+func respondPrerequisitesNotMet(c *gin.Context, err *usecase.PrerequisitesNotMetError) {
+    c.JSON(http.StatusForbidden, gin.H{
+        "error":                 err.Error(),
+        "missing_prerequisites": err.MissingCourses,
+        "required_level":        err.RequiredLevel,
+        "user_level":            err.UserLevel,
+    })
+}
+
 // GetEnrollment обрабатывает получение записи о зачислении по ID
 func (h *EnrollmentHandler) GetEnrollment(c *gin.Context) {
     idStr := c.Param("id")
@@ -88,7 +219,7 @@ func (h *EnrollmentHandler) Delete(c *gin.Context) {
 
     err = h.enrollmentUseCase.DeleteEnrollment(ctx, id)
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        c.JSON(statusFromError(err), gin.H{"error": err.Error()})
         return
     }
 
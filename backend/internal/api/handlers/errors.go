@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+)
+
+// statusFromError подбирает HTTP-статус для ошибки, пришедшей из usecase
+func statusFromError(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrPermissionDenied):
+		return http.StatusForbidden
+	case errors.Is(err, usecase.ErrCourseFull), errors.Is(err, usecase.ErrLessonRequirementsNotMet),
+		errors.Is(err, models.ErrCouponLimitReached):
+		return http.StatusConflict
+	case errors.Is(err, usecase.ErrPaymentRequired):
+		return http.StatusPaymentRequired
+	case errors.Is(err, usecase.ErrFileTooLarge), errors.Is(err, usecase.ErrQuotaExceeded):
+		return http.StatusRequestEntityTooLarge
+	case strings.Contains(err.Error(), "validation failed"):
+		return http.StatusBadRequest
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+type GroupHandler struct {
+	groupUseCase *usecase.GroupUseCase
+}
+
+func NewGroupHandler(groupUseCase *usecase.GroupUseCase) *GroupHandler {
+	return &GroupHandler{
+		groupUseCase: groupUseCase,
+	}
+}
+
+// CreateGroup создаёт новую группу
+func (h *GroupHandler) CreateGroup(c *gin.Context) {
+	var request dto.CreateGroupRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt("userID")
+
+	group, err := h.groupUseCase.CreateGroup(c.Request.Context(), userID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, group)
+}
+
+// GetGroups возвращает группы текущего пользователя (все группы для администратора)
+func (h *GroupHandler) GetGroups(c *gin.Context) {
+	userID := c.GetInt("userID")
+	userRole := c.GetString("userRole")
+
+	groups, err := h.groupUseCase.GetGroups(c.Request.Context(), userID, userRole)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"groups": groups})
+}
+
+// GetGroup возвращает группу по ID
+func (h *GroupHandler) GetGroup(c *gin.Context) {
+	groupID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	userRole := c.GetString("userRole")
+
+	group, err := h.groupUseCase.GetGroup(c.Request.Context(), userID, userRole, groupID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// UpdateGroup обновляет название и описание группы
+func (h *GroupHandler) UpdateGroup(c *gin.Context) {
+	groupID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	var request dto.CreateGroupRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	userRole := c.GetString("userRole")
+
+	group, err := h.groupUseCase.UpdateGroup(c.Request.Context(), userID, userRole, groupID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// DeleteGroup удаляет группу
+func (h *GroupHandler) DeleteGroup(c *gin.Context) {
+	groupID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	userRole := c.GetString("userRole")
+
+	if err := h.groupUseCase.DeleteGroup(c.Request.Context(), userID, userRole, groupID); err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Group deleted successfully"})
+}
+
+// GetMembers возвращает участников группы
+func (h *GroupHandler) GetMembers(c *gin.Context) {
+	groupID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	userRole := c.GetString("userRole")
+
+	members, err := h.groupUseCase.GetMembers(c.Request.Context(), userID, userRole, groupID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": members})
+}
+
+// AddMember добавляет пользователя в группу и зачисляет его на курсы группы
+func (h *GroupHandler) AddMember(c *gin.Context) {
+	groupID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	var request dto.GroupMemberRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	userRole := c.GetString("userRole")
+
+	member, enrollments, err := h.groupUseCase.AddMember(c.Request.Context(), userID, userRole, groupID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"member":      member,
+		"enrollments": enrollments,
+	})
+}
+
+// RemoveMember исключает пользователя из группы
+func (h *GroupHandler) RemoveMember(c *gin.Context) {
+	groupID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+	memberID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	userRole := c.GetString("userRole")
+
+	if err := h.groupUseCase.RemoveMember(c.Request.Context(), userID, userRole, groupID, memberID); err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed successfully"})
+}
+
+// GetCourses возвращает курсы группы
+func (h *GroupHandler) GetCourses(c *gin.Context) {
+	groupID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	userRole := c.GetString("userRole")
+
+	courses, err := h.groupUseCase.GetCourses(c.Request.Context(), userID, userRole, groupID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"courses": courses})
+}
+
+// EnrollGroup зачисляет всех студентов группы на курс
+func (h *GroupHandler) EnrollGroup(c *gin.Context) {
+	groupID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	var request dto.GroupCourseRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	userRole := c.GetString("userRole")
+
+	results, err := h.groupUseCase.EnrollGroup(c.Request.Context(), userID, userRole, groupID, request.CourseID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"enrollments": results})
+}
+
+// RemoveCourse отвязывает курс от группы
+func (h *GroupHandler) RemoveCourse(c *gin.Context) {
+	groupID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+	courseID, err := strconv.Atoi(c.Param("course_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	userRole := c.GetString("userRole")
+
+	if err := h.groupUseCase.RemoveCourse(c.Request.Context(), userID, userRole, groupID, courseID); err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Course removed from group"})
+}
+
+// GetProgressReport возвращает отчёт о прогрессе группы по курсу
+func (h *GroupHandler) GetProgressReport(c *gin.Context) {
+	groupID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+	courseID, err := strconv.Atoi(c.Param("course_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	userRole := c.GetString("userRole")
+
+	report, err := h.groupUseCase.GetProgressReport(c.Request.Context(), userID, userRole, groupID, courseID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
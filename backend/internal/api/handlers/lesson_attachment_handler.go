@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+type LessonAttachmentHandler struct {
+	lessonAttachmentUseCase *usecase.LessonAttachmentUseCase
+}
+
+func NewLessonAttachmentHandler(lessonAttachmentUseCase *usecase.LessonAttachmentUseCase) *LessonAttachmentHandler {
+	return &LessonAttachmentHandler{lessonAttachmentUseCase: lessonAttachmentUseCase}
+}
+
+func lessonAttachmentParams(c *gin.Context) (int, int, int, bool) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return 0, 0, 0, false
+	}
+	attachmentID, err := strconv.Atoi(c.Param("attachment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment ID"})
+		return 0, 0, 0, false
+	}
+	return courseID, lessonID, attachmentID, true
+}
+
+// GetAttachments возвращает вложения урока со счётчиками скачиваний
+func (h *LessonAttachmentHandler) GetAttachments(c *gin.Context) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return
+	}
+
+	attachments, err := h.lessonAttachmentUseCase.GetAttachments(c.Request.Context(), courseID, lessonID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"attachments": attachments})
+}
+
+// UploadAttachment загружает вложение полем "file" multipart-формы; title и watermark - необязательные поля формы
+func (h *LessonAttachmentHandler) UploadAttachment(c *gin.Context) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return
+	}
+
+	var request dto.LessonAttachmentUploadRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+		return
+	}
+	defer f.Close()
+
+	attachment, err := h.lessonAttachmentUseCase.UploadAttachment(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID, &request, file.Filename, file.Size, f)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+// UpdateAttachment меняет название вложения и признак водяного знака
+func (h *LessonAttachmentHandler) UpdateAttachment(c *gin.Context) {
+	courseID, lessonID, attachmentID, ok := lessonAttachmentParams(c)
+	if !ok {
+		return
+	}
+
+	var request dto.UpdateLessonAttachmentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	attachment, err := h.lessonAttachmentUseCase.UpdateAttachment(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID, attachmentID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, attachment)
+}
+
+// DeleteAttachment удаляет вложение урока
+func (h *LessonAttachmentHandler) DeleteAttachment(c *gin.Context) {
+	courseID, lessonID, attachmentID, ok := lessonAttachmentParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.lessonAttachmentUseCase.DeleteAttachment(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID, attachmentID); err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Attachment deleted"})
+}
+
+// DownloadAttachment отдаёт содержимое вложения; доступ проверяется как для остальных материалов курса
+func (h *LessonAttachmentHandler) DownloadAttachment(c *gin.Context) {
+	courseID, lessonID, attachmentID, ok := lessonAttachmentParams(c)
+	if !ok {
+		return
+	}
+
+	attachment, content, err := h.lessonAttachmentUseCase.DownloadAttachment(c.Request.Context(), c.GetInt("userID"), courseID, lessonID, attachmentID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+	defer content.Close()
+
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", attachment.Filename))
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, nil)
+}
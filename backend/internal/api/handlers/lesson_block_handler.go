@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+type LessonBlockHandler struct {
+	lessonBlockUseCase *usecase.LessonBlockUseCase
+}
+
+func NewLessonBlockHandler(lessonBlockUseCase *usecase.LessonBlockUseCase) *LessonBlockHandler {
+	return &LessonBlockHandler{lessonBlockUseCase: lessonBlockUseCase}
+}
+
+func lessonBlockParams(c *gin.Context) (int, int, int, bool) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return 0, 0, 0, false
+	}
+	blockID, err := strconv.Atoi(c.Param("block_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid block ID"})
+		return 0, 0, 0, false
+	}
+	return courseID, lessonID, blockID, true
+}
+
+// GetSchemas возвращает JSON-схемы поля data для каждого типа блока
+func (h *LessonBlockHandler) GetSchemas(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"schemas": usecase.LessonBlockSchemas})
+}
+
+// GetBlocks возвращает блоки урока по порядку
+func (h *LessonBlockHandler) GetBlocks(c *gin.Context) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return
+	}
+
+	blocks, err := h.lessonBlockUseCase.GetBlocks(c.Request.Context(), c.GetInt("userID"), courseID, lessonID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"blocks": blocks})
+}
+
+// CreateBlock добавляет блок в конец урока
+func (h *LessonBlockHandler) CreateBlock(c *gin.Context) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return
+	}
+
+	var request dto.LessonBlockRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	block, err := h.lessonBlockUseCase.CreateBlock(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, block)
+}
+
+// UpdateBlock меняет содержимое блока
+func (h *LessonBlockHandler) UpdateBlock(c *gin.Context) {
+	courseID, lessonID, blockID, ok := lessonBlockParams(c)
+	if !ok {
+		return
+	}
+
+	var request dto.LessonBlockRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	block, err := h.lessonBlockUseCase.UpdateBlock(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID, blockID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, block)
+}
+
+// DeleteBlock удаляет блок
+func (h *LessonBlockHandler) DeleteBlock(c *gin.Context) {
+	courseID, lessonID, blockID, ok := lessonBlockParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.lessonBlockUseCase.DeleteBlock(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID, blockID); err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Block deleted"})
+}
+
+// ReorderBlocks меняет порядок блоков урока
+func (h *LessonBlockHandler) ReorderBlocks(c *gin.Context) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return
+	}
+
+	var request dto.ReorderLessonBlocksRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	blocks, err := h.lessonBlockUseCase.ReorderBlocks(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"blocks": blocks})
+}
+
+// CompleteBlock отмечает блок завершённым текущим студентом
+func (h *LessonBlockHandler) CompleteBlock(c *gin.Context) {
+	courseID, lessonID, blockID, ok := lessonBlockParams(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.lessonBlockUseCase.CompleteBlock(c.Request.Context(), c.GetInt("userID"), courseID, lessonID, blockID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -40,6 +40,7 @@ func (h *LessonHandler) CreateLesson(c *gin.Context) {
 
 
 	userID := c.GetInt("userID")
+	userRole := c.GetString("userRole")
 
 	ctx := c.Request.Context()
 
@@ -48,12 +49,15 @@ func (h *LessonHandler) CreateLesson(c *gin.Context) {
 		Content:   request.Content,
 		CourseID:  courseID,
 		VideoURL:  request.VideoURL,
+		VideoDuration: request.VideoDuration,
 		UserID: userID,
+		UserRole: userRole,
+		ModuleID: request.ModuleID,
 	}
 
 	lesson, err := h.lessonUseCase.CreateLesson(ctx, input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -74,7 +78,7 @@ func (h *LessonHandler) GetLessonsByCourse(c *gin.Context) {
 	ctx := c.Request.Context()
 	
 	// h.lessonUseCase.GetLessonsForStudent(ctx, userID, courseID)
-	lessons, err := h.lessonUseCase.GetLessonsForStudent(ctx, userID, courseID)
+	lessons, err := h.lessonUseCase.GetLessonsForStudent(ctx, userID, c.GetString("userRole"), courseID)
 	
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -85,4 +89,127 @@ func (h *LessonHandler) GetLessonsByCourse(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"lessons": lessons,
 	})
-}
\ No newline at end of file
+}
+
+// GetLesson возвращает один урок курса
+func (h *LessonHandler) GetLesson(c *gin.Context) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return
+	}
+
+	lesson, err := h.lessonUseCase.GetLesson(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, lesson)
+}
+
+// UpdateLesson заменяет содержимое урока
+func (h *LessonHandler) UpdateLesson(c *gin.Context) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return
+	}
+
+	var request dto.UpdateLessonRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lesson, err := h.lessonUseCase.UpdateLesson(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, lesson)
+}
+
+// PatchLesson меняет переданные поля урока
+func (h *LessonHandler) PatchLesson(c *gin.Context) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return
+	}
+
+	var request dto.PatchLessonRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lesson, err := h.lessonUseCase.PatchLesson(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, lesson)
+}
+
+// DeleteLesson переносит урок в корзину курса
+func (h *LessonHandler) DeleteLesson(c *gin.Context) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.lessonUseCase.DeleteLesson(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID); err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Lesson deleted"})
+}
+
+// RestoreLesson возвращает урок из корзины
+func (h *LessonHandler) RestoreLesson(c *gin.Context) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return
+	}
+
+	lesson, err := h.lessonUseCase.RestoreLesson(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, lesson)
+}
+
+// GetDeletedLessons возвращает корзину курса
+func (h *LessonHandler) GetDeletedLessons(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	lessons, err := h.lessonUseCase.GetDeletedLessons(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"lessons": lessons})
+}
+
+// lessonParams разбирает ID курса и урока из пути; при ошибке отвечает 400
+func lessonParams(c *gin.Context) (int, int, bool) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return 0, 0, false
+	}
+	lessonID, err := strconv.Atoi(c.Param("lesson_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lesson ID"})
+		return 0, 0, false
+	}
+	return courseID, lessonID, true
+}
@@ -58,7 +58,5 @@ func (h *LessonProgressHandler) GetCourseProgress(c *gin.Context) {
         return
 	}
 	
-	c.JSON(http.StatusOK, gin.H{
-		"progress": progress,
-	})
+	c.JSON(http.StatusOK, progress)
 }
\ No newline at end of file
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+type LessonReleaseHandler struct {
+	lessonReleaseUseCase *usecase.LessonReleaseUseCase
+}
+
+func NewLessonReleaseHandler(lessonReleaseUseCase *usecase.LessonReleaseUseCase) *LessonReleaseHandler {
+	return &LessonReleaseHandler{lessonReleaseUseCase: lessonReleaseUseCase}
+}
+
+// GetRules возвращает правила открытия уроков курса
+func (h *LessonReleaseHandler) GetRules(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	rules, err := h.lessonReleaseUseCase.GetRules(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rules": rules})
+}
+
+// SetRule задаёт правило открытия урока
+func (h *LessonReleaseHandler) SetRule(c *gin.Context) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return
+	}
+
+	var request dto.LessonReleaseRuleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := h.lessonReleaseUseCase.SetRule(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteRule открывает урок сразу
+func (h *LessonReleaseHandler) DeleteRule(c *gin.Context) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.lessonReleaseUseCase.DeleteRule(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID); err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Lesson release rule deleted"})
+}
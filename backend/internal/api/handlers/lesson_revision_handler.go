@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+)
+
+type LessonRevisionHandler struct {
+	lessonRevisionUseCase *usecase.LessonRevisionUseCase
+}
+
+func NewLessonRevisionHandler(lessonRevisionUseCase *usecase.LessonRevisionUseCase) *LessonRevisionHandler {
+	return &LessonRevisionHandler{lessonRevisionUseCase: lessonRevisionUseCase}
+}
+
+// ListRevisions возвращает историю правок урока
+func (h *LessonRevisionHandler) ListRevisions(c *gin.Context) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.lessonRevisionUseCase.ListRevisions(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revisions": revisions})
+}
+
+// GetRevision возвращает ревизию урока с содержимым
+func (h *LessonRevisionHandler) GetRevision(c *gin.Context) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return
+	}
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision number"})
+		return
+	}
+
+	revision, err := h.lessonRevisionUseCase.GetRevision(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID, number)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, revision)
+}
+
+// DiffRevisions сравнивает ревизии ?from= и ?to=
+func (h *LessonRevisionHandler) DiffRevisions(c *gin.Context) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return
+	}
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from"})
+		return
+	}
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to"})
+		return
+	}
+
+	diff, err := h.lessonRevisionUseCase.DiffRevisions(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID, from, to)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// RestoreRevision возвращает урок к ревизии, сохраняя это новой ревизией
+func (h *LessonRevisionHandler) RestoreRevision(c *gin.Context) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return
+	}
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision number"})
+		return
+	}
+
+	revision, err := h.lessonRevisionUseCase.RestoreRevision(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID, number)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, revision)
+}
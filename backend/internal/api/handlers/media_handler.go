@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+type MediaHandler struct {
+	mediaUseCase *usecase.MediaUseCase
+}
+
+func NewMediaHandler(mediaUseCase *usecase.MediaUseCase) *MediaHandler {
+	return &MediaHandler{mediaUseCase: mediaUseCase}
+}
+
+// Upload загружает файл полем "file" multipart-формы; course_id и public - необязательные поля формы
+func (h *MediaHandler) Upload(c *gin.Context) {
+	var request dto.MediaUploadRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+		return
+	}
+	defer f.Close()
+
+	media, err := h.mediaUseCase.Upload(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), &request, file.Filename, file.Size, f)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, media)
+}
+
+// GetMyFiles возвращает файлы текущего пользователя и использование квоты
+func (h *MediaHandler) GetMyFiles(c *gin.Context) {
+	files, usage, err := h.mediaUseCase.GetMyFiles(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"files": files, "usage": usage})
+}
+
+// GetFile возвращает файл с временной ссылкой на скачивание
+func (h *MediaHandler) GetFile(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid media ID"})
+		return
+	}
+
+	file, err := h.mediaUseCase.GetFile(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), id)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, file)
+}
+
+// DeleteFile удаляет файл
+func (h *MediaHandler) DeleteFile(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid media ID"})
+		return
+	}
+
+	if err := h.mediaUseCase.DeleteFile(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), id); err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "File deleted successfully"})
+}
+
+// GetPublicContent отдаёт содержимое публичного файла без авторизации
+func (h *MediaHandler) GetPublicContent(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid media ID"})
+		return
+	}
+
+	file, content, err := h.mediaUseCase.OpenPublic(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+	defer content.Close()
+
+	c.Header("Cache-Control", "public, max-age=86400")
+	serveMedia(c, file, content, "inline")
+}
+
+// Download отдаёт файл по подписанной ссылке локального хранилища
+func (h *MediaHandler) Download(c *gin.Context) {
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expires"})
+		return
+	}
+	key := c.Param("key")
+	if len(key) > 0 && key[0] == '/' {
+		key = key[1:]
+	}
+
+	file, content, err := h.mediaUseCase.OpenSigned(c.Request.Context(), key, expires, c.Query("signature"))
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+	defer content.Close()
+
+	c.Header("Cache-Control", "private, no-store")
+	serveMedia(c, file, content, "attachment")
+}
+
+// SetCourseImage делает загруженное изображение обложкой курса
+func (h *MediaHandler) SetCourseImage(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	var request dto.CourseImageRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	course, err := h.mediaUseCase.SetCourseImage(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"id": course.ID, "image_url": course.ImageUrl})
+}
+
+// serveMedia отдаёт содержимое файла; nosniff не даёт браузеру переопределить проверенный тип
+func serveMedia(c *gin.Context, file *models.MediaFile, content io.Reader, disposition string) {
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Disposition", fmt.Sprintf("%s; filename=%q", disposition, file.Filename))
+	c.DataFromReader(http.StatusOK, file.Size, file.ContentType, content, nil)
+}
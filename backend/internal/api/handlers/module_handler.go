@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+type ModuleHandler struct {
+	moduleUseCase *usecase.ModuleUseCase
+}
+
+func NewModuleHandler(moduleUseCase *usecase.ModuleUseCase) *ModuleHandler {
+	return &ModuleHandler{
+		moduleUseCase: moduleUseCase,
+	}
+}
+
+// GetModules возвращает модули курса вместе с уроками
+func (h *ModuleHandler) GetModules(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	modules, err := h.moduleUseCase.GetModules(c.Request.Context(), c.GetInt("userID"), courseID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"modules": modules})
+}
+
+// CreateModule создаёт модуль в курсе
+func (h *ModuleHandler) CreateModule(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	var request dto.ModuleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	module, err := h.moduleUseCase.CreateModule(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, module)
+}
+
+// UpdateModule изменяет название, описание и позицию модуля
+func (h *ModuleHandler) UpdateModule(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+	moduleID, err := strconv.Atoi(c.Param("module_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid module ID"})
+		return
+	}
+
+	var request dto.ModuleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	module, err := h.moduleUseCase.UpdateModule(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, moduleID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, module)
+}
+
+// DeleteModule удаляет модуль, его уроки переходят в модуль по умолчанию
+func (h *ModuleHandler) DeleteModule(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+	moduleID, err := strconv.Atoi(c.Param("module_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid module ID"})
+		return
+	}
+
+	if err := h.moduleUseCase.DeleteModule(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, moduleID); err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Module deleted"})
+}
+
+// MoveLesson переносит урок в другой модуль и/или на другую позицию
+func (h *ModuleHandler) MoveLesson(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+	lessonID, err := strconv.Atoi(c.Param("lesson_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lesson ID"})
+		return
+	}
+
+	var request dto.MoveLessonRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lesson, err := h.moduleUseCase.MoveLesson(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, lesson)
+}
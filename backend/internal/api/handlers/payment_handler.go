@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+// maxWebhookBodySize - предел размера тела вебхука платёжного провайдера
+const maxWebhookBodySize = 1 << 20
+
+type PaymentHandler struct {
+	paymentUseCase *usecase.PaymentUseCase
+}
+
+func NewPaymentHandler(paymentUseCase *usecase.PaymentUseCase) *PaymentHandler {
+	return &PaymentHandler{paymentUseCase: paymentUseCase}
+}
+
+// SetPrice задаёт цену курса
+func (h *PaymentHandler) SetPrice(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	var request dto.CoursePriceRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	course, err := h.paymentUseCase.SetPrice(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, course)
+}
+
+// CreateOrder оформляет покупку курса текущим пользователем
+func (h *PaymentHandler) CreateOrder(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	// тело необязательно: без него заказ оформляется без купона
+	var request dto.CreateOrderRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	order, err := h.paymentUseCase.CreateOrder(c.Request.Context(), c.GetInt("userID"), courseID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, order)
+}
+
+// GetMyOrders возвращает заказы текущего пользователя
+func (h *PaymentHandler) GetMyOrders(c *gin.Context) {
+	orders, err := h.paymentUseCase.GetMyOrders(c.Request.Context(), c.GetInt("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"orders": orders})
+}
+
+// GetOrder возвращает заказ
+func (h *PaymentHandler) GetOrder(c *gin.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	order, err := h.paymentUseCase.GetOrder(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), orderID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// GetInvoice отдаёт PDF-счёт по оплаченному заказу
+func (h *PaymentHandler) GetInvoice(c *gin.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	pdfData, err := h.paymentUseCase.GetInvoice(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), orderID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=invoice-%d.pdf", orderID))
+	c.Data(http.StatusOK, "application/pdf", pdfData)
+}
+
+// RefundOrder возвращает деньги за заказ и отзывает запись на курс
+func (h *PaymentHandler) RefundOrder(c *gin.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	order, err := h.paymentUseCase.RefundOrder(c.Request.Context(), orderID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// HandleWebhook принимает уведомления платёжного провайдера, подпись - в заголовке X-Payment-Signature
+func (h *PaymentHandler) HandleWebhook(c *gin.Context) {
+	payload, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+		return
+	}
+
+	if err := h.paymentUseCase.HandleWebhook(c.Request.Context(), payload, c.GetHeader("X-Payment-Signature")); err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook processed"})
+}
+
+// CreateCoupon создаёт купон
+func (h *PaymentHandler) CreateCoupon(c *gin.Context) {
+	var request dto.CouponRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	coupon, err := h.paymentUseCase.CreateCoupon(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, coupon)
+}
+
+// GetCoupons возвращает все купоны
+func (h *PaymentHandler) GetCoupons(c *gin.Context) {
+	coupons, err := h.paymentUseCase.GetCoupons(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"coupons": coupons})
+}
+
+// GetCourseCoupons возвращает купоны курса
+func (h *PaymentHandler) GetCourseCoupons(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	coupons, err := h.paymentUseCase.GetCourseCoupons(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"coupons": coupons})
+}
+
+// DeleteCoupon удаляет купон
+func (h *PaymentHandler) DeleteCoupon(c *gin.Context) {
+	couponID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid coupon ID"})
+		return
+	}
+
+	if err := h.paymentUseCase.DeleteCoupon(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), couponID); err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Coupon deleted successfully"})
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+)
+
+type RecommendationHandler struct {
+	recommendationUseCase *usecase.RecommendationUseCase
+}
+
+func NewRecommendationHandler(recommendationUseCase *usecase.RecommendationUseCase) *RecommendationHandler {
+	return &RecommendationHandler{recommendationUseCase: recommendationUseCase}
+}
+
+// GetRecommendations возвращает рекомендованные текущему пользователю курсы, ?limit= - сколько (по умолчанию 10)
+func (h *RecommendationHandler) GetRecommendations(c *gin.Context) {
+	limit := 0
+	if value := c.Query("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+	}
+
+	courses, err := h.recommendationUseCase.GetRecommendations(c.Request.Context(), c.GetInt("userID"), limit)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"courses": courses})
+}
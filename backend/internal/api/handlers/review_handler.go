@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+type ReviewHandler struct {
+	reviewUseCase *usecase.ReviewUseCase
+}
+
+func NewReviewHandler(reviewUseCase *usecase.ReviewUseCase) *ReviewHandler {
+	return &ReviewHandler{
+		reviewUseCase: reviewUseCase,
+	}
+}
+
+// reviewParams разбирает ID курса и отзыва из пути
+func reviewParams(c *gin.Context) (int, int, bool) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return 0, 0, false
+	}
+	reviewID, err := strconv.Atoi(c.Param("review_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return 0, 0, false
+	}
+	return courseID, reviewID, true
+}
+
+// GetCourseReviews возвращает отзывы курса
+func (h *ReviewHandler) GetCourseReviews(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	reviews, err := h.reviewUseCase.GetCourseReviews(c.Request.Context(), c.GetString("userRole"), courseID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reviews": reviews})
+}
+
+// CreateReview оставляет отзыв о курсе
+func (h *ReviewHandler) CreateReview(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	var request dto.ReviewRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := h.reviewUseCase.CreateReview(c.Request.Context(), c.GetInt("userID"), courseID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, review)
+}
+
+// UpdateReview редактирует свой отзыв
+func (h *ReviewHandler) UpdateReview(c *gin.Context) {
+	courseID, reviewID, ok := reviewParams(c)
+	if !ok {
+		return
+	}
+
+	var request dto.ReviewRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := h.reviewUseCase.UpdateReview(c.Request.Context(), c.GetInt("userID"), courseID, reviewID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// DeleteReview удаляет отзыв
+func (h *ReviewHandler) DeleteReview(c *gin.Context) {
+	courseID, reviewID, ok := reviewParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.reviewUseCase.DeleteReview(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, reviewID); err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Review deleted"})
+}
+
+// ReplyToReview сохраняет ответ преподавателя на отзыв
+func (h *ReviewHandler) ReplyToReview(c *gin.Context) {
+	courseID, reviewID, ok := reviewParams(c)
+	if !ok {
+		return
+	}
+
+	var request dto.ReviewReplyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := h.reviewUseCase.ReplyToReview(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, reviewID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// ReportReview отправляет жалобу на отзыв
+func (h *ReviewHandler) ReportReview(c *gin.Context) {
+	courseID, reviewID, ok := reviewParams(c)
+	if !ok {
+		return
+	}
+
+	var request dto.ReviewReportRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.reviewUseCase.ReportReview(c.Request.Context(), c.GetInt("userID"), courseID, reviewID, &request); err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Review reported"})
+}
+
+// SetReviewStatus скрывает или показывает отзыв
+func (h *ReviewHandler) SetReviewStatus(c *gin.Context) {
+	courseID, reviewID, ok := reviewParams(c)
+	if !ok {
+		return
+	}
+
+	var request dto.ReviewStatusRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := h.reviewUseCase.SetReviewStatus(c.Request.Context(), courseID, reviewID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// GetReportedReviews возвращает очередь модерации
+func (h *ReviewHandler) GetReportedReviews(c *gin.Context) {
+	reviews, err := h.reviewUseCase.GetReportedReviews(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reviews": reviews})
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+)
+
+// максимальный размер CSV-файла импорта
+const maxImportFileSize = 5 << 20
+
+type UserImportHandler struct {
+	userImportUseCase *usecase.UserImportUseCase
+}
+
+func NewUserImportHandler(userImportUseCase *usecase.UserImportUseCase) *UserImportHandler {
+	return &UserImportHandler{
+		userImportUseCase: userImportUseCase,
+	}
+}
+
+// ImportUsers импортирует пользователей из CSV. Файл передаётся полем "file"
+// multipart-формы либо телом запроса с Content-Type text/csv.
+// Query: dry_run=true - только проверка, invite=true - отправить пароли письмом.
+func (h *UserImportHandler) ImportUsers(c *gin.Context) {
+	dryRun := c.Query("dry_run") == "true"
+	sendInvites := c.Query("invite") == "true"
+
+	var reader io.Reader
+	if file, err := c.FormFile("file"); err == nil {
+		if file.Size > maxImportFileSize {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "CSV file is too large"})
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+			return
+		}
+		defer f.Close()
+		reader = f
+	} else {
+		reader = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize)
+	}
+
+	report, err := h.userImportUseCase.ImportUsers(c.Request.Context(), reader, dryRun, sendInvites)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	switch {
+	case report.DryRun:
+		c.JSON(http.StatusOK, report)
+	case report.Invalid > 0:
+		// ни одна учётная запись не создана
+		c.JSON(http.StatusUnprocessableEntity, report)
+	default:
+		c.JSON(http.StatusCreated, report)
+	}
+}
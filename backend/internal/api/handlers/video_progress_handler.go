@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+type VideoProgressHandler struct {
+	videoProgressUseCase *usecase.VideoProgressUseCase
+}
+
+func NewVideoProgressHandler(videoProgressUseCase *usecase.VideoProgressUseCase) *VideoProgressHandler {
+	return &VideoProgressHandler{videoProgressUseCase: videoProgressUseCase}
+}
+
+// Heartbeat принимает отрезок, проигранный с прошлого heartbeat, и возвращает прогресс просмотра
+func (h *VideoProgressHandler) Heartbeat(c *gin.Context) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return
+	}
+
+	var request dto.VideoHeartbeatRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	progress, err := h.videoProgressUseCase.Heartbeat(c.Request.Context(), c.GetInt("userID"), courseID, lessonID, &request)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// GetProgress возвращает прогресс просмотра видео урока текущим пользователем
+func (h *VideoProgressHandler) GetProgress(c *gin.Context) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return
+	}
+
+	progress, err := h.videoProgressUseCase.GetProgress(c.Request.Context(), c.GetInt("userID"), courseID, lessonID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// GetLessonReport возвращает просмотр видео урока всеми студентами
+func (h *VideoProgressHandler) GetLessonReport(c *gin.Context) {
+	courseID, lessonID, ok := lessonParams(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.videoProgressUseCase.GetLessonReport(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID)
+	if err != nil {
+		c.JSON(statusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"students": report})
+}
@@ -1,6 +1,7 @@
 package middlewares
 
 import (
+	"errors"
 	_"log"
 	"net/http"
 	"strings"
@@ -91,8 +92,8 @@ func EnrollmentMiddleware(enrollmentUseCase *usecase.EnrollmentUseCase) gin.Hand
 			return
 		}
 		
-		// Проверяем, зачислен ли пользователь на курс
-		enrolled, err := enrollmentUseCase.IsUserEnrolled(ctx, userID, courseID)
+		// Проверяем, зачислен ли пользователь на курс (или входит в команду курса, например ассистент)
+		enrolled, err := enrollmentUseCase.HasCourseAccess(ctx, userID, courseID)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			c.Abort()
@@ -109,6 +110,38 @@ func EnrollmentMiddleware(enrollmentUseCase *usecase.EnrollmentUseCase) gin.Hand
 	}
 }
 
+// LessonReleaseMiddleware закрывает содержимое уроков, которые ещё не открылись студенту по расписанию.
+// Ставится после EnrollmentMiddleware на маршруты с :lesson_id.
+func LessonReleaseMiddleware(lessonReleaseUseCase *usecase.LessonReleaseUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		courseID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+			c.Abort()
+			return
+		}
+		lessonID, err := strconv.Atoi(c.Param("lesson_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lesson ID"})
+			c.Abort()
+			return
+		}
+
+		err = lessonReleaseUseCase.CheckLessonReleased(c.Request.Context(), c.GetInt("userID"), c.GetString("userRole"), courseID, lessonID)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, usecase.ErrPermissionDenied) {
+				status = http.StatusForbidden
+			}
+			c.JSON(status, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 // func EnrollmentByLessonMiddleware(
 //     lessonUseCase	*usecase.LessonUseCase,
 //     enrollmentUseCase *usecase.EnrollmentUseCase,
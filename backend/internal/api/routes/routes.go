@@ -9,16 +9,36 @@ import (
 	"gitlab.com/w0ikid/study-platform/internal/app/config"
 )
 
-func SetupRoutes(r *gin.Engine, userUseCase *usecase.UserUseCase, courseUseCase *usecase.CourseUseCase, lessonUseCase *usecase.LessonUseCase ,enrollment *usecase.EnrollmentUseCase , lessonProgressUseCase *usecase.LessonProgressUseCase , certificateUseCase *usecase.CertificateUseCase, cfg *config.Config) {
+func SetupRoutes(r *gin.Engine, userUseCase *usecase.UserUseCase, courseUseCase *usecase.CourseUseCase, lessonUseCase *usecase.LessonUseCase ,enrollment *usecase.EnrollmentUseCase , lessonProgressUseCase *usecase.LessonProgressUseCase , certificateUseCase *usecase.CertificateUseCase, groupUseCase *usecase.GroupUseCase, userImportUseCase *usecase.UserImportUseCase, categoryUseCase *usecase.CategoryUseCase, coursePackageUseCase *usecase.CoursePackageUseCase, moduleUseCase *usecase.ModuleUseCase, reviewUseCase *usecase.ReviewUseCase, paymentUseCase *usecase.PaymentUseCase, courseVersionUseCase *usecase.CourseVersionUseCase, announcementUseCase *usecase.AnnouncementUseCase, discussionUseCase *usecase.DiscussionUseCase, mediaUseCase *usecase.MediaUseCase, recommendationUseCase *usecase.RecommendationUseCase, analyticsUseCase *usecase.AnalyticsUseCase, lessonBlockUseCase *usecase.LessonBlockUseCase, lessonAttachmentUseCase *usecase.LessonAttachmentUseCase, videoProgressUseCase *usecase.VideoProgressUseCase, lessonReleaseUseCase *usecase.LessonReleaseUseCase, lessonRevisionUseCase *usecase.LessonRevisionUseCase, assignmentUseCase *usecase.AssignmentUseCase, cfg *config.Config) {
 	userHandler := handlers.NewUserHandler(userUseCase)
 	courseHandler := handlers.NewCourseHandler(courseUseCase)
 	enrollmentHandler := handlers.NewEnrollmentHandler(enrollment)
+	coursePackageHandler := handlers.NewCoursePackageHandler(coursePackageUseCase)
 	lessonHandler := handlers.NewLessonHandler(lessonUseCase)
 	lessonProgressHandler := handlers.NewLessonProgressHandler(lessonProgressUseCase)
+	moduleHandler := handlers.NewModuleHandler(moduleUseCase)
+	reviewHandler := handlers.NewReviewHandler(reviewUseCase)
+	paymentHandler := handlers.NewPaymentHandler(paymentUseCase)
+	courseVersionHandler := handlers.NewCourseVersionHandler(courseVersionUseCase)
+	announcementHandler := handlers.NewAnnouncementHandler(announcementUseCase)
+	discussionHandler := handlers.NewDiscussionHandler(discussionUseCase)
+	mediaHandler := handlers.NewMediaHandler(mediaUseCase)
+	lessonAttachmentHandler := handlers.NewLessonAttachmentHandler(lessonAttachmentUseCase)
+	videoProgressHandler := handlers.NewVideoProgressHandler(videoProgressUseCase)
+	recommendationHandler := handlers.NewRecommendationHandler(recommendationUseCase)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsUseCase)
+	lessonBlockHandler := handlers.NewLessonBlockHandler(lessonBlockUseCase)
+	lessonReleaseHandler := handlers.NewLessonReleaseHandler(lessonReleaseUseCase)
+	lessonRevisionHandler := handlers.NewLessonRevisionHandler(lessonRevisionUseCase)
+	assignmentHandler := handlers.NewAssignmentHandler(assignmentUseCase)
 	certificateHandler := handlers.NewCertificateHandler(certificateUseCase)
+	groupHandler := handlers.NewGroupHandler(groupUseCase)
+	userImportHandler := handlers.NewUserImportHandler(userImportUseCase)
+	categoryHandler := handlers.NewCategoryHandler(categoryUseCase)
 	// Middlewares
 	authMiddleware := middlewares.AuthMiddleware(cfg.JWT)
 	enrollmentMiddleware := middlewares.EnrollmentMiddleware(enrollment)
+	releaseMiddleware := middlewares.LessonReleaseMiddleware(lessonReleaseUseCase)
 	// enrollmentByLesson := middlewares.EnrollmentByLessonMiddleware(lessonUseCase, enrollment)
 	api := r.Group("/api")
 	{	
@@ -35,6 +55,7 @@ func SetupRoutes(r *gin.Engine, userUseCase *usecase.UserUseCase, courseUseCase
 			users.GET("/:username", authMiddleware, userHandler.GetUserByUsername)
 			users.DELETE("/:id", authMiddleware, middlewares.RoleMiddleware("admin"), userHandler.DeleteUser)
 			users.GET("/", authMiddleware, userHandler.SearchUsers)	
+			users.POST("/import", authMiddleware, middlewares.RoleMiddleware("admin"), userImportHandler.ImportUsers)
 		}
 		// Courses
 		courses := api.Group("/courses")
@@ -42,19 +63,140 @@ func SetupRoutes(r *gin.Engine, userUseCase *usecase.UserUseCase, courseUseCase
 			courses.POST("/", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), courseHandler.CreateCourse)
 			courses.GET("/:id", authMiddleware, courseHandler.GetCourse)
 			courses.GET("/", authMiddleware, courseHandler.GetAllCourses)
+			courses.GET("/recommended", authMiddleware, recommendationHandler.GetRecommendations)
 			
 
 			// enrollments
 			courses.POST("/:id/enroll", authMiddleware, enrollmentHandler.CreateEnrollment)
+			courses.POST("/:id/enrollments", authMiddleware, middlewares.RoleMiddleware("admin"), enrollmentHandler.AdminEnroll)
+			courses.DELETE("/:id/enroll", authMiddleware, enrollmentHandler.DropCourse)
+			// scheduling and waitlist
+			courses.PUT("/:id/schedule", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), enrollmentHandler.UpdateSchedule)
+			courses.GET("/:id/waitlist", authMiddleware, enrollmentHandler.GetWaitlist)
+			// pricing and purchase
+			courses.PUT("/:id/price", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), paymentHandler.SetPrice)
+			courses.POST("/:id/orders", authMiddleware, paymentHandler.CreateOrder)
+			courses.GET("/:id/coupons", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), paymentHandler.GetCourseCoupons)
+			courses.DELETE("/:id/waitlist", authMiddleware, enrollmentHandler.LeaveWaitlist)
+			// prerequisites
+			courses.GET("/:id/prerequisites", authMiddleware, courseHandler.GetPrerequisites)
+			courses.PUT("/:id/prerequisites", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), courseHandler.SetPrerequisites)
 			// courses.GET("/:course_id/enrollments", authMiddleware, enrollmentHandler.GetEnrollmentsByCourse)
 			
-			// courses.PUT("/:id", courseHandler.UpdateCourse)
+			courses.PUT("/:id", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), courseHandler.UpdateCourse)
+			// tags
+			courses.POST("/:id/tags", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), courseHandler.AddTags)
+			courses.DELETE("/:id/tags/:tag", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), courseHandler.RemoveTag)
 			courses.DELETE("/:id", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), courseHandler.Delete)
+			// course staff
+			courses.GET("/:id/staff", authMiddleware, courseHandler.GetStaff)
+			courses.POST("/:id/staff", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), courseHandler.AddStaff)
+			courses.DELETE("/:id/staff/:user_id", authMiddleware, courseHandler.RemoveStaff)
+			courses.POST("/:id/transfer", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), courseHandler.TransferOwnership)
+			courses.GET("/:id/students", authMiddleware, courseHandler.GetRoster)
+			courses.GET("/:id/analytics", authMiddleware, analyticsHandler.GetCourseAnalytics)
+			courses.POST("/:id/clone", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), courseHandler.CloneCourse)
+			// export / import
+			courses.GET("/:id/export", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), coursePackageHandler.ExportCourse)
+			courses.POST("/import", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), coursePackageHandler.ImportCourse)
+
+			// reviews
+			courses.GET("/:id/reviews", authMiddleware, reviewHandler.GetCourseReviews)
+			courses.POST("/:id/reviews", authMiddleware, reviewHandler.CreateReview)
+			courses.PUT("/:id/reviews/:review_id", authMiddleware, reviewHandler.UpdateReview)
+			courses.DELETE("/:id/reviews/:review_id", authMiddleware, reviewHandler.DeleteReview)
+			courses.POST("/:id/reviews/:review_id/reply", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), reviewHandler.ReplyToReview)
+			courses.POST("/:id/reviews/:review_id/report", authMiddleware, reviewHandler.ReportReview)
+			courses.PUT("/:id/reviews/:review_id/status", authMiddleware, middlewares.RoleMiddleware("admin"), reviewHandler.SetReviewStatus)
 
 			// lessons
 			courses.POST("/:id/lessons", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), lessonHandler.CreateLesson)
 			courses.GET("/:id/lessons", authMiddleware, enrollmentMiddleware, lessonHandler.GetLessonsByCourse)
+			courses.GET("/:id/lessons/deleted", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), lessonHandler.GetDeletedLessons)
+			courses.GET("/:id/lessons/:lesson_id", authMiddleware, enrollmentMiddleware, lessonHandler.GetLesson)
+			courses.PUT("/:id/lessons/:lesson_id", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), lessonHandler.UpdateLesson)
+			courses.PATCH("/:id/lessons/:lesson_id", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), lessonHandler.PatchLesson)
+			courses.DELETE("/:id/lessons/:lesson_id", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), lessonHandler.DeleteLesson)
+			courses.POST("/:id/lessons/:lesson_id/restore", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), lessonHandler.RestoreLesson)
 			courses.POST("/:id/lessons/:lesson_id/complete", authMiddleware, enrollmentMiddleware, lessonProgressHandler.CompleteLesson)
+			courses.POST("/:id/lessons/:lesson_id/move", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), moduleHandler.MoveLesson)
+			// lesson content blocks
+			courses.GET("/:id/lessons/:lesson_id/blocks", authMiddleware, enrollmentMiddleware, releaseMiddleware, lessonBlockHandler.GetBlocks)
+			courses.POST("/:id/lessons/:lesson_id/blocks", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), lessonBlockHandler.CreateBlock)
+			courses.PUT("/:id/lessons/:lesson_id/blocks/order", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), lessonBlockHandler.ReorderBlocks)
+			courses.PUT("/:id/lessons/:lesson_id/blocks/:block_id", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), lessonBlockHandler.UpdateBlock)
+			courses.DELETE("/:id/lessons/:lesson_id/blocks/:block_id", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), lessonBlockHandler.DeleteBlock)
+			courses.POST("/:id/lessons/:lesson_id/blocks/:block_id/complete", authMiddleware, enrollmentMiddleware, releaseMiddleware, lessonBlockHandler.CompleteBlock)
+
+			// lesson file attachments
+			courses.GET("/:id/lessons/:lesson_id/attachments", authMiddleware, enrollmentMiddleware, releaseMiddleware, lessonAttachmentHandler.GetAttachments)
+			courses.POST("/:id/lessons/:lesson_id/attachments", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), lessonAttachmentHandler.UploadAttachment)
+			courses.PUT("/:id/lessons/:lesson_id/attachments/:attachment_id", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), lessonAttachmentHandler.UpdateAttachment)
+			courses.DELETE("/:id/lessons/:lesson_id/attachments/:attachment_id", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), lessonAttachmentHandler.DeleteAttachment)
+			courses.GET("/:id/lessons/:lesson_id/attachments/:attachment_id/download", authMiddleware, enrollmentMiddleware, releaseMiddleware, lessonAttachmentHandler.DownloadAttachment)
+
+			// lesson video watch progress
+			courses.POST("/:id/lessons/:lesson_id/video/heartbeat", authMiddleware, enrollmentMiddleware, releaseMiddleware, videoProgressHandler.Heartbeat)
+			courses.GET("/:id/lessons/:lesson_id/video/progress", authMiddleware, enrollmentMiddleware, releaseMiddleware, videoProgressHandler.GetProgress)
+			courses.GET("/:id/lessons/:lesson_id/video/report", authMiddleware, videoProgressHandler.GetLessonReport)
+
+			// lesson drip release schedule
+			courses.GET("/:id/release-rules", authMiddleware, lessonReleaseHandler.GetRules)
+			courses.PUT("/:id/lessons/:lesson_id/release", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), lessonReleaseHandler.SetRule)
+			courses.DELETE("/:id/lessons/:lesson_id/release", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), lessonReleaseHandler.DeleteRule)
+
+			// lesson revision history
+			courses.GET("/:id/lessons/:lesson_id/revisions", authMiddleware, lessonRevisionHandler.ListRevisions)
+			courses.GET("/:id/lessons/:lesson_id/revisions/diff", authMiddleware, lessonRevisionHandler.DiffRevisions)
+			courses.GET("/:id/lessons/:lesson_id/revisions/:number", authMiddleware, lessonRevisionHandler.GetRevision)
+			courses.POST("/:id/lessons/:lesson_id/revisions/:number/restore", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), lessonRevisionHandler.RestoreRevision)
+
+			// lesson assignments, submissions and grading
+			courses.GET("/:id/assignments/queue", authMiddleware, assignmentHandler.GradingQueue)
+			courses.GET("/:id/lessons/:lesson_id/assignment", authMiddleware, enrollmentMiddleware, releaseMiddleware, assignmentHandler.GetAssignment)
+			courses.PUT("/:id/lessons/:lesson_id/assignment", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), assignmentHandler.SetAssignment)
+			courses.DELETE("/:id/lessons/:lesson_id/assignment", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), assignmentHandler.DeleteAssignment)
+			courses.POST("/:id/lessons/:lesson_id/assignment/submissions", authMiddleware, enrollmentMiddleware, releaseMiddleware, assignmentHandler.Submit)
+			courses.GET("/:id/lessons/:lesson_id/assignment/submissions", authMiddleware, enrollmentMiddleware, releaseMiddleware, assignmentHandler.ListSubmissions)
+			courses.GET("/:id/lessons/:lesson_id/assignment/submissions/:submission_id/download", authMiddleware, enrollmentMiddleware, assignmentHandler.DownloadSubmission)
+			courses.POST("/:id/lessons/:lesson_id/assignment/submissions/:submission_id/grade", authMiddleware, assignmentHandler.GradeSubmission)
+
+			// modules
+			courses.GET("/:id/modules", authMiddleware, enrollmentMiddleware, moduleHandler.GetModules)
+			courses.POST("/:id/modules", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), moduleHandler.CreateModule)
+			courses.PUT("/:id/modules/:module_id", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), moduleHandler.UpdateModule)
+			courses.DELETE("/:id/modules/:module_id", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), moduleHandler.DeleteModule)
+			// versions
+			courses.POST("/:id/versions", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), courseVersionHandler.PublishVersion)
+			courses.GET("/:id/versions", authMiddleware, courseVersionHandler.GetVersions)
+			courses.GET("/:id/versions/:version_id", authMiddleware, courseVersionHandler.GetVersion)
+			courses.GET("/:id/version", authMiddleware, enrollmentMiddleware, courseVersionHandler.GetStudentVersion)
+			courses.POST("/:id/version/upgrade", authMiddleware, enrollmentMiddleware, courseVersionHandler.UpgradeVersion)
+			// announcements
+			courses.GET("/:id/announcements", authMiddleware, enrollmentMiddleware, announcementHandler.GetCourseAnnouncements)
+			courses.POST("/:id/announcements", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), announcementHandler.CreateAnnouncement)
+			courses.GET("/:id/announcements/:announcement_id", authMiddleware, enrollmentMiddleware, announcementHandler.GetAnnouncement)
+			courses.PUT("/:id/announcements/:announcement_id", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), announcementHandler.UpdateAnnouncement)
+			courses.DELETE("/:id/announcements/:announcement_id", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), announcementHandler.DeleteAnnouncement)
+			courses.POST("/:id/announcements/:announcement_id/read", authMiddleware, enrollmentMiddleware, announcementHandler.MarkRead)
+			courses.GET("/:id/announcements/:announcement_id/reads", authMiddleware, announcementHandler.GetReads)
+			courses.PUT("/:id/image", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), mediaHandler.SetCourseImage)
+			// discussions
+			courses.GET("/:id/discussions", authMiddleware, enrollmentMiddleware, discussionHandler.GetThreads)
+			courses.POST("/:id/discussions", authMiddleware, enrollmentMiddleware, discussionHandler.CreateThread)
+			courses.GET("/:id/discussions/:thread_id", authMiddleware, enrollmentMiddleware, discussionHandler.GetThread)
+			courses.PUT("/:id/discussions/:thread_id", authMiddleware, enrollmentMiddleware, discussionHandler.UpdateThread)
+			courses.DELETE("/:id/discussions/:thread_id", authMiddleware, enrollmentMiddleware, discussionHandler.DeleteThread)
+			courses.GET("/:id/discussions/:thread_id/history", authMiddleware, enrollmentMiddleware, discussionHandler.GetThreadHistory)
+			courses.PUT("/:id/discussions/:thread_id/accepted", authMiddleware, enrollmentMiddleware, discussionHandler.SetAcceptedAnswer)
+			courses.POST("/:id/discussions/:thread_id/upvote", authMiddleware, enrollmentMiddleware, discussionHandler.UpvoteThread)
+			courses.DELETE("/:id/discussions/:thread_id/upvote", authMiddleware, enrollmentMiddleware, discussionHandler.RemoveThreadUpvote)
+			courses.POST("/:id/discussions/:thread_id/posts", authMiddleware, enrollmentMiddleware, discussionHandler.CreatePost)
+			courses.PUT("/:id/discussions/:thread_id/posts/:post_id", authMiddleware, enrollmentMiddleware, discussionHandler.UpdatePost)
+			courses.DELETE("/:id/discussions/:thread_id/posts/:post_id", authMiddleware, enrollmentMiddleware, discussionHandler.DeletePost)
+			courses.GET("/:id/discussions/:thread_id/posts/:post_id/history", authMiddleware, enrollmentMiddleware, discussionHandler.GetPostHistory)
+			courses.POST("/:id/discussions/:thread_id/posts/:post_id/upvote", authMiddleware, enrollmentMiddleware, discussionHandler.UpvotePost)
+			courses.DELETE("/:id/discussions/:thread_id/posts/:post_id/upvote", authMiddleware, enrollmentMiddleware, discussionHandler.RemovePostUpvote)
 			// lesson progress
 			courses.GET("/:id/progress", authMiddleware, enrollmentMiddleware, lessonProgressHandler.GetCourseProgress)
 
@@ -69,10 +211,71 @@ func SetupRoutes(r *gin.Engine, userUseCase *usecase.UserUseCase, courseUseCase
 			enrollment.GET("/", authMiddleware, enrollmentHandler.GetAllEnrollment)
 			enrollment.DELETE("/:id", authMiddleware, middlewares.RoleMiddleware("admin"), enrollmentHandler.Delete)
 		}
+		// Orders and payments
+		orders := api.Group("/orders")
+		{
+			orders.GET("/", authMiddleware, paymentHandler.GetMyOrders)
+			orders.GET("/:id", authMiddleware, paymentHandler.GetOrder)
+			orders.GET("/:id/invoice", authMiddleware, paymentHandler.GetInvoice)
+			orders.POST("/:id/refund", authMiddleware, middlewares.RoleMiddleware("admin"), paymentHandler.RefundOrder)
+		}
+		coupons := api.Group("/coupons")
+		{
+			coupons.GET("/", authMiddleware, middlewares.RoleMiddleware("admin"), paymentHandler.GetCoupons)
+			coupons.POST("/", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), paymentHandler.CreateCoupon)
+			coupons.DELETE("/:id", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), paymentHandler.DeleteCoupon)
+		}
+		// вебхук платёжного провайдера: без JWT, подлинность проверяется подписью
+		api.POST("/payments/webhook", paymentHandler.HandleWebhook)
 		certificates := api.Group("/certificates")
 		{
 			certificates.GET("/course/:course_id", authMiddleware, certificateHandler.GenerateCertificate)
 		}
+		// Categories
+		categories := api.Group("/categories")
+		{
+			categories.GET("/", authMiddleware, categoryHandler.GetCategories)
+			categories.GET("/:id", authMiddleware, categoryHandler.GetCategory)
+			categories.POST("/", authMiddleware, middlewares.RoleMiddleware("admin"), categoryHandler.CreateCategory)
+			categories.PUT("/:id", authMiddleware, middlewares.RoleMiddleware("admin"), categoryHandler.UpdateCategory)
+			categories.DELETE("/:id", authMiddleware, middlewares.RoleMiddleware("admin"), categoryHandler.DeleteCategory)
+		}
+		api.GET("/tags", authMiddleware, courseHandler.GetTags)
+		api.GET("/announcements", authMiddleware, announcementHandler.GetFeed)
+		api.GET("/lesson-blocks/schemas", authMiddleware, lessonBlockHandler.GetSchemas)
+
+		media := api.Group("/media")
+		{
+			media.POST("", authMiddleware, mediaHandler.Upload)
+			media.GET("", authMiddleware, mediaHandler.GetMyFiles)
+			media.GET("/:id", authMiddleware, mediaHandler.GetFile)
+			media.DELETE("/:id", authMiddleware, mediaHandler.DeleteFile)
+			// без авторизации: публичные файлы и подписанные ссылки локального хранилища
+			media.GET("/:id/content", mediaHandler.GetPublicContent)
+			media.GET("/download/*key", mediaHandler.Download)
+		}
+		// moderation queue
+		api.GET("/reviews/reported", authMiddleware, middlewares.RoleMiddleware("admin"), reviewHandler.GetReportedReviews)
+		// Groups / cohorts
+		groups := api.Group("/groups")
+		{
+			groups.POST("/", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), groupHandler.CreateGroup)
+			groups.GET("/", authMiddleware, groupHandler.GetGroups)
+			groups.GET("/:id", authMiddleware, groupHandler.GetGroup)
+			groups.PUT("/:id", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), groupHandler.UpdateGroup)
+			groups.DELETE("/:id", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), groupHandler.DeleteGroup)
+
+			// members
+			groups.GET("/:id/members", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), groupHandler.GetMembers)
+			groups.POST("/:id/members", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), groupHandler.AddMember)
+			groups.DELETE("/:id/members/:user_id", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), groupHandler.RemoveMember)
+
+			// bulk enrollment and reports
+			groups.GET("/:id/courses", authMiddleware, groupHandler.GetCourses)
+			groups.POST("/:id/courses", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), groupHandler.EnrollGroup)
+			groups.DELETE("/:id/courses/:course_id", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), groupHandler.RemoveCourse)
+			groups.GET("/:id/courses/:course_id/progress", authMiddleware, middlewares.RoleMiddleware("admin", "teacher"), groupHandler.GetProgressReport)
+		}
 		// lessons := api.Group("lessons")
 		// {
 		// 	lessons.POST("/:id/complete", authMiddleware, enrollmentByLesson, lessonProgressHandler.CompleteLesson)
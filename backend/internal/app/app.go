@@ -3,15 +3,20 @@ package app
 import (
 	"context"
 	"fmt"
+	"log"
+	"time"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"gitlab.com/w0ikid/study-platform/internal/app/config"
 	"gitlab.com/w0ikid/study-platform/internal/app/connections"
 	"gitlab.com/w0ikid/study-platform/internal/app/start"
-	_ "gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
 	"gitlab.com/w0ikid/study-platform/internal/domain/repositories"
 	"gitlab.com/w0ikid/study-platform/internal/domain/services"
 	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/pkg/mailer"
+	"gitlab.com/w0ikid/study-platform/pkg/payment"
+	"gitlab.com/w0ikid/study-platform/pkg/storage"
 )
 
 func Run(configFile string) error {
@@ -35,6 +40,41 @@ func Run(configFile string) error {
 
 	fmt.Printf("%s - DBname\n", cfg.DB.DBName)
 
+	// Почта: без SMTP_HOST письма пишутся в лог
+	var mail mailer.Mailer = mailer.NewLogMailer()
+	if cfg.SMTP.Host != "" {
+		mail = mailer.NewSMTPMailer(cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.Username, cfg.SMTP.Password, cfg.SMTP.From)
+	}
+
+	// Платёжный провайдер: local подтверждает оплату подписанным вебхуком без реальных списаний
+	var paymentProvider payment.PaymentProvider
+	switch cfg.Payment.Provider {
+	case "local":
+		paymentProvider = payment.NewLocalProvider(cfg.Payment.WebhookSecret, cfg.AppURL)
+	default:
+		return fmt.Errorf("unknown payment provider %q", cfg.Payment.Provider)
+	}
+
+	var blobStore storage.BlobStore
+	switch cfg.Storage.Driver {
+	case "local":
+		blobStore, err = storage.NewLocalStore(cfg.Storage.LocalDir, cfg.Storage.PublicURL, cfg.Storage.SigningSecret)
+	case "s3":
+		blobStore, err = storage.NewS3Store(storage.S3Config{
+			Endpoint:  cfg.Storage.S3Endpoint,
+			Region:    cfg.Storage.S3Region,
+			Bucket:    cfg.Storage.S3Bucket,
+			AccessKey: cfg.Storage.S3AccessKey,
+			SecretKey: cfg.Storage.S3SecretKey,
+			PathStyle: cfg.Storage.S3PathStyle,
+		})
+	default:
+		return fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+	}
+	if err != nil {
+		return err
+	}
+
 	// Инициализация репозиториев
 	userRepo := repositories.NewUserRepository(conn.DB)
 	courseRepo := repositories.NewCourseRepository(conn.DB)
@@ -42,6 +82,24 @@ func Run(configFile string) error {
 	enrollmentRepo := repositories.NewEnrollmentRepository(conn.DB)
 	lessonRepo := repositories.NewLessonRepository(conn.DB)
 	lessonProgressRepo := repositories.NewLessonProgressRepository(conn.DB)
+	groupRepo := repositories.NewGroupRepository(conn.DB)
+	categoryRepo := repositories.NewCategoryRepository(conn.DB)
+	moduleRepo := repositories.NewModuleRepository(conn.DB)
+	reviewRepo := repositories.NewReviewRepository(conn.DB)
+	orderRepo := repositories.NewOrderRepository(conn.DB)
+	couponRepo := repositories.NewCouponRepository(conn.DB)
+	courseVersionRepo := repositories.NewCourseVersionRepository(conn.DB)
+	announcementRepo := repositories.NewAnnouncementRepository(conn.DB)
+	discussionRepo := repositories.NewDiscussionRepository(conn.DB)
+	mediaRepo := repositories.NewMediaRepository(conn.DB)
+	recommendationRepo := repositories.NewRecommendationRepository(conn.DB)
+	analyticsRepo := repositories.NewAnalyticsRepository(conn.DB)
+	lessonBlockRepo := repositories.NewLessonBlockRepository(conn.DB)
+	lessonAttachmentRepo := repositories.NewLessonAttachmentRepository(conn.DB)
+	videoProgressRepo := repositories.NewVideoProgressRepository(conn.DB)
+	lessonReleaseRepo := repositories.NewLessonReleaseRepository(conn.DB)
+	lessonRevisionRepo := repositories.NewLessonRevisionRepository(conn.DB)
+	assignmentRepo := repositories.NewAssignmentRepository(conn.DB)
 	// Инициализация сервисов
 	userService := services.NewUserService(userRepo)
 	courseService := services.NewCourseService(courseRepo)
@@ -49,21 +107,86 @@ func Run(configFile string) error {
 	enrollmentService := services.NewEnrollmentService(enrollmentRepo)
 	lessonService := services.NewLessonService(lessonRepo)
 	lessonProgressService := services.NewLessonProgressService(lessonProgressRepo)
+	groupService := services.NewGroupService(groupRepo)
+	categoryService := services.NewCategoryService(categoryRepo)
+	moduleService := services.NewModuleService(moduleRepo)
+	reviewService := services.NewReviewService(reviewRepo)
+	orderService := services.NewOrderService(orderRepo)
+	couponService := services.NewCouponService(couponRepo)
+	courseVersionService := services.NewCourseVersionService(courseVersionRepo)
+	announcementService := services.NewAnnouncementService(announcementRepo)
+	discussionService := services.NewDiscussionService(discussionRepo)
+	mediaService := services.NewMediaService(mediaRepo)
+	recommendationService := services.NewRecommendationService(recommendationRepo)
+	analyticsService := services.NewAnalyticsService(analyticsRepo)
+	lessonBlockService := services.NewLessonBlockService(lessonBlockRepo)
+	lessonAttachmentService := services.NewLessonAttachmentService(lessonAttachmentRepo)
+	videoProgressService := services.NewVideoProgressService(videoProgressRepo)
+	lessonReleaseService := services.NewLessonReleaseService(lessonReleaseRepo)
+	lessonRevisionService := services.NewLessonRevisionService(lessonRevisionRepo)
+	assignmentService := services.NewAssignmentService(assignmentRepo)
 	// Инициализация usecase
 	userUseCase := usecase.NewUserUseCase(userService, cfg)
-	courseUseCase := usecase.NewCourseUseCase(courseService)
-	enrollmentUseCase := usecase.NewEnrollmentUseCase(enrollmentService, courseService)
-	lessonUseCase := usecase.NewLessonUseCase(lessonService, enrollmentService, courseService)
-	lessonProgressUseCase := usecase.NewLessonProgressUseCase(lessonProgressService, lessonService, enrollmentService, courseService, userService)
+	courseUseCase := usecase.NewCourseUseCase(courseService, categoryService, userService)
+	enrollmentUseCase := usecase.NewEnrollmentUseCase(enrollmentService, courseService, userService, mail, cfg.AppURL)
+	lessonReleaseUseCase := usecase.NewLessonReleaseUseCase(lessonReleaseService, lessonService, courseService, enrollmentService, lessonProgressService, mail, cfg.AppURL)
+	lessonProgressUseCase := usecase.NewLessonProgressUseCase(lessonProgressService, lessonService, enrollmentService, courseService, userService, moduleService, courseVersionService)
+	lessonProgressUseCase.OnModuleCompleted(func(ctx context.Context, event models.ModuleCompletedEvent) {
+		log.Printf("user %d completed module %d of course %d", event.UserID, event.ModuleID, event.CourseID)
+	})
+	lessonUseCase := usecase.NewLessonUseCase(lessonService, enrollmentService, courseService, moduleService, courseVersionService, videoProgressService, lessonReleaseUseCase, lessonRevisionService, lessonProgressUseCase)
+	lessonRevisionUseCase := usecase.NewLessonRevisionUseCase(lessonRevisionService, lessonService, courseService)
+	// HTML для уроков, созданных до перехода на markdown
+	rendered, err := lessonUseCase.RenderStoredContent(context.Background())
+	if err != nil {
+		return err
+	}
+	if rendered > 0 {
+		log.Printf("rendered markdown for %d lessons", rendered)
+	}
+	videoProgressUseCase := usecase.NewVideoProgressUseCase(videoProgressService, lessonService, courseService, lessonProgressUseCase, cfg.VideoCompletionPercent)
+	lessonProgressUseCase.AddCompletionRequirement(lessonReleaseUseCase.CheckReleased)
+	lessonProgressUseCase.AddCompletionRequirement(videoProgressUseCase.CheckWatched)
 	certificateUseCase := usecase.NewCertificateUseCase(certificateService, enrollmentService, userService, courseService)
+	groupUseCase := usecase.NewGroupUseCase(groupService, userService, courseService, lessonService, enrollmentService, enrollmentUseCase)
+	userImportUseCase := usecase.NewUserImportUseCase(userService, courseService, groupService, enrollmentUseCase, mail, cfg.AppURL)
+	categoryUseCase := usecase.NewCategoryUseCase(categoryService)
+	moduleUseCase := usecase.NewModuleUseCase(moduleService, lessonService, courseService, courseVersionService)
+	reviewUseCase := usecase.NewReviewUseCase(reviewService, courseService, enrollmentService, lessonService, lessonProgressService, cfg.ReviewMinProgress)
+	paymentUseCase := usecase.NewPaymentUseCase(orderService, couponService, courseService, userService, enrollmentUseCase, paymentProvider)
+	courseVersionUseCase := usecase.NewCourseVersionUseCase(courseVersionService, courseService, moduleService, lessonService)
+	announcementUseCase := usecase.NewAnnouncementUseCase(announcementService, courseService, enrollmentService, userService, mail, cfg.AppURL)
+	discussionUseCase := usecase.NewDiscussionUseCase(discussionService, courseService, lessonService, userService, enrollmentUseCase, mail, cfg.AppURL)
+	mediaUseCase := usecase.NewMediaUseCase(mediaService, courseService, enrollmentUseCase, blobStore, usecase.MediaLimits{
+		MaxUploadBytes: int64(cfg.Storage.MaxUploadMB) << 20,
+		QuotaBytes:     int64(cfg.Storage.QuotaMB) << 20,
+		URLTTL:         time.Duration(cfg.Storage.URLTTLMinutes) * time.Minute,
+		PublicURL:      cfg.Storage.PublicURL,
+	})
+	recommendationUseCase := usecase.NewRecommendationUseCase(recommendationService, courseService, enrollmentService, userService)
+	analyticsUseCase := usecase.NewAnalyticsUseCase(analyticsService, courseService, time.Duration(cfg.AnalyticsCacheMinutes)*time.Minute)
+	lessonBlockUseCase := usecase.NewLessonBlockUseCase(lessonBlockService, lessonService, courseService, mediaService, lessonProgressUseCase)
+	lessonProgressUseCase.AddCompletionRequirement(lessonBlockUseCase.CheckBlocksCompleted)
+	lessonAttachmentUseCase := usecase.NewLessonAttachmentUseCase(lessonAttachmentService, lessonService, courseService, userService, mediaService, mediaUseCase, blobStore)
+	coursePackageUseCase := usecase.NewCoursePackageUseCase(courseService, lessonService, categoryService, moduleService, lessonBlockService, lessonAttachmentService, lessonAttachmentUseCase, lessonReleaseService, assignmentService, blobStore)
+	assignmentUseCase := usecase.NewAssignmentUseCase(assignmentService, lessonService, courseService, mediaUseCase, blobStore)
+	lessonProgressUseCase.AddCompletionRequirement(assignmentUseCase.CheckPassed)
+
+	// Фоновые задачи останавливаются после остановки HTTP сервера
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go announcementUseCase.RunEmailDispatcher(jobsCtx, time.Minute)
+	go recommendationUseCase.RunSimilarityJob(jobsCtx, time.Duration(cfg.RecommendationsIntervalMinutes)*time.Minute)
+	go lessonReleaseUseCase.RunUnlockNotifier(jobsCtx, 24*time.Hour)
+
 	// Запуск HTTP сервера
-	start.HTTP(cfg, userUseCase, courseUseCase, lessonUseCase, enrollmentUseCase, lessonProgressUseCase, certificateUseCase)
+	start.HTTP(cfg, userUseCase, courseUseCase, lessonUseCase, enrollmentUseCase, lessonProgressUseCase, certificateUseCase, groupUseCase, userImportUseCase, categoryUseCase, coursePackageUseCase, moduleUseCase, reviewUseCase, paymentUseCase, courseVersionUseCase, announcementUseCase, discussionUseCase, mediaUseCase, recommendationUseCase, analyticsUseCase, lessonBlockUseCase, lessonAttachmentUseCase, videoProgressUseCase, lessonReleaseUseCase, lessonRevisionUseCase, assignmentUseCase)
 
 	return nil
 }
 
 // autoMigrate выполняет автоматическую миграцию для всех моделей
-func autoMigrate(conn *pgx.Conn) error {
+func autoMigrate(conn *pgxpool.Pool) error {
 	ctx := context.Background()
 	fmt.Println("Starting auto migration...")
 	queries := []string{
@@ -132,6 +255,410 @@ func autoMigrate(conn *pgx.Conn) error {
 			issued_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			CONSTRAINT uq_certificate_user_course UNIQUE(user_id, course_id)  -- Гарантия одного сертификата на курс
 		);`,
+		`CREATE TABLE IF NOT EXISTS study_groups (
+			id SERIAL PRIMARY KEY,
+			name TEXT NOT NULL,
+			description TEXT NOT NULL DEFAULT '',
+			created_by INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS group_members (
+			group_id INT NOT NULL REFERENCES study_groups(id) ON DELETE CASCADE,
+			user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			role TEXT NOT NULL DEFAULT 'member', -- owner - преподаватель группы, member - студент
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (group_id, user_id)
+		);`,
+		`CREATE TABLE IF NOT EXISTS group_courses (
+			group_id INT NOT NULL REFERENCES study_groups(id) ON DELETE CASCADE,
+			course_id INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (group_id, course_id)
+		);`,
+		`CREATE TABLE IF NOT EXISTS categories (
+			id SERIAL PRIMARY KEY,
+			name TEXT NOT NULL,
+			description TEXT NOT NULL DEFAULT '',
+			parent_id INT REFERENCES categories(id) ON DELETE SET NULL, -- NULL - корневая категория
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`ALTER TABLE courses
+			ADD COLUMN IF NOT EXISTS category_id INT REFERENCES categories(id) ON DELETE SET NULL,
+			ADD COLUMN IF NOT EXISTS level INT NOT NULL DEFAULT 0, -- 0 - beginner, 1 - intermediate, 2 - advanced (как User.Level)
+			ADD COLUMN IF NOT EXISTS duration_minutes INT NOT NULL DEFAULT 0,
+			ADD COLUMN IF NOT EXISTS language TEXT NOT NULL DEFAULT '';`,
+		`CREATE TABLE IF NOT EXISTS course_tags (
+			course_id INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+			tag TEXT NOT NULL,
+			PRIMARY KEY (course_id, tag)
+		);`,
+		`CREATE INDEX IF NOT EXISTS idx_course_tags_tag ON course_tags(tag);`,
+		`ALTER TABLE courses
+			ADD COLUMN IF NOT EXISTS min_user_level INT NOT NULL DEFAULT 0; -- минимальный User.Level для записи`,
+		`CREATE TABLE IF NOT EXISTS course_prerequisites (
+			course_id INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+			prerequisite_id INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+			PRIMARY KEY (course_id, prerequisite_id),
+			CHECK (course_id <> prerequisite_id)
+		);`,
+		`CREATE TABLE IF NOT EXISTS course_staff (
+			course_id INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+			user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			role TEXT NOT NULL DEFAULT 'ta', -- 'owner', 'co_teacher' или 'ta'
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (course_id, user_id)
+		);`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_course_staff_owner ON course_staff(course_id) WHERE role = 'owner';`,
+		// владельцы существующих курсов
+		`INSERT INTO course_staff (course_id, user_id, role)
+			SELECT id, teacher_id, 'owner' FROM courses
+			ON CONFLICT (course_id, user_id) DO NOTHING;`,
+		`CREATE TABLE IF NOT EXISTS course_modules (
+			id SERIAL PRIMARY KEY,
+			course_id INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+			title TEXT NOT NULL,
+			description TEXT NOT NULL DEFAULT '',
+			position INT NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);`,
+		// уроки без module_id относятся к неявному модулю по умолчанию
+		`ALTER TABLE lessons
+			ADD COLUMN IF NOT EXISTS module_id INT REFERENCES course_modules(id) ON DELETE SET NULL,
+			ADD COLUMN IF NOT EXISTS position INT NOT NULL DEFAULT 0;`,
+		`CREATE TABLE IF NOT EXISTS module_completions (
+			user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			course_id INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+			module_id INT NOT NULL, -- 0 - модуль по умолчанию
+			completed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, course_id, module_id)
+		);`,
+		// рейтинг курса хранится на курсе и пересчитывается при изменении отзывов
+		`ALTER TABLE courses ADD COLUMN IF NOT EXISTS rating_average DOUBLE PRECISION NOT NULL DEFAULT 0;`,
+		`ALTER TABLE courses ADD COLUMN IF NOT EXISTS rating_count INT NOT NULL DEFAULT 0;`,
+		`CREATE TABLE IF NOT EXISTS course_reviews (
+			id SERIAL PRIMARY KEY,
+			course_id INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+			user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			rating INT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+			body TEXT NOT NULL DEFAULT '',
+			status VARCHAR(20) NOT NULL DEFAULT 'visible', -- visible, hidden
+			reply TEXT,
+			reply_author_id INT REFERENCES users(id) ON DELETE SET NULL,
+			replied_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (course_id, user_id)
+		);`,
+		`CREATE TABLE IF NOT EXISTS review_reports (
+			review_id INT NOT NULL REFERENCES course_reviews(id) ON DELETE CASCADE,
+			user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			reason TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (review_id, user_id)
+		);`,
+		// расписание курса, окно записи и лимит мест (NULL - без ограничения)
+		`ALTER TABLE courses ADD COLUMN IF NOT EXISTS starts_at TIMESTAMPTZ;`,
+		`ALTER TABLE courses ADD COLUMN IF NOT EXISTS ends_at TIMESTAMPTZ;`,
+		`ALTER TABLE courses ADD COLUMN IF NOT EXISTS enrollment_opens_at TIMESTAMPTZ;`,
+		`ALTER TABLE courses ADD COLUMN IF NOT EXISTS enrollment_closes_at TIMESTAMPTZ;`,
+		`ALTER TABLE courses ADD COLUMN IF NOT EXISTS capacity INT CHECK (capacity > 0);`,
+		`CREATE TABLE IF NOT EXISTS course_waitlist (
+			id SERIAL PRIMARY KEY,
+			course_id INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+			user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (course_id, user_id)
+		);`,
+		// платные курсы: цена в минимальных единицах валюты, 0 - бесплатно
+		`ALTER TABLE courses ADD COLUMN IF NOT EXISTS price_cents INT NOT NULL DEFAULT 0 CHECK (price_cents >= 0);`,
+		`ALTER TABLE courses ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'USD';`,
+		`CREATE TABLE IF NOT EXISTS coupons (
+			id SERIAL PRIMARY KEY,
+			code TEXT NOT NULL,
+			type TEXT NOT NULL CHECK (type IN ('percent', 'fixed')),
+			value INT NOT NULL CHECK (value > 0),
+			course_id INT REFERENCES courses(id) ON DELETE CASCADE,
+			expires_at TIMESTAMPTZ,
+			max_uses INT CHECK (max_uses > 0),
+			used_count INT NOT NULL DEFAULT 0,
+			created_by INT REFERENCES users(id) ON DELETE SET NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_coupons_code ON coupons (UPPER(code));`,
+		`CREATE TABLE IF NOT EXISTS orders (
+			id SERIAL PRIMARY KEY,
+			user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			course_id INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+			coupon_id INT REFERENCES coupons(id) ON DELETE SET NULL,
+			coupon_code TEXT,
+			amount_cents INT NOT NULL,
+			discount_cents INT NOT NULL DEFAULT 0,
+			total_cents INT NOT NULL,
+			currency VARCHAR(3) NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending',
+			provider TEXT NOT NULL,
+			payment_id TEXT,
+			checkout_url TEXT,
+			paid_at TIMESTAMPTZ,
+			refunded_at TIMESTAMPTZ,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (provider, payment_id)
+		);`,
+		`CREATE INDEX IF NOT EXISTS idx_orders_user ON orders (user_id);`,
+		// версии курса: неизменяемые снимки модулей и уроков, студент закреплён за версией
+		`CREATE TABLE IF NOT EXISTS course_versions (
+			id SERIAL PRIMARY KEY,
+			course_id INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+			version INT NOT NULL,
+			notes TEXT NOT NULL DEFAULT '',
+			content JSONB NOT NULL,
+			lesson_count INT NOT NULL DEFAULT 0,
+			published_by INT REFERENCES users(id) ON DELETE SET NULL,
+			published_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			UNIQUE (course_id, version)
+		);`,
+		`ALTER TABLE enrollments ADD COLUMN IF NOT EXISTS version_id INT REFERENCES course_versions(id) ON DELETE SET NULL;`,
+		// урок, удалённый из черновика, уходит в корзину и остаётся в опубликованных версиях,
+		// поэтому прогресс по нему сохраняется без снятия внешнего ключа. Ключ, снятый прежней
+		// миграцией, возвращается; прогресс по уже физически удалённым урокам убирается.
+		`DELETE FROM lesson_progress lp WHERE NOT EXISTS (SELECT 1 FROM lessons l WHERE l.id = lp.lesson_id);`,
+		`DO $$
+		BEGIN
+			IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_lesson' AND conrelid = 'lesson_progress'::regclass) THEN
+				ALTER TABLE lesson_progress ADD CONSTRAINT fk_lesson FOREIGN KEY (lesson_id) REFERENCES lessons(id) ON DELETE CASCADE;
+			END IF;
+		END $$;`,
+		// объявления курса и отметки о прочтении
+		`CREATE TABLE IF NOT EXISTS announcements (
+			id SERIAL PRIMARY KEY,
+			course_id INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+			author_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			title TEXT NOT NULL,
+			body TEXT NOT NULL,
+			pinned BOOLEAN NOT NULL DEFAULT FALSE,
+			send_email BOOLEAN NOT NULL DEFAULT FALSE,
+			publish_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			emailed_at TIMESTAMPTZ,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`CREATE INDEX IF NOT EXISTS idx_announcements_course ON announcements (course_id, publish_at);`,
+		`CREATE TABLE IF NOT EXISTS announcement_reads (
+			announcement_id INT NOT NULL REFERENCES announcements(id) ON DELETE CASCADE,
+			user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			read_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (announcement_id, user_id)
+		);`,
+		// похожесть курсов по совместным записям студентов, пересчитывается фоновой задачей
+		`CREATE TABLE IF NOT EXISTS course_similarities (
+			course_id INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+			similar_course_id INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+			score DOUBLE PRECISION NOT NULL,
+			co_enrolled INT NOT NULL,
+			computed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (course_id, similar_course_id)
+		);`,
+		// загруженные файлы; содержимое лежит в хранилище (локальный каталог или S3) по storage_key
+		`CREATE TABLE IF NOT EXISTS media_files (
+			id SERIAL PRIMARY KEY,
+			owner_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			course_id INT REFERENCES courses(id) ON DELETE SET NULL,
+			filename TEXT NOT NULL,
+			content_type TEXT NOT NULL,
+			size BIGINT NOT NULL,
+			public BOOLEAN NOT NULL DEFAULT FALSE,
+			storage_key TEXT NOT NULL UNIQUE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`CREATE INDEX IF NOT EXISTS idx_media_files_owner ON media_files (owner_id);`,
+		// обсуждения курса и уроков: темы, вложенные ответы, голоса и история правок
+		`CREATE TABLE IF NOT EXISTS discussion_threads (
+			id SERIAL PRIMARY KEY,
+			course_id INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+			lesson_id INT REFERENCES lessons(id) ON DELETE CASCADE,
+			author_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			title TEXT NOT NULL,
+			body TEXT NOT NULL,
+			accepted_post_id INT,
+			last_activity_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`CREATE INDEX IF NOT EXISTS idx_discussion_threads_course ON discussion_threads (course_id, lesson_id);`,
+		`CREATE TABLE IF NOT EXISTS discussion_posts (
+			id SERIAL PRIMARY KEY,
+			thread_id INT NOT NULL REFERENCES discussion_threads(id) ON DELETE CASCADE,
+			parent_id INT REFERENCES discussion_posts(id) ON DELETE CASCADE,
+			author_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			body TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`CREATE INDEX IF NOT EXISTS idx_discussion_posts_thread ON discussion_posts (thread_id);`,
+		`CREATE TABLE IF NOT EXISTS discussion_thread_votes (
+			thread_id INT NOT NULL REFERENCES discussion_threads(id) ON DELETE CASCADE,
+			user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			PRIMARY KEY (thread_id, user_id)
+		);`,
+		`CREATE TABLE IF NOT EXISTS discussion_post_votes (
+			post_id INT NOT NULL REFERENCES discussion_posts(id) ON DELETE CASCADE,
+			user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			PRIMARY KEY (post_id, user_id)
+		);`,
+		`CREATE TABLE IF NOT EXISTS discussion_edits (
+			id SERIAL PRIMARY KEY,
+			thread_id INT NOT NULL REFERENCES discussion_threads(id) ON DELETE CASCADE,
+			post_id INT REFERENCES discussion_posts(id) ON DELETE CASCADE,
+			editor_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			title TEXT,
+			body TEXT NOT NULL,
+			edited_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);`,
+		// аналитика курса: когорта по дате записи и завершения уроков курса
+		`CREATE INDEX IF NOT EXISTS idx_enrollments_course_created ON enrollments (course_id, created_at);`,
+		`CREATE INDEX IF NOT EXISTS idx_lesson_progress_course_completed ON lesson_progress (course_id, user_id) WHERE is_completed;`,
+		// content урока - markdown, content_html - очищенный HTML; NULL - ещё не построен (заполняется при запуске)
+		`ALTER TABLE lessons ADD COLUMN IF NOT EXISTS content_html TEXT;`,
+		// блоки урока; data проверяется JSON-схемой типа блока
+		`CREATE TABLE IF NOT EXISTS lesson_blocks (
+			id SERIAL PRIMARY KEY,
+			lesson_id INT NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+			position INT NOT NULL,
+			type VARCHAR(20) NOT NULL,
+			data JSONB NOT NULL,
+			html TEXT NOT NULL DEFAULT '',
+			required BOOLEAN NOT NULL DEFAULT TRUE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`CREATE INDEX IF NOT EXISTS idx_lesson_blocks_lesson ON lesson_blocks (lesson_id, position);`,
+		`CREATE TABLE IF NOT EXISTS lesson_block_completions (
+			block_id INT NOT NULL REFERENCES lesson_blocks(id) ON DELETE CASCADE,
+			user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			completed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (block_id, user_id)
+		);`,
+		// файлы уроков: содержимое хранится в media_files, одна запись на урок и файл
+		`CREATE TABLE IF NOT EXISTS lesson_attachments (
+			id SERIAL PRIMARY KEY,
+			lesson_id INT NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+			media_id INT NOT NULL REFERENCES media_files(id) ON DELETE CASCADE,
+			title TEXT NOT NULL,
+			watermark BOOLEAN NOT NULL DEFAULT FALSE,
+			download_count INT NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`CREATE INDEX IF NOT EXISTS idx_lesson_attachments_lesson ON lesson_attachments (lesson_id);`,
+		`CREATE INDEX IF NOT EXISTS idx_lesson_attachments_media ON lesson_attachments (media_id);`,
+		// просмотр видео уроков: позиция, объединённые просмотренные отрезки и признаки перемотки
+		`CREATE TABLE IF NOT EXISTS video_progress (
+			user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			lesson_id INT NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+			course_id INT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+			position INT NOT NULL DEFAULT 0,
+			duration INT NOT NULL DEFAULT 0,
+			intervals JSONB NOT NULL DEFAULT '[]',
+			watched_seconds INT NOT NULL DEFAULT 0,
+			skip_count INT NOT NULL DEFAULT 0,
+			skipped_seconds INT NOT NULL DEFAULT 0,
+			rejected_seconds INT NOT NULL DEFAULT 0,
+			completed_at TIMESTAMP,
+			last_heartbeat_at TIMESTAMP,
+			PRIMARY KEY (user_id, lesson_id)
+		);`,
+		`CREATE INDEX IF NOT EXISTS idx_video_progress_lesson ON video_progress (lesson_id);`,
+		`CREATE INDEX IF NOT EXISTS idx_video_progress_user_course ON video_progress (user_id, course_id);`,
+		// постепенное открытие уроков: одно правило на урок и отметки об отправленных уведомлениях
+		`CREATE TABLE IF NOT EXISTS lesson_release_rules (
+			lesson_id INT PRIMARY KEY REFERENCES lessons(id) ON DELETE CASCADE,
+			type TEXT NOT NULL, -- date, enrollment_days или after_lesson
+			release_at TIMESTAMP,
+			days_after_enrollment INT,
+			after_lesson_id INT REFERENCES lessons(id) ON DELETE SET NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS lesson_release_notifications (
+			user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			lesson_id INT NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+			notified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, lesson_id)
+		);`,
+		// история правок уроков: каждое сохранение - новая ревизия с полным снимком
+		`CREATE TABLE IF NOT EXISTS lesson_revisions (
+			id SERIAL PRIMARY KEY,
+			lesson_id INT NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+			number INT NOT NULL, -- порядковый номер внутри урока, с 1
+			author_id INT REFERENCES users(id) ON DELETE SET NULL,
+			title TEXT NOT NULL,
+			content TEXT NOT NULL,
+			video_url TEXT NOT NULL DEFAULT '',
+			restored_from INT, -- номер восстановленной ревизии
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			CONSTRAINT uq_lesson_revision_number UNIQUE(lesson_id, number)
+		);`,
+		// текущее содержимое существующих уроков становится первой ревизией
+		`INSERT INTO lesson_revisions (lesson_id, number, title, content, video_url, created_at)
+			SELECT l.id, 1, l.title, COALESCE(l.content, ''), COALESCE(l.video_url, ''), COALESCE(l.updated_at, CURRENT_TIMESTAMP)
+			FROM lessons l
+			WHERE NOT EXISTS (SELECT 1 FROM lesson_revisions rv WHERE rv.lesson_id = l.id);`,
+		// удалённые уроки остаются в корзине курса и могут быть восстановлены
+		`ALTER TABLE lessons ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;`,
+		// длительность видео урока задаёт команда курса; heartbeat с другой длительностью отклоняется
+		`ALTER TABLE lessons ADD COLUMN IF NOT EXISTS video_duration INT;`,
+		`CREATE INDEX IF NOT EXISTS idx_lessons_course_deleted ON lessons (course_id) WHERE deleted_at IS NOT NULL;`,
+		// задания уроков: рубрика, попытки студентов (файл в медиатеке) и оценки со снимком рубрики
+		`CREATE TABLE IF NOT EXISTS assignments (
+			id SERIAL PRIMARY KEY,
+			lesson_id INT NOT NULL UNIQUE REFERENCES lessons(id) ON DELETE CASCADE,
+			title TEXT NOT NULL,
+			instructions TEXT NOT NULL DEFAULT '',
+			instructions_html TEXT NOT NULL DEFAULT '',
+			due_at TIMESTAMP,
+			allowed_extensions TEXT[] NOT NULL DEFAULT '{}',
+			max_score INT NOT NULL,
+			passing_score INT NOT NULL DEFAULT 0,
+			require_passing BOOLEAN NOT NULL DEFAULT FALSE,
+			late_penalty_percent INT NOT NULL DEFAULT 0, -- за каждый начатый день опоздания
+			max_late_days INT, -- NULL - опоздание не ограничено
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS assignment_criteria (
+			id SERIAL PRIMARY KEY,
+			assignment_id INT NOT NULL REFERENCES assignments(id) ON DELETE CASCADE,
+			position INT NOT NULL,
+			title TEXT NOT NULL,
+			description TEXT NOT NULL DEFAULT '',
+			max_points INT NOT NULL
+		);`,
+		`CREATE INDEX IF NOT EXISTS idx_assignment_criteria_assignment ON assignment_criteria (assignment_id);`,
+		`CREATE TABLE IF NOT EXISTS assignment_submissions (
+			id SERIAL PRIMARY KEY,
+			assignment_id INT NOT NULL REFERENCES assignments(id) ON DELETE CASCADE,
+			user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			attempt INT NOT NULL, -- порядковый номер попытки студента, с 1
+			media_id INT REFERENCES media_files(id) ON DELETE SET NULL,
+			comment TEXT NOT NULL DEFAULT '',
+			days_late INT NOT NULL DEFAULT 0,
+			submitted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			raw_score INT,
+			penalty_percent INT,
+			score DOUBLE PRECISION, -- с учётом штрафа за опоздание
+			feedback TEXT,
+			criteria_scores JSONB,
+			graded_by INT REFERENCES users(id) ON DELETE SET NULL,
+			graded_at TIMESTAMP, -- NULL - попытка ждёт проверки
+			CONSTRAINT uq_assignment_submission_attempt UNIQUE(assignment_id, user_id, attempt)
+		);`,
+		`CREATE INDEX IF NOT EXISTS idx_assignment_submissions_pending ON assignment_submissions (assignment_id, submitted_at) WHERE graded_at IS NULL;`,
+		// засчитанный просмотр ограничен временем с первого heartbeat, а не только с прошлого
+		`ALTER TABLE video_progress ADD COLUMN IF NOT EXISTS credited_seconds INT NOT NULL DEFAULT 0;`,
+		`ALTER TABLE video_progress ADD COLUMN IF NOT EXISTS first_heartbeat_at TIMESTAMP;`,
 	}
 
 	for i, query := range queries {
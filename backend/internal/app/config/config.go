@@ -9,6 +9,14 @@ type Config struct {
 	HTTPServer HTTPServerConfig `env:"HTTP_SERVER"`
 	DB         DBConfig         `env:"DB"`
 	JWT		   JWTConfig        `env:"JWT"`
+	SMTP       SMTPConfig       `env:"SMTP"`
+	Payment    PaymentConfig    `env:"PAYMENT"`
+	Storage    StorageConfig    `env:"STORAGE"`
+	AppURL     string           `env:"APP_URL" envDefault:"http://localhost:4200"` // адрес фронта для ссылок в письмах
+	ReviewMinProgress int       `env:"REVIEW_MIN_PROGRESS" envDefault:"0"` // % прохождения курса, после которого можно оставить отзыв
+	RecommendationsIntervalMinutes int `env:"RECOMMENDATIONS_INTERVAL_MINUTES" envDefault:"60"` // как часто пересчитывать похожесть курсов
+	AnalyticsCacheMinutes int `env:"ANALYTICS_CACHE_MINUTES" envDefault:"5"` // сколько хранить посчитанную статистику курса
+	VideoCompletionPercent int `env:"VIDEO_COMPLETION_PERCENT" envDefault:"90"` // % просмотренных секунд видео для завершения урока; 0 - без проверки
 }
 
 type HTTPServerConfig struct {
@@ -32,6 +40,39 @@ type JWTConfig struct {
 }
 
 
+// SMTPConfig - если Host пустой, письма пишутся в лог
+type SMTPConfig struct {
+	Host     string `env:"SMTP_HOST"`
+	Port     string `env:"SMTP_PORT" envDefault:"587"`
+	Username string `env:"SMTP_USERNAME"`
+	Password string `env:"SMTP_PASSWORD"`
+	From     string `env:"SMTP_FROM" envDefault:"no-reply@eduapp.local"`
+}
+
+// PaymentConfig - платёжный провайдер; пока поддерживается только local (без реальных списаний).
+// Секрет вебхука обязателен: с известным значением по умолчанию любой мог бы подделать оплату.
+type PaymentConfig struct {
+	Provider      string `env:"PAYMENT_PROVIDER" envDefault:"local"`
+	WebhookSecret string `env:"PAYMENT_WEBHOOK_SECRET,required,notEmpty"`
+}
+
+// StorageConfig - хранилище загруженных файлов: local (каталог на диске) или s3 (S3-совместимое, например MinIO).
+// Секрет подписи ссылок обязателен: с известным значением по умолчанию любой мог бы подписать ссылку на чужой файл.
+type StorageConfig struct {
+	Driver        string `env:"STORAGE_DRIVER" envDefault:"local"`
+	LocalDir      string `env:"STORAGE_LOCAL_DIR" envDefault:"./uploads"`
+	PublicURL     string `env:"STORAGE_PUBLIC_URL" envDefault:"http://localhost:8080"` // адрес API для ссылок на файлы
+	SigningSecret string `env:"STORAGE_SIGNING_SECRET,required,notEmpty"`
+	S3Endpoint    string `env:"STORAGE_S3_ENDPOINT" envDefault:"http://localhost:9000"`
+	S3Region      string `env:"STORAGE_S3_REGION" envDefault:"us-east-1"`
+	S3Bucket      string `env:"STORAGE_S3_BUCKET" envDefault:"study-platform"`
+	S3AccessKey   string `env:"STORAGE_S3_ACCESS_KEY"`
+	S3SecretKey   string `env:"STORAGE_S3_SECRET_KEY"`
+	S3PathStyle   bool   `env:"STORAGE_S3_PATH_STYLE" envDefault:"true"`
+	MaxUploadMB   int    `env:"STORAGE_MAX_UPLOAD_MB" envDefault:"50"`
+	QuotaMB       int    `env:"STORAGE_QUOTA_MB" envDefault:"1024"` // на одного пользователя, администраторы без лимита
+	URLTTLMinutes int    `env:"STORAGE_URL_TTL_MINUTES" envDefault:"15"`
+}
 
 func NewConfig(filenames ...string) (*Config, error) {
 	if len(filenames) > 0 && filenames[0] != "" {
@@ -3,21 +3,27 @@ package connections
 import (
 	"fmt"
 	"context"
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"gitlab.com/w0ikid/study-platform/internal/app/config"
 )
+// Connections хранит пул соединений: одно pgx-соединение нельзя использовать
+// одновременно из нескольких запросов, транзакций и фоновых задач
 type Connections struct {
-	DB *pgx.Conn
+	DB *pgxpool.Pool
 }
 
 func (c *Connections) Close() {
-	c.DB.Close(context.Background())
+	c.DB.Close()
 }
 
 func NewConnections(cfg *config.Config) (*Connections, error) {
-	conn, err := pgx.Connect(context.Background(), cfg.DB.GetDBConnString())
+	pool, err := pgxpool.New(context.Background(), cfg.DB.GetDBConnString())
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
-	return &Connections{DB: conn}, nil
+	if err := pool.Ping(context.Background()); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return &Connections{DB: pool}, nil
 }
\ No newline at end of file
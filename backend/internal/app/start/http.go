@@ -22,14 +22,14 @@ import (
     "github.com/swaggo/files"                // swagger embed files
     _ "gitlab.com/w0ikid/study-platform/docs"                // docs is generated by Swag CLI, you have to import it.
 )
-func HTTP(cfg *config.Config, userUseCase *usecase.UserUseCase, courseUseCase *usecase.CourseUseCase, lessonUseCase *usecase.LessonUseCase , enrollment *usecase.EnrollmentUseCase, lessonProgressUseCase *usecase.LessonProgressUseCase, certificateUseCase *usecase.CertificateUseCase)  {
+func HTTP(cfg *config.Config, userUseCase *usecase.UserUseCase, courseUseCase *usecase.CourseUseCase, lessonUseCase *usecase.LessonUseCase , enrollment *usecase.EnrollmentUseCase, lessonProgressUseCase *usecase.LessonProgressUseCase, certificateUseCase *usecase.CertificateUseCase, groupUseCase *usecase.GroupUseCase, userImportUseCase *usecase.UserImportUseCase, categoryUseCase *usecase.CategoryUseCase, coursePackageUseCase *usecase.CoursePackageUseCase, moduleUseCase *usecase.ModuleUseCase, reviewUseCase *usecase.ReviewUseCase, paymentUseCase *usecase.PaymentUseCase, courseVersionUseCase *usecase.CourseVersionUseCase, announcementUseCase *usecase.AnnouncementUseCase, discussionUseCase *usecase.DiscussionUseCase, mediaUseCase *usecase.MediaUseCase, recommendationUseCase *usecase.RecommendationUseCase, analyticsUseCase *usecase.AnalyticsUseCase, lessonBlockUseCase *usecase.LessonBlockUseCase, lessonAttachmentUseCase *usecase.LessonAttachmentUseCase, videoProgressUseCase *usecase.VideoProgressUseCase, lessonReleaseUseCase *usecase.LessonReleaseUseCase, lessonRevisionUseCase *usecase.LessonRevisionUseCase, assignmentUseCase *usecase.AssignmentUseCase)  {
 	router := gin.Default()
 
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:4200"}, // адрес фронта
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", "Content-Disposition"},
 		AllowCredentials: true,
 		MaxAge: 12 * time.Hour,
 	}))
@@ -37,7 +37,7 @@ func HTTP(cfg *config.Config, userUseCase *usecase.UserUseCase, courseUseCase *u
 	// Swagger UI доступен по /swagger/index.html
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	routes.SetupRoutes(router, userUseCase, courseUseCase,  lessonUseCase, enrollment, lessonProgressUseCase,certificateUseCase ,cfg)
+	routes.SetupRoutes(router, userUseCase, courseUseCase,  lessonUseCase, enrollment, lessonProgressUseCase,certificateUseCase, groupUseCase, userImportUseCase, categoryUseCase, coursePackageUseCase, moduleUseCase, reviewUseCase, paymentUseCase, courseVersionUseCase, announcementUseCase, discussionUseCase, mediaUseCase, recommendationUseCase, analyticsUseCase, lessonBlockUseCase, lessonAttachmentUseCase, videoProgressUseCase, lessonReleaseUseCase, lessonRevisionUseCase, assignmentUseCase, cfg)
 
 	
 	// Создаем HTTP сервер
@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// Шаг графика записей на курс
+const (
+	AnalyticsIntervalDay   = "day"
+	AnalyticsIntervalWeek  = "week"
+	AnalyticsIntervalMonth = "month"
+)
+
+// AnalyticsFilter - когорта студентов, записавшихся на курс в [From, To); nil - без ограничения
+type AnalyticsFilter struct {
+	CourseID int
+	From     *time.Time
+	To       *time.Time
+	Interval string
+}
+
+// CourseAnalytics - статистика прохождения курса для команды курса.
+// Все показатели считаются по студентам когорты и текущему набору уроков курса.
+type CourseAnalytics struct {
+	CourseID                   int                   `json:"course_id"`
+	From                       *time.Time            `json:"from,omitempty"`
+	To                         *time.Time            `json:"to,omitempty"`
+	Interval                   string                `json:"interval"`
+	Students                   int                   `json:"students"`
+	NotStarted                 int                   `json:"not_started"` // не завершили ни одного урока
+	CompletedStudents          int                   `json:"completed_students"`
+	CompletionRate             float64               `json:"completion_rate"` // доля от Students, 0..1
+	MedianHoursToComplete      *float64              `json:"median_hours_to_complete"`
+	MedianHoursBetweenLessons  *float64              `json:"median_hours_between_lessons"`
+	EnrollmentsOverTime        []*PeriodCount        `json:"enrollments_over_time"`
+	Lessons                    []*LessonFunnelEntry  `json:"lessons"`
+	ProgressDistribution       []*DistributionBucket `json:"progress_distribution"`
+	TimeToCompleteDistribution []*DistributionBucket `json:"time_to_complete_distribution"`
+	GeneratedAt                time.Time             `json:"generated_at"`
+}
+
+// CompletionSummary - сводка по когорте, считается одним запросом
+type CompletionSummary struct {
+	Students              int
+	NotStarted            int
+	CompletedStudents     int
+	MedianHoursToComplete *float64
+}
+
+// PeriodCount - количество записей за период, начинающийся в Period
+type PeriodCount struct {
+	Period time.Time `json:"period"`
+	Count  int       `json:"count"`
+}
+
+// LessonFunnelEntry - урок в порядке прохождения: сколько студентов его завершили
+// и для скольких он стал последним перед тем, как они остановились
+type LessonFunnelEntry struct {
+	LessonID       int     `json:"lesson_id"`
+	Position       int     `json:"position"` // с 1, в порядке показа студентам
+	Title          string  `json:"title"`
+	Completed      int     `json:"completed"`
+	CompletionRate float64 `json:"completion_rate"`
+	DropOff        int     `json:"drop_off"`
+	DropOffRate    float64 `json:"drop_off_rate"`
+}
+
+// DistributionBucket - столбец гистограммы
+type DistributionBucket struct {
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
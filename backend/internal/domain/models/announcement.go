@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Announcement - объявление преподавателя для студентов курса. Body - markdown,
+// BodyHTML - очищенный HTML из него, строится при отдаче.
+// До PublishAt объявление видит только команда курса.
+type Announcement struct {
+	ID         int        `json:"id"`
+	CourseID   int        `json:"course_id"`
+	CourseName string     `json:"course_name,omitempty"`
+	AuthorID   int        `json:"author_id"`
+	AuthorName string     `json:"author_name"`
+	Title      string     `json:"title"`
+	Body       string     `json:"body"`
+	BodyHTML   string     `json:"body_html"`
+	Pinned     bool       `json:"pinned"`
+	SendEmail  bool       `json:"send_email"`
+	PublishAt  time.Time  `json:"publish_at"`
+	EmailedAt  *time.Time `json:"emailed_at,omitempty"`
+	IsRead     bool       `json:"is_read"`    // прочитано текущим пользователем
+	ReadCount  int        `json:"read_count"` // сколько студентов прочитали
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// IsPublished - наступило ли время публикации
+func (a *Announcement) IsPublished(now time.Time) bool {
+	return !a.PublishAt.After(now)
+}
+
+// AnnouncementRead - отметка о прочтении объявления
+type AnnouncementRead struct {
+	UserID   int       `json:"user_id"`
+	Username string    `json:"username"`
+	ReadAt   time.Time `json:"read_at"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// Assignment - задание урока: студент сдаёт файл, преподаватель оценивает его по рубрике.
+// На урок приходится не больше одного задания.
+type Assignment struct {
+	ID                 int                    `json:"id"`
+	CourseID           int                    `json:"course_id"`
+	LessonID           int                    `json:"lesson_id"`
+	Title              string                 `json:"title"`
+	Instructions       string                 `json:"instructions"`      // markdown
+	InstructionsHTML   string                 `json:"instructions_html"` // очищенный HTML из Instructions
+	DueAt              *time.Time             `json:"due_at,omitempty"`
+	AllowedExtensions  []string               `json:"allowed_extensions"` // без точки; пусто - любые файлы, разрешённые медиатекой
+	MaxScore           int                    `json:"max_score"`
+	PassingScore       int                    `json:"passing_score"`
+	RequirePassing     bool                   `json:"require_passing"`         // урок нельзя завершить без оценки не ниже PassingScore
+	LatePenaltyPercent int                    `json:"late_penalty_percent"`    // снижение оценки за каждый начатый день опоздания
+	MaxLateDays        *int                   `json:"max_late_days,omitempty"` // nil - опоздание не ограничено, 0 - после срока сдавать нельзя
+	Criteria           []*AssignmentCriterion `json:"criteria"`                // сумма баллов критериев равна MaxScore
+	CreatedAt          time.Time              `json:"created_at"`
+	UpdatedAt          time.Time              `json:"updated_at"`
+}
+
+// AssignmentCriterion - критерий рубрики
+type AssignmentCriterion struct {
+	ID          int    `json:"id"`
+	Position    int    `json:"position"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	MaxPoints   int    `json:"max_points"`
+}
+
+// AssignmentSubmission - одна попытка сдачи задания; все попытки сохраняются, текущая - с наибольшим Attempt
+type AssignmentSubmission struct {
+	ID              int              `json:"id"`
+	AssignmentID    int              `json:"assignment_id"`
+	UserID          int              `json:"user_id"`
+	UserName        string           `json:"user_name,omitempty"`
+	Attempt         int              `json:"attempt"`
+	MediaID         *int             `json:"media_id"` // nil, если студент удалил файл из медиатеки
+	Filename        string           `json:"filename"`
+	ContentType     string           `json:"content_type"`
+	Size            int64            `json:"size"`
+	StorageKey      string           `json:"-"`
+	Comment         string           `json:"comment,omitempty"`
+	DaysLate        int              `json:"days_late"` // начатых дней после срока сдачи
+	SubmittedAt     time.Time        `json:"submitted_at"`
+	Grade           *AssignmentGrade `json:"grade,omitempty"`
+	LessonID        int              `json:"lesson_id,omitempty"`        // заполняется в очереди на проверку
+	AssignmentTitle string           `json:"assignment_title,omitempty"` // заполняется в очереди на проверку
+}
+
+// AssignmentGrade - оценка попытки. Score - баллы после штрафа за опоздание.
+type AssignmentGrade struct {
+	RawScore       int                        `json:"raw_score"`
+	PenaltyPercent int                        `json:"penalty_percent"`
+	Score          float64                    `json:"score"`
+	Feedback       string                     `json:"feedback,omitempty"`
+	Criteria       []AssignmentCriterionScore `json:"criteria,omitempty"` // снимок рубрики на момент оценки
+	GradedBy       *int                       `json:"graded_by"`
+	GraderName     string                     `json:"grader_name,omitempty"`
+	GradedAt       time.Time                  `json:"graded_at"`
+}
+
+// AssignmentCriterionScore - баллы по критерию рубрики
+type AssignmentCriterionScore struct {
+	CriterionID int    `json:"criterion_id"`
+	Title       string `json:"title"`
+	Points      int    `json:"points"`
+	MaxPoints   int    `json:"max_points"`
+	Comment     string `json:"comment,omitempty"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type Category struct {
+	ID          int         `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	ParentID    *int        `json:"parent_id,omitempty"` // nil - корневая категория
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	Children    []*Category `json:"children,omitempty"`
+}
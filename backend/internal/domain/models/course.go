@@ -2,6 +2,26 @@ package models
 
 import "time"
 
+// Уровни сложности курса совпадают со шкалой User.Level
+const (
+	CourseLevelBeginner     = 0
+	CourseLevelIntermediate = 1
+	CourseLevelAdvanced     = 2
+)
+
+// Статусы курса
+const (
+	CourseStatusActive   = "active"
+	CourseStatusInactive = "inactive"
+	CourseStatusDraft    = "draft" // черновик: не виден в каталоге, запись закрыта
+)
+
+var courseLevelNames = map[int]string{
+	CourseLevelBeginner:     "beginner",
+	CourseLevelIntermediate: "intermediate",
+	CourseLevelAdvanced:     "advanced",
+}
+
 type Course struct {
 	ID          int       `json:"id"`
 	Name        string    `json:"name"`
@@ -9,6 +29,76 @@ type Course struct {
 	ImageUrl 	string 	  `json:"image_url"`
 	TeacherID   int       `json:"teacher_id"`
 	Status      string    `json:"status"`
+	CategoryID      *int     `json:"category_id,omitempty"`
+	Level           int      `json:"level"`            // 0 - beginner, 1 - intermediate, 2 - advanced
+	DurationMinutes int      `json:"duration_minutes"` // примерная длительность курса
+	Language        string   `json:"language,omitempty"`
+	Tags            []string `json:"tags"`
+	MinUserLevel    int      `json:"min_user_level"` // минимальный User.Level для записи, 0 - без ограничения
+	RatingAverage   float64  `json:"rating_average"` // средняя оценка по видимым отзывам
+	RatingCount     int      `json:"rating_count"`
+	StartsAt           *time.Time `json:"starts_at,omitempty"`
+	EndsAt             *time.Time `json:"ends_at,omitempty"`
+	EnrollmentOpensAt  *time.Time `json:"enrollment_opens_at,omitempty"`
+	EnrollmentClosesAt *time.Time `json:"enrollment_closes_at,omitempty"`
+	Capacity           *int       `json:"capacity,omitempty"` // nil - без ограничения мест
+	EnrolledCount      int        `json:"enrolled_count"`
+	PriceCents         int        `json:"price_cents"` // 0 - бесплатный курс
+	Currency           string     `json:"currency"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
+
+// CourseFilter - параметры фильтрации каталога курсов, нулевые значения не фильтруют
+type CourseFilter struct {
+	Search      string
+	CategoryID  int // включая подкатегории
+	Tags        []string
+	Level       *int
+	Language    string
+	MinDuration int
+	MaxDuration int
+	Sort        string // CourseSortRating - по рейтингу, иначе по ID
+}
+
+// CourseSortRating - сортировка каталога по средней оценке и числу отзывов
+const CourseSortRating = "rating"
+
+// TagCount - тег и количество курсов с ним
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// IsPaid - нужна ли оплата для записи на курс
+func (c *Course) IsPaid() bool {
+	return c.PriceCents > 0
+}
+
+// CourseLevelName возвращает название уровня сложности
+func CourseLevelName(level int) string {
+	return courseLevelNames[level]
+}
+
+// CourseLevelFromName переводит название уровня в число, ok=false для неизвестного названия
+func CourseLevelFromName(name string) (int, bool) {
+	for level, levelName := range courseLevelNames {
+		if levelName == name {
+			return level, true
+		}
+	}
+	return 0, false
+}
+
+// EnrollmentWindowError возвращает причину, по которой запись на курс сейчас закрыта, или "" если открыта
+func (c *Course) EnrollmentWindowError(now time.Time) string {
+	switch {
+	case c.EnrollmentOpensAt != nil && now.Before(*c.EnrollmentOpensAt):
+		return "enrollment opens at " + c.EnrollmentOpensAt.Format(time.RFC3339)
+	case c.EnrollmentClosesAt != nil && !now.Before(*c.EnrollmentClosesAt):
+		return "enrollment is closed"
+	case c.EndsAt != nil && !now.Before(*c.EndsAt):
+		return "course has already ended"
+	}
+	return ""
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Формат пакета экспорта курса: ZIP-архив с manifest.json и файлами из каталога assets/
+const (
+	CoursePackageFormat       = "study-platform/course"
+	CoursePackageVersion      = 1 // увеличивается при несовместимых изменениях манифеста
+	CoursePackageManifestName = "manifest.json"
+)
+
+// CoursePackageManifest - описание курса в пакете экспорта. ID внутри манифеста
+// относятся к исходной инсталляции и при импорте заменяются новыми.
+type CoursePackageManifest struct {
+	Format     string                `json:"format"`
+	Version    int                   `json:"version"`
+	ExportedAt time.Time             `json:"exported_at"`
+	Course     CoursePackageCourse   `json:"course"`
+	Modules    []CoursePackageModule `json:"modules,omitempty"`
+	Lessons    []CoursePackageLesson `json:"lessons"`
+	Assets     []CoursePackageAsset  `json:"assets"`
+}
+
+type CoursePackageCourse struct {
+	ID              int                         `json:"id"`
+	Name            string                      `json:"name"`
+	Description     string                      `json:"description"`
+	ImageUrl        string                      `json:"image_url,omitempty"`
+	Status          string                      `json:"status"`
+	CategoryPath    []string                    `json:"category_path,omitempty"` // названия категорий от корня
+	Level           int                         `json:"level"`
+	DurationMinutes int                         `json:"duration_minutes"`
+	Language        string                      `json:"language,omitempty"`
+	MinUserLevel    int                         `json:"min_user_level"`
+	Tags            []string                    `json:"tags"`
+	Prerequisites   []CoursePackagePrerequisite `json:"prerequisites"`
+	TeacherID       int                         `json:"teacher_id"`
+}
+
+// CoursePackagePrerequisite - пререквизит ищется при импорте по названию курса
+type CoursePackagePrerequisite struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type CoursePackageLesson struct {
+	ID            int                      `json:"id"`
+	ModuleID      int                      `json:"module_id,omitempty"` // 0 - модуль по умолчанию
+	Position      int                      `json:"position"`
+	Title         string                   `json:"title"`
+	Content       string                   `json:"content"`
+	VideoURL      string                   `json:"video_url,omitempty"`
+	VideoDuration *int                     `json:"video_duration,omitempty"`
+	Blocks        []CoursePackageBlock     `json:"blocks,omitempty"`
+	Release       *CoursePackageRelease    `json:"release,omitempty"`
+	Assignment    *CoursePackageAssignment `json:"assignment,omitempty"`
+}
+
+// CoursePackageAssignment - задание урока с рубрикой; сданные работы в пакет не попадают
+type CoursePackageAssignment struct {
+	Title              string                   `json:"title"`
+	Instructions       string                   `json:"instructions"`
+	DueAt              *time.Time               `json:"due_at,omitempty"`
+	AllowedExtensions  []string                 `json:"allowed_extensions,omitempty"`
+	MaxScore           int                      `json:"max_score"`
+	PassingScore       int                      `json:"passing_score"`
+	RequirePassing     bool                     `json:"require_passing"`
+	LatePenaltyPercent int                      `json:"late_penalty_percent"`
+	MaxLateDays        *int                     `json:"max_late_days,omitempty"`
+	Criteria           []CoursePackageCriterion `json:"criteria,omitempty"`
+}
+
+// CoursePackageCriterion - критерий рубрики задания, по порядку
+type CoursePackageCriterion struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	MaxPoints   int    `json:"max_points"`
+}
+
+// CoursePackageRelease - правило открытия урока; AfterLessonID - ID урока внутри манифеста
+type CoursePackageRelease struct {
+	Type                string     `json:"type"`
+	ReleaseAt           *time.Time `json:"release_at,omitempty"`
+	DaysAfterEnrollment *int       `json:"days_after_enrollment,omitempty"`
+	AfterLessonID       *int       `json:"after_lesson_id,omitempty"`
+}
+
+// CoursePackageBlock - блок урока в пакете, по порядку
+type CoursePackageBlock struct {
+	Type     string          `json:"type"`
+	Data     json.RawMessage `json:"data"`
+	Required bool            `json:"required"`
+}
+
+// CoursePackageModule - модуль курса в пакете
+type CoursePackageModule struct {
+	ID          int    `json:"id"`
+	Position    int    `json:"position"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// CoursePackageAsset - файл, вложенный в архив (путь относительно корня архива).
+// Файлы с LessonID импортируются как вложения урока.
+type CoursePackageAsset struct {
+	Path        string `json:"path"`
+	LessonID    int    `json:"lesson_id,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Watermark   bool   `json:"watermark,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int64  `json:"size"`
+}
+
+// CourseImportConflict - расхождение, которое не мешает импорту, но требует внимания
+type CourseImportConflict struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// CourseImportReport - результат проверки и импорта пакета курса
+type CourseImportReport struct {
+	DryRun         bool                    `json:"dry_run"`
+	Version        int                     `json:"version"`
+	SourceCourseID int                     `json:"source_course_id"`
+	Errors         []string                `json:"errors,omitempty"`
+	Conflicts      []*CourseImportConflict `json:"conflicts,omitempty"`
+	Course         *Course                 `json:"course,omitempty"`
+	ModuleIDs      map[int]int             `json:"module_ids,omitempty"` // старый ID модуля -> новый
+	LessonIDs      map[int]int             `json:"lesson_ids,omitempty"` // старый ID урока -> новый
+}
+
+// AddConflict добавляет в отчёт расхождение
+func (r *CourseImportReport) AddConflict(field, message string) {
+	r.Conflicts = append(r.Conflicts, &CourseImportConflict{Field: field, Message: message})
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Роли в команде курса
+const (
+	CourseStaffRoleOwner     = "owner"      // владелец: всё, включая управление командой и удаление курса
+	CourseStaffRoleCoTeacher = "co_teacher" // соавтор: редактирует курс и уроки
+	CourseStaffRoleTA        = "ta"         // ассистент: видит список студентов и выставляет оценки
+)
+
+type CourseStaff struct {
+	CourseID  int       `json:"course_id"`
+	UserID    int       `json:"user_id"`
+	Username  string    `json:"username"`
+	Name      string    `json:"name"`
+	Surname   string    `json:"surname"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CanEditContent - может ли участник команды менять курс и его уроки
+func (s *CourseStaff) CanEditContent() bool {
+	return s.Role == CourseStaffRoleOwner || s.Role == CourseStaffRoleCoTeacher
+}
+
+// CourseRosterEntry - студент курса в списке для преподавателей
+type CourseRosterEntry struct {
+	EnrollmentID     int       `json:"enrollment_id"`
+	UserID           int       `json:"user_id"`
+	Username         string    `json:"username"`
+	Name             string    `json:"name"`
+	Surname          string    `json:"surname"`
+	Email            string    `json:"email"`
+	Status           string    `json:"status"`
+	CompletedLessons int       `json:"completed_lessons"`
+	EnrolledAt       time.Time `json:"enrolled_at"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// CourseVersion - опубликованный неизменяемый снимок модулей и уроков курса.
+// Уроки в снимке сохраняют ID из рабочего черновика, поэтому прогресс студента
+// переносится между версиями по ID урока.
+type CourseVersion struct {
+	ID          int                   `json:"id"`
+	CourseID    int                   `json:"course_id"`
+	Version     int                   `json:"version"` // порядковый номер внутри курса, начиная с 1
+	Notes       string                `json:"notes,omitempty"`
+	LessonCount int                   `json:"lesson_count"`
+	PublishedBy int                   `json:"published_by"`
+	PublishedAt time.Time             `json:"published_at"`
+	Content     *CourseVersionContent `json:"content,omitempty"` // в списках версий не загружается
+}
+
+// CourseVersionContent - содержимое версии. Версионируются только модули и уроки (название,
+// текст, видео); блоки, вложения, задания и правила открытия уроков не входят в снимок:
+// студенты закреплённой версии видят их текущее состояние, а их правка не считается
+// изменением урока и не сбрасывает прогресс.
+type CourseVersionContent struct {
+	Modules []*Module `json:"modules"`
+	Lessons []*Lesson `json:"lessons"`
+}
+
+// LessonChanges - разница в уроках между двумя версиями курса (ID уроков)
+type LessonChanges struct {
+	Unchanged []int `json:"unchanged"` // прогресс по ним сохраняется
+	Changed   []int `json:"changed"`   // прогресс по ним сбрасывается
+	Added     []int `json:"added"`
+	Removed   []int `json:"removed"`
+}
+
+// StudentVersionInfo - версия курса, закреплённая за студентом, и доступное обновление
+type StudentVersionInfo struct {
+	Current          *CourseVersion `json:"current"`
+	Latest           *CourseVersion `json:"latest"`
+	UpgradeAvailable bool           `json:"upgrade_available"`
+	Changes          *LessonChanges `json:"changes,omitempty"`
+}
+
+// SameContent - совпадает ли содержимое уроков; перенос в другой модуль или позицию изменением не считается
+func (l *Lesson) SameContent(other *Lesson) bool {
+	return l.Title == other.Title && l.Content == other.Content && l.VideoURL == other.VideoURL
+}
+
+// DiffLessons сравнивает уроки двух версий курса
+func DiffLessons(from, to []*Lesson) *LessonChanges {
+	changes := &LessonChanges{Unchanged: []int{}, Changed: []int{}, Added: []int{}, Removed: []int{}}
+
+	previous := make(map[int]*Lesson, len(from))
+	for _, lesson := range from {
+		previous[lesson.ID] = lesson
+	}
+	for _, lesson := range to {
+		old, ok := previous[lesson.ID]
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, lesson.ID)
+		case old.SameContent(lesson):
+			changes.Unchanged = append(changes.Unchanged, lesson.ID)
+		default:
+			changes.Changed = append(changes.Changed, lesson.ID)
+		}
+		delete(previous, lesson.ID)
+	}
+	for _, lesson := range from {
+		if _, ok := previous[lesson.ID]; ok {
+			changes.Removed = append(changes.Removed, lesson.ID)
+		}
+	}
+	return changes
+}
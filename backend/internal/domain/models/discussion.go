@@ -0,0 +1,97 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DiscussionThread - тема обсуждения курса или, если задан LessonID, конкретного урока.
+// Body и тексты ответов - markdown, BodyHTML - очищенный HTML из него, строится при отдаче.
+type DiscussionThread struct {
+	ID             int               `json:"id"`
+	CourseID       int               `json:"course_id"`
+	LessonID       *int              `json:"lesson_id,omitempty"`
+	AuthorID       int               `json:"author_id"`
+	AuthorName     string            `json:"author_name"`
+	Title          string            `json:"title"`
+	Body           string            `json:"body"`
+	BodyHTML       string            `json:"body_html"`
+	AcceptedPostID *int              `json:"accepted_post_id,omitempty"`
+	Upvotes        int               `json:"upvotes"`
+	Upvoted        bool              `json:"upvoted"` // голос текущего пользователя
+	ReplyCount     int               `json:"reply_count"`
+	Edited         bool              `json:"edited"`
+	LastActivityAt time.Time         `json:"last_activity_at"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+	Posts          []*DiscussionPost `json:"posts,omitempty"`
+}
+
+// DiscussionPost - ответ в теме; ParentID указывает на ответ, к которому он вложен
+type DiscussionPost struct {
+	ID         int               `json:"id"`
+	ThreadID   int               `json:"thread_id"`
+	ParentID   *int              `json:"parent_id,omitempty"`
+	AuthorID   int               `json:"author_id"`
+	AuthorName string            `json:"author_name"`
+	Body       string            `json:"body"`
+	BodyHTML   string            `json:"body_html"`
+	Upvotes    int               `json:"upvotes"`
+	Upvoted    bool              `json:"upvoted"`
+	IsAccepted bool              `json:"is_accepted"`
+	Edited     bool              `json:"edited"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+	Replies    []*DiscussionPost `json:"replies,omitempty"`
+}
+
+// DiscussionEdit - предыдущая редакция темы или ответа
+type DiscussionEdit struct {
+	ID         int       `json:"id"`
+	ThreadID   int       `json:"thread_id"`
+	PostID     *int      `json:"post_id,omitempty"`
+	EditorID   int       `json:"editor_id"`
+	EditorName string    `json:"editor_name"`
+	Title      string    `json:"title,omitempty"`
+	Body       string    `json:"body"`
+	EditedAt   time.Time `json:"edited_at"`
+}
+
+// BuildPostTree раскладывает ответы (упорядоченные по времени) в дерево по ParentID
+func BuildPostTree(posts []*DiscussionPost) []*DiscussionPost {
+	byID := make(map[int]*DiscussionPost, len(posts))
+	for _, post := range posts {
+		byID[post.ID] = post
+	}
+
+	roots := []*DiscussionPost{}
+	for _, post := range posts {
+		if post.ParentID != nil {
+			if parent, ok := byID[*post.ParentID]; ok {
+				parent.Replies = append(parent.Replies, post)
+				continue
+			}
+		}
+		roots = append(roots, post)
+	}
+	return roots
+}
+
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([A-Za-z0-9_.-]+)`)
+
+// ParseMentions возвращает уникальные имена пользователей, упомянутых через @username
+func ParseMentions(body string) []string {
+	seen := map[string]bool{}
+	var usernames []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		username := strings.TrimRight(match[1], ".-")
+		key := strings.ToLower(username)
+		if username == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		usernames = append(usernames, username)
+	}
+	return usernames
+}
@@ -8,4 +8,27 @@ type Enrollment struct {
 	Status    string    `json:"status"` // roles: active, completed
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
-}
\ No newline at end of file
+}
+
+// Результат попытки записи на курс с ограниченным числом мест
+const (
+	EnrollmentResultEnrolled   = "enrolled"
+	EnrollmentResultWaitlisted = "waitlisted"
+	EnrollmentResultFull       = "full" // мест нет, а в лист ожидания студента не ставили
+)
+
+type EnrollmentResult struct {
+	Status     string         `json:"status"`
+	Enrollment *Enrollment    `json:"enrollment,omitempty"`
+	Waitlist   *WaitlistEntry `json:"waitlist,omitempty"`
+}
+
+// WaitlistEntry - место студента в листе ожидания курса (Position начинается с 1)
+type WaitlistEntry struct {
+	ID        int       `json:"id"`
+	CourseID  int       `json:"course_id"`
+	UserID    int       `json:"user_id"`
+	Username  string    `json:"username,omitempty"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+}
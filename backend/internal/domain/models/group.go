@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+const (
+	GroupRoleOwner  = "owner"  // преподаватель, управляющий группой
+	GroupRoleMember = "member" // студент группы
+)
+
+type Group struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	CreatedBy   int       `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type GroupMember struct {
+	GroupID   int       `json:"group_id"`
+	UserID    int       `json:"user_id"`
+	Username  string    `json:"username"`
+	Name      string    `json:"name"`
+	Surname   string    `json:"surname"`
+	Role      string    `json:"role"` // roles: owner, member
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type GroupCourse struct {
+	GroupID   int       `json:"group_id"`
+	CourseID  int       `json:"course_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GroupMemberProgress - прогресс одного участника группы по курсу
+type GroupMemberProgress struct {
+	UserID           int     `json:"user_id"`
+	Username         string  `json:"username"`
+	Name             string  `json:"name"`
+	Surname          string  `json:"surname"`
+	Enrolled         bool    `json:"enrolled"`
+	EnrollmentStatus string  `json:"enrollment_status,omitempty"`
+	CompletedLessons int     `json:"completed_lessons"`
+	Progress         float64 `json:"progress"`
+}
+
+// GroupProgressReport - агрегированный отчёт по группе для одного курса
+type GroupProgressReport struct {
+	GroupID         int                    `json:"group_id"`
+	CourseID        int                    `json:"course_id"`
+	TotalLessons    int                    `json:"total_lessons"`
+	MembersCount    int                    `json:"members_count"`
+	CompletedCount  int                    `json:"completed_count"`
+	AverageProgress float64                `json:"average_progress"`
+	Members         []*GroupMemberProgress `json:"members"`
+}
+
+// BulkEnrollmentResult - результат зачисления одного участника группы на курс
+type BulkEnrollmentResult struct {
+	UserID          int    `json:"user_id"`
+	CourseID        int    `json:"course_id"`
+	Enrolled        bool   `json:"enrolled"`
+	AlreadyEnrolled bool   `json:"already_enrolled,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
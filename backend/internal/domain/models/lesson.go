@@ -5,12 +5,20 @@ import (
 )
 
 type Lesson struct {
-    ID        int       `json:"id"`
-    CourseID  int       `json:"course_id"`
-    Title     string    `json:"title"`
-    Content   string    `json:"content"`
-    VideoURL  string    `json:"video_url,omitempty"`
-    CreatedAt time.Time `json:"created_at"`
-    UpdatedAt time.Time `json:"updated_at"`
+    ID             int            `json:"id"`
+    CourseID       int            `json:"course_id"`
+    ModuleID       *int           `json:"module_id"` // nil - неявный модуль по умолчанию
+    Position       int            `json:"position"`  // порядок урока внутри модуля
+    Title          string         `json:"title"`
+    Content        string         `json:"content"`      // markdown
+    ContentHTML    string         `json:"content_html"` // очищенный HTML из Content, строится при сохранении
+    VideoURL       string         `json:"video_url,omitempty"`
+    VideoDuration  *int           `json:"video_duration,omitempty"` // длительность видео в секундах, по ней считается просмотр
+    Blocks         []*LessonBlock `json:"blocks,omitempty"`          // заполняется только при импорте курса
+    ResumePosition *int           `json:"resume_position,omitempty"` // секунда видео, с которой студент продолжит просмотр
+    Lock           *LessonLock    `json:"lock,omitempty"`            // урок ещё закрыт для студента: отдаётся только название
+    DeletedAt      *time.Time     `json:"deleted_at,omitempty"`      // заполняется только в списке удалённых уроков
+    CreatedAt      time.Time      `json:"created_at"`
+    UpdatedAt      time.Time      `json:"updated_at"`
 }
 // 
\ No newline at end of file
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// LessonAttachment - файл урока для скачивания. Содержимое хранится как файл медиатеки курса,
+// имя, тип и размер берутся из него. С Watermark PDF при скачивании подписывается именем студента.
+type LessonAttachment struct {
+	ID            int       `json:"id"`
+	LessonID      int       `json:"lesson_id"`
+	MediaID       int       `json:"media_id"`
+	Title         string    `json:"title"`
+	Filename      string    `json:"filename"`
+	ContentType   string    `json:"content_type"`
+	Size          int64     `json:"size"`
+	Watermark     bool      `json:"watermark"`
+	DownloadCount int       `json:"download_count"`
+	StorageKey    string    `json:"-"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// IsPDF - является ли вложение PDF-документом
+func (a *LessonAttachment) IsPDF() bool {
+	return a.ContentType == "application/pdf"
+}
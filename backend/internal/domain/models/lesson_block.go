@@ -0,0 +1,39 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Типы блоков урока; формат Data каждого типа задаётся JSON-схемой
+const (
+	LessonBlockText    = "text"    // markdown
+	LessonBlockVideo   = "video"   // ссылка на видео
+	LessonBlockImage   = "image"   // загруженное изображение или внешняя ссылка
+	LessonBlockCode    = "code"    // фрагмент кода с языком
+	LessonBlockCallout = "callout" // выделенная заметка: info, tip, warning, danger
+	LessonBlockFile    = "file"    // загруженный файл для скачивания
+	LessonBlockQuiz    = "quiz"    // ссылка на тест
+)
+
+// LessonBlock - блок содержимого урока. HTML строится из markdown для блоков text и callout.
+// Урок считается пройденным, когда студент завершил все обязательные (Required) блоки.
+type LessonBlock struct {
+	ID          int             `json:"id"`
+	LessonID    int             `json:"lesson_id"`
+	Position    int             `json:"position"`
+	Type        string          `json:"type"`
+	Data        json.RawMessage `json:"data"`
+	HTML        string          `json:"html,omitempty"`
+	Required    bool            `json:"required"`
+	IsCompleted bool            `json:"is_completed"` // завершён текущим пользователем
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// LessonBlockCompletion - результат завершения блока студентом
+type LessonBlockCompletion struct {
+	BlockID           int  `json:"block_id"`
+	RemainingRequired int  `json:"remaining_required"` // сколько обязательных блоков урока ещё не завершено
+	LessonCompleted   bool `json:"lesson_completed"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Типы правил открытия урока
+const (
+	LessonReleaseDate           = "date"            // в указанную дату
+	LessonReleaseEnrollmentDays = "enrollment_days" // через N дней после записи студента на курс
+	LessonReleaseAfterLesson    = "after_lesson"    // после завершения другого урока курса
+)
+
+// LessonReleaseRule - правило постепенного открытия урока. Заполнено только поле своего типа.
+// Урок без правила доступен сразу.
+type LessonReleaseRule struct {
+	LessonID            int        `json:"lesson_id"`
+	Type                string     `json:"type"`
+	ReleaseAt           *time.Time `json:"release_at,omitempty"`
+	DaysAfterEnrollment *int       `json:"days_after_enrollment,omitempty"`
+	AfterLessonID       *int       `json:"after_lesson_id,omitempty"` // nil, если урок-условие удалён: правило больше не закрывает урок
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// LessonLock - почему урок ещё закрыт для студента: дата открытия или урок, который нужно завершить.
+// AvailableAt пуст, если дату открытия нельзя вычислить (например, нет записи на курс).
+type LessonLock struct {
+	Type          string     `json:"type"`
+	AvailableAt   *time.Time `json:"available_at,omitempty"`
+	AfterLessonID *int       `json:"after_lesson_id,omitempty"`
+}
+
+// LessonUnlock - урок, открывшийся студенту по дате, для письма-уведомления
+type LessonUnlock struct {
+	UserID      int       `json:"user_id"`
+	Email       string    `json:"email"`
+	Name        string    `json:"name"`
+	CourseID    int       `json:"course_id"`
+	CourseName  string    `json:"course_name"`
+	LessonID    int       `json:"lesson_id"`
+	LessonTitle string    `json:"lesson_title"`
+	UnlockAt    time.Time `json:"unlock_at"`
+}
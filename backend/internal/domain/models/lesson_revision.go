@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// LessonRevision - снимок урока после сохранения. Number растёт внутри урока с 1;
+// восстановление старой ревизии создаёт новую ревизию с RestoredFrom.
+type LessonRevision struct {
+	ID           int       `json:"id"`
+	LessonID     int       `json:"lesson_id"`
+	Number       int       `json:"number"`
+	AuthorID     *int      `json:"author_id"` // nil для исходного содержимого уроков, созданных до истории правок
+	AuthorName   string    `json:"author_name,omitempty"`
+	Title        string    `json:"title"`
+	Content      string    `json:"content,omitempty"` // в списке ревизий не заполняется
+	VideoURL     string    `json:"video_url,omitempty"`
+	RestoredFrom *int      `json:"restored_from,omitempty"` // номер восстановленной ревизии
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// LessonFieldChange - изменение однострочного поля урока между ревизиями
+type LessonFieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// LessonRevisionDiff - разница между двумя ревизиями урока. ContentDiff - unified diff markdown.
+type LessonRevisionDiff struct {
+	LessonID     int                 `json:"lesson_id"`
+	From         int                 `json:"from"`
+	To           int                 `json:"to"`
+	Changes      []LessonFieldChange `json:"changes"`
+	ContentDiff  string              `json:"content_diff"`
+	AddedLines   int                 `json:"added_lines"`
+	RemovedLines int                 `json:"removed_lines"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// MediaFile - загруженный файл. Файл курса (CourseID) доступен участникам курса,
+// публичный (Public) - всем, остальные - только владельцу.
+type MediaFile struct {
+	ID           int        `json:"id"`
+	OwnerID      int        `json:"owner_id"`
+	CourseID     *int       `json:"course_id,omitempty"`
+	Filename     string     `json:"filename"`
+	ContentType  string     `json:"content_type"`
+	Size         int64      `json:"size"`
+	Public       bool       `json:"public"`
+	StorageKey   string     `json:"-"`
+	CreatedAt    time.Time  `json:"created_at"`
+	URL          string     `json:"url,omitempty"` // временная ссылка на скачивание
+	URLExpiresAt *time.Time `json:"url_expires_at,omitempty"`
+}
+
+// IsImage - является ли файл изображением
+func (f *MediaFile) IsImage() bool {
+	return strings.HasPrefix(f.ContentType, "image/")
+}
+
+// MediaUsage - занятое пользователем место и его квота; QuotaBytes == 0 - без ограничений
+type MediaUsage struct {
+	UsedBytes  int64 `json:"used_bytes"`
+	QuotaBytes int64 `json:"quota_bytes"`
+}
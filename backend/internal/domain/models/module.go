@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// DefaultModuleTitle - название неявного модуля, в котором находятся уроки без module_id
+const DefaultModuleTitle = "General"
+
+// Module - раздел курса (например, учебная неделя), объединяющий уроки
+type Module struct {
+	ID          int       `json:"id"` // 0 - неявный модуль по умолчанию
+	CourseID    int       `json:"course_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	Position    int       `json:"position"`
+	IsDefault   bool      `json:"is_default"`
+	Lessons     []*Lesson `json:"lessons"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ModuleProgress - прогресс студента по одному модулю
+type ModuleProgress struct {
+	ModuleID         int        `json:"module_id"`
+	Title            string     `json:"title"`
+	Position         int        `json:"position"`
+	CompletedLessons int        `json:"completed_lessons"`
+	TotalLessons     int        `json:"total_lessons"`
+	Progress         float64    `json:"progress"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+}
+
+// CourseProgress - прогресс студента по курсу с разбивкой по модулям
+type CourseProgress struct {
+	CourseID         int               `json:"course_id"`
+	CompletedLessons int               `json:"completed_lessons"`
+	TotalLessons     int               `json:"total_lessons"`
+	Progress         float64           `json:"progress"`
+	Modules          []*ModuleProgress `json:"modules"`
+}
+
+// ModuleCompletedEvent - студент завершил все уроки модуля
+type ModuleCompletedEvent struct {
+	UserID      int       `json:"user_id"`
+	CourseID    int       `json:"course_id"`
+	ModuleID    int       `json:"module_id"`
+	CompletedAt time.Time `json:"completed_at"`
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Статусы заказа
+const (
+	OrderStatusPending  = "pending" // ожидает подтверждения оплаты от провайдера
+	OrderStatusPaid     = "paid"
+	OrderStatusFailed   = "failed"
+	OrderStatusRefunded = "refunded" // деньги возвращены, запись на курс отозвана
+)
+
+// Order - покупка платного курса студентом. Суммы хранятся в минимальных единицах валюты.
+type Order struct {
+	ID            int        `json:"id"`
+	UserID        int        `json:"user_id"`
+	CourseID      int        `json:"course_id"`
+	CourseName    string     `json:"course_name,omitempty"`
+	CouponID      *int       `json:"coupon_id,omitempty"`
+	CouponCode    *string    `json:"coupon_code,omitempty"`
+	AmountCents   int        `json:"amount_cents"` // цена курса на момент заказа
+	DiscountCents int        `json:"discount_cents"`
+	TotalCents    int        `json:"total_cents"` // к оплате
+	Currency      string     `json:"currency"`
+	Status        string     `json:"status"`
+	Provider      string     `json:"provider,omitempty"`
+	PaymentID     *string    `json:"payment_id,omitempty"`
+	CheckoutURL   *string    `json:"checkout_url,omitempty"`
+	PaidAt        *time.Time `json:"paid_at,omitempty"`
+	RefundedAt    *time.Time `json:"refunded_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// Типы скидки купона
+const (
+	CouponTypePercent = "percent" // Value - процент от 1 до 100
+	CouponTypeFixed   = "fixed"   // Value - сумма в минимальных единицах валюты
+)
+
+// Coupon - промокод на скидку; без CourseID действует на любой курс
+type Coupon struct {
+	ID        int        `json:"id"`
+	Code      string     `json:"code"`
+	Type      string     `json:"type"`
+	Value     int        `json:"value"`
+	CourseID  *int       `json:"course_id,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	MaxUses   *int       `json:"max_uses,omitempty"` // nil - без ограничения
+	UsedCount int        `json:"used_count"`         // число оплаченных заказов с купоном
+	CreatedBy int        `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Discount возвращает скидку для суммы amount, не больше самой суммы
+func (c *Coupon) Discount(amount int) int {
+	discount := c.Value
+	if c.Type == CouponTypePercent {
+		discount = amount * c.Value / 100
+	}
+	if discount > amount {
+		return amount
+	}
+	return discount
+}
+
+// ErrCouponLimitReached возвращается при оплате заказа, если купон исчерпали другие заказы,
+// пока этот ждал оплаты
+var ErrCouponLimitReached = errors.New("coupon usage limit reached")
+
+// UnavailableReason объясняет, почему купон нельзя применить к курсу, пустая строка - можно
+func (c *Coupon) UnavailableReason(courseID int, now time.Time) string {
+	switch {
+	case c.CourseID != nil && *c.CourseID != courseID:
+		return "coupon is not valid for this course"
+	case c.ExpiresAt != nil && !now.Before(*c.ExpiresAt):
+		return "coupon has expired"
+	case c.MaxUses != nil && c.UsedCount >= *c.MaxUses:
+		return ErrCouponLimitReached.Error()
+	}
+	return ""
+}
@@ -0,0 +1,15 @@
+package models
+
+// CoursePrerequisite - курс, который нужно завершить перед записью на другой курс
+type CoursePrerequisite struct {
+	CourseID       int    `json:"course_id"`
+	PrerequisiteID int    `json:"prerequisite_id"`
+	Name           string `json:"name"`
+}
+
+// CoursePrerequisites - требования курса к студенту
+type CoursePrerequisites struct {
+	CourseID     int                   `json:"course_id"`
+	MinUserLevel int                   `json:"min_user_level"` // 0 - без ограничения по уровню
+	Courses      []*CoursePrerequisite `json:"courses"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// CourseSimilarity - насколько пересекаются студенты двух курсов (коэффициент Жаккара по записям)
+type CourseSimilarity struct {
+	CourseID        int       `json:"course_id"`
+	SimilarCourseID int       `json:"similar_course_id"`
+	Score           float64   `json:"score"`
+	CoEnrolled      int       `json:"co_enrolled"` // студентов, записанных на оба курса
+	ComputedAt      time.Time `json:"computed_at"`
+}
+
+// Причины, по которым курс попал в рекомендации
+const (
+	RecommendationReasonCoEnrollment = "students_also_enrolled"
+	RecommendationReasonCategory     = "same_category"
+	RecommendationReasonTags         = "matching_tags"
+	RecommendationReasonLevel        = "matches_your_level"
+	RecommendationReasonRating       = "highly_rated"
+)
+
+// RecommendedCourse - курс из рекомендаций с итоговой оценкой (0..1) и причинами
+type RecommendedCourse struct {
+	Course
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Статусы отзыва
+const (
+	ReviewStatusVisible = "visible"
+	ReviewStatusHidden  = "hidden" // скрыт модератором и не учитывается в рейтинге курса
+)
+
+// Review - оценка курса студентом (1-5) с текстом. У студента один отзыв на курс.
+type Review struct {
+	ID            int        `json:"id"`
+	CourseID      int        `json:"course_id"`
+	UserID        int        `json:"user_id"`
+	Username      string     `json:"username"`
+	Rating        int        `json:"rating"`
+	Body          string     `json:"body"`
+	Status        string     `json:"status"`
+	Reply         *string    `json:"reply,omitempty"` // ответ преподавателя
+	ReplyAuthorID *int       `json:"reply_author_id,omitempty"`
+	RepliedAt     *time.Time `json:"replied_at,omitempty"`
+	ReportsCount  int        `json:"reports_count"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// ReviewReport - жалоба пользователя на отзыв
+type ReviewReport struct {
+	ReviewID  int       `json:"review_id"`
+	UserID    int       `json:"user_id"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
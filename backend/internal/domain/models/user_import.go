@@ -0,0 +1,38 @@
+package models
+
+// UserImportEntry - пользователь из CSV-импорта вместе с курсом и группой, куда его нужно добавить
+type UserImportEntry struct {
+	User     *User
+	CourseID int // 0 - без зачисления на курс
+	GroupID  int // 0 - без добавления в группу
+}
+
+// UserImportRowResult - результат проверки и импорта одной строки CSV
+type UserImportRowResult struct {
+	Line              int                     `json:"line"`
+	Username          string                  `json:"username"`
+	Email             string                  `json:"email"`
+	Errors            []string                `json:"errors,omitempty"`
+	UserID            int                     `json:"user_id,omitempty"`
+	TemporaryPassword string                  `json:"temporary_password,omitempty"`
+	InviteSent        bool                    `json:"invite_sent,omitempty"`
+	InviteError       string                  `json:"invite_error,omitempty"`
+	Enrollments       []*UserImportEnrollment `json:"enrollments,omitempty"`
+}
+
+// UserImportEnrollment - зачисление импортированного пользователя на курс строки или курс его группы
+type UserImportEnrollment struct {
+	CourseID int    `json:"course_id"`
+	Enrolled bool   `json:"enrolled"`
+	Error    string `json:"error,omitempty"`
+}
+
+// UserImportReport - итог импорта пользователей
+type UserImportReport struct {
+	DryRun  bool                   `json:"dry_run"`
+	Total   int                    `json:"total"`
+	Valid   int                    `json:"valid"`
+	Invalid int                    `json:"invalid"`
+	Created int                    `json:"created"`
+	Rows    []*UserImportRowResult `json:"rows"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// WatchInterval - просмотренный отрезок видео [Start, End) в секундах
+type WatchInterval struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// VideoProgress - просмотр видео урока студентом. Intervals - объединённые просмотренные
+// отрезки, WatchedSeconds - число различных просмотренных секунд. Перемотки вперёд
+// (SkipCount, SkippedSeconds) и отрезки длиннее, чем могло проиграться между heartbeat
+// (RejectedSeconds), в просмотр не засчитываются. CreditedSeconds - сумма принятых отрезков
+// с повторами; она не может превысить время, прошедшее с первого heartbeat (FirstHeartbeatAt).
+type VideoProgress struct {
+	UserID           int             `json:"user_id"`
+	Username         string          `json:"username,omitempty"` // заполняется в отчёте для команды курса
+	LessonID         int             `json:"lesson_id"`
+	CourseID         int             `json:"course_id"`
+	Position         int             `json:"position"`
+	Duration         int             `json:"duration"`
+	Intervals        []WatchInterval `json:"intervals"`
+	WatchedSeconds   int             `json:"watched_seconds"`
+	WatchedPercent   float64         `json:"watched_percent"`
+	SkipCount        int             `json:"skip_count"`
+	SkippedSeconds   int             `json:"skipped_seconds"`
+	RejectedSeconds  int             `json:"rejected_seconds"`
+	CreditedSeconds  int             `json:"credited_seconds"`
+	LessonCompleted  bool            `json:"lesson_completed"`
+	CompletedAt      *time.Time      `json:"completed_at,omitempty"` // когда просмотр засчитал урок
+	FirstHeartbeatAt *time.Time      `json:"first_heartbeat_at,omitempty"`
+	LastHeartbeatAt  *time.Time      `json:"last_heartbeat_at,omitempty"`
+}
@@ -0,0 +1,223 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+)
+
+// Столбцы гистограмм; индексы совпадают с номерами, которые возвращают запросы
+var (
+	progressBucketLabels       = []string{"0%", "1-24%", "25-49%", "50-74%", "75-99%", "100%"}
+	timeToCompleteBucketLabels = []string{"under 1 day", "1-7 days", "8-30 days", "31-90 days", "over 90 days"}
+)
+
+type AnalyticsRepositoryInterface interface {
+	FindEnrollmentsOverTime(ctx context.Context, filter models.AnalyticsFilter) ([]*models.PeriodCount, error)
+	FindLessonFunnel(ctx context.Context, filter models.AnalyticsFilter) ([]*models.LessonFunnelEntry, error)
+	FindCompletionSummary(ctx context.Context, filter models.AnalyticsFilter) (*models.CompletionSummary, error)
+	FindProgressDistribution(ctx context.Context, filter models.AnalyticsFilter) ([]*models.DistributionBucket, error)
+	FindTimeToCompleteDistribution(ctx context.Context, filter models.AnalyticsFilter) ([]*models.DistributionBucket, error)
+	FindMedianHoursBetweenLessons(ctx context.Context, filter models.AnalyticsFilter) (*float64, error)
+}
+
+type AnalyticsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAnalyticsRepository(db *pgxpool.Pool) *AnalyticsRepository {
+	return &AnalyticsRepository{db: db}
+}
+
+// analyticsCohort - общие CTE: когорта ($1 - курс, $2/$3 - границы даты записи),
+// уроки курса в порядке показа студентам и завершения уроков студентами когорты
+const analyticsCohort = `
+	WITH cohort AS (
+		SELECT e.user_id, e.created_at AS enrolled_at
+		FROM enrollments e
+		WHERE e.course_id = $1
+		  AND ($2::TIMESTAMP IS NULL OR e.created_at >= $2)
+		  AND ($3::TIMESTAMP IS NULL OR e.created_at < $3)
+	), ordered_lessons AS (
+		SELECT l.id, l.title,
+			ROW_NUMBER() OVER (ORDER BY COALESCE(m.position, 0), m.id NULLS FIRST, l.position, l.id) AS rank
+		FROM lessons l
+		LEFT JOIN course_modules m ON m.id = l.module_id
+		WHERE l.course_id = $1 AND l.deleted_at IS NULL
+	), completions AS (
+		SELECT p.user_id, p.lesson_id, p.completed_at, ol.rank
+		FROM lesson_progress p
+		JOIN cohort c ON c.user_id = p.user_id
+		JOIN ordered_lessons ol ON ol.id = p.lesson_id
+		WHERE p.course_id = $1 AND p.is_completed
+	), per_student AS (
+		SELECT c.user_id, c.enrolled_at,
+			COUNT(cp.lesson_id) AS completed,
+			MAX(cp.rank) AS furthest_rank,
+			MAX(cp.completed_at) AS last_completed_at
+		FROM cohort c
+		LEFT JOIN completions cp ON cp.user_id = c.user_id
+		GROUP BY c.user_id, c.enrolled_at
+	), totals AS (
+		SELECT COUNT(*) AS lessons FROM ordered_lessons
+	)`
+
+func timeArg(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
+
+// FindEnrollmentsOverTime возвращает число записей когорты по периодам filter.Interval
+func (r *AnalyticsRepository) FindEnrollmentsOverTime(ctx context.Context, filter models.AnalyticsFilter) ([]*models.PeriodCount, error) {
+	query := `
+		SELECT date_trunc($4, e.created_at) AS period, COUNT(*)
+		FROM enrollments e
+		WHERE e.course_id = $1
+		  AND ($2::TIMESTAMP IS NULL OR e.created_at >= $2)
+		  AND ($3::TIMESTAMP IS NULL OR e.created_at < $3)
+		GROUP BY period
+		ORDER BY period`
+	rows, err := r.db.Query(ctx, query, filter.CourseID, timeArg(filter.From), timeArg(filter.To), filter.Interval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count enrollments over time: %w", err)
+	}
+	defer rows.Close()
+
+	periods := []*models.PeriodCount{}
+	for rows.Next() {
+		var period models.PeriodCount
+		if err := rows.Scan(&period.Period, &period.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan enrollment period: %w", err)
+		}
+		periods = append(periods, &period)
+	}
+	return periods, rows.Err()
+}
+
+// FindLessonFunnel возвращает по каждому уроку число завершивших его студентов и число студентов,
+// у которых это самый дальний завершённый урок, а курс не пройден (точка ухода)
+func (r *AnalyticsRepository) FindLessonFunnel(ctx context.Context, filter models.AnalyticsFilter) ([]*models.LessonFunnelEntry, error) {
+	query := analyticsCohort + `
+		SELECT ol.id, ol.rank, ol.title,
+			(SELECT COUNT(*) FROM completions cp WHERE cp.lesson_id = ol.id),
+			(SELECT COUNT(*) FROM per_student ps, totals t WHERE ps.furthest_rank = ol.rank AND ps.completed < t.lessons)
+		FROM ordered_lessons ol
+		ORDER BY ol.rank`
+	rows, err := r.db.Query(ctx, query, filter.CourseID, timeArg(filter.From), timeArg(filter.To))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build lesson funnel: %w", err)
+	}
+	defer rows.Close()
+
+	lessons := []*models.LessonFunnelEntry{}
+	for rows.Next() {
+		var lesson models.LessonFunnelEntry
+		if err := rows.Scan(&lesson.LessonID, &lesson.Position, &lesson.Title, &lesson.Completed, &lesson.DropOff); err != nil {
+			return nil, fmt.Errorf("failed to scan lesson funnel: %w", err)
+		}
+		lessons = append(lessons, &lesson)
+	}
+	return lessons, rows.Err()
+}
+
+// FindCompletionSummary возвращает размер когорты, число не начавших и завершивших курс
+// и медиану времени от записи до завершения последнего урока
+func (r *AnalyticsRepository) FindCompletionSummary(ctx context.Context, filter models.AnalyticsFilter) (*models.CompletionSummary, error) {
+	query := analyticsCohort + `
+		SELECT COUNT(*),
+			COUNT(*) FILTER (WHERE ps.completed = 0),
+			COUNT(*) FILTER (WHERE t.lessons > 0 AND ps.completed >= t.lessons),
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM ps.last_completed_at - ps.enrolled_at) / 3600)
+				FILTER (WHERE t.lessons > 0 AND ps.completed >= t.lessons)
+		FROM per_student ps
+		CROSS JOIN totals t`
+	var summary models.CompletionSummary
+	err := r.db.QueryRow(ctx, query, filter.CourseID, timeArg(filter.From), timeArg(filter.To)).
+		Scan(&summary.Students, &summary.NotStarted, &summary.CompletedStudents, &summary.MedianHoursToComplete)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize course completion: %w", err)
+	}
+	return &summary, nil
+}
+
+// FindProgressDistribution возвращает распределение студентов когорты по проценту пройденных уроков
+func (r *AnalyticsRepository) FindProgressDistribution(ctx context.Context, filter models.AnalyticsFilter) ([]*models.DistributionBucket, error) {
+	query := analyticsCohort + `
+		SELECT bucket, COUNT(*)
+		FROM (
+			SELECT CASE
+				WHEN t.lessons = 0 OR ps.completed = 0 THEN 0
+				WHEN ps.completed >= t.lessons THEN 5
+				ELSE 1 + LEAST(ps.completed * 4 / t.lessons, 3)
+			END AS bucket
+			FROM per_student ps
+			CROSS JOIN totals t
+		) buckets
+		GROUP BY bucket`
+	return r.queryBuckets(ctx, query, filter, progressBucketLabels)
+}
+
+// FindTimeToCompleteDistribution возвращает распределение завершивших курс по времени прохождения
+func (r *AnalyticsRepository) FindTimeToCompleteDistribution(ctx context.Context, filter models.AnalyticsFilter) ([]*models.DistributionBucket, error) {
+	query := analyticsCohort + `
+		SELECT bucket, COUNT(*)
+		FROM (
+			SELECT CASE
+				WHEN ps.last_completed_at - ps.enrolled_at < INTERVAL '1 day' THEN 0
+				WHEN ps.last_completed_at - ps.enrolled_at <= INTERVAL '7 days' THEN 1
+				WHEN ps.last_completed_at - ps.enrolled_at <= INTERVAL '30 days' THEN 2
+				WHEN ps.last_completed_at - ps.enrolled_at <= INTERVAL '90 days' THEN 3
+				ELSE 4
+			END AS bucket
+			FROM per_student ps
+			CROSS JOIN totals t
+			WHERE t.lessons > 0 AND ps.completed >= t.lessons
+		) buckets
+		GROUP BY bucket`
+	return r.queryBuckets(ctx, query, filter, timeToCompleteBucketLabels)
+}
+
+// FindMedianHoursBetweenLessons возвращает медиану интервала между соседними завершениями уроков одного студента
+func (r *AnalyticsRepository) FindMedianHoursBetweenLessons(ctx context.Context, filter models.AnalyticsFilter) (*float64, error) {
+	query := analyticsCohort + `
+		SELECT percentile_cont(0.5) WITHIN GROUP (ORDER BY hours)
+		FROM (
+			SELECT EXTRACT(EPOCH FROM completed_at - LAG(completed_at) OVER (PARTITION BY user_id ORDER BY completed_at)) / 3600 AS hours
+			FROM completions
+		) gaps
+		WHERE hours IS NOT NULL`
+	var median *float64
+	if err := r.db.QueryRow(ctx, query, filter.CourseID, timeArg(filter.From), timeArg(filter.To)).Scan(&median); err != nil {
+		return nil, fmt.Errorf("failed to calculate time between lessons: %w", err)
+	}
+	return median, nil
+}
+
+// queryBuckets выполняет запрос, возвращающий (номер столбца, количество), и заполняет все столбцы гистограммы
+func (r *AnalyticsRepository) queryBuckets(ctx context.Context, query string, filter models.AnalyticsFilter, labels []string) ([]*models.DistributionBucket, error) {
+	rows, err := r.db.Query(ctx, query, filter.CourseID, timeArg(filter.From), timeArg(filter.To))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build distribution: %w", err)
+	}
+	defer rows.Close()
+
+	buckets := make([]*models.DistributionBucket, len(labels))
+	for i, label := range labels {
+		buckets[i] = &models.DistributionBucket{Label: label}
+	}
+	for rows.Next() {
+		var index, count int
+		if err := rows.Scan(&index, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan distribution: %w", err)
+		}
+		if index >= 0 && index < len(buckets) {
+			buckets[index].Count = count
+		}
+	}
+	return buckets, rows.Err()
+}
@@ -0,0 +1,203 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+)
+
+type AnnouncementRepositoryInterface interface {
+	Create(ctx context.Context, announcement *models.Announcement) error
+	FindByID(ctx context.Context, id, userID int) (*models.Announcement, error)
+	FindByCourse(ctx context.Context, courseID, userID int, includeScheduled bool) ([]*models.Announcement, error)
+	FindFeed(ctx context.Context, userID int, unreadOnly bool) ([]*models.Announcement, error)
+	FindPendingEmails(ctx context.Context) ([]*models.Announcement, error)
+	ClaimEmail(ctx context.Context, id int) (bool, error)
+	Update(ctx context.Context, announcement *models.Announcement) error
+	Delete(ctx context.Context, id int) error
+	MarkRead(ctx context.Context, id, userID int) error
+	FindReads(ctx context.Context, id int) ([]*models.AnnouncementRead, error)
+}
+
+type AnnouncementRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAnnouncementRepository(db *pgxpool.Pool) *AnnouncementRepository {
+	return &AnnouncementRepository{db: db}
+}
+
+// announcementColumns ожидает ID текущего пользователя первым параметром запроса
+const announcementColumns = `a.id, a.course_id, c.name, a.author_id, u.username, a.title, a.body,
+		a.pinned, a.send_email, a.publish_at, a.emailed_at,
+		EXISTS (SELECT 1 FROM announcement_reads ar WHERE ar.announcement_id = a.id AND ar.user_id = $1),
+		(SELECT COUNT(*) FROM announcement_reads ar WHERE ar.announcement_id = a.id),
+		a.created_at, a.updated_at`
+
+const announcementFrom = ` FROM announcements a
+		JOIN courses c ON c.id = a.course_id
+		JOIN users u ON u.id = a.author_id`
+
+func announcementScanFields(announcement *models.Announcement) []any {
+	return []any{
+		&announcement.ID, &announcement.CourseID, &announcement.CourseName, &announcement.AuthorID, &announcement.AuthorName,
+		&announcement.Title, &announcement.Body,
+		&announcement.Pinned, &announcement.SendEmail, &announcement.PublishAt, &announcement.EmailedAt,
+		&announcement.IsRead, &announcement.ReadCount,
+		&announcement.CreatedAt, &announcement.UpdatedAt,
+	}
+}
+
+// Create добавляет объявление, без времени публикации - публикует сразу
+func (r *AnnouncementRepository) Create(ctx context.Context, announcement *models.Announcement) error {
+	query := `
+		INSERT INTO announcements (course_id, author_id, title, body, pinned, send_email, publish_at)
+		VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7, NOW()))
+		RETURNING id, publish_at, created_at, updated_at`
+	var publishAt any
+	if !announcement.PublishAt.IsZero() {
+		publishAt = announcement.PublishAt
+	}
+	err := r.db.QueryRow(ctx, query, announcement.CourseID, announcement.AuthorID, announcement.Title, announcement.Body,
+		announcement.Pinned, announcement.SendEmail, publishAt).
+		Scan(&announcement.ID, &announcement.PublishAt, &announcement.CreatedAt, &announcement.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create announcement: %w", err)
+	}
+	return nil
+}
+
+// FindByID ищет объявление; IsRead заполняется для пользователя userID
+func (r *AnnouncementRepository) FindByID(ctx context.Context, id, userID int) (*models.Announcement, error) {
+	var announcement models.Announcement
+	query := `SELECT ` + announcementColumns + announcementFrom + ` WHERE a.id = $2`
+	if err := r.db.QueryRow(ctx, query, userID, id).Scan(announcementScanFields(&announcement)...); err != nil {
+		return nil, fmt.Errorf("announcement not found: %w", err)
+	}
+	return &announcement, nil
+}
+
+// FindByCourse возвращает объявления курса: закреплённые первыми, затем новые.
+// Без includeScheduled - только опубликованные.
+func (r *AnnouncementRepository) FindByCourse(ctx context.Context, courseID, userID int, includeScheduled bool) ([]*models.Announcement, error) {
+	query := `SELECT ` + announcementColumns + announcementFrom + `
+		WHERE a.course_id = $2 AND ($3 OR a.publish_at <= NOW())
+		ORDER BY a.pinned DESC, a.publish_at DESC, a.id DESC`
+	return r.queryAnnouncements(ctx, query, userID, courseID, includeScheduled)
+}
+
+// FindFeed возвращает опубликованные объявления всех курсов, на которые записан пользователь
+func (r *AnnouncementRepository) FindFeed(ctx context.Context, userID int, unreadOnly bool) ([]*models.Announcement, error) {
+	query := `SELECT ` + announcementColumns + announcementFrom + `
+		JOIN enrollments e ON e.course_id = a.course_id AND e.user_id = $1
+		WHERE a.publish_at <= NOW()
+			AND (NOT $2 OR NOT EXISTS (SELECT 1 FROM announcement_reads ar WHERE ar.announcement_id = a.id AND ar.user_id = $1))
+		ORDER BY a.publish_at DESC, a.id DESC
+		LIMIT 100`
+	return r.queryAnnouncements(ctx, query, userID, unreadOnly)
+}
+
+// FindPendingEmails возвращает опубликованные объявления, письма по которым ещё не разосланы
+func (r *AnnouncementRepository) FindPendingEmails(ctx context.Context) ([]*models.Announcement, error) {
+	query := `SELECT ` + announcementColumns + announcementFrom + `
+		WHERE a.send_email AND a.emailed_at IS NULL AND a.publish_at <= NOW()
+		ORDER BY a.publish_at`
+	return r.queryAnnouncements(ctx, query, 0)
+}
+
+func (r *AnnouncementRepository) queryAnnouncements(ctx context.Context, query string, args ...any) ([]*models.Announcement, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch announcements: %w", err)
+	}
+	defer rows.Close()
+
+	var announcements []*models.Announcement
+	for rows.Next() {
+		var announcement models.Announcement
+		if err := rows.Scan(announcementScanFields(&announcement)...); err != nil {
+			return nil, fmt.Errorf("error scanning announcement: %w", err)
+		}
+		announcements = append(announcements, &announcement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return announcements, nil
+}
+
+// ClaimEmail отмечает рассылку объявления начатой. false - рассылку уже забрал другой обработчик.
+func (r *AnnouncementRepository) ClaimEmail(ctx context.Context, id int) (bool, error) {
+	commandTag, err := r.db.Exec(ctx, `UPDATE announcements SET emailed_at = NOW() WHERE id = $1 AND emailed_at IS NULL`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim announcement email: %w", err)
+	}
+	return commandTag.RowsAffected() == 1, nil
+}
+
+// Update обновляет текст, закрепление, рассылку и время публикации объявления
+func (r *AnnouncementRepository) Update(ctx context.Context, announcement *models.Announcement) error {
+	query := `
+		UPDATE announcements
+		SET title = $1, body = $2, pinned = $3, send_email = $4, publish_at = $5, updated_at = NOW()
+		WHERE id = $6
+		RETURNING updated_at`
+	err := r.db.QueryRow(ctx, query, announcement.Title, announcement.Body, announcement.Pinned, announcement.SendEmail,
+		announcement.PublishAt, announcement.ID).Scan(&announcement.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update announcement: %w", err)
+	}
+	return nil
+}
+
+// Delete удаляет объявление
+func (r *AnnouncementRepository) Delete(ctx context.Context, id int) error {
+	if _, err := r.db.Exec(ctx, `DELETE FROM announcements WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete announcement: %w", err)
+	}
+	return nil
+}
+
+// MarkRead отмечает объявление прочитанным, повторная отметка сохраняет первое время прочтения
+func (r *AnnouncementRepository) MarkRead(ctx context.Context, id, userID int) error {
+	query := `
+		INSERT INTO announcement_reads (announcement_id, user_id) VALUES ($1, $2)
+		ON CONFLICT (announcement_id, user_id) DO NOTHING`
+	if _, err := r.db.Exec(ctx, query, id, userID); err != nil {
+		return fmt.Errorf("failed to mark announcement read: %w", err)
+	}
+	return nil
+}
+
+// FindReads возвращает, кто и когда прочитал объявление
+func (r *AnnouncementRepository) FindReads(ctx context.Context, id int) ([]*models.AnnouncementRead, error) {
+	query := `
+		SELECT ar.user_id, u.username, ar.read_at
+		FROM announcement_reads ar JOIN users u ON u.id = ar.user_id
+		WHERE ar.announcement_id = $1
+		ORDER BY ar.read_at`
+	rows, err := r.db.Query(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch announcement reads: %w", err)
+	}
+	defer rows.Close()
+
+	var reads []*models.AnnouncementRead
+	for rows.Next() {
+		var read models.AnnouncementRead
+		if err := rows.Scan(&read.UserID, &read.Username, &read.ReadAt); err != nil {
+			return nil, fmt.Errorf("error scanning announcement read: %w", err)
+		}
+		reads = append(reads, &read)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return reads, nil
+}
@@ -0,0 +1,362 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+)
+
+type AssignmentRepositoryInterface interface {
+	FindByLesson(ctx context.Context, lessonID int) (*models.Assignment, error)
+	FindByCourse(ctx context.Context, courseID int) ([]*models.Assignment, error)
+	Save(ctx context.Context, assignment *models.Assignment) error
+	Delete(ctx context.Context, lessonID int) error
+	CreateSubmission(ctx context.Context, submission *models.AssignmentSubmission) error
+	FindSubmission(ctx context.Context, id int) (*models.AssignmentSubmission, error)
+	FindSubmissions(ctx context.Context, assignmentID int, userID *int) ([]*models.AssignmentSubmission, error)
+	FindGradingQueue(ctx context.Context, courseID int) ([]*models.AssignmentSubmission, error)
+	FindLatestGraded(ctx context.Context, assignmentID, userID int) (*models.AssignmentSubmission, error)
+	SaveGrade(ctx context.Context, submissionID int, grade *models.AssignmentGrade) error
+}
+
+type AssignmentRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAssignmentRepository(db *pgxpool.Pool) *AssignmentRepository {
+	return &AssignmentRepository{db: db}
+}
+
+const assignmentColumns = `a.id, l.course_id, a.lesson_id, a.title, a.instructions, a.instructions_html, a.due_at, a.allowed_extensions,
+	a.max_score, a.passing_score, a.require_passing, a.late_penalty_percent, a.max_late_days, a.created_at, a.updated_at`
+
+func assignmentScanFields(assignment *models.Assignment) []any {
+	return []any{
+		&assignment.ID, &assignment.CourseID, &assignment.LessonID, &assignment.Title, &assignment.Instructions, &assignment.InstructionsHTML,
+		&assignment.DueAt, &assignment.AllowedExtensions, &assignment.MaxScore, &assignment.PassingScore, &assignment.RequirePassing,
+		&assignment.LatePenaltyPercent, &assignment.MaxLateDays, &assignment.CreatedAt, &assignment.UpdatedAt,
+	}
+}
+
+// файл попытки берётся из медиатеки; если студент удалил файл, попытка остаётся без него
+const assignmentSubmissionColumns = `s.id, s.assignment_id, s.user_id, COALESCE(u.username, ''), s.attempt, f.id,
+	COALESCE(f.filename, ''), COALESCE(f.content_type, ''), COALESCE(f.size, 0), COALESCE(f.storage_key, ''), s.comment, s.days_late, s.submitted_at,
+	s.raw_score, s.penalty_percent, s.score, s.feedback, s.criteria_scores, s.graded_by, COALESCE(g.username, ''), s.graded_at`
+
+const assignmentSubmissionJoins = `
+	JOIN users u ON u.id = s.user_id
+	LEFT JOIN media_files f ON f.id = s.media_id
+	LEFT JOIN users g ON g.id = s.graded_by`
+
+// assignmentSubmissionRow собирает попытку и необязательную оценку из строки запроса
+type assignmentSubmissionRow struct {
+	submission     models.AssignmentSubmission
+	rawScore       *int
+	penaltyPercent *int
+	score          *float64
+	feedback       *string
+	criteria       []models.AssignmentCriterionScore
+	gradedBy       *int
+	graderName     string
+	gradedAt       *time.Time
+}
+
+func (row *assignmentSubmissionRow) scanFields() []any {
+	s := &row.submission
+	return []any{
+		&s.ID, &s.AssignmentID, &s.UserID, &s.UserName, &s.Attempt, &s.MediaID,
+		&s.Filename, &s.ContentType, &s.Size, &s.StorageKey, &s.Comment, &s.DaysLate, &s.SubmittedAt,
+		&row.rawScore, &row.penaltyPercent, &row.score, &row.feedback, &row.criteria, &row.gradedBy, &row.graderName, &row.gradedAt,
+	}
+}
+
+func (row *assignmentSubmissionRow) result() *models.AssignmentSubmission {
+	submission := row.submission
+	if row.gradedAt != nil {
+		grade := &models.AssignmentGrade{
+			Criteria:   row.criteria,
+			GradedBy:   row.gradedBy,
+			GraderName: row.graderName,
+			GradedAt:   *row.gradedAt,
+		}
+		if row.rawScore != nil {
+			grade.RawScore = *row.rawScore
+		}
+		if row.penaltyPercent != nil {
+			grade.PenaltyPercent = *row.penaltyPercent
+		}
+		if row.score != nil {
+			grade.Score = *row.score
+		}
+		if row.feedback != nil {
+			grade.Feedback = *row.feedback
+		}
+		submission.Grade = grade
+	}
+	return &submission
+}
+
+// FindByLesson возвращает задание урока с рубрикой; nil, если у урока нет задания
+func (r *AssignmentRepository) FindByLesson(ctx context.Context, lessonID int) (*models.Assignment, error) {
+	query := `SELECT ` + assignmentColumns + ` FROM assignments a JOIN lessons l ON l.id = a.lesson_id WHERE a.lesson_id = $1`
+	var assignment models.Assignment
+	err := r.db.QueryRow(ctx, query, lessonID).Scan(assignmentScanFields(&assignment)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find assignment: %w", err)
+	}
+	if assignment.Criteria, err = r.findCriteria(ctx, assignment.ID); err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+// FindByCourse возвращает задания уроков курса с рубриками
+func (r *AssignmentRepository) FindByCourse(ctx context.Context, courseID int) ([]*models.Assignment, error) {
+	query := `
+		SELECT ` + assignmentColumns + `
+		FROM assignments a
+		JOIN lessons l ON l.id = a.lesson_id
+		WHERE l.course_id = $1 AND l.deleted_at IS NULL
+		ORDER BY a.lesson_id`
+	rows, err := r.db.Query(ctx, query, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch assignments: %w", err)
+	}
+	defer rows.Close()
+
+	assignments := []*models.Assignment{}
+	for rows.Next() {
+		var assignment models.Assignment
+		if err := rows.Scan(assignmentScanFields(&assignment)...); err != nil {
+			return nil, fmt.Errorf("failed to scan assignment: %w", err)
+		}
+		assignments = append(assignments, &assignment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for _, assignment := range assignments {
+		if assignment.Criteria, err = r.findCriteria(ctx, assignment.ID); err != nil {
+			return nil, err
+		}
+	}
+	return assignments, nil
+}
+
+func (r *AssignmentRepository) findCriteria(ctx context.Context, assignmentID int) ([]*models.AssignmentCriterion, error) {
+	query := `
+		SELECT id, position, title, description, max_points
+		FROM assignment_criteria
+		WHERE assignment_id = $1
+		ORDER BY position`
+	rows, err := r.db.Query(ctx, query, assignmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch assignment criteria: %w", err)
+	}
+	defer rows.Close()
+
+	criteria := []*models.AssignmentCriterion{}
+	for rows.Next() {
+		var criterion models.AssignmentCriterion
+		if err := rows.Scan(&criterion.ID, &criterion.Position, &criterion.Title, &criterion.Description, &criterion.MaxPoints); err != nil {
+			return nil, fmt.Errorf("failed to scan assignment criterion: %w", err)
+		}
+		criteria = append(criteria, &criterion)
+	}
+	return criteria, rows.Err()
+}
+
+// Save создаёт или заменяет задание урока; рубрика заменяется целиком.
+// Снимки критериев в уже выставленных оценках не меняются.
+func (r *AssignmentRepository) Save(ctx context.Context, assignment *models.Assignment) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO assignments (lesson_id, title, instructions, instructions_html, due_at, allowed_extensions,
+			max_score, passing_score, require_passing, late_penalty_percent, max_late_days)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (lesson_id) DO UPDATE SET
+			title = EXCLUDED.title,
+			instructions = EXCLUDED.instructions,
+			instructions_html = EXCLUDED.instructions_html,
+			due_at = EXCLUDED.due_at,
+			allowed_extensions = EXCLUDED.allowed_extensions,
+			max_score = EXCLUDED.max_score,
+			passing_score = EXCLUDED.passing_score,
+			require_passing = EXCLUDED.require_passing,
+			late_penalty_percent = EXCLUDED.late_penalty_percent,
+			max_late_days = EXCLUDED.max_late_days,
+			updated_at = NOW()
+		RETURNING id, created_at, updated_at`
+	err = tx.QueryRow(ctx, query, assignment.LessonID, assignment.Title, assignment.Instructions, assignment.InstructionsHTML,
+		assignment.DueAt, assignment.AllowedExtensions, assignment.MaxScore, assignment.PassingScore, assignment.RequirePassing,
+		assignment.LatePenaltyPercent, assignment.MaxLateDays).
+		Scan(&assignment.ID, &assignment.CreatedAt, &assignment.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save assignment: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM assignment_criteria WHERE assignment_id = $1`, assignment.ID); err != nil {
+		return fmt.Errorf("failed to replace assignment criteria: %w", err)
+	}
+	for i, criterion := range assignment.Criteria {
+		criterion.Position = i + 1
+		err := tx.QueryRow(ctx,
+			`INSERT INTO assignment_criteria (assignment_id, position, title, description, max_points) VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+			assignment.ID, criterion.Position, criterion.Title, criterion.Description, criterion.MaxPoints,
+		).Scan(&criterion.ID)
+		if err != nil {
+			return fmt.Errorf("failed to create assignment criterion: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Delete удаляет задание урока вместе с рубрикой и сданными работами
+func (r *AssignmentRepository) Delete(ctx context.Context, lessonID int) error {
+	commandTag, err := r.db.Exec(ctx, `DELETE FROM assignments WHERE lesson_id = $1`, lessonID)
+	if err != nil {
+		return fmt.Errorf("failed to delete assignment: %w", err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return errors.New("assignment not found")
+	}
+	return nil
+}
+
+// CreateSubmission сохраняет новую попытку студента со следующим номером
+func (r *AssignmentRepository) CreateSubmission(ctx context.Context, submission *models.AssignmentSubmission) error {
+	query := `
+		INSERT INTO assignment_submissions (assignment_id, user_id, attempt, media_id, comment, days_late)
+		SELECT $1, $2, COALESCE(MAX(attempt), 0) + 1, $3, $4, $5
+		FROM assignment_submissions
+		WHERE assignment_id = $1 AND user_id = $2
+		RETURNING id, attempt, submitted_at`
+	err := r.db.QueryRow(ctx, query, submission.AssignmentID, submission.UserID, submission.MediaID, submission.Comment, submission.DaysLate).
+		Scan(&submission.ID, &submission.Attempt, &submission.SubmittedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create assignment submission: %w", err)
+	}
+	return nil
+}
+
+// FindSubmission ищет попытку по ID вместе с файлом и оценкой
+func (r *AssignmentRepository) FindSubmission(ctx context.Context, id int) (*models.AssignmentSubmission, error) {
+	query := `SELECT ` + assignmentSubmissionColumns + ` FROM assignment_submissions s` + assignmentSubmissionJoins + ` WHERE s.id = $1`
+	var row assignmentSubmissionRow
+	if err := r.db.QueryRow(ctx, query, id).Scan(row.scanFields()...); err != nil {
+		return nil, fmt.Errorf("assignment submission not found: %w", err)
+	}
+	return row.result(), nil
+}
+
+// FindSubmissions возвращает попытки по заданию, новые первыми; userID ограничивает выборку одним студентом
+func (r *AssignmentRepository) FindSubmissions(ctx context.Context, assignmentID int, userID *int) ([]*models.AssignmentSubmission, error) {
+	query := `
+		SELECT ` + assignmentSubmissionColumns + `
+		FROM assignment_submissions s` + assignmentSubmissionJoins + `
+		WHERE s.assignment_id = $1 AND ($2::int IS NULL OR s.user_id = $2)
+		ORDER BY s.submitted_at DESC, s.id DESC`
+	return r.querySubmissions(ctx, query, assignmentID, userID)
+}
+
+// FindGradingQueue возвращает последние непроверенные попытки по заданиям курса, самые старые первыми.
+// Попытка, после которой студент сдал новую, в очередь не попадает.
+func (r *AssignmentRepository) FindGradingQueue(ctx context.Context, courseID int) ([]*models.AssignmentSubmission, error) {
+	query := `
+		SELECT ` + assignmentSubmissionColumns + `, a.lesson_id, a.title
+		FROM assignment_submissions s` + assignmentSubmissionJoins + `
+		JOIN assignments a ON a.id = s.assignment_id
+		JOIN lessons l ON l.id = a.lesson_id
+		WHERE l.course_id = $1 AND l.deleted_at IS NULL AND s.graded_at IS NULL
+			AND s.attempt = (
+				SELECT MAX(latest.attempt) FROM assignment_submissions latest
+				WHERE latest.assignment_id = s.assignment_id AND latest.user_id = s.user_id
+			)
+		ORDER BY s.submitted_at, s.id`
+	rows, err := r.db.Query(ctx, query, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch grading queue: %w", err)
+	}
+	defer rows.Close()
+
+	submissions := []*models.AssignmentSubmission{}
+	for rows.Next() {
+		var row assignmentSubmissionRow
+		fields := append(row.scanFields(), &row.submission.LessonID, &row.submission.AssignmentTitle)
+		if err := rows.Scan(fields...); err != nil {
+			return nil, fmt.Errorf("failed to scan assignment submission: %w", err)
+		}
+		submissions = append(submissions, row.result())
+	}
+	return submissions, rows.Err()
+}
+
+// FindLatestGraded возвращает последнюю оценённую попытку студента; nil, если оценок ещё нет
+func (r *AssignmentRepository) FindLatestGraded(ctx context.Context, assignmentID, userID int) (*models.AssignmentSubmission, error) {
+	query := `
+		SELECT ` + assignmentSubmissionColumns + `
+		FROM assignment_submissions s` + assignmentSubmissionJoins + `
+		WHERE s.assignment_id = $1 AND s.user_id = $2 AND s.graded_at IS NOT NULL
+		ORDER BY s.attempt DESC
+		LIMIT 1`
+	var row assignmentSubmissionRow
+	if err := r.db.QueryRow(ctx, query, assignmentID, userID).Scan(row.scanFields()...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find graded submission: %w", err)
+	}
+	return row.result(), nil
+}
+
+// SaveGrade выставляет или заменяет оценку попытки
+func (r *AssignmentRepository) SaveGrade(ctx context.Context, submissionID int, grade *models.AssignmentGrade) error {
+	query := `
+		UPDATE assignment_submissions
+		SET raw_score = $1, penalty_percent = $2, score = $3, feedback = $4, criteria_scores = $5, graded_by = $6, graded_at = NOW()
+		WHERE id = $7
+		RETURNING graded_at`
+	err := r.db.QueryRow(ctx, query, grade.RawScore, grade.PenaltyPercent, grade.Score, grade.Feedback, grade.Criteria, grade.GradedBy, submissionID).
+		Scan(&grade.GradedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save assignment grade: %w", err)
+	}
+	return nil
+}
+
+func (r *AssignmentRepository) querySubmissions(ctx context.Context, query string, args ...any) ([]*models.AssignmentSubmission, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch assignment submissions: %w", err)
+	}
+	defer rows.Close()
+
+	submissions := []*models.AssignmentSubmission{}
+	for rows.Next() {
+		var row assignmentSubmissionRow
+		if err := rows.Scan(row.scanFields()...); err != nil {
+			return nil, fmt.Errorf("failed to scan assignment submission: %w", err)
+		}
+		submissions = append(submissions, row.result())
+	}
+	return submissions, rows.Err()
+}
@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+)
+
+type CategoryRepositoryInterface interface {
+	Create(ctx context.Context, category *models.Category) error
+	FindByID(ctx context.Context, id int) (*models.Category, error)
+	FindAll(ctx context.Context) ([]*models.Category, error)
+	Update(ctx context.Context, category *models.Category) error
+	Delete(ctx context.Context, id int) error
+	IsDescendant(ctx context.Context, categoryID, ancestorID int) (bool, error)
+}
+
+type CategoryRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCategoryRepository(db *pgxpool.Pool) *CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+// Create добавляет новую категорию
+func (r *CategoryRepository) Create(ctx context.Context, category *models.Category) error {
+	query := `
+		INSERT INTO categories (name, description, parent_id)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at, updated_at`
+	err := r.db.QueryRow(ctx, query, category.Name, category.Description, category.ParentID).
+		Scan(&category.ID, &category.CreatedAt, &category.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create category: %w", err)
+	}
+	return nil
+}
+
+// FindByID ищет категорию по ID
+func (r *CategoryRepository) FindByID(ctx context.Context, id int) (*models.Category, error) {
+	var category models.Category
+	query := `
+		SELECT id, name, description, parent_id, created_at, updated_at
+		FROM categories WHERE id = $1`
+	err := r.db.QueryRow(ctx, query, id).
+		Scan(&category.ID, &category.Name, &category.Description, &category.ParentID, &category.CreatedAt, &category.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("category not found: %w", err)
+	}
+	return &category, nil
+}
+
+// FindAll возвращает все категории плоским списком
+func (r *CategoryRepository) FindAll(ctx context.Context) ([]*models.Category, error) {
+	query := `
+		SELECT id, name, description, parent_id, created_at, updated_at
+		FROM categories ORDER BY name`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch categories: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []*models.Category
+	for rows.Next() {
+		var category models.Category
+		if err := rows.Scan(&category.ID, &category.Name, &category.Description, &category.ParentID, &category.CreatedAt, &category.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning category: %w", err)
+		}
+		categories = append(categories, &category)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return categories, nil
+}
+
+// Update обновляет категорию
+func (r *CategoryRepository) Update(ctx context.Context, category *models.Category) error {
+	query := `
+		UPDATE categories
+		SET name = $1, description = $2, parent_id = $3, updated_at = NOW()
+		WHERE id = $4`
+	_, err := r.db.Exec(ctx, query, category.Name, category.Description, category.ParentID, category.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update category: %w", err)
+	}
+	return nil
+}
+
+// Delete удаляет категорию; подкатегории поднимаются на уровень выше, у курсов категория сбрасывается
+func (r *CategoryRepository) Delete(ctx context.Context, id int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		UPDATE categories SET parent_id = (SELECT parent_id FROM categories WHERE id = $1), updated_at = NOW()
+		WHERE parent_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to move subcategories: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM categories WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// IsDescendant проверяет, находится ли categoryID в поддереве ancestorID (включая сам ancestorID)
+func (r *CategoryRepository) IsDescendant(ctx context.Context, categoryID, ancestorID int) (bool, error) {
+	query := `
+		WITH RECURSIVE tree AS (
+			SELECT id FROM categories WHERE id = $1
+			UNION ALL
+			SELECT c.id FROM categories c JOIN tree ON c.parent_id = tree.id
+		)
+		SELECT EXISTS (SELECT 1 FROM tree WHERE id = $2)`
+	var exists bool
+	if err := r.db.QueryRow(ctx, query, ancestorID, categoryID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check category tree: %w", err)
+	}
+	return exists, nil
+}
@@ -3,7 +3,7 @@ package repositories
 import (
 	"context"
 	"gitlab.com/w0ikid/study-platform/internal/domain/models"
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"log"
 )
 
@@ -16,10 +16,10 @@ type CertificateRepositoryInterface interface {
 }
 
 type CertificateRepository struct {
-	db *pgx.Conn
+	db *pgxpool.Pool
 }
 
-func NewCertificateRepository(db *pgx.Conn) *CertificateRepository {
+func NewCertificateRepository(db *pgxpool.Pool) *CertificateRepository {
 	return &CertificateRepository{db: db}
 }
 
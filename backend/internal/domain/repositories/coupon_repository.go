@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+)
+
+type CouponRepositoryInterface interface {
+	Create(ctx context.Context, coupon *models.Coupon) error
+	FindByID(ctx context.Context, id int) (*models.Coupon, error)
+	FindByCode(ctx context.Context, code string) (*models.Coupon, error)
+	FindAll(ctx context.Context) ([]*models.Coupon, error)
+	FindByCourse(ctx context.Context, courseID int) ([]*models.Coupon, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type CouponRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCouponRepository(db *pgxpool.Pool) *CouponRepository {
+	return &CouponRepository{db: db}
+}
+
+const couponColumns = `id, code, type, value, course_id, expires_at, max_uses, used_count, created_by, created_at`
+
+func couponScanFields(coupon *models.Coupon) []any {
+	return []any{
+		&coupon.ID, &coupon.Code, &coupon.Type, &coupon.Value, &coupon.CourseID,
+		&coupon.ExpiresAt, &coupon.MaxUses, &coupon.UsedCount, &coupon.CreatedBy, &coupon.CreatedAt,
+	}
+}
+
+// Create добавляет купон
+func (r *CouponRepository) Create(ctx context.Context, coupon *models.Coupon) error {
+	query := `
+		INSERT INTO coupons (code, type, value, course_id, expires_at, max_uses, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, used_count, created_at`
+	err := r.db.QueryRow(ctx, query, coupon.Code, coupon.Type, coupon.Value, coupon.CourseID,
+		coupon.ExpiresAt, coupon.MaxUses, coupon.CreatedBy).
+		Scan(&coupon.ID, &coupon.UsedCount, &coupon.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create coupon: %w", err)
+	}
+	return nil
+}
+
+// FindByID ищет купон по ID
+func (r *CouponRepository) FindByID(ctx context.Context, id int) (*models.Coupon, error) {
+	var coupon models.Coupon
+	query := `SELECT ` + couponColumns + ` FROM coupons WHERE id = $1`
+	if err := r.db.QueryRow(ctx, query, id).Scan(couponScanFields(&coupon)...); err != nil {
+		return nil, fmt.Errorf("coupon not found: %w", err)
+	}
+	return &coupon, nil
+}
+
+// FindByCode ищет купон по коду без учёта регистра, nil - если купона нет
+func (r *CouponRepository) FindByCode(ctx context.Context, code string) (*models.Coupon, error) {
+	var coupon models.Coupon
+	query := `SELECT ` + couponColumns + ` FROM coupons WHERE UPPER(code) = UPPER($1)`
+	err := r.db.QueryRow(ctx, query, code).Scan(couponScanFields(&coupon)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch coupon: %w", err)
+	}
+	return &coupon, nil
+}
+
+// FindAll возвращает все купоны
+func (r *CouponRepository) FindAll(ctx context.Context) ([]*models.Coupon, error) {
+	return r.queryCoupons(ctx, `SELECT `+couponColumns+` FROM coupons ORDER BY id`)
+}
+
+// FindByCourse возвращает купоны, привязанные к курсу
+func (r *CouponRepository) FindByCourse(ctx context.Context, courseID int) ([]*models.Coupon, error) {
+	return r.queryCoupons(ctx, `SELECT `+couponColumns+` FROM coupons WHERE course_id = $1 ORDER BY id`, courseID)
+}
+
+func (r *CouponRepository) queryCoupons(ctx context.Context, query string, args ...any) ([]*models.Coupon, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch coupons: %w", err)
+	}
+	defer rows.Close()
+
+	var coupons []*models.Coupon
+	for rows.Next() {
+		var coupon models.Coupon
+		if err := rows.Scan(couponScanFields(&coupon)...); err != nil {
+			return nil, fmt.Errorf("error scanning coupon: %w", err)
+		}
+		coupons = append(coupons, &coupon)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return coupons, nil
+}
+
+// Delete удаляет купон, в заказах остаётся его код
+func (r *CouponRepository) Delete(ctx context.Context, id int) error {
+	if _, err := r.db.Exec(ctx, `DELETE FROM coupons WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete coupon: %w", err)
+	}
+	return nil
+}
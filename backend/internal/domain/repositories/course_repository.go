@@ -2,9 +2,12 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"gitlab.com/w0ikid/study-platform/internal/domain/models"
 )
 
@@ -12,29 +15,65 @@ type CourseRepositoryInterface interface {
 	Create(ctx context.Context, course *models.Course) error
 	FindByID(ctx context.Context, id int) (*models.Course, error)
 	FindAll(ctx context.Context) ([]models.Course, error)
+	FindByFilter(ctx context.Context, filter models.CourseFilter) ([]models.Course, error)
 	Update(ctx context.Context, course *models.Course) error
 	Delete(ctx context.Context, id int) error
+	AddTags(ctx context.Context, courseID int, tags []string) error
+	RemoveTag(ctx context.Context, courseID int, tag string) error
+	FindAllTags(ctx context.Context) ([]models.TagCount, error)
+	FindPrerequisites(ctx context.Context, courseID int) ([]*models.CoursePrerequisite, error)
+	SetPrerequisites(ctx context.Context, courseID int, prerequisiteIDs []int, minUserLevel int) error
+	HasPrerequisitePath(ctx context.Context, fromCourseID, toCourseID int) (bool, error)
+	FindMissingPrerequisites(ctx context.Context, courseID, userID int) ([]*models.CoursePrerequisite, error)
+	FindStaff(ctx context.Context, courseID int) ([]*models.CourseStaff, error)
+	FindStaffMember(ctx context.Context, courseID, userID int) (*models.CourseStaff, error)
+	AddStaff(ctx context.Context, member *models.CourseStaff) error
+	RemoveStaff(ctx context.Context, courseID, userID int) error
+	TransferOwnership(ctx context.Context, courseID, newOwnerID int) error
+	FindRoster(ctx context.Context, courseID int) ([]*models.CourseRosterEntry, error)
+	UpdateSchedule(ctx context.Context, course *models.Course) error
+	UpdatePrice(ctx context.Context, course *models.Course) error
+	Clone(ctx context.Context, sourceID int, clone *models.Course, includeEnrollments bool) error
+	FindByName(ctx context.Context, name string) ([]models.Course, error)
+	CreateWithContent(ctx context.Context, course *models.Course, prerequisiteIDs []int, modules []*models.Module, lessons []*models.Lesson) error
 }
 
 type CourseRepository struct {
-	db *pgx.Conn
+	db *pgxpool.Pool
 }
 
-func NewCourseRepository(db *pgx.Conn) *CourseRepository {
+func NewCourseRepository(db *pgxpool.Pool) *CourseRepository {
 	return &CourseRepository{db: db}
 }
 
-// Create добавляет новый курс
+// Create добавляет новый курс, создатель становится его владельцем в команде курса
 func (r *CourseRepository) Create(ctx context.Context, course *models.Course) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
-		INSERT INTO courses (name, description, image_url ,teacher_id, status)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO courses (name, description, image_url ,teacher_id, status, category_id, level, duration_minutes, language)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id, created_at, updated_at`
-	err := r.db.QueryRow(ctx, query, course.Name, course.Description, course.ImageUrl ,course.TeacherID, course.Status).
+	err = tx.QueryRow(ctx, query, course.Name, course.Description, course.ImageUrl ,course.TeacherID, course.Status,
+		course.CategoryID, course.Level, course.DurationMinutes, course.Language).
 		Scan(&course.ID, &course.CreatedAt, &course.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to create course: %w", err)
 	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO course_staff (course_id, user_id, role) VALUES ($1, $2, $3)`,
+		course.ID, course.TeacherID, models.CourseStaffRoleOwner)
+	if err != nil {
+		return fmt.Errorf("failed to add course owner: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return nil
 }
 
@@ -42,10 +81,9 @@ func (r *CourseRepository) Create(ctx context.Context, course *models.Course) er
 func (r *CourseRepository) FindByID(ctx context.Context, id int) (*models.Course, error) {
 	var course models.Course
 	query := `
-		SELECT id, name, description, image_url ,teacher_id, status, created_at, updated_at
-		FROM courses WHERE id = $1`
-	err := r.db.QueryRow(ctx, query, id).
-		Scan(&course.ID, &course.Name, &course.Description, &course.ImageUrl, &course.TeacherID, &course.Status, &course.CreatedAt, &course.UpdatedAt)
+		SELECT ` + courseColumns + `
+		FROM courses c WHERE c.id = $1`
+	err := r.db.QueryRow(ctx, query, id).Scan(courseScanFields(&course)...)
 	if err != nil {
 		return nil, fmt.Errorf("course not found: %w", err)
 	}
@@ -55,9 +93,94 @@ func (r *CourseRepository) FindByID(ctx context.Context, id int) (*models.Course
 // FindAll возвращает список всех курсов
 func (r *CourseRepository) FindAll(ctx context.Context) ([]models.Course, error) {
 	query := `
-		SELECT id, name, description, image_url,teacher_id, status, created_at, updated_at
-		FROM courses`
-	rows, err := r.db.Query(ctx, query)
+		SELECT ` + courseColumns + `
+		FROM courses c`
+	return r.queryCourses(ctx, query)
+}
+
+// FindByFilter возвращает курсы каталога, подходящие под фильтр
+func (r *CourseRepository) FindByFilter(ctx context.Context, filter models.CourseFilter) ([]models.Course, error) {
+	query, args := catalogQuery(filter)
+	return r.queryCourses(ctx, query, args...)
+}
+
+// catalogQuery строит запрос каталога и его аргументы по фильтру
+func catalogQuery(filter models.CourseFilter) (string, []any) {
+	var conditions []string
+	var args []any
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	// черновики в каталоге не показываются
+	conditions = append(conditions, "c.status IS DISTINCT FROM "+arg(models.CourseStatusDraft))
+	if filter.Search != "" {
+		p := arg("%" + filter.Search + "%")
+		conditions = append(conditions, fmt.Sprintf("(c.name ILIKE %s OR c.description ILIKE %s)", p, p))
+	}
+	if filter.CategoryID != 0 {
+		// категория вместе со всеми подкатегориями
+		conditions = append(conditions, fmt.Sprintf(`c.category_id IN (
+			WITH RECURSIVE tree AS (
+				SELECT id FROM categories WHERE id = %s
+				UNION ALL
+				SELECT cat.id FROM categories cat JOIN tree ON cat.parent_id = tree.id
+			) SELECT id FROM tree)`, arg(filter.CategoryID)))
+	}
+	for _, tag := range filter.Tags {
+		conditions = append(conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM course_tags ct WHERE ct.course_id = c.id AND ct.tag = %s)", arg(tag)))
+	}
+	if filter.Level != nil {
+		conditions = append(conditions, "c.level = "+arg(*filter.Level))
+	}
+	if filter.Language != "" {
+		conditions = append(conditions, "LOWER(c.language) = LOWER("+arg(filter.Language)+")")
+	}
+	if filter.MinDuration > 0 {
+		conditions = append(conditions, "c.duration_minutes >= "+arg(filter.MinDuration))
+	}
+	if filter.MaxDuration > 0 {
+		conditions = append(conditions, "c.duration_minutes <= "+arg(filter.MaxDuration))
+	}
+
+	query := `
+		SELECT ` + courseColumns + `
+		FROM courses c
+		WHERE ` + strings.Join(conditions, " AND ")
+	if filter.Sort == models.CourseSortRating {
+		query += "\n\t\tORDER BY c.rating_average DESC, c.rating_count DESC, c.id"
+	} else {
+		query += "\n\t\tORDER BY c.id"
+	}
+	return query, args
+}
+
+// courseColumns - колонки курса в порядке courseScanFields, теги собираются в массив
+const courseColumns = `c.id, c.name, c.description, c.image_url, c.teacher_id, c.status,
+		c.category_id, c.level, c.duration_minutes, c.language, c.min_user_level,
+		c.rating_average, c.rating_count,
+		c.starts_at, c.ends_at, c.enrollment_opens_at, c.enrollment_closes_at, c.capacity,
+		c.price_cents, c.currency,
+		(SELECT COUNT(*) FROM enrollments e WHERE e.course_id = c.id),
+		COALESCE((SELECT array_agg(ct.tag ORDER BY ct.tag) FROM course_tags ct WHERE ct.course_id = c.id), '{}'),
+		c.created_at, c.updated_at`
+
+func courseScanFields(course *models.Course) []any {
+	return []any{
+		&course.ID, &course.Name, &course.Description, &course.ImageUrl, &course.TeacherID, &course.Status,
+		&course.CategoryID, &course.Level, &course.DurationMinutes, &course.Language, &course.MinUserLevel,
+		&course.RatingAverage, &course.RatingCount,
+		&course.StartsAt, &course.EndsAt, &course.EnrollmentOpensAt, &course.EnrollmentClosesAt, &course.Capacity,
+		&course.PriceCents, &course.Currency,
+		&course.EnrolledCount,
+		&course.Tags,
+		&course.CreatedAt, &course.UpdatedAt,
+	}
+}
+
+func (r *CourseRepository) queryCourses(ctx context.Context, query string, args ...any) ([]models.Course, error) {
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch courses: %w", err)
 	}
@@ -66,12 +189,16 @@ func (r *CourseRepository) FindAll(ctx context.Context) ([]models.Course, error)
 	var courses []models.Course
 	for rows.Next() {
 		var course models.Course
-		if err := rows.Scan(&course.ID, &course.Name, &course.Description, &course.ImageUrl ,&course.TeacherID, &course.Status, &course.CreatedAt, &course.UpdatedAt); err != nil {
+		if err := rows.Scan(courseScanFields(&course)...); err != nil {
 			return nil, fmt.Errorf("error scanning course: %w", err)
 		}
 		courses = append(courses, course)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
 	return courses, nil
 }
 
@@ -79,15 +206,41 @@ func (r *CourseRepository) FindAll(ctx context.Context) ([]models.Course, error)
 func (r *CourseRepository) Update(ctx context.Context, course *models.Course) error {
 	query := `
 		UPDATE courses 
-		SET name = $1, description = $2, status = $3, updated_at = NOW()
-		WHERE id = $4`
-	_, err := r.db.Exec(ctx, query, course.Name, course.Description, course.Status, course.ID)
+		SET name = $1, description = $2, status = $3, image_url = $4,
+			category_id = $5, level = $6, duration_minutes = $7, language = $8, updated_at = NOW()
+		WHERE id = $9`
+	_, err := r.db.Exec(ctx, query, course.Name, course.Description, course.Status, course.ImageUrl,
+		course.CategoryID, course.Level, course.DurationMinutes, course.Language, course.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update course: %w", err)
 	}
 	return nil
 }
 
+// UpdateSchedule сохраняет даты курса, окно записи и лимит мест
+func (r *CourseRepository) UpdateSchedule(ctx context.Context, course *models.Course) error {
+	query := `
+		UPDATE courses
+		SET starts_at = $1, ends_at = $2, enrollment_opens_at = $3, enrollment_closes_at = $4, capacity = $5, updated_at = NOW()
+		WHERE id = $6`
+	_, err := r.db.Exec(ctx, query, course.StartsAt, course.EndsAt, course.EnrollmentOpensAt, course.EnrollmentClosesAt,
+		course.Capacity, course.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update course schedule: %w", err)
+	}
+	return nil
+}
+
+// UpdatePrice сохраняет цену курса
+func (r *CourseRepository) UpdatePrice(ctx context.Context, course *models.Course) error {
+	query := `UPDATE courses SET price_cents = $1, currency = $2, updated_at = NOW() WHERE id = $3`
+	_, err := r.db.Exec(ctx, query, course.PriceCents, course.Currency, course.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update course price: %w", err)
+	}
+	return nil
+}
+
 // Delete удаляет курс
 func (r *CourseRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM courses WHERE id = $1`
@@ -97,3 +250,615 @@ func (r *CourseRepository) Delete(ctx context.Context, id int) error {
 	}
 	return nil
 }
+
+// AddTags добавляет теги курсу, уже существующие теги пропускаются
+func (r *CourseRepository) AddTags(ctx context.Context, courseID int, tags []string) error {
+	query := `
+		INSERT INTO course_tags (course_id, tag)
+		SELECT $1, UNNEST($2::text[])
+		ON CONFLICT (course_id, tag) DO NOTHING`
+	_, err := r.db.Exec(ctx, query, courseID, tags)
+	if err != nil {
+		return fmt.Errorf("failed to add course tags: %w", err)
+	}
+	return nil
+}
+
+// RemoveTag удаляет тег курса
+func (r *CourseRepository) RemoveTag(ctx context.Context, courseID int, tag string) error {
+	query := `DELETE FROM course_tags WHERE course_id = $1 AND tag = $2`
+	_, err := r.db.Exec(ctx, query, courseID, tag)
+	if err != nil {
+		return fmt.Errorf("failed to remove course tag: %w", err)
+	}
+	return nil
+}
+
+// FindAllTags возвращает все теги с количеством курсов, самые популярные первыми
+func (r *CourseRepository) FindAllTags(ctx context.Context) ([]models.TagCount, error) {
+	query := `
+		SELECT tag, COUNT(*) FROM course_tags
+		GROUP BY tag
+		ORDER BY COUNT(*) DESC, tag`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []models.TagCount
+	for rows.Next() {
+		var tag models.TagCount
+		if err := rows.Scan(&tag.Tag, &tag.Count); err != nil {
+			return nil, fmt.Errorf("error scanning tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// FindPrerequisites возвращает курсы-пререквизиты курса
+func (r *CourseRepository) FindPrerequisites(ctx context.Context, courseID int) ([]*models.CoursePrerequisite, error) {
+	query := `
+		SELECT cp.course_id, cp.prerequisite_id, c.name
+		FROM course_prerequisites cp
+		JOIN courses c ON c.id = cp.prerequisite_id
+		WHERE cp.course_id = $1
+		ORDER BY c.name`
+	return r.queryPrerequisites(ctx, query, courseID)
+}
+
+// SetPrerequisites заменяет список пререквизитов и минимальный уровень курса одной транзакцией
+func (r *CourseRepository) SetPrerequisites(ctx context.Context, courseID int, prerequisiteIDs []int, minUserLevel int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM course_prerequisites WHERE course_id = $1`, courseID); err != nil {
+		return fmt.Errorf("failed to clear prerequisites: %w", err)
+	}
+
+	if len(prerequisiteIDs) > 0 {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO course_prerequisites (course_id, prerequisite_id)
+			SELECT $1, UNNEST($2::int[])
+			ON CONFLICT DO NOTHING`, courseID, prerequisiteIDs)
+		if err != nil {
+			return fmt.Errorf("failed to save prerequisites: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE courses SET min_user_level = $1, updated_at = NOW() WHERE id = $2`, minUserLevel, courseID)
+	if err != nil {
+		return fmt.Errorf("failed to update course level requirement: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// HasPrerequisitePath проверяет, зависит ли fromCourseID (прямо или через цепочку) от toCourseID
+func (r *CourseRepository) HasPrerequisitePath(ctx context.Context, fromCourseID, toCourseID int) (bool, error) {
+	query := `
+		WITH RECURSIVE chain AS (
+			SELECT prerequisite_id FROM course_prerequisites WHERE course_id = $1
+			UNION
+			SELECT cp.prerequisite_id FROM course_prerequisites cp JOIN chain ON cp.course_id = chain.prerequisite_id
+		)
+		SELECT EXISTS (SELECT 1 FROM chain WHERE prerequisite_id = $2)`
+	var exists bool
+	if err := r.db.QueryRow(ctx, query, fromCourseID, toCourseID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check prerequisite chain: %w", err)
+	}
+	return exists, nil
+}
+
+// FindMissingPrerequisites возвращает пререквизиты, которые пользователь ещё не завершил:
+// нет ни завершённой записи на курс, ни сертификата
+func (r *CourseRepository) FindMissingPrerequisites(ctx context.Context, courseID, userID int) ([]*models.CoursePrerequisite, error) {
+	query := `
+		SELECT cp.course_id, cp.prerequisite_id, c.name
+		FROM course_prerequisites cp
+		JOIN courses c ON c.id = cp.prerequisite_id
+		WHERE cp.course_id = $1
+			AND NOT EXISTS (
+				SELECT 1 FROM enrollments e
+				WHERE e.user_id = $2 AND e.course_id = cp.prerequisite_id AND e.status = 'completed')
+			AND NOT EXISTS (
+				SELECT 1 FROM certificates cert
+				WHERE cert.user_id = $2 AND cert.course_id = cp.prerequisite_id)
+		ORDER BY c.name`
+	return r.queryPrerequisites(ctx, query, courseID, userID)
+}
+
+func (r *CourseRepository) queryPrerequisites(ctx context.Context, query string, args ...any) ([]*models.CoursePrerequisite, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch prerequisites: %w", err)
+	}
+	defer rows.Close()
+
+	prerequisites := []*models.CoursePrerequisite{}
+	for rows.Next() {
+		var prerequisite models.CoursePrerequisite
+		if err := rows.Scan(&prerequisite.CourseID, &prerequisite.PrerequisiteID, &prerequisite.Name); err != nil {
+			return nil, fmt.Errorf("error scanning prerequisite: %w", err)
+		}
+		prerequisites = append(prerequisites, &prerequisite)
+	}
+
+	return prerequisites, rows.Err()
+}
+
+const courseStaffColumns = `cs.course_id, cs.user_id, u.username, COALESCE(u.name, ''), COALESCE(u.surname, ''), cs.role, cs.created_at`
+
+// FindStaff возвращает команду курса: владельца, соавторов и ассистентов
+func (r *CourseRepository) FindStaff(ctx context.Context, courseID int) ([]*models.CourseStaff, error) {
+	query := `
+		SELECT ` + courseStaffColumns + `
+		FROM course_staff cs
+		JOIN users u ON u.id = cs.user_id
+		WHERE cs.course_id = $1
+		ORDER BY CASE cs.role WHEN 'owner' THEN 0 WHEN 'co_teacher' THEN 1 ELSE 2 END, u.username`
+	rows, err := r.db.Query(ctx, query, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch course staff: %w", err)
+	}
+	defer rows.Close()
+
+	var staff []*models.CourseStaff
+	for rows.Next() {
+		var member models.CourseStaff
+		if err := rows.Scan(&member.CourseID, &member.UserID, &member.Username, &member.Name, &member.Surname, &member.Role, &member.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning course staff: %w", err)
+		}
+		staff = append(staff, &member)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return staff, nil
+}
+
+// FindStaffMember возвращает участника команды курса или nil, если пользователь в неё не входит
+func (r *CourseRepository) FindStaffMember(ctx context.Context, courseID, userID int) (*models.CourseStaff, error) {
+	var member models.CourseStaff
+	query := `
+		SELECT ` + courseStaffColumns + `
+		FROM course_staff cs
+		JOIN users u ON u.id = cs.user_id
+		WHERE cs.course_id = $1 AND cs.user_id = $2`
+	err := r.db.QueryRow(ctx, query, courseID, userID).
+		Scan(&member.CourseID, &member.UserID, &member.Username, &member.Name, &member.Surname, &member.Role, &member.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find course staff member: %w", err)
+	}
+	return &member, nil
+}
+
+// AddStaff добавляет пользователя в команду курса, повторное добавление меняет роль
+func (r *CourseRepository) AddStaff(ctx context.Context, member *models.CourseStaff) error {
+	query := `
+		INSERT INTO course_staff (course_id, user_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (course_id, user_id) DO UPDATE SET role = EXCLUDED.role
+		RETURNING created_at`
+	err := r.db.QueryRow(ctx, query, member.CourseID, member.UserID, member.Role).Scan(&member.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add course staff member: %w", err)
+	}
+	return nil
+}
+
+// RemoveStaff исключает пользователя из команды курса (владельца удалить нельзя)
+func (r *CourseRepository) RemoveStaff(ctx context.Context, courseID, userID int) error {
+	query := `DELETE FROM course_staff WHERE course_id = $1 AND user_id = $2 AND role <> $3`
+	commandTag, err := r.db.Exec(ctx, query, courseID, userID, models.CourseStaffRoleOwner)
+	if err != nil {
+		return fmt.Errorf("failed to remove course staff member: %w", err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return fmt.Errorf("user %d is not a staff member of course %d", userID, courseID)
+	}
+	return nil
+}
+
+// TransferOwnership передаёт курс другому пользователю, прежний владелец остаётся соавтором
+func (r *CourseRepository) TransferOwnership(ctx context.Context, courseID, newOwnerID int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `UPDATE course_staff SET role = $2 WHERE course_id = $1 AND role = $3`,
+		courseID, models.CourseStaffRoleCoTeacher, models.CourseStaffRoleOwner)
+	if err != nil {
+		return fmt.Errorf("failed to demote previous owner: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO course_staff (course_id, user_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (course_id, user_id) DO UPDATE SET role = EXCLUDED.role`,
+		courseID, newOwnerID, models.CourseStaffRoleOwner)
+	if err != nil {
+		return fmt.Errorf("failed to set new owner: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE courses SET teacher_id = $1, updated_at = NOW() WHERE id = $2`, newOwnerID, courseID)
+	if err != nil {
+		return fmt.Errorf("failed to update course teacher: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// FindRoster возвращает студентов курса с количеством завершённых уроков
+func (r *CourseRepository) FindRoster(ctx context.Context, courseID int) ([]*models.CourseRosterEntry, error) {
+	query := `
+		SELECT e.id, u.id, u.username, COALESCE(u.name, ''), COALESCE(u.surname, ''), u.email,
+			COALESCE(e.status, ''), e.created_at,
+			COUNT(lp.id) FILTER (WHERE lp.is_completed)
+		FROM enrollments e
+		JOIN users u ON u.id = e.user_id
+		LEFT JOIN lesson_progress lp ON lp.user_id = e.user_id AND lp.course_id = e.course_id
+		WHERE e.course_id = $1
+		GROUP BY e.id, u.id
+		ORDER BY u.username`
+	rows, err := r.db.Query(ctx, query, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch course roster: %w", err)
+	}
+	defer rows.Close()
+
+	var roster []*models.CourseRosterEntry
+	for rows.Next() {
+		var entry models.CourseRosterEntry
+		if err := rows.Scan(
+			&entry.EnrollmentID,
+			&entry.UserID,
+			&entry.Username,
+			&entry.Name,
+			&entry.Surname,
+			&entry.Email,
+			&entry.Status,
+			&entry.EnrolledAt,
+			&entry.CompletedLessons,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning course roster: %w", err)
+		}
+		roster = append(roster, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return roster, nil
+}
+
+// Clone копирует курс одной транзакцией: сам курс с ценой (владелец - clone.TeacherID), теги,
+// пререквизиты и уроки. Записи студентов копируются только при includeEnrollments,
+// прогресс и сертификаты не переносятся.
+func (r *CourseRepository) Clone(ctx context.Context, sourceID int, clone *models.Course, includeEnrollments bool) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO courses (name, description, image_url, teacher_id, status, category_id, level, duration_minutes, language, min_user_level, price_cents, currency)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id, created_at, updated_at`
+	err = tx.QueryRow(ctx, query, clone.Name, clone.Description, clone.ImageUrl, clone.TeacherID, clone.Status,
+		clone.CategoryID, clone.Level, clone.DurationMinutes, clone.Language, clone.MinUserLevel, clone.PriceCents, clone.Currency).
+		Scan(&clone.ID, &clone.CreatedAt, &clone.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create course copy: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO course_staff (course_id, user_id, role) VALUES ($1, $2, $3)`,
+		clone.ID, clone.TeacherID, models.CourseStaffRoleOwner)
+	if err != nil {
+		return fmt.Errorf("failed to add course owner: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO course_tags (course_id, tag)
+		SELECT $1, tag FROM course_tags WHERE course_id = $2`, clone.ID, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to copy course tags: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO course_prerequisites (course_id, prerequisite_id)
+		SELECT $1, prerequisite_id FROM course_prerequisites WHERE course_id = $2`, clone.ID, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to copy course prerequisites: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT id, title, description, position FROM course_modules WHERE course_id = $1 ORDER BY position, id`, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to get course modules: %w", err)
+	}
+	var modules []*models.Module
+	for rows.Next() {
+		module := &models.Module{}
+		if err := rows.Scan(&module.ID, &module.Title, &module.Description, &module.Position); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan course module: %w", err)
+		}
+		modules = append(modules, module)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get course modules: %w", err)
+	}
+
+	oldModuleIDs := make([]int, 0, len(modules))
+	newModuleIDs := make([]int, 0, len(modules))
+	for _, module := range modules {
+		var newID int
+		err = tx.QueryRow(ctx, `
+			INSERT INTO course_modules (course_id, title, description, position)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id`, clone.ID, module.Title, module.Description, module.Position).Scan(&newID)
+		if err != nil {
+			return fmt.Errorf("failed to copy course module: %w", err)
+		}
+		oldModuleIDs = append(oldModuleIDs, module.ID)
+		newModuleIDs = append(newModuleIDs, newID)
+	}
+
+	rows, err = tx.Query(ctx, `SELECT id FROM lessons WHERE course_id = $1 AND deleted_at IS NULL ORDER BY id`, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to get lessons: %w", err)
+	}
+	var oldLessonIDs []int
+	for rows.Next() {
+		var lessonID int
+		if err := rows.Scan(&lessonID); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan lesson: %w", err)
+		}
+		oldLessonIDs = append(oldLessonIDs, lessonID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get lessons: %w", err)
+	}
+
+	newLessonIDs := make([]int, 0, len(oldLessonIDs))
+	for _, lessonID := range oldLessonIDs {
+		var newID int
+		err = tx.QueryRow(ctx, `
+			INSERT INTO lessons (course_id, title, content, content_html, video_url, video_duration, module_id, position)
+			SELECT $1, l.title, l.content, l.content_html, l.video_url, l.video_duration, m.new_id, l.position
+			FROM lessons l
+			LEFT JOIN unnest($3::int[], $4::int[]) AS m(old_id, new_id) ON m.old_id = l.module_id
+			WHERE l.id = $2
+			RETURNING id`, clone.ID, lessonID, oldModuleIDs, newModuleIDs).Scan(&newID)
+		if err != nil {
+			return fmt.Errorf("failed to copy lesson: %w", err)
+		}
+		newLessonIDs = append(newLessonIDs, newID)
+	}
+
+	// блоки уроков копируются без отметок о завершении
+	_, err = tx.Exec(ctx, `
+		INSERT INTO lesson_blocks (lesson_id, position, type, data, html, required)
+		SELECT m.new_id, b.position, b.type, b.data, b.html, b.required
+		FROM lesson_blocks b
+		JOIN unnest($1::int[], $2::int[]) AS m(old_id, new_id) ON m.old_id = b.lesson_id`, oldLessonIDs, newLessonIDs)
+	if err != nil {
+		return fmt.Errorf("failed to copy lesson blocks: %w", err)
+	}
+
+	// вложения ссылаются на те же файлы медиатеки, счётчики скачиваний начинаются заново
+	_, err = tx.Exec(ctx, `
+		INSERT INTO lesson_attachments (lesson_id, media_id, title, watermark)
+		SELECT m.new_id, a.media_id, a.title, a.watermark
+		FROM lesson_attachments a
+		JOIN unnest($1::int[], $2::int[]) AS m(old_id, new_id) ON m.old_id = a.lesson_id
+		ORDER BY a.id`, oldLessonIDs, newLessonIDs)
+	if err != nil {
+		return fmt.Errorf("failed to copy lesson attachments: %w", err)
+	}
+
+	// правила открытия уроков; урок-условие заменяется его копией
+	_, err = tx.Exec(ctx, `
+		INSERT INTO lesson_release_rules (lesson_id, type, release_at, days_after_enrollment, after_lesson_id)
+		SELECT m.new_id, r.type, r.release_at, r.days_after_enrollment, after_m.new_id
+		FROM lesson_release_rules r
+		JOIN unnest($1::int[], $2::int[]) AS m(old_id, new_id) ON m.old_id = r.lesson_id
+		LEFT JOIN unnest($1::int[], $2::int[]) AS after_m(old_id, new_id) ON after_m.old_id = r.after_lesson_id`, oldLessonIDs, newLessonIDs)
+	if err != nil {
+		return fmt.Errorf("failed to copy lesson release rules: %w", err)
+	}
+
+	// задания копируются вместе с рубрикой, сданные работы остаются в исходном курсе
+	_, err = tx.Exec(ctx, `
+		INSERT INTO assignments (lesson_id, title, instructions, instructions_html, due_at, allowed_extensions,
+			max_score, passing_score, require_passing, late_penalty_percent, max_late_days)
+		SELECT m.new_id, a.title, a.instructions, a.instructions_html, a.due_at, a.allowed_extensions,
+			a.max_score, a.passing_score, a.require_passing, a.late_penalty_percent, a.max_late_days
+		FROM assignments a
+		JOIN unnest($1::int[], $2::int[]) AS m(old_id, new_id) ON m.old_id = a.lesson_id`, oldLessonIDs, newLessonIDs)
+	if err != nil {
+		return fmt.Errorf("failed to copy assignments: %w", err)
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO assignment_criteria (assignment_id, position, title, description, max_points)
+		SELECT na.id, c.position, c.title, c.description, c.max_points
+		FROM assignment_criteria c
+		JOIN assignments a ON a.id = c.assignment_id
+		JOIN unnest($1::int[], $2::int[]) AS m(old_id, new_id) ON m.old_id = a.lesson_id
+		JOIN assignments na ON na.lesson_id = m.new_id`, oldLessonIDs, newLessonIDs)
+	if err != nil {
+		return fmt.Errorf("failed to copy assignment criteria: %w", err)
+	}
+
+	// история правок не копируется: текущее содержимое становится первой ревизией копии
+	_, err = tx.Exec(ctx, `
+		INSERT INTO lesson_revisions (lesson_id, number, author_id, title, content, video_url)
+		SELECT l.id, 1, $2, l.title, l.content, COALESCE(l.video_url, '')
+		FROM lessons l
+		WHERE l.id = ANY($1::int[])`, newLessonIDs, clone.TeacherID)
+	if err != nil {
+		return fmt.Errorf("failed to create lesson revisions: %w", err)
+	}
+
+	// переносятся только учащиеся и завершившие курс студенты; в копии все они активны
+	// и занимают места, поэтому перенос не может превысить лимит мест копии
+	if includeEnrollments {
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO enrollments (user_id, course_id, status, created_at, updated_at)
+			SELECT user_id, $1, 'active', NOW(), NOW() FROM enrollments
+			WHERE course_id = $2 AND user_id <> $3 AND status IN ('active', 'completed')`, clone.ID, sourceID, clone.TeacherID)
+		if err != nil {
+			return fmt.Errorf("failed to copy enrollments: %w", err)
+		}
+		if clone.Capacity != nil && int(tag.RowsAffected()) > *clone.Capacity {
+			return fmt.Errorf("validation failed: %d students do not fit into the course capacity of %d", tag.RowsAffected(), *clone.Capacity)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// FindByName ищет курсы по точному названию без учёта регистра
+func (r *CourseRepository) FindByName(ctx context.Context, name string) ([]models.Course, error) {
+	query := `
+		SELECT ` + courseColumns + `
+		FROM courses c
+		WHERE LOWER(c.name) = LOWER($1)
+		ORDER BY c.id`
+	return r.queryCourses(ctx, query, name)
+}
+
+// CreateWithContent создаёт курс вместе с тегами, пререквизитами, модулями и уроками одной транзакцией.
+// ModuleID уроков ссылается на ID из переданных модулей; после вставки в модели записываются новые ID.
+func (r *CourseRepository) CreateWithContent(ctx context.Context, course *models.Course, prerequisiteIDs []int, modules []*models.Module, lessons []*models.Lesson) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO courses (name, description, image_url, teacher_id, status, category_id, level, duration_minutes, language, min_user_level)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at, updated_at`
+	err = tx.QueryRow(ctx, query, course.Name, course.Description, course.ImageUrl, course.TeacherID, course.Status,
+		course.CategoryID, course.Level, course.DurationMinutes, course.Language, course.MinUserLevel).
+		Scan(&course.ID, &course.CreatedAt, &course.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create course: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO course_staff (course_id, user_id, role) VALUES ($1, $2, $3)`,
+		course.ID, course.TeacherID, models.CourseStaffRoleOwner)
+	if err != nil {
+		return fmt.Errorf("failed to add course owner: %w", err)
+	}
+
+	for _, tag := range course.Tags {
+		_, err = tx.Exec(ctx, `INSERT INTO course_tags (course_id, tag) VALUES ($1, $2) ON CONFLICT DO NOTHING`, course.ID, tag)
+		if err != nil {
+			return fmt.Errorf("failed to add course tag: %w", err)
+		}
+	}
+
+	for _, prerequisiteID := range prerequisiteIDs {
+		_, err = tx.Exec(ctx, `INSERT INTO course_prerequisites (course_id, prerequisite_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			course.ID, prerequisiteID)
+		if err != nil {
+			return fmt.Errorf("failed to add course prerequisite: %w", err)
+		}
+	}
+
+	moduleIDs := make(map[int]int, len(modules))
+	for _, module := range modules {
+		sourceID := module.ID
+		module.CourseID = course.ID
+		err = tx.QueryRow(ctx, `
+			INSERT INTO course_modules (course_id, title, description, position)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, created_at, updated_at`,
+			module.CourseID, module.Title, module.Description, module.Position).
+			Scan(&module.ID, &module.CreatedAt, &module.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create course module: %w", err)
+		}
+		moduleIDs[sourceID] = module.ID
+	}
+
+	positions := make(map[int]int)
+	for _, lesson := range lessons {
+		lesson.CourseID = course.ID
+		moduleKey := 0
+		if lesson.ModuleID != nil {
+			newID, ok := moduleIDs[*lesson.ModuleID]
+			if !ok {
+				return fmt.Errorf("failed to create lesson: unknown module %d", *lesson.ModuleID)
+			}
+			lesson.ModuleID = &newID
+			moduleKey = newID
+		}
+		positions[moduleKey]++
+		lesson.Position = positions[moduleKey]
+
+		err = tx.QueryRow(ctx, `
+			INSERT INTO lessons (course_id, title, content, content_html, video_url, video_duration, module_id, position)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id, created_at, updated_at`,
+			lesson.CourseID, lesson.Title, lesson.Content, lesson.ContentHTML, lesson.VideoURL, lesson.VideoDuration, lesson.ModuleID, lesson.Position).
+			Scan(&lesson.ID, &lesson.CreatedAt, &lesson.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create lesson: %w", err)
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO lesson_revisions (lesson_id, number, author_id, title, content, video_url)
+			VALUES ($1, 1, $2, $3, $4, $5)`,
+			lesson.ID, course.TeacherID, lesson.Title, lesson.Content, lesson.VideoURL)
+		if err != nil {
+			return fmt.Errorf("failed to create lesson revision: %w", err)
+		}
+
+		for i, block := range lesson.Blocks {
+			block.LessonID = lesson.ID
+			block.Position = i + 1
+			err = tx.QueryRow(ctx, `
+				INSERT INTO lesson_blocks (lesson_id, position, type, data, html, required)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				RETURNING id, created_at, updated_at`,
+				block.LessonID, block.Position, block.Type, block.Data, block.HTML, block.Required).
+				Scan(&block.ID, &block.CreatedAt, &block.UpdatedAt)
+			if err != nil {
+				return fmt.Errorf("failed to create lesson block: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -1 +1,44 @@
 package repositories
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+)
+
+func TestCatalogQuery(t *testing.T) {
+	t.Run("Drafts are excluded without filters", func(t *testing.T) {
+		query, args := catalogQuery(models.CourseFilter{})
+
+		assert.Contains(t, query, "WHERE c.status IS DISTINCT FROM $1")
+		assert.Equal(t, []any{models.CourseStatusDraft}, args)
+		assert.Contains(t, query, "ORDER BY c.id")
+	})
+
+	t.Run("Filters are combined with draft exclusion", func(t *testing.T) {
+		level := 2
+		query, args := catalogQuery(models.CourseFilter{
+			Search:      "go",
+			CategoryID:  4,
+			Tags:        []string{"backend", "web"},
+			Level:       &level,
+			Language:    "EN",
+			MinDuration: 30,
+			MaxDuration: 600,
+			Sort:        models.CourseSortRating,
+		})
+
+		assert.Contains(t, query, "c.status IS DISTINCT FROM $1 AND (c.name ILIKE $2 OR c.description ILIKE $2)")
+		assert.Contains(t, query, "SELECT id FROM categories WHERE id = $3")
+		assert.Contains(t, query, "ct.tag = $4")
+		assert.Contains(t, query, "ct.tag = $5")
+		assert.Contains(t, query, "c.level = $6")
+		assert.Contains(t, query, "LOWER(c.language) = LOWER($7)")
+		assert.Contains(t, query, "c.duration_minutes >= $8")
+		assert.Contains(t, query, "c.duration_minutes <= $9")
+		assert.Contains(t, query, "ORDER BY c.rating_average DESC, c.rating_count DESC, c.id")
+		assert.Equal(t, []any{models.CourseStatusDraft, "%go%", 4, "backend", "web", 2, "EN", 30, 600}, args)
+	})
+}
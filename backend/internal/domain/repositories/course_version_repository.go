@@ -0,0 +1,173 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+)
+
+type CourseVersionRepositoryInterface interface {
+	Create(ctx context.Context, version *models.CourseVersion) error
+	FindByID(ctx context.Context, id int) (*models.CourseVersion, error)
+	FindLatest(ctx context.Context, courseID int) (*models.CourseVersion, error)
+	FindByCourse(ctx context.Context, courseID int) ([]*models.CourseVersion, error)
+	PinEnrollment(ctx context.Context, userID, courseID int) (*int, error)
+	UpgradeEnrollment(ctx context.Context, userID, courseID, versionID int, resetLessonIDs []int) error
+}
+
+type CourseVersionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCourseVersionRepository(db *pgxpool.Pool) *CourseVersionRepository {
+	return &CourseVersionRepository{db: db}
+}
+
+const courseVersionColumns = `id, course_id, version, notes, lesson_count, published_by, published_at`
+
+func courseVersionScanFields(version *models.CourseVersion) []any {
+	return []any{
+		&version.ID, &version.CourseID, &version.Version, &version.Notes, &version.LessonCount,
+		&version.PublishedBy, &version.PublishedAt,
+	}
+}
+
+// Create публикует новую версию курса со следующим номером. Студенты, записанные
+// до первой публикации, закрепляются за этой версией.
+func (r *CourseVersionRepository) Create(ctx context.Context, version *models.CourseVersion) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// блокировка курса сериализует нумерацию версий
+	if _, err := tx.Exec(ctx, `SELECT id FROM courses WHERE id = $1 FOR UPDATE`, version.CourseID); err != nil {
+		return fmt.Errorf("failed to lock course: %w", err)
+	}
+
+	query := `
+		INSERT INTO course_versions (course_id, version, notes, content, lesson_count, published_by)
+		VALUES ($1, (SELECT COALESCE(MAX(version), 0) + 1 FROM course_versions WHERE course_id = $1), $2, $3, $4, $5)
+		RETURNING id, version, published_at`
+	err = tx.QueryRow(ctx, query, version.CourseID, version.Notes, version.Content, version.LessonCount, version.PublishedBy).
+		Scan(&version.ID, &version.Version, &version.PublishedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create course version: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE enrollments SET version_id = $1 WHERE course_id = $2 AND version_id IS NULL`,
+		version.ID, version.CourseID)
+	if err != nil {
+		return fmt.Errorf("failed to pin enrollments: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// FindByID возвращает версию вместе со снимком содержимого
+func (r *CourseVersionRepository) FindByID(ctx context.Context, id int) (*models.CourseVersion, error) {
+	var version models.CourseVersion
+	query := `SELECT ` + courseVersionColumns + `, content FROM course_versions WHERE id = $1`
+	err := r.db.QueryRow(ctx, query, id).Scan(append(courseVersionScanFields(&version), &version.Content)...)
+	if err != nil {
+		return nil, fmt.Errorf("course version not found: %w", err)
+	}
+	return &version, nil
+}
+
+// FindLatest возвращает последнюю версию курса со снимком, nil - если курс ещё не публиковался
+func (r *CourseVersionRepository) FindLatest(ctx context.Context, courseID int) (*models.CourseVersion, error) {
+	var version models.CourseVersion
+	query := `SELECT ` + courseVersionColumns + `, content FROM course_versions
+		WHERE course_id = $1 ORDER BY version DESC LIMIT 1`
+	err := r.db.QueryRow(ctx, query, courseID).Scan(append(courseVersionScanFields(&version), &version.Content)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch course version: %w", err)
+	}
+	return &version, nil
+}
+
+// FindByCourse возвращает версии курса без снимков, новые первыми
+func (r *CourseVersionRepository) FindByCourse(ctx context.Context, courseID int) ([]*models.CourseVersion, error) {
+	query := `SELECT ` + courseVersionColumns + ` FROM course_versions
+		WHERE course_id = $1 ORDER BY version DESC`
+	rows, err := r.db.Query(ctx, query, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch course versions: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []*models.CourseVersion
+	for rows.Next() {
+		var version models.CourseVersion
+		if err := rows.Scan(courseVersionScanFields(&version)...); err != nil {
+			return nil, fmt.Errorf("error scanning course version: %w", err)
+		}
+		versions = append(versions, &version)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return versions, nil
+}
+
+// PinEnrollment возвращает версию, закреплённую за студентом. Если студент ещё не закреплён,
+// он закрепляется за последней опубликованной версией. nil - студент не записан на курс
+// или курс ещё не публиковался.
+func (r *CourseVersionRepository) PinEnrollment(ctx context.Context, userID, courseID int) (*int, error) {
+	var versionID *int
+	query := `
+		UPDATE enrollments SET version_id = COALESCE(version_id,
+			(SELECT id FROM course_versions WHERE course_id = $2 ORDER BY version DESC LIMIT 1))
+		WHERE user_id = $1 AND course_id = $2
+		RETURNING version_id`
+	err := r.db.QueryRow(ctx, query, userID, courseID).Scan(&versionID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to pin course version: %w", err)
+	}
+	return versionID, nil
+}
+
+// UpgradeEnrollment переводит студента на другую версию курса и сбрасывает прогресс по изменившимся урокам
+func (r *CourseVersionRepository) UpgradeEnrollment(ctx context.Context, userID, courseID, versionID int, resetLessonIDs []int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `UPDATE enrollments SET version_id = $1, updated_at = NOW() WHERE user_id = $2 AND course_id = $3`,
+		versionID, userID, courseID)
+	if err != nil {
+		return fmt.Errorf("failed to upgrade course version: %w", err)
+	}
+
+	if len(resetLessonIDs) > 0 {
+		_, err = tx.Exec(ctx, `DELETE FROM lesson_progress WHERE user_id = $1 AND course_id = $2 AND lesson_id = ANY($3)`,
+			userID, courseID, resetLessonIDs)
+		if err != nil {
+			return fmt.Errorf("failed to reset lesson progress: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,313 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+)
+
+type DiscussionRepositoryInterface interface {
+	CreateThread(ctx context.Context, thread *models.DiscussionThread) error
+	FindThreadByID(ctx context.Context, id, userID int) (*models.DiscussionThread, error)
+	FindThreads(ctx context.Context, courseID int, lessonID *int, userID int) ([]*models.DiscussionThread, error)
+	UpdateThread(ctx context.Context, thread *models.DiscussionThread, editorID int) error
+	DeleteThread(ctx context.Context, id int) error
+	SetAcceptedPost(ctx context.Context, threadID int, postID *int) error
+	VoteThread(ctx context.Context, threadID, userID int, upvote bool) error
+	CreatePost(ctx context.Context, post *models.DiscussionPost) error
+	FindPostByID(ctx context.Context, id, userID int) (*models.DiscussionPost, error)
+	FindPosts(ctx context.Context, threadID, userID int) ([]*models.DiscussionPost, error)
+	UpdatePost(ctx context.Context, post *models.DiscussionPost, editorID int) error
+	DeletePost(ctx context.Context, post *models.DiscussionPost) error
+	VotePost(ctx context.Context, postID, userID int, upvote bool) error
+	FindEdits(ctx context.Context, threadID int, postID *int) ([]*models.DiscussionEdit, error)
+}
+
+type DiscussionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewDiscussionRepository(db *pgxpool.Pool) *DiscussionRepository {
+	return &DiscussionRepository{db: db}
+}
+
+// threadColumns ожидает ID текущего пользователя первым параметром запроса
+const threadColumns = `t.id, t.course_id, t.lesson_id, t.author_id, u.username, t.title, t.body, t.accepted_post_id,
+		(SELECT COUNT(*) FROM discussion_thread_votes v WHERE v.thread_id = t.id),
+		EXISTS (SELECT 1 FROM discussion_thread_votes v WHERE v.thread_id = t.id AND v.user_id = $1),
+		(SELECT COUNT(*) FROM discussion_posts p WHERE p.thread_id = t.id),
+		EXISTS (SELECT 1 FROM discussion_edits e WHERE e.thread_id = t.id AND e.post_id IS NULL),
+		t.last_activity_at, t.created_at, t.updated_at
+	FROM discussion_threads t
+	JOIN users u ON u.id = t.author_id`
+
+func threadScanFields(thread *models.DiscussionThread) []any {
+	return []any{
+		&thread.ID, &thread.CourseID, &thread.LessonID, &thread.AuthorID, &thread.AuthorName, &thread.Title, &thread.Body, &thread.AcceptedPostID,
+		&thread.Upvotes, &thread.Upvoted, &thread.ReplyCount, &thread.Edited,
+		&thread.LastActivityAt, &thread.CreatedAt, &thread.UpdatedAt,
+	}
+}
+
+// postColumns ожидает ID текущего пользователя первым параметром запроса
+const postColumns = `p.id, p.thread_id, p.parent_id, p.author_id, u.username, p.body,
+		(SELECT COUNT(*) FROM discussion_post_votes v WHERE v.post_id = p.id),
+		EXISTS (SELECT 1 FROM discussion_post_votes v WHERE v.post_id = p.id AND v.user_id = $1),
+		t.accepted_post_id IS NOT DISTINCT FROM p.id,
+		EXISTS (SELECT 1 FROM discussion_edits e WHERE e.post_id = p.id),
+		p.created_at, p.updated_at
+	FROM discussion_posts p
+	JOIN discussion_threads t ON t.id = p.thread_id
+	JOIN users u ON u.id = p.author_id`
+
+func postScanFields(post *models.DiscussionPost) []any {
+	return []any{
+		&post.ID, &post.ThreadID, &post.ParentID, &post.AuthorID, &post.AuthorName, &post.Body,
+		&post.Upvotes, &post.Upvoted, &post.IsAccepted, &post.Edited,
+		&post.CreatedAt, &post.UpdatedAt,
+	}
+}
+
+// CreateThread создаёт тему обсуждения
+func (r *DiscussionRepository) CreateThread(ctx context.Context, thread *models.DiscussionThread) error {
+	query := `
+		INSERT INTO discussion_threads (course_id, lesson_id, author_id, title, body)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, last_activity_at, created_at, updated_at`
+	err := r.db.QueryRow(ctx, query, thread.CourseID, thread.LessonID, thread.AuthorID, thread.Title, thread.Body).
+		Scan(&thread.ID, &thread.LastActivityAt, &thread.CreatedAt, &thread.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create discussion thread: %w", err)
+	}
+	return nil
+}
+
+// FindThreadByID ищет тему; Upvoted заполняется для пользователя userID
+func (r *DiscussionRepository) FindThreadByID(ctx context.Context, id, userID int) (*models.DiscussionThread, error) {
+	var thread models.DiscussionThread
+	query := `SELECT ` + threadColumns + ` WHERE t.id = $2`
+	if err := r.db.QueryRow(ctx, query, userID, id).Scan(threadScanFields(&thread)...); err != nil {
+		return nil, fmt.Errorf("discussion thread not found: %w", err)
+	}
+	return &thread, nil
+}
+
+// FindThreads возвращает темы курса, начиная с последних обновлённых; с lessonID - только темы урока
+func (r *DiscussionRepository) FindThreads(ctx context.Context, courseID int, lessonID *int, userID int) ([]*models.DiscussionThread, error) {
+	query := `SELECT ` + threadColumns + `
+		WHERE t.course_id = $2 AND ($3::INT IS NULL OR t.lesson_id = $3)
+		ORDER BY t.last_activity_at DESC`
+	rows, err := r.db.Query(ctx, query, userID, courseID, lessonID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find discussion threads: %w", err)
+	}
+	defer rows.Close()
+
+	threads := []*models.DiscussionThread{}
+	for rows.Next() {
+		var thread models.DiscussionThread
+		if err := rows.Scan(threadScanFields(&thread)...); err != nil {
+			return nil, fmt.Errorf("failed to scan discussion thread: %w", err)
+		}
+		threads = append(threads, &thread)
+	}
+	return threads, rows.Err()
+}
+
+// UpdateThread сохраняет новые заголовок и текст темы, предыдущая редакция уходит в историю
+func (r *DiscussionRepository) UpdateThread(ctx context.Context, thread *models.DiscussionThread, editorID int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO discussion_edits (thread_id, editor_id, title, body)
+		SELECT id, $2, title, body FROM discussion_threads WHERE id = $1`, thread.ID, editorID)
+	if err != nil {
+		return fmt.Errorf("failed to save discussion thread history: %w", err)
+	}
+
+	err = tx.QueryRow(ctx, `
+		UPDATE discussion_threads SET title = $2, body = $3, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`, thread.ID, thread.Title, thread.Body).Scan(&thread.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update discussion thread: %w", err)
+	}
+	thread.Edited = true
+
+	return tx.Commit(ctx)
+}
+
+// DeleteThread удаляет тему вместе с ответами
+func (r *DiscussionRepository) DeleteThread(ctx context.Context, id int) error {
+	if _, err := r.db.Exec(ctx, `DELETE FROM discussion_threads WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete discussion thread: %w", err)
+	}
+	return nil
+}
+
+// SetAcceptedPost отмечает принятый ответ темы; nil снимает отметку
+func (r *DiscussionRepository) SetAcceptedPost(ctx context.Context, threadID int, postID *int) error {
+	if _, err := r.db.Exec(ctx, `UPDATE discussion_threads SET accepted_post_id = $2 WHERE id = $1`, threadID, postID); err != nil {
+		return fmt.Errorf("failed to set accepted post: %w", err)
+	}
+	return nil
+}
+
+// VoteThread ставит или снимает голос пользователя за тему
+func (r *DiscussionRepository) VoteThread(ctx context.Context, threadID, userID int, upvote bool) error {
+	query := `DELETE FROM discussion_thread_votes WHERE thread_id = $1 AND user_id = $2`
+	if upvote {
+		query = `INSERT INTO discussion_thread_votes (thread_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	}
+	if _, err := r.db.Exec(ctx, query, threadID, userID); err != nil {
+		return fmt.Errorf("failed to vote for discussion thread: %w", err)
+	}
+	return nil
+}
+
+// CreatePost добавляет ответ и обновляет время последней активности темы
+func (r *DiscussionRepository) CreatePost(ctx context.Context, post *models.DiscussionPost) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO discussion_posts (thread_id, parent_id, author_id, body)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at`, post.ThreadID, post.ParentID, post.AuthorID, post.Body).
+		Scan(&post.ID, &post.CreatedAt, &post.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create discussion post: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE discussion_threads SET last_activity_at = NOW() WHERE id = $1`, post.ThreadID); err != nil {
+		return fmt.Errorf("failed to update discussion thread activity: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// FindPostByID ищет ответ; Upvoted заполняется для пользователя userID
+func (r *DiscussionRepository) FindPostByID(ctx context.Context, id, userID int) (*models.DiscussionPost, error) {
+	var post models.DiscussionPost
+	query := `SELECT ` + postColumns + ` WHERE p.id = $2`
+	if err := r.db.QueryRow(ctx, query, userID, id).Scan(postScanFields(&post)...); err != nil {
+		return nil, fmt.Errorf("discussion post not found: %w", err)
+	}
+	return &post, nil
+}
+
+// FindPosts возвращает все ответы темы в порядке создания
+func (r *DiscussionRepository) FindPosts(ctx context.Context, threadID, userID int) ([]*models.DiscussionPost, error) {
+	query := `SELECT ` + postColumns + ` WHERE p.thread_id = $2 ORDER BY p.created_at, p.id`
+	rows, err := r.db.Query(ctx, query, userID, threadID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find discussion posts: %w", err)
+	}
+	defer rows.Close()
+
+	var posts []*models.DiscussionPost
+	for rows.Next() {
+		var post models.DiscussionPost
+		if err := rows.Scan(postScanFields(&post)...); err != nil {
+			return nil, fmt.Errorf("failed to scan discussion post: %w", err)
+		}
+		posts = append(posts, &post)
+	}
+	return posts, rows.Err()
+}
+
+// UpdatePost сохраняет новый текст ответа, предыдущая редакция уходит в историю
+func (r *DiscussionRepository) UpdatePost(ctx context.Context, post *models.DiscussionPost, editorID int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO discussion_edits (thread_id, post_id, editor_id, body)
+		SELECT thread_id, id, $2, body FROM discussion_posts WHERE id = $1`, post.ID, editorID)
+	if err != nil {
+		return fmt.Errorf("failed to save discussion post history: %w", err)
+	}
+
+	err = tx.QueryRow(ctx, `
+		UPDATE discussion_posts SET body = $2, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`, post.ID, post.Body).Scan(&post.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update discussion post: %w", err)
+	}
+	post.Edited = true
+
+	return tx.Commit(ctx)
+}
+
+// DeletePost удаляет ответ вместе с вложенными; снимает отметку принятого ответа, если он удалён
+func (r *DiscussionRepository) DeletePost(ctx context.Context, post *models.DiscussionPost) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM discussion_posts WHERE id = $1`, post.ID); err != nil {
+		return fmt.Errorf("failed to delete discussion post: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE discussion_threads SET accepted_post_id = NULL
+		WHERE id = $1 AND accepted_post_id IS NOT NULL
+		  AND NOT EXISTS (SELECT 1 FROM discussion_posts WHERE id = accepted_post_id)`, post.ThreadID)
+	if err != nil {
+		return fmt.Errorf("failed to reset accepted post: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// VotePost ставит или снимает голос пользователя за ответ
+func (r *DiscussionRepository) VotePost(ctx context.Context, postID, userID int, upvote bool) error {
+	query := `DELETE FROM discussion_post_votes WHERE post_id = $1 AND user_id = $2`
+	if upvote {
+		query = `INSERT INTO discussion_post_votes (post_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	}
+	if _, err := r.db.Exec(ctx, query, postID, userID); err != nil {
+		return fmt.Errorf("failed to vote for discussion post: %w", err)
+	}
+	return nil
+}
+
+// FindEdits возвращает историю правок темы (postID == nil) или ответа, начиная с последней
+func (r *DiscussionRepository) FindEdits(ctx context.Context, threadID int, postID *int) ([]*models.DiscussionEdit, error) {
+	query := `
+		SELECT e.id, e.thread_id, e.post_id, e.editor_id, u.username, COALESCE(e.title, ''), e.body, e.edited_at
+		FROM discussion_edits e
+		JOIN users u ON u.id = e.editor_id
+		WHERE e.thread_id = $1 AND e.post_id IS NOT DISTINCT FROM $2
+		ORDER BY e.edited_at DESC, e.id DESC`
+	rows, err := r.db.Query(ctx, query, threadID, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find discussion edits: %w", err)
+	}
+	defer rows.Close()
+
+	edits := []*models.DiscussionEdit{}
+	for rows.Next() {
+		var edit models.DiscussionEdit
+		if err := rows.Scan(&edit.ID, &edit.ThreadID, &edit.PostID, &edit.EditorID, &edit.EditorName, &edit.Title, &edit.Body, &edit.EditedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan discussion edit: %w", err)
+		}
+		edits = append(edits, &edit)
+	}
+	return edits, rows.Err()
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"errors"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"gitlab.com/w0ikid/study-platform/internal/domain/models"
 )
 
@@ -16,13 +17,18 @@ type EnrollmentRepositoryInterface interface {
 	FindByCourseID(ctx context.Context, courseID int) ([]*models.Enrollment, error)
 	UpdateStatus(ctx context.Context, id int, status string) error
 	Delete(ctx context.Context, id int) error
+	Enroll(ctx context.Context, enrollment *models.Enrollment, joinWaitlist bool) (*models.EnrollmentResult, error)
+	PromoteWaitlisted(ctx context.Context, courseID int) ([]*models.WaitlistEntry, error)
+	FindWaitlist(ctx context.Context, courseID int) ([]*models.WaitlistEntry, error)
+	FindWaitlistEntry(ctx context.Context, courseID, userID int) (*models.WaitlistEntry, error)
+	DeleteWaitlistEntry(ctx context.Context, courseID, userID int) error
 }
 
 type EnrollmentRepository struct {
-	db *pgx.Conn
+	db *pgxpool.Pool
 }
 
-func NewEnrollmentRepository(db *pgx.Conn) *EnrollmentRepository {
+func NewEnrollmentRepository(db *pgxpool.Pool) *EnrollmentRepository {
 	return &EnrollmentRepository{db: db}
 }
 
@@ -146,4 +152,186 @@ func (r *EnrollmentRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM enrollments WHERE id = $1`
 	_, err := r.db.Exec(ctx, query, id)
 	return err
-}
\ No newline at end of file
+}
+
+// lockCourseSeats блокирует строку курса до конца транзакции и возвращает лимит мест и число
+// занятых мест. Место занимает только активная запись: завершившие курс студенты его освобождают.
+// Все изменения записи на курс с лимитом идут через эту блокировку,
+// поэтому параллельные запросы не могут превысить capacity.
+func lockCourseSeats(ctx context.Context, tx pgx.Tx, courseID int) (*int, int, error) {
+	var capacity *int
+	if err := tx.QueryRow(ctx, `SELECT capacity FROM courses WHERE id = $1 FOR UPDATE`, courseID).Scan(&capacity); err != nil {
+		return nil, 0, fmt.Errorf("course not found: %w", err)
+	}
+	var enrolled int
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM enrollments WHERE course_id = $1 AND status = 'active'`, courseID).Scan(&enrolled); err != nil {
+		return nil, 0, fmt.Errorf("failed to count enrollments: %w", err)
+	}
+	return capacity, enrolled, nil
+}
+
+// Enroll записывает студента на курс, если есть свободные места. Если мест нет, студент
+// ставится в лист ожидания (joinWaitlist) или возвращается результат EnrollmentResultFull.
+func (r *EnrollmentRepository) Enroll(ctx context.Context, enrollment *models.Enrollment, joinWaitlist bool) (*models.EnrollmentResult, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	capacity, enrolled, err := lockCourseSeats(ctx, tx, enrollment.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.EnrollmentResult{}
+	switch {
+	case capacity == nil || enrolled < *capacity:
+		err = tx.QueryRow(ctx, `
+			INSERT INTO enrollments (user_id, course_id, created_at, updated_at)
+			VALUES ($1, $2, NOW(), NOW())
+			RETURNING id, status, created_at, updated_at`, enrollment.UserID, enrollment.CourseID).
+			Scan(&enrollment.ID, &enrollment.Status, &enrollment.CreatedAt, &enrollment.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create enrollment: %w", err)
+		}
+		// место получено - из листа ожидания студент выходит
+		if _, err := tx.Exec(ctx, `DELETE FROM course_waitlist WHERE course_id = $1 AND user_id = $2`,
+			enrollment.CourseID, enrollment.UserID); err != nil {
+			return nil, fmt.Errorf("failed to update waitlist: %w", err)
+		}
+		result.Status = models.EnrollmentResultEnrolled
+		result.Enrollment = enrollment
+	case joinWaitlist:
+		_, err = tx.Exec(ctx, `
+			INSERT INTO course_waitlist (course_id, user_id) VALUES ($1, $2)
+			ON CONFLICT (course_id, user_id) DO NOTHING`, enrollment.CourseID, enrollment.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to join waitlist: %w", err)
+		}
+		result.Status = models.EnrollmentResultWaitlisted
+		result.Waitlist, err = findWaitlistEntry(ctx, tx, enrollment.CourseID, enrollment.UserID)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		result.Status = models.EnrollmentResultFull
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return result, nil
+}
+
+// PromoteWaitlisted записывает студентов из листа ожидания в порядке очереди, пока есть свободные места,
+// и возвращает переведённых
+func (r *EnrollmentRepository) PromoteWaitlisted(ctx context.Context, courseID int) ([]*models.WaitlistEntry, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	capacity, enrolled, err := lockCourseSeats(ctx, tx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	var promoted []*models.WaitlistEntry
+	for capacity == nil || enrolled < *capacity {
+		entry := &models.WaitlistEntry{CourseID: courseID}
+		err := tx.QueryRow(ctx, `
+			DELETE FROM course_waitlist
+			WHERE id = (SELECT id FROM course_waitlist WHERE course_id = $1 ORDER BY created_at, id LIMIT 1)
+			RETURNING id, user_id, created_at`, courseID).Scan(&entry.ID, &entry.UserID, &entry.CreatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to take waitlist entry: %w", err)
+		}
+
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO enrollments (user_id, course_id, created_at, updated_at)
+			VALUES ($1, $2, NOW(), NOW())
+			ON CONFLICT (user_id, course_id) DO NOTHING`, entry.UserID, courseID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to enroll from waitlist: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			continue // уже записан другим способом
+		}
+		enrolled++
+		promoted = append(promoted, entry)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return promoted, nil
+}
+
+// FindWaitlist возвращает лист ожидания курса в порядке очереди
+func (r *EnrollmentRepository) FindWaitlist(ctx context.Context, courseID int) ([]*models.WaitlistEntry, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT w.id, w.course_id, w.user_id, u.username,
+			ROW_NUMBER() OVER (ORDER BY w.created_at, w.id), w.created_at
+		FROM course_waitlist w
+		JOIN users u ON u.id = w.user_id
+		WHERE w.course_id = $1
+		ORDER BY w.created_at, w.id`, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying waitlist: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*models.WaitlistEntry{}
+	for rows.Next() {
+		entry := &models.WaitlistEntry{}
+		if err := rows.Scan(&entry.ID, &entry.CourseID, &entry.UserID, &entry.Username, &entry.Position, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning waitlist entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return entries, nil
+}
+
+// FindWaitlistEntry возвращает место студента в листе ожидания или nil, если его там нет
+func (r *EnrollmentRepository) FindWaitlistEntry(ctx context.Context, courseID, userID int) (*models.WaitlistEntry, error) {
+	return findWaitlistEntry(ctx, r.db, courseID, userID)
+}
+
+func findWaitlistEntry(ctx context.Context, db interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}, courseID, userID int) (*models.WaitlistEntry, error) {
+	entry := &models.WaitlistEntry{}
+	err := db.QueryRow(ctx, `
+		SELECT id, course_id, user_id, position, created_at FROM (
+			SELECT id, course_id, user_id, ROW_NUMBER() OVER (ORDER BY created_at, id) AS position, created_at
+			FROM course_waitlist WHERE course_id = $1
+		) w WHERE user_id = $2`, courseID, userID).
+		Scan(&entry.ID, &entry.CourseID, &entry.UserID, &entry.Position, &entry.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find waitlist entry: %w", err)
+	}
+	return entry, nil
+}
+
+// DeleteWaitlistEntry убирает студента из листа ожидания
+func (r *EnrollmentRepository) DeleteWaitlistEntry(ctx context.Context, courseID, userID int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM course_waitlist WHERE course_id = $1 AND user_id = $2`, courseID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to leave waitlist: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("waitlist entry not found")
+	}
+	return nil
+}
@@ -0,0 +1,312 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+)
+
+type GroupRepositoryInterface interface {
+	Create(ctx context.Context, group *models.Group) error
+	FindByID(ctx context.Context, id int) (*models.Group, error)
+	FindAll(ctx context.Context) ([]*models.Group, error)
+	FindByUserID(ctx context.Context, userID int) ([]*models.Group, error)
+	Update(ctx context.Context, group *models.Group) error
+	Delete(ctx context.Context, id int) error
+
+	AddMember(ctx context.Context, member *models.GroupMember) error
+	RemoveMember(ctx context.Context, groupID, userID int) error
+	FindMember(ctx context.Context, groupID, userID int) (*models.GroupMember, error)
+	FindMembers(ctx context.Context, groupID int) ([]*models.GroupMember, error)
+
+	AddCourse(ctx context.Context, groupCourse *models.GroupCourse) error
+	RemoveCourse(ctx context.Context, groupID, courseID int) error
+	FindCourses(ctx context.Context, groupID int) ([]*models.GroupCourse, error)
+
+	FindMemberProgress(ctx context.Context, groupID, courseID int) ([]*models.GroupMemberProgress, error)
+}
+
+type GroupRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewGroupRepository(db *pgxpool.Pool) *GroupRepository {
+	return &GroupRepository{db: db}
+}
+
+// Create добавляет новую группу
+func (r *GroupRepository) Create(ctx context.Context, group *models.Group) error {
+	query := `
+		INSERT INTO study_groups (name, description, created_by)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at, updated_at`
+	err := r.db.QueryRow(ctx, query, group.Name, group.Description, group.CreatedBy).
+		Scan(&group.ID, &group.CreatedAt, &group.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create group: %w", err)
+	}
+	return nil
+}
+
+// FindByID ищет группу по ID
+func (r *GroupRepository) FindByID(ctx context.Context, id int) (*models.Group, error) {
+	var group models.Group
+	query := `
+		SELECT id, name, description, created_by, created_at, updated_at
+		FROM study_groups WHERE id = $1`
+	err := r.db.QueryRow(ctx, query, id).
+		Scan(&group.ID, &group.Name, &group.Description, &group.CreatedBy, &group.CreatedAt, &group.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("group not found: %w", err)
+	}
+	return &group, nil
+}
+
+// FindAll возвращает все группы
+func (r *GroupRepository) FindAll(ctx context.Context) ([]*models.Group, error) {
+	query := `
+		SELECT id, name, description, created_by, created_at, updated_at
+		FROM study_groups ORDER BY id`
+	return r.queryGroups(ctx, query)
+}
+
+// FindByUserID возвращает группы, в которых пользователь состоит (как владелец или участник)
+func (r *GroupRepository) FindByUserID(ctx context.Context, userID int) ([]*models.Group, error) {
+	query := `
+		SELECT g.id, g.name, g.description, g.created_by, g.created_at, g.updated_at
+		FROM study_groups g
+		JOIN group_members gm ON gm.group_id = g.id
+		WHERE gm.user_id = $1
+		ORDER BY g.id`
+	return r.queryGroups(ctx, query, userID)
+}
+
+func (r *GroupRepository) queryGroups(ctx context.Context, query string, args ...any) ([]*models.Group, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch groups: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []*models.Group
+	for rows.Next() {
+		var group models.Group
+		if err := rows.Scan(&group.ID, &group.Name, &group.Description, &group.CreatedBy, &group.CreatedAt, &group.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning group: %w", err)
+		}
+		groups = append(groups, &group)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return groups, nil
+}
+
+// Update обновляет название и описание группы
+func (r *GroupRepository) Update(ctx context.Context, group *models.Group) error {
+	query := `
+		UPDATE study_groups
+		SET name = $1, description = $2, updated_at = NOW()
+		WHERE id = $3`
+	_, err := r.db.Exec(ctx, query, group.Name, group.Description, group.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update group: %w", err)
+	}
+	return nil
+}
+
+// Delete удаляет группу (участники и курсы группы удаляются каскадно)
+func (r *GroupRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM study_groups WHERE id = $1`
+	_, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete group: %w", err)
+	}
+	return nil
+}
+
+// AddMember добавляет участника в группу, повторное добавление меняет роль.
+// Последнего владельца группы понизить нельзя.
+func (r *GroupRepository) AddMember(ctx context.Context, member *models.GroupMember) error {
+	query := `
+		INSERT INTO group_members (group_id, user_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (group_id, user_id) DO UPDATE SET role = EXCLUDED.role
+		WHERE group_members.role <> $4 OR EXCLUDED.role = $4 OR EXISTS (
+			SELECT 1 FROM group_members o
+			WHERE o.group_id = EXCLUDED.group_id AND o.user_id <> EXCLUDED.user_id AND o.role = $4)
+		RETURNING created_at`
+	err := r.db.QueryRow(ctx, query, member.GroupID, member.UserID, member.Role, models.GroupRoleOwner).
+		Scan(&member.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errors.New("group must have at least one owner")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add group member: %w", err)
+	}
+	return nil
+}
+
+// RemoveMember удаляет участника из группы
+func (r *GroupRepository) RemoveMember(ctx context.Context, groupID, userID int) error {
+	query := `DELETE FROM group_members WHERE group_id = $1 AND user_id = $2`
+	commandTag, err := r.db.Exec(ctx, query, groupID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove group member: %w", err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return fmt.Errorf("user %d is not a member of group %d", userID, groupID)
+	}
+	return nil
+}
+
+// FindMember возвращает участника группы или nil, если пользователь в группе не состоит
+func (r *GroupRepository) FindMember(ctx context.Context, groupID, userID int) (*models.GroupMember, error) {
+	var member models.GroupMember
+	query := `
+		SELECT gm.group_id, gm.user_id, u.username, COALESCE(u.name, ''), COALESCE(u.surname, ''), gm.role, gm.created_at
+		FROM group_members gm
+		JOIN users u ON u.id = gm.user_id
+		WHERE gm.group_id = $1 AND gm.user_id = $2`
+	err := r.db.QueryRow(ctx, query, groupID, userID).
+		Scan(&member.GroupID, &member.UserID, &member.Username, &member.Name, &member.Surname, &member.Role, &member.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find group member: %w", err)
+	}
+	return &member, nil
+}
+
+// FindMembers возвращает всех участников группы
+func (r *GroupRepository) FindMembers(ctx context.Context, groupID int) ([]*models.GroupMember, error) {
+	query := `
+		SELECT gm.group_id, gm.user_id, u.username, COALESCE(u.name, ''), COALESCE(u.surname, ''), gm.role, gm.created_at
+		FROM group_members gm
+		JOIN users u ON u.id = gm.user_id
+		WHERE gm.group_id = $1
+		ORDER BY gm.role, u.username`
+	rows, err := r.db.Query(ctx, query, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch group members: %w", err)
+	}
+	defer rows.Close()
+
+	var members []*models.GroupMember
+	for rows.Next() {
+		var member models.GroupMember
+		if err := rows.Scan(&member.GroupID, &member.UserID, &member.Username, &member.Name, &member.Surname, &member.Role, &member.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning group member: %w", err)
+		}
+		members = append(members, &member)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return members, nil
+}
+
+// AddCourse привязывает курс к группе
+func (r *GroupRepository) AddCourse(ctx context.Context, groupCourse *models.GroupCourse) error {
+	query := `
+		INSERT INTO group_courses (group_id, course_id)
+		VALUES ($1, $2)
+		ON CONFLICT (group_id, course_id) DO UPDATE SET group_id = EXCLUDED.group_id
+		RETURNING created_at`
+	err := r.db.QueryRow(ctx, query, groupCourse.GroupID, groupCourse.CourseID).
+		Scan(&groupCourse.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add group course: %w", err)
+	}
+	return nil
+}
+
+// RemoveCourse отвязывает курс от группы (зачисления студентов сохраняются)
+func (r *GroupRepository) RemoveCourse(ctx context.Context, groupID, courseID int) error {
+	query := `DELETE FROM group_courses WHERE group_id = $1 AND course_id = $2`
+	_, err := r.db.Exec(ctx, query, groupID, courseID)
+	if err != nil {
+		return fmt.Errorf("failed to remove group course: %w", err)
+	}
+	return nil
+}
+
+// FindCourses возвращает курсы, на которые зачислена группа
+func (r *GroupRepository) FindCourses(ctx context.Context, groupID int) ([]*models.GroupCourse, error) {
+	query := `
+		SELECT group_id, course_id, created_at
+		FROM group_courses WHERE group_id = $1
+		ORDER BY created_at`
+	rows, err := r.db.Query(ctx, query, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch group courses: %w", err)
+	}
+	defer rows.Close()
+
+	var courses []*models.GroupCourse
+	for rows.Next() {
+		var course models.GroupCourse
+		if err := rows.Scan(&course.GroupID, &course.CourseID, &course.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning group course: %w", err)
+		}
+		courses = append(courses, &course)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return courses, nil
+}
+
+// FindMemberProgress считает количество завершённых уроков курса для каждого студента группы
+func (r *GroupRepository) FindMemberProgress(ctx context.Context, groupID, courseID int) ([]*models.GroupMemberProgress, error) {
+	query := `
+		SELECT u.id, u.username, COALESCE(u.name, ''), COALESCE(u.surname, ''),
+			e.id IS NOT NULL, COALESCE(e.status, ''),
+			COUNT(lp.id) FILTER (WHERE lp.is_completed)
+		FROM group_members gm
+		JOIN users u ON u.id = gm.user_id
+		LEFT JOIN enrollments e ON e.user_id = u.id AND e.course_id = $2
+		LEFT JOIN lesson_progress lp ON lp.user_id = u.id AND lp.course_id = $2
+		WHERE gm.group_id = $1 AND gm.role = $3
+		GROUP BY u.id, u.username, u.name, u.surname, e.id, e.status
+		ORDER BY u.username`
+	rows, err := r.db.Query(ctx, query, groupID, courseID, models.GroupRoleMember)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch group progress: %w", err)
+	}
+	defer rows.Close()
+
+	var progresses []*models.GroupMemberProgress
+	for rows.Next() {
+		var progress models.GroupMemberProgress
+		if err := rows.Scan(
+			&progress.UserID,
+			&progress.Username,
+			&progress.Name,
+			&progress.Surname,
+			&progress.Enrolled,
+			&progress.EnrollmentStatus,
+			&progress.CompletedLessons,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning group progress: %w", err)
+		}
+		progresses = append(progresses, &progress)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return progresses, nil
+}
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+)
+
+type LessonAttachmentRepositoryInterface interface {
+	Create(ctx context.Context, attachment *models.LessonAttachment) error
+	FindByID(ctx context.Context, id int) (*models.LessonAttachment, error)
+	FindByLesson(ctx context.Context, lessonID int) ([]*models.LessonAttachment, error)
+	Update(ctx context.Context, attachment *models.LessonAttachment) error
+	Delete(ctx context.Context, id int) error
+	CountByMedia(ctx context.Context, mediaID int) (int, error)
+	IncrementDownloads(ctx context.Context, id int) (int, error)
+}
+
+type LessonAttachmentRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewLessonAttachmentRepository(db *pgxpool.Pool) *LessonAttachmentRepository {
+	return &LessonAttachmentRepository{db: db}
+}
+
+const lessonAttachmentColumns = `a.id, a.lesson_id, a.media_id, a.title, f.filename, f.content_type, f.size, a.watermark, a.download_count, f.storage_key, a.created_at`
+
+func lessonAttachmentScanFields(attachment *models.LessonAttachment) []any {
+	return []any{
+		&attachment.ID, &attachment.LessonID, &attachment.MediaID, &attachment.Title, &attachment.Filename, &attachment.ContentType,
+		&attachment.Size, &attachment.Watermark, &attachment.DownloadCount, &attachment.StorageKey, &attachment.CreatedAt,
+	}
+}
+
+// Create прикрепляет файл медиатеки к уроку
+func (r *LessonAttachmentRepository) Create(ctx context.Context, attachment *models.LessonAttachment) error {
+	query := `
+		INSERT INTO lesson_attachments (lesson_id, media_id, title, watermark)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`
+	err := r.db.QueryRow(ctx, query, attachment.LessonID, attachment.MediaID, attachment.Title, attachment.Watermark).
+		Scan(&attachment.ID, &attachment.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create lesson attachment: %w", err)
+	}
+	return nil
+}
+
+// FindByID ищет вложение по ID вместе с данными файла
+func (r *LessonAttachmentRepository) FindByID(ctx context.Context, id int) (*models.LessonAttachment, error) {
+	query := `SELECT ` + lessonAttachmentColumns + ` FROM lesson_attachments a JOIN media_files f ON f.id = a.media_id WHERE a.id = $1`
+	var attachment models.LessonAttachment
+	if err := r.db.QueryRow(ctx, query, id).Scan(lessonAttachmentScanFields(&attachment)...); err != nil {
+		return nil, fmt.Errorf("lesson attachment not found: %w", err)
+	}
+	return &attachment, nil
+}
+
+// FindByLesson возвращает вложения урока в порядке добавления
+func (r *LessonAttachmentRepository) FindByLesson(ctx context.Context, lessonID int) ([]*models.LessonAttachment, error) {
+	query := `
+		SELECT ` + lessonAttachmentColumns + `
+		FROM lesson_attachments a
+		JOIN media_files f ON f.id = a.media_id
+		WHERE a.lesson_id = $1
+		ORDER BY a.id`
+	rows, err := r.db.Query(ctx, query, lessonID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch lesson attachments: %w", err)
+	}
+	defer rows.Close()
+
+	attachments := []*models.LessonAttachment{}
+	for rows.Next() {
+		var attachment models.LessonAttachment
+		if err := rows.Scan(lessonAttachmentScanFields(&attachment)...); err != nil {
+			return nil, fmt.Errorf("failed to scan lesson attachment: %w", err)
+		}
+		attachments = append(attachments, &attachment)
+	}
+	return attachments, rows.Err()
+}
+
+// Update меняет название вложения и признак водяного знака
+func (r *LessonAttachmentRepository) Update(ctx context.Context, attachment *models.LessonAttachment) error {
+	query := `UPDATE lesson_attachments SET title = $1, watermark = $2 WHERE id = $3`
+	if _, err := r.db.Exec(ctx, query, attachment.Title, attachment.Watermark, attachment.ID); err != nil {
+		return fmt.Errorf("failed to update lesson attachment: %w", err)
+	}
+	return nil
+}
+
+// Delete открепляет файл от урока; сам файл остаётся в медиатеке
+func (r *LessonAttachmentRepository) Delete(ctx context.Context, id int) error {
+	if _, err := r.db.Exec(ctx, `DELETE FROM lesson_attachments WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete lesson attachment: %w", err)
+	}
+	return nil
+}
+
+// CountByMedia - сколько вложений ссылается на файл (файл общий у копий курса)
+func (r *LessonAttachmentRepository) CountByMedia(ctx context.Context, mediaID int) (int, error) {
+	var count int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM lesson_attachments WHERE media_id = $1`, mediaID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count lesson attachments: %w", err)
+	}
+	return count, nil
+}
+
+// IncrementDownloads увеличивает счётчик скачиваний и возвращает новое значение
+func (r *LessonAttachmentRepository) IncrementDownloads(ctx context.Context, id int) (int, error) {
+	var count int
+	query := `UPDATE lesson_attachments SET download_count = download_count + 1 WHERE id = $1 RETURNING download_count`
+	if err := r.db.QueryRow(ctx, query, id).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count attachment download: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,177 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+)
+
+type LessonBlockRepositoryInterface interface {
+	Create(ctx context.Context, block *models.LessonBlock) error
+	FindByID(ctx context.Context, id int) (*models.LessonBlock, error)
+	FindByLesson(ctx context.Context, lessonID, userID int) ([]*models.LessonBlock, error)
+	Update(ctx context.Context, block *models.LessonBlock) error
+	Delete(ctx context.Context, id int) error
+	Reorder(ctx context.Context, lessonID int, blockIDs []int) error
+	MarkCompleted(ctx context.Context, blockID, userID int) error
+	CountRemainingRequired(ctx context.Context, lessonID, userID int) (int, error)
+}
+
+type LessonBlockRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewLessonBlockRepository(db *pgxpool.Pool) *LessonBlockRepository {
+	return &LessonBlockRepository{db: db}
+}
+
+const lessonBlockColumns = `b.id, b.lesson_id, b.position, b.type, b.data, b.html, b.required, b.created_at, b.updated_at`
+
+func lessonBlockScanFields(block *models.LessonBlock) []any {
+	return []any{&block.ID, &block.LessonID, &block.Position, &block.Type, &block.Data, &block.HTML, &block.Required, &block.CreatedAt, &block.UpdatedAt}
+}
+
+// Create добавляет блок в конец урока
+func (r *LessonBlockRepository) Create(ctx context.Context, block *models.LessonBlock) error {
+	query := `
+		INSERT INTO lesson_blocks (lesson_id, position, type, data, html, required)
+		VALUES ($1, COALESCE((SELECT MAX(position) FROM lesson_blocks WHERE lesson_id = $1), 0) + 1, $2, $3, $4, $5)
+		RETURNING id, position, created_at, updated_at`
+	err := r.db.QueryRow(ctx, query, block.LessonID, block.Type, block.Data, block.HTML, block.Required).
+		Scan(&block.ID, &block.Position, &block.CreatedAt, &block.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create lesson block: %w", err)
+	}
+	return nil
+}
+
+// FindByID ищет блок по ID
+func (r *LessonBlockRepository) FindByID(ctx context.Context, id int) (*models.LessonBlock, error) {
+	query := `SELECT ` + lessonBlockColumns + ` FROM lesson_blocks b WHERE b.id = $1`
+	var block models.LessonBlock
+	if err := r.db.QueryRow(ctx, query, id).Scan(lessonBlockScanFields(&block)...); err != nil {
+		return nil, fmt.Errorf("lesson block not found: %w", err)
+	}
+	return &block, nil
+}
+
+// FindByLesson возвращает блоки урока по порядку с отметкой о завершении пользователем
+func (r *LessonBlockRepository) FindByLesson(ctx context.Context, lessonID, userID int) ([]*models.LessonBlock, error) {
+	query := `
+		SELECT ` + lessonBlockColumns + `, c.block_id IS NOT NULL
+		FROM lesson_blocks b
+		LEFT JOIN lesson_block_completions c ON c.block_id = b.id AND c.user_id = $2
+		WHERE b.lesson_id = $1
+		ORDER BY b.position, b.id`
+	rows, err := r.db.Query(ctx, query, lessonID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch lesson blocks: %w", err)
+	}
+	defer rows.Close()
+
+	blocks := []*models.LessonBlock{}
+	for rows.Next() {
+		var block models.LessonBlock
+		if err := rows.Scan(append(lessonBlockScanFields(&block), &block.IsCompleted)...); err != nil {
+			return nil, fmt.Errorf("failed to scan lesson block: %w", err)
+		}
+		blocks = append(blocks, &block)
+	}
+	return blocks, rows.Err()
+}
+
+// Update меняет содержимое блока; тип блока не меняется
+func (r *LessonBlockRepository) Update(ctx context.Context, block *models.LessonBlock) error {
+	query := `
+		UPDATE lesson_blocks
+		SET data = $1, html = $2, required = $3, updated_at = NOW()
+		WHERE id = $4
+		RETURNING updated_at`
+	if err := r.db.QueryRow(ctx, query, block.Data, block.HTML, block.Required, block.ID).Scan(&block.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to update lesson block: %w", err)
+	}
+	return nil
+}
+
+// Delete удаляет блок и сдвигает следующие блоки урока
+func (r *LessonBlockRepository) Delete(ctx context.Context, id int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var lessonID, position int
+	err = tx.QueryRow(ctx, `DELETE FROM lesson_blocks WHERE id = $1 RETURNING lesson_id, position`, id).Scan(&lessonID, &position)
+	if err != nil {
+		return fmt.Errorf("failed to delete lesson block: %w", err)
+	}
+	_, err = tx.Exec(ctx, `UPDATE lesson_blocks SET position = position - 1 WHERE lesson_id = $1 AND position > $2`, lessonID, position)
+	if err != nil {
+		return fmt.Errorf("failed to shift lesson blocks: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Reorder расставляет блоки урока в порядке blockIDs; список должен содержать все блоки урока
+func (r *LessonBlockRepository) Reorder(ctx context.Context, lessonID int, blockIDs []int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	commandTag, err := tx.Exec(ctx, `
+		UPDATE lesson_blocks b
+		SET position = o.position, updated_at = NOW()
+		FROM unnest($2::int[]) WITH ORDINALITY AS o(id, position)
+		WHERE b.id = o.id AND b.lesson_id = $1`, lessonID, blockIDs)
+	if err != nil {
+		return fmt.Errorf("failed to reorder lesson blocks: %w", err)
+	}
+
+	var total int
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM lesson_blocks WHERE lesson_id = $1`, lessonID).Scan(&total); err != nil {
+		return fmt.Errorf("failed to count lesson blocks: %w", err)
+	}
+	if int(commandTag.RowsAffected()) != len(blockIDs) || total != len(blockIDs) {
+		return fmt.Errorf("validation failed: block order must list every block of the lesson exactly once")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// MarkCompleted отмечает блок завершённым пользователем; повторная отметка ничего не меняет
+func (r *LessonBlockRepository) MarkCompleted(ctx context.Context, blockID, userID int) error {
+	query := `
+		INSERT INTO lesson_block_completions (block_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (block_id, user_id) DO NOTHING`
+	if _, err := r.db.Exec(ctx, query, blockID, userID); err != nil {
+		return fmt.Errorf("failed to complete lesson block: %w", err)
+	}
+	return nil
+}
+
+// CountRemainingRequired возвращает число обязательных блоков урока, которые пользователь ещё не завершил
+func (r *LessonBlockRepository) CountRemainingRequired(ctx context.Context, lessonID, userID int) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM lesson_blocks b
+		LEFT JOIN lesson_block_completions c ON c.block_id = b.id AND c.user_id = $2
+		WHERE b.lesson_id = $1 AND b.required AND c.block_id IS NULL`
+	var remaining int
+	if err := r.db.QueryRow(ctx, query, lessonID, userID).Scan(&remaining); err != nil {
+		return 0, fmt.Errorf("failed to count remaining lesson blocks: %w", err)
+	}
+	return remaining, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
     "errors"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
     "gitlab.com/w0ikid/study-platform/internal/domain/models"
 )
 
@@ -13,13 +14,14 @@ type LessonProgressRepositoryInterface interface {
     FindByUserAndLesson(ctx context.Context, userID, lessonID int) (*models.LessonProgress, error)
     Update(ctx context.Context, progress *models.LessonProgress) error
     FindByUserAndCourse(ctx context.Context, userID, courseID int) ([]*models.LessonProgress, error)
+    FindCompletedByCourse(ctx context.Context, courseID int) ([]*models.LessonProgress, error)
 }
 
 type LessonProgressRepository struct {
-    db *pgx.Conn
+    db *pgxpool.Pool
 }
 
-func NewLessonProgressRepository(db *pgx.Conn) *LessonProgressRepository {
+func NewLessonProgressRepository(db *pgxpool.Pool) *LessonProgressRepository {
     return &LessonProgressRepository{db: db}
 }
 
@@ -91,6 +93,23 @@ func (r *LessonProgressRepository) FindByUserAndCourse(ctx context.Context, user
     if err != nil {
         return nil, fmt.Errorf("failed to find lesson progress by user and course: %w", err)
     }
+    return scanLessonProgress(rows)
+}
+
+// FindCompletedByCourse возвращает пройденные уроки курса всеми студентами одним запросом
+func (r *LessonProgressRepository) FindCompletedByCourse(ctx context.Context, courseID int) ([]*models.LessonProgress, error) {
+    query := `
+        SELECT id, user_id, lesson_id, course_id, is_completed, completed_at, created_at, updated_at
+        FROM lesson_progress
+        WHERE course_id = $1 AND is_completed`
+    rows, err := r.db.Query(ctx, query, courseID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to find lesson progress by course: %w", err)
+    }
+    return scanLessonProgress(rows)
+}
+
+func scanLessonProgress(rows pgx.Rows) ([]*models.LessonProgress, error) {
     defer rows.Close()
 
     var progresses []*models.LessonProgress
@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+)
+
+type LessonReleaseRepositoryInterface interface {
+	FindByLesson(ctx context.Context, lessonID int) (*models.LessonReleaseRule, error)
+	FindByCourse(ctx context.Context, courseID int) ([]*models.LessonReleaseRule, error)
+	Save(ctx context.Context, rule *models.LessonReleaseRule) error
+	Delete(ctx context.Context, lessonID int) error
+	FindPendingUnlocks(ctx context.Context, lookbackDays int) ([]*models.LessonUnlock, error)
+	MarkNotified(ctx context.Context, userID int, lessonIDs []int) ([]int, error)
+}
+
+type LessonReleaseRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewLessonReleaseRepository(db *pgxpool.Pool) *LessonReleaseRepository {
+	return &LessonReleaseRepository{db: db}
+}
+
+// урок-условие в корзине не держит урок закрытым, как и удалённый насовсем (after_lesson_id = NULL)
+const lessonReleaseColumns = `r.lesson_id, r.type, r.release_at, r.days_after_enrollment,
+	(SELECT al.id FROM lessons al WHERE al.id = r.after_lesson_id AND al.deleted_at IS NULL), r.created_at, r.updated_at`
+
+func lessonReleaseScanFields(rule *models.LessonReleaseRule) []any {
+	return []any{
+		&rule.LessonID, &rule.Type, &rule.ReleaseAt, &rule.DaysAfterEnrollment, &rule.AfterLessonID, &rule.CreatedAt, &rule.UpdatedAt,
+	}
+}
+
+// FindByLesson возвращает правило открытия урока; nil, если урок доступен сразу
+func (r *LessonReleaseRepository) FindByLesson(ctx context.Context, lessonID int) (*models.LessonReleaseRule, error) {
+	query := `SELECT ` + lessonReleaseColumns + ` FROM lesson_release_rules r WHERE r.lesson_id = $1`
+	var rule models.LessonReleaseRule
+	err := r.db.QueryRow(ctx, query, lessonID).Scan(lessonReleaseScanFields(&rule)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find lesson release rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// FindByCourse возвращает правила открытия уроков курса
+func (r *LessonReleaseRepository) FindByCourse(ctx context.Context, courseID int) ([]*models.LessonReleaseRule, error) {
+	query := `
+		SELECT ` + lessonReleaseColumns + `
+		FROM lesson_release_rules r
+		JOIN lessons l ON l.id = r.lesson_id
+		WHERE l.course_id = $1 AND l.deleted_at IS NULL
+		ORDER BY r.lesson_id`
+	rows, err := r.db.Query(ctx, query, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch lesson release rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []*models.LessonReleaseRule{}
+	for rows.Next() {
+		var rule models.LessonReleaseRule
+		if err := rows.Scan(lessonReleaseScanFields(&rule)...); err != nil {
+			return nil, fmt.Errorf("failed to scan lesson release rule: %w", err)
+		}
+		rules = append(rules, &rule)
+	}
+	return rules, rows.Err()
+}
+
+// Save создаёт или заменяет правило открытия урока
+func (r *LessonReleaseRepository) Save(ctx context.Context, rule *models.LessonReleaseRule) error {
+	query := `
+		INSERT INTO lesson_release_rules (lesson_id, type, release_at, days_after_enrollment, after_lesson_id)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (lesson_id) DO UPDATE SET
+			type = EXCLUDED.type,
+			release_at = EXCLUDED.release_at,
+			days_after_enrollment = EXCLUDED.days_after_enrollment,
+			after_lesson_id = EXCLUDED.after_lesson_id,
+			updated_at = NOW()
+		RETURNING created_at, updated_at`
+	err := r.db.QueryRow(ctx, query, rule.LessonID, rule.Type, rule.ReleaseAt, rule.DaysAfterEnrollment, rule.AfterLessonID).
+		Scan(&rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save lesson release rule: %w", err)
+	}
+	return nil
+}
+
+// Delete убирает правило, урок становится доступен сразу
+func (r *LessonReleaseRepository) Delete(ctx context.Context, lessonID int) error {
+	commandTag, err := r.db.Exec(ctx, `DELETE FROM lesson_release_rules WHERE lesson_id = $1`, lessonID)
+	if err != nil {
+		return fmt.Errorf("failed to delete lesson release rule: %w", err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return errors.New("lesson release rule not found")
+	}
+	return nil
+}
+
+// FindPendingUnlocks возвращает уроки, открывшиеся по дате за последние lookbackDays дней,
+// о которых студенты с активной записью ещё не получили письмо. Уроки, открытые
+// по дате ещё до записи студента, не считаются новыми.
+func (r *LessonReleaseRepository) FindPendingUnlocks(ctx context.Context, lookbackDays int) ([]*models.LessonUnlock, error) {
+	query := `
+		WITH unlocks AS (
+			SELECT e.user_id, l.course_id, l.id AS lesson_id, l.title,
+				CASE r.type
+					WHEN 'date' THEN r.release_at
+					ELSE e.created_at + make_interval(days => r.days_after_enrollment)
+				END AS unlock_at
+			FROM lesson_release_rules r
+			JOIN lessons l ON l.id = r.lesson_id AND l.deleted_at IS NULL
+			JOIN enrollments e ON e.course_id = l.course_id AND e.status = 'active'
+			WHERE r.type = 'enrollment_days' OR (r.type = 'date' AND e.created_at < r.release_at)
+		)
+		SELECT un.user_id, u.email, u.name, un.course_id, c.name, un.lesson_id, un.title, un.unlock_at
+		FROM unlocks un
+		JOIN users u ON u.id = un.user_id
+		JOIN courses c ON c.id = un.course_id
+		WHERE un.unlock_at <= NOW() AND un.unlock_at > NOW() - make_interval(days => $1)
+			AND NOT EXISTS (
+				SELECT 1 FROM lesson_release_notifications n
+				WHERE n.user_id = un.user_id AND n.lesson_id = un.lesson_id
+			)
+		ORDER BY un.user_id, un.course_id, un.unlock_at, un.lesson_id`
+	rows, err := r.db.Query(ctx, query, lookbackDays)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch unlocked lessons: %w", err)
+	}
+	defer rows.Close()
+
+	unlocks := []*models.LessonUnlock{}
+	for rows.Next() {
+		var unlock models.LessonUnlock
+		var name *string
+		err := rows.Scan(&unlock.UserID, &unlock.Email, &name, &unlock.CourseID, &unlock.CourseName,
+			&unlock.LessonID, &unlock.LessonTitle, &unlock.UnlockAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan unlocked lesson: %w", err)
+		}
+		if name != nil {
+			unlock.Name = *name
+		}
+		unlocks = append(unlocks, &unlock)
+	}
+	return unlocks, rows.Err()
+}
+
+// MarkNotified отмечает уведомление студента об открытии уроков и возвращает уроки,
+// которые ещё не были отмечены (их и нужно включить в письмо)
+func (r *LessonReleaseRepository) MarkNotified(ctx context.Context, userID int, lessonIDs []int) ([]int, error) {
+	query := `
+		INSERT INTO lesson_release_notifications (user_id, lesson_id)
+		SELECT $1, unnest($2::int[])
+		ON CONFLICT DO NOTHING
+		RETURNING lesson_id`
+	rows, err := r.db.Query(ctx, query, userID, lessonIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark unlock notifications: %w", err)
+	}
+	defer rows.Close()
+
+	claimed := []int{}
+	for rows.Next() {
+		var lessonID int
+		if err := rows.Scan(&lessonID); err != nil {
+			return nil, fmt.Errorf("failed to scan unlock notification: %w", err)
+		}
+		claimed = append(claimed, lessonID)
+	}
+	return claimed, rows.Err()
+}
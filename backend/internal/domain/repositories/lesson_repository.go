@@ -2,9 +2,11 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"gitlab.com/w0ikid/study-platform/internal/domain/models"
 )
 
@@ -14,23 +16,29 @@ type LessonRepositoryInterface interface {
 	FindByCourseID(ctx context.Context, courseID int) ([]*models.Lesson, error)
 	Update(ctx context.Context, lesson *models.Lesson) error
 	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, courseID, id int) (*models.Lesson, error)
+	FindDeletedByCourseID(ctx context.Context, courseID int) ([]*models.Lesson, error)
+	FindUnrendered(ctx context.Context, limit int) ([]*models.Lesson, error)
+	UpdateContentHTML(ctx context.Context, id int, contentHTML string) error
 }
 type LessonRepository struct {
-	db *pgx.Conn
+	db *pgxpool.Pool
 }
 
-func NewLessonRepository(db *pgx.Conn) *LessonRepository {
+func NewLessonRepository(db *pgxpool.Pool) *LessonRepository {
 	return &LessonRepository{db: db}
 }
 
 // Create inserts a new lesson into the database and returns the created lesson
 func (r *LessonRepository) Create(ctx context.Context, lesson *models.Lesson) error {
+	// new lessons are appended to the end of their module
 	query := `
-		INSERT INTO lessons (course_id, title, content, video_url)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, created_at, updated_at`
-	err := r.db.QueryRow(ctx, query, lesson.CourseID, lesson.Title, lesson.Content, lesson.VideoURL).
-		Scan(&lesson.ID, &lesson.CreatedAt, &lesson.UpdatedAt)
+		INSERT INTO lessons (course_id, title, content, content_html, video_url, module_id, position, video_duration)
+		VALUES ($1, $2, $3, $4, $5, $6, COALESCE(
+			(SELECT MAX(position) FROM lessons WHERE course_id = $1 AND module_id IS NOT DISTINCT FROM $6), 0) + 1, $7)
+		RETURNING id, position, created_at, updated_at`
+	err := r.db.QueryRow(ctx, query, lesson.CourseID, lesson.Title, lesson.Content, lesson.ContentHTML, lesson.VideoURL, lesson.ModuleID, lesson.VideoDuration).
+		Scan(&lesson.ID, &lesson.Position, &lesson.CreatedAt, &lesson.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to create lesson: %w", err)
 	}
@@ -40,16 +48,20 @@ func (r *LessonRepository) Create(ctx context.Context, lesson *models.Lesson) er
 // FindByID retrieves a lesson by its ID
 func (r *LessonRepository) FindByID(ctx context.Context, id int) (*models.Lesson, error) {
 	query := `
-		SELECT id, course_id, title, content, video_url, created_at, updated_at
+		SELECT id, course_id, module_id, position, title, content, COALESCE(content_html, ''), video_url, video_duration, created_at, updated_at
 		FROM lessons
-		WHERE id = $1`
+		WHERE id = $1 AND deleted_at IS NULL`
 	var lesson models.Lesson
 	err := r.db.QueryRow(ctx, query, id).Scan(
 		&lesson.ID,
 		&lesson.CourseID,
+		&lesson.ModuleID,
+		&lesson.Position,
 		&lesson.Title,
 		&lesson.Content,
+		&lesson.ContentHTML,
 		&lesson.VideoURL,
+		&lesson.VideoDuration,
 		&lesson.CreatedAt,
 		&lesson.UpdatedAt,
 	)
@@ -61,10 +73,13 @@ func (r *LessonRepository) FindByID(ctx context.Context, id int) (*models.Lesson
 
 // FindByCourseID retrieves all lessons for a given course ID
 func (r *LessonRepository) FindByCourseID(ctx context.Context, courseID int) ([]*models.Lesson, error) {
+	// ordered as shown to students: default module first, then modules and lessons by position
 	query := `
-		SELECT id, course_id, title, content, video_url, created_at, updated_at
-		FROM lessons
-		WHERE course_id = $1`
+		SELECT l.id, l.course_id, l.module_id, l.position, l.title, l.content, COALESCE(l.content_html, ''), l.video_url, l.video_duration, l.created_at, l.updated_at
+		FROM lessons l
+		LEFT JOIN course_modules m ON m.id = l.module_id
+		WHERE l.course_id = $1 AND l.deleted_at IS NULL
+		ORDER BY COALESCE(m.position, 0), m.id NULLS FIRST, l.position, l.id`
 	rows, err := r.db.Query(ctx, query, courseID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find lessons by course id: %w", err)
@@ -77,9 +92,13 @@ func (r *LessonRepository) FindByCourseID(ctx context.Context, courseID int) ([]
 		if err := rows.Scan(
 			&lesson.ID,
 			&lesson.CourseID,
+			&lesson.ModuleID,
+			&lesson.Position,
 			&lesson.Title,
 			&lesson.Content,
+			&lesson.ContentHTML,
 			&lesson.VideoURL,
+			&lesson.VideoDuration,
 			&lesson.CreatedAt,
 			&lesson.UpdatedAt,
 		); err != nil {
@@ -92,29 +111,139 @@ func (r *LessonRepository) FindByCourseID(ctx context.Context, courseID int) ([]
 
 // Update updates an existing lesson in the database
 func (r *LessonRepository) Update(ctx context.Context, lesson *models.Lesson) error {
+	return updateLesson(ctx, r.db, lesson)
+}
+
+// updateLesson сохраняет содержимое урока; в транзакции строка урока остаётся заблокированной до её конца
+func updateLesson(ctx context.Context, db interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}, lesson *models.Lesson) error {
 	query := `
 		UPDATE lessons
-		SET title = $1, content = $2, video_url = $3, updated_at = NOW()
-		WHERE id = $4`
-	_, err := r.db.Exec(ctx, query, lesson.Title, lesson.Content, lesson.VideoURL, lesson.ID)
+		SET title = $1, content = $2, content_html = $3, video_url = $4, video_duration = $5, updated_at = NOW()
+		WHERE id = $6 AND deleted_at IS NULL
+		RETURNING updated_at`
+	err := db.QueryRow(ctx, query, lesson.Title, lesson.Content, lesson.ContentHTML, lesson.VideoURL, lesson.VideoDuration, lesson.ID).Scan(&lesson.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to update lesson: %w", err)
-	}	
+	}
 	return nil
 }
 
-// Delete removes a lesson from the database
+// Delete moves a lesson to the trash: it disappears from the course but keeps blocks, progress and revisions
 func (r *LessonRepository) Delete(ctx context.Context, id int) error {
 	query := `
-		DELETE FROM lessons
-		WHERE id = $1`
-	_, err := r.db.Exec(ctx, query, id)
+		UPDATE lessons
+		SET deleted_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL`
+	tag, err := r.db.Exec(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete lesson: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("lesson not found")
+	}
 	return nil
 }
 
+// Restore returns a deleted lesson of the course to its previous module and position
+func (r *LessonRepository) Restore(ctx context.Context, courseID, id int) (*models.Lesson, error) {
+	query := `
+		UPDATE lessons
+		SET deleted_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND course_id = $2 AND deleted_at IS NOT NULL
+		RETURNING id, course_id, module_id, position, title, content, COALESCE(content_html, ''), video_url, video_duration, created_at, updated_at`
+	var lesson models.Lesson
+	err := r.db.QueryRow(ctx, query, id, courseID).Scan(
+		&lesson.ID,
+		&lesson.CourseID,
+		&lesson.ModuleID,
+		&lesson.Position,
+		&lesson.Title,
+		&lesson.Content,
+		&lesson.ContentHTML,
+		&lesson.VideoURL,
+		&lesson.VideoDuration,
+		&lesson.CreatedAt,
+		&lesson.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("deleted lesson not found: %w", err)
+	}
+	return &lesson, nil
+}
+
+// FindDeletedByCourseID returns the course trash, most recently deleted first
+func (r *LessonRepository) FindDeletedByCourseID(ctx context.Context, courseID int) ([]*models.Lesson, error) {
+	query := `
+		SELECT id, course_id, module_id, position, title, video_url, created_at, updated_at, deleted_at
+		FROM lessons
+		WHERE course_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id`
+	rows, err := r.db.Query(ctx, query, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find deleted lessons: %w", err)
+	}
+	defer rows.Close()
+
+	lessons := []*models.Lesson{}
+	for rows.Next() {
+		var lesson models.Lesson
+		if err := rows.Scan(
+			&lesson.ID,
+			&lesson.CourseID,
+			&lesson.ModuleID,
+			&lesson.Position,
+			&lesson.Title,
+			&lesson.VideoURL,
+			&lesson.CreatedAt,
+			&lesson.UpdatedAt,
+			&lesson.DeletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan lesson: %w", err)
+		}
+		lessons = append(lessons, &lesson)
+	}
+	return lessons, rows.Err()
+}
+
+// FindUnrendered returns lessons whose HTML has not been rendered yet (created before markdown rendering)
+func (r *LessonRepository) FindUnrendered(ctx context.Context, limit int) ([]*models.Lesson, error) {
+	query := `
+		SELECT id, content
+		FROM lessons
+		WHERE content_html IS NULL
+		ORDER BY id
+		LIMIT $1`
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find unrendered lessons: %w", err)
+	}
+	defer rows.Close()
+
+	var lessons []*models.Lesson
+	for rows.Next() {
+		var lesson models.Lesson
+		if err := rows.Scan(&lesson.ID, &lesson.Content); err != nil {
+			return nil, fmt.Errorf("failed to scan lesson: %w", err)
+		}
+		lessons = append(lessons, &lesson)
+	}
+	return lessons, rows.Err()
+}
+
+// UpdateContentHTML stores rendered HTML without touching updated_at
+func (r *LessonRepository) UpdateContentHTML(ctx context.Context, id int, contentHTML string) error {
+	query := `
+		UPDATE lessons
+		SET content_html = $1
+		WHERE id = $2`
+	_, err := r.db.Exec(ctx, query, contentHTML, id)
+	if err != nil {
+		return fmt.Errorf("failed to update lesson html: %w", err)
+	}
+	return nil
+}
 
 // LessonProgress -----------------------
 
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+)
+
+type LessonRevisionRepositoryInterface interface {
+	Create(ctx context.Context, revision *models.LessonRevision) error
+	UpdateLesson(ctx context.Context, lesson *models.Lesson, revision *models.LessonRevision) error
+	FindByNumber(ctx context.Context, lessonID, number int) (*models.LessonRevision, error)
+	FindByLesson(ctx context.Context, lessonID int) ([]*models.LessonRevision, error)
+}
+
+type LessonRevisionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewLessonRevisionRepository(db *pgxpool.Pool) *LessonRevisionRepository {
+	return &LessonRevisionRepository{db: db}
+}
+
+// Create сохраняет ревизию со следующим номером внутри урока
+func (r *LessonRevisionRepository) Create(ctx context.Context, revision *models.LessonRevision) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT id FROM lessons WHERE id = $1 FOR UPDATE`, revision.LessonID); err != nil {
+		return fmt.Errorf("failed to lock lesson: %w", err)
+	}
+	if err := insertLessonRevision(ctx, tx, revision); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// UpdateLesson сохраняет урок и его ревизию в одной транзакции: урок не меняется без записи в истории
+func (r *LessonRevisionRepository) UpdateLesson(ctx context.Context, lesson *models.Lesson, revision *models.LessonRevision) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := updateLesson(ctx, tx, lesson); err != nil {
+		return err
+	}
+	if err := insertLessonRevision(ctx, tx, revision); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// insertLessonRevision выдаёт ревизии следующий номер. Вызывается, когда строка урока заблокирована
+// в той же транзакции, поэтому параллельные сохранения урока получают разные номера.
+func insertLessonRevision(ctx context.Context, tx pgx.Tx, revision *models.LessonRevision) error {
+	query := `
+		INSERT INTO lesson_revisions (lesson_id, number, author_id, title, content, video_url, restored_from)
+		SELECT $1, COALESCE(MAX(number), 0) + 1, $2, $3, $4, $5, $6
+		FROM lesson_revisions
+		WHERE lesson_id = $1
+		RETURNING id, number, created_at`
+	err := tx.QueryRow(ctx, query, revision.LessonID, revision.AuthorID, revision.Title, revision.Content, revision.VideoURL, revision.RestoredFrom).
+		Scan(&revision.ID, &revision.Number, &revision.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create lesson revision: %w", err)
+	}
+	return nil
+}
+
+// FindByNumber возвращает ревизию урока вместе с содержимым
+func (r *LessonRevisionRepository) FindByNumber(ctx context.Context, lessonID, number int) (*models.LessonRevision, error) {
+	query := `
+		SELECT rv.id, rv.lesson_id, rv.number, rv.author_id, COALESCE(u.username, ''), rv.title, rv.content, rv.video_url, rv.restored_from, rv.created_at
+		FROM lesson_revisions rv
+		LEFT JOIN users u ON u.id = rv.author_id
+		WHERE rv.lesson_id = $1 AND rv.number = $2`
+	var revision models.LessonRevision
+	err := r.db.QueryRow(ctx, query, lessonID, number).Scan(
+		&revision.ID, &revision.LessonID, &revision.Number, &revision.AuthorID, &revision.AuthorName,
+		&revision.Title, &revision.Content, &revision.VideoURL, &revision.RestoredFrom, &revision.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("lesson revision not found: %w", err)
+	}
+	return &revision, nil
+}
+
+// FindByLesson возвращает ревизии урока без содержимого, новые первыми
+func (r *LessonRevisionRepository) FindByLesson(ctx context.Context, lessonID int) ([]*models.LessonRevision, error) {
+	query := `
+		SELECT rv.id, rv.lesson_id, rv.number, rv.author_id, COALESCE(u.username, ''), rv.title, rv.video_url, rv.restored_from, rv.created_at
+		FROM lesson_revisions rv
+		LEFT JOIN users u ON u.id = rv.author_id
+		WHERE rv.lesson_id = $1
+		ORDER BY rv.number DESC`
+	rows, err := r.db.Query(ctx, query, lessonID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch lesson revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []*models.LessonRevision{}
+	for rows.Next() {
+		var revision models.LessonRevision
+		err := rows.Scan(&revision.ID, &revision.LessonID, &revision.Number, &revision.AuthorID, &revision.AuthorName,
+			&revision.Title, &revision.VideoURL, &revision.RestoredFrom, &revision.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan lesson revision: %w", err)
+		}
+		revisions = append(revisions, &revision)
+	}
+	return revisions, rows.Err()
+}
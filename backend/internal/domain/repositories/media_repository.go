@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+)
+
+type MediaRepositoryInterface interface {
+	CreateWithinQuota(ctx context.Context, file *models.MediaFile, quotaBytes int64) (bool, error)
+	FindByID(ctx context.Context, id int) (*models.MediaFile, error)
+	FindByStorageKey(ctx context.Context, key string) (*models.MediaFile, error)
+	FindByOwner(ctx context.Context, ownerID int) ([]*models.MediaFile, error)
+	SumSizeByOwner(ctx context.Context, ownerID int) (int64, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type MediaRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewMediaRepository(db *pgxpool.Pool) *MediaRepository {
+	return &MediaRepository{db: db}
+}
+
+const mediaColumns = `id, owner_id, course_id, filename, content_type, size, public, storage_key, created_at`
+
+func mediaScanFields(file *models.MediaFile) []any {
+	return []any{&file.ID, &file.OwnerID, &file.CourseID, &file.Filename, &file.ContentType, &file.Size, &file.Public, &file.StorageKey, &file.CreatedAt}
+}
+
+// CreateWithinQuota сохраняет файл, если с ним владелец не превысит квоту (0 - без ограничений).
+// Строка владельца блокируется, чтобы параллельные загрузки не обошли квоту.
+func (r *MediaRepository) CreateWithinQuota(ctx context.Context, file *models.MediaFile, quotaBytes int64) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if quotaBytes > 0 {
+		if _, err := tx.Exec(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, file.OwnerID); err != nil {
+			return false, fmt.Errorf("failed to lock media owner: %w", err)
+		}
+		var used int64
+		if err := tx.QueryRow(ctx, `SELECT COALESCE(SUM(size), 0) FROM media_files WHERE owner_id = $1`, file.OwnerID).Scan(&used); err != nil {
+			return false, fmt.Errorf("failed to calculate media usage: %w", err)
+		}
+		if used+file.Size > quotaBytes {
+			return false, nil
+		}
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO media_files (owner_id, course_id, filename, content_type, size, public, storage_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at`,
+		file.OwnerID, file.CourseID, file.Filename, file.ContentType, file.Size, file.Public, file.StorageKey).
+		Scan(&file.ID, &file.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to create media file: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return true, nil
+}
+
+// FindByID ищет файл по ID
+func (r *MediaRepository) FindByID(ctx context.Context, id int) (*models.MediaFile, error) {
+	var file models.MediaFile
+	query := `SELECT ` + mediaColumns + ` FROM media_files WHERE id = $1`
+	if err := r.db.QueryRow(ctx, query, id).Scan(mediaScanFields(&file)...); err != nil {
+		return nil, fmt.Errorf("media file not found: %w", err)
+	}
+	return &file, nil
+}
+
+// FindByStorageKey ищет файл по ключу в хранилище, nil - если такого нет
+func (r *MediaRepository) FindByStorageKey(ctx context.Context, key string) (*models.MediaFile, error) {
+	var file models.MediaFile
+	query := `SELECT ` + mediaColumns + ` FROM media_files WHERE storage_key = $1`
+	err := r.db.QueryRow(ctx, query, key).Scan(mediaScanFields(&file)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find media file: %w", err)
+	}
+	return &file, nil
+}
+
+// FindByOwner возвращает файлы пользователя, начиная с новых
+func (r *MediaRepository) FindByOwner(ctx context.Context, ownerID int) ([]*models.MediaFile, error) {
+	query := `SELECT ` + mediaColumns + ` FROM media_files WHERE owner_id = $1 ORDER BY created_at DESC, id DESC`
+	rows, err := r.db.Query(ctx, query, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find media files: %w", err)
+	}
+	defer rows.Close()
+
+	files := []*models.MediaFile{}
+	for rows.Next() {
+		var file models.MediaFile
+		if err := rows.Scan(mediaScanFields(&file)...); err != nil {
+			return nil, fmt.Errorf("failed to scan media file: %w", err)
+		}
+		files = append(files, &file)
+	}
+	return files, rows.Err()
+}
+
+// SumSizeByOwner возвращает суммарный размер файлов пользователя
+func (r *MediaRepository) SumSizeByOwner(ctx context.Context, ownerID int) (int64, error) {
+	var used int64
+	if err := r.db.QueryRow(ctx, `SELECT COALESCE(SUM(size), 0) FROM media_files WHERE owner_id = $1`, ownerID).Scan(&used); err != nil {
+		return 0, fmt.Errorf("failed to calculate media usage: %w", err)
+	}
+	return used, nil
+}
+
+// Delete удаляет запись о файле
+func (r *MediaRepository) Delete(ctx context.Context, id int) error {
+	if _, err := r.db.Exec(ctx, `DELETE FROM media_files WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete media file: %w", err)
+	}
+	return nil
+}
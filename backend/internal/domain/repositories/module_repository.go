@@ -0,0 +1,231 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+)
+
+type ModuleRepositoryInterface interface {
+	Create(ctx context.Context, module *models.Module) error
+	FindByID(ctx context.Context, id int) (*models.Module, error)
+	FindByCourseID(ctx context.Context, courseID int) ([]*models.Module, error)
+	Update(ctx context.Context, module *models.Module) error
+	Delete(ctx context.Context, id int) error
+	MoveLesson(ctx context.Context, lessonID int, moduleID *int, position int) error
+	RecordCompletion(ctx context.Context, userID, courseID, moduleID int) (bool, error)
+	FindCompletions(ctx context.Context, userID, courseID int) (map[int]time.Time, error)
+	DeleteCompletions(ctx context.Context, courseID, moduleID int, userIDs []int) error
+}
+
+type ModuleRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewModuleRepository(db *pgxpool.Pool) *ModuleRepository {
+	return &ModuleRepository{db: db}
+}
+
+// Create добавляет модуль, без указания позиции - в конец курса
+func (r *ModuleRepository) Create(ctx context.Context, module *models.Module) error {
+	query := `
+		INSERT INTO course_modules (course_id, title, description, position)
+		VALUES ($1, $2, $3, CASE WHEN $4 > 0 THEN $4
+			ELSE COALESCE((SELECT MAX(position) FROM course_modules WHERE course_id = $1), 0) + 1 END)
+		RETURNING id, position, created_at, updated_at`
+	err := r.db.QueryRow(ctx, query, module.CourseID, module.Title, module.Description, module.Position).
+		Scan(&module.ID, &module.Position, &module.CreatedAt, &module.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create module: %w", err)
+	}
+	return nil
+}
+
+// FindByID ищет модуль по ID
+func (r *ModuleRepository) FindByID(ctx context.Context, id int) (*models.Module, error) {
+	var module models.Module
+	query := `
+		SELECT id, course_id, title, description, position, created_at, updated_at
+		FROM course_modules WHERE id = $1`
+	err := r.db.QueryRow(ctx, query, id).
+		Scan(&module.ID, &module.CourseID, &module.Title, &module.Description, &module.Position, &module.CreatedAt, &module.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("module not found: %w", err)
+	}
+	return &module, nil
+}
+
+// FindByCourseID возвращает модули курса по порядку
+func (r *ModuleRepository) FindByCourseID(ctx context.Context, courseID int) ([]*models.Module, error) {
+	query := `
+		SELECT id, course_id, title, description, position, created_at, updated_at
+		FROM course_modules WHERE course_id = $1
+		ORDER BY position, id`
+	rows, err := r.db.Query(ctx, query, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch modules: %w", err)
+	}
+	defer rows.Close()
+
+	var modules []*models.Module
+	for rows.Next() {
+		var module models.Module
+		if err := rows.Scan(&module.ID, &module.CourseID, &module.Title, &module.Description, &module.Position, &module.CreatedAt, &module.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning module: %w", err)
+		}
+		modules = append(modules, &module)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return modules, nil
+}
+
+// Update обновляет название, описание и позицию модуля
+func (r *ModuleRepository) Update(ctx context.Context, module *models.Module) error {
+	query := `
+		UPDATE course_modules
+		SET title = $1, description = $2, position = $3, updated_at = NOW()
+		WHERE id = $4
+		RETURNING updated_at`
+	err := r.db.QueryRow(ctx, query, module.Title, module.Description, module.Position, module.ID).Scan(&module.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update module: %w", err)
+	}
+	return nil
+}
+
+// Delete удаляет модуль, его уроки переходят в модуль по умолчанию
+func (r *ModuleRepository) Delete(ctx context.Context, id int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// уроки ставятся в конец модуля по умолчанию, сохраняя свой порядок
+	_, err = tx.Exec(ctx, `
+		UPDATE lessons l
+		SET module_id = NULL, position = moved.position, updated_at = NOW()
+		FROM (
+			SELECT id, COALESCE((SELECT MAX(position) FROM lessons
+				WHERE course_id = src.course_id AND module_id IS NULL), 0)
+				+ ROW_NUMBER() OVER (ORDER BY position, id) AS position
+			FROM lessons src WHERE module_id = $1
+		) moved
+		WHERE l.id = moved.id`, id)
+	if err != nil {
+		return fmt.Errorf("failed to move module lessons: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM course_modules WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete module: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// MoveLesson переносит урок в модуль (nil - модуль по умолчанию) на указанную позицию,
+// сдвигая следующие уроки. position <= 0 - в конец модуля.
+func (r *ModuleRepository) MoveLesson(ctx context.Context, lessonID int, moduleID *int, position int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var courseID int
+	if err := tx.QueryRow(ctx, `SELECT course_id FROM lessons WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, lessonID).Scan(&courseID); err != nil {
+		return fmt.Errorf("lesson not found: %w", err)
+	}
+
+	if position <= 0 {
+		err = tx.QueryRow(ctx, `
+			SELECT COALESCE(MAX(position), 0) + 1 FROM lessons
+			WHERE course_id = $1 AND module_id IS NOT DISTINCT FROM $2 AND id <> $3`,
+			courseID, moduleID, lessonID).Scan(&position)
+		if err != nil {
+			return fmt.Errorf("failed to find lesson position: %w", err)
+		}
+	} else {
+		_, err = tx.Exec(ctx, `
+			UPDATE lessons SET position = position + 1
+			WHERE course_id = $1 AND module_id IS NOT DISTINCT FROM $2 AND position >= $3 AND id <> $4`,
+			courseID, moduleID, position, lessonID)
+		if err != nil {
+			return fmt.Errorf("failed to shift lessons: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE lessons SET module_id = $1, position = $2, updated_at = NOW() WHERE id = $3`,
+		moduleID, position, lessonID)
+	if err != nil {
+		return fmt.Errorf("failed to move lesson: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// RecordCompletion фиксирует завершение модуля студентом. Возвращает false,
+// если модуль уже был отмечен завершённым ранее.
+func (r *ModuleRepository) RecordCompletion(ctx context.Context, userID, courseID, moduleID int) (bool, error) {
+	query := `
+		INSERT INTO module_completions (user_id, course_id, module_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, course_id, module_id) DO NOTHING`
+	commandTag, err := r.db.Exec(ctx, query, userID, courseID, moduleID)
+	if err != nil {
+		return false, fmt.Errorf("failed to record module completion: %w", err)
+	}
+	return commandTag.RowsAffected() == 1, nil
+}
+
+// DeleteCompletions снимает отметку о завершении модуля с перечисленных студентов
+func (r *ModuleRepository) DeleteCompletions(ctx context.Context, courseID, moduleID int, userIDs []int) error {
+	query := `
+		DELETE FROM module_completions
+		WHERE course_id = $1 AND module_id = $2 AND user_id = ANY($3)`
+	if _, err := r.db.Exec(ctx, query, courseID, moduleID, userIDs); err != nil {
+		return fmt.Errorf("failed to delete module completion: %w", err)
+	}
+	return nil
+}
+
+// FindCompletions возвращает время завершения модулей курса студентом по ID модуля
+func (r *ModuleRepository) FindCompletions(ctx context.Context, userID, courseID int) (map[int]time.Time, error) {
+	query := `
+		SELECT module_id, completed_at FROM module_completions
+		WHERE user_id = $1 AND course_id = $2`
+	rows, err := r.db.Query(ctx, query, userID, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch module completions: %w", err)
+	}
+	defer rows.Close()
+
+	completions := make(map[int]time.Time)
+	for rows.Next() {
+		var moduleID int
+		var completedAt time.Time
+		if err := rows.Scan(&moduleID, &completedAt); err != nil {
+			return nil, fmt.Errorf("error scanning module completion: %w", err)
+		}
+		completions[moduleID] = completedAt
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return completions, nil
+}
@@ -0,0 +1,214 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+)
+
+type OrderRepositoryInterface interface {
+	Create(ctx context.Context, order *models.Order) (*models.Order, error)
+	FindByID(ctx context.Context, id int) (*models.Order, error)
+	FindByPaymentID(ctx context.Context, provider, paymentID string) (*models.Order, error)
+	FindByUser(ctx context.Context, userID int) ([]*models.Order, error)
+	SetPayment(ctx context.Context, order *models.Order) error
+	MarkPaid(ctx context.Context, id int) (bool, error)
+	MarkFailed(ctx context.Context, id int) (bool, error)
+	MarkRefunded(ctx context.Context, id int) (bool, error)
+}
+
+type OrderRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewOrderRepository(db *pgxpool.Pool) *OrderRepository {
+	return &OrderRepository{db: db}
+}
+
+const orderColumns = `o.id, o.user_id, o.course_id, c.name, o.coupon_id, o.coupon_code,
+		o.amount_cents, o.discount_cents, o.total_cents, o.currency, o.status,
+		o.provider, o.payment_id, o.checkout_url, o.paid_at, o.refunded_at,
+		o.created_at, o.updated_at`
+
+func orderScanFields(order *models.Order) []any {
+	return []any{
+		&order.ID, &order.UserID, &order.CourseID, &order.CourseName, &order.CouponID, &order.CouponCode,
+		&order.AmountCents, &order.DiscountCents, &order.TotalCents, &order.Currency, &order.Status,
+		&order.Provider, &order.PaymentID, &order.CheckoutURL, &order.PaidAt, &order.RefundedAt,
+		&order.CreatedAt, &order.UpdatedAt,
+	}
+}
+
+// Create добавляет заказ. Если у пользователя уже есть ожидающий оплаты заказ на этот курс,
+// новый не создаётся и возвращается существующий; строка пользователя блокируется,
+// чтобы одновременные запросы не создали два ожидающих заказа.
+func (r *OrderRepository) Create(ctx context.Context, order *models.Order) (*models.Order, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, order.UserID); err != nil {
+		return nil, fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	var pending models.Order
+	query := `SELECT ` + orderColumns + ` FROM orders o JOIN courses c ON c.id = o.course_id
+		WHERE o.user_id = $1 AND o.course_id = $2 AND o.status = $3
+		ORDER BY o.id DESC
+		LIMIT 1`
+	err = tx.QueryRow(ctx, query, order.UserID, order.CourseID, models.OrderStatusPending).Scan(orderScanFields(&pending)...)
+	if err == nil {
+		return &pending, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to fetch pending order: %w", err)
+	}
+
+	query = `
+		INSERT INTO orders (user_id, course_id, coupon_id, coupon_code, amount_cents, discount_cents, total_cents, currency, status, provider)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at, updated_at`
+	err = tx.QueryRow(ctx, query, order.UserID, order.CourseID, order.CouponID, order.CouponCode,
+		order.AmountCents, order.DiscountCents, order.TotalCents, order.Currency, order.Status, order.Provider).
+		Scan(&order.ID, &order.CreatedAt, &order.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil, nil
+}
+
+// FindByID ищет заказ по ID
+func (r *OrderRepository) FindByID(ctx context.Context, id int) (*models.Order, error) {
+	var order models.Order
+	query := `SELECT ` + orderColumns + ` FROM orders o JOIN courses c ON c.id = o.course_id WHERE o.id = $1`
+	if err := r.db.QueryRow(ctx, query, id).Scan(orderScanFields(&order)...); err != nil {
+		return nil, fmt.Errorf("order not found: %w", err)
+	}
+	return &order, nil
+}
+
+// FindByPaymentID ищет заказ по ID платежа у провайдера, nil - если такого платежа нет
+func (r *OrderRepository) FindByPaymentID(ctx context.Context, provider, paymentID string) (*models.Order, error) {
+	var order models.Order
+	query := `SELECT ` + orderColumns + ` FROM orders o JOIN courses c ON c.id = o.course_id
+		WHERE o.provider = $1 AND o.payment_id = $2`
+	err := r.db.QueryRow(ctx, query, provider, paymentID).Scan(orderScanFields(&order)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch order: %w", err)
+	}
+	return &order, nil
+}
+
+// FindByUser возвращает заказы пользователя, новые первыми
+func (r *OrderRepository) FindByUser(ctx context.Context, userID int) ([]*models.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders o JOIN courses c ON c.id = o.course_id
+		WHERE o.user_id = $1
+		ORDER BY o.created_at DESC, o.id DESC`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch orders: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []*models.Order
+	for rows.Next() {
+		var order models.Order
+		if err := rows.Scan(orderScanFields(&order)...); err != nil {
+			return nil, fmt.Errorf("error scanning order: %w", err)
+		}
+		orders = append(orders, &order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return orders, nil
+}
+
+// SetPayment сохраняет платёж, созданный у провайдера для заказа
+func (r *OrderRepository) SetPayment(ctx context.Context, order *models.Order) error {
+	query := `
+		UPDATE orders SET provider = $1, payment_id = $2, checkout_url = $3, updated_at = NOW()
+		WHERE id = $4
+		RETURNING updated_at`
+	err := r.db.QueryRow(ctx, query, order.Provider, order.PaymentID, order.CheckoutURL, order.ID).Scan(&order.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update order payment: %w", err)
+	}
+	return nil
+}
+
+// MarkPaid переводит ожидающий заказ в оплаченные и засчитывает использование купона.
+// Возвращает false, если заказ уже не ожидал оплаты (повторный вебхук), и
+// models.ErrCouponLimitReached, если купон исчерпан - тогда заказ остаётся ожидающим.
+func (r *OrderRepository) MarkPaid(ctx context.Context, id int) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var couponID *int
+	err = tx.QueryRow(ctx, `
+		UPDATE orders SET status = $1, paid_at = NOW(), updated_at = NOW()
+		WHERE id = $2 AND status = $3
+		RETURNING coupon_id`, models.OrderStatusPaid, id, models.OrderStatusPending).Scan(&couponID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to mark order paid: %w", err)
+	}
+
+	if couponID != nil {
+		commandTag, err := tx.Exec(ctx, `
+			UPDATE coupons SET used_count = used_count + 1
+			WHERE id = $1 AND (max_uses IS NULL OR used_count < max_uses)`, *couponID)
+		if err != nil {
+			return false, fmt.Errorf("failed to update coupon usage: %w", err)
+		}
+		if commandTag.RowsAffected() == 0 {
+			return false, models.ErrCouponLimitReached
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return true, nil
+}
+
+// MarkFailed отмечает неудачную оплату ожидающего заказа
+func (r *OrderRepository) MarkFailed(ctx context.Context, id int) (bool, error) {
+	commandTag, err := r.db.Exec(ctx, `
+		UPDATE orders SET status = $1, updated_at = NOW()
+		WHERE id = $2 AND status = $3`, models.OrderStatusFailed, id, models.OrderStatusPending)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark order failed: %w", err)
+	}
+	return commandTag.RowsAffected() == 1, nil
+}
+
+// MarkRefunded отмечает возврат оплаченного заказа
+func (r *OrderRepository) MarkRefunded(ctx context.Context, id int) (bool, error) {
+	commandTag, err := r.db.Exec(ctx, `
+		UPDATE orders SET status = $1, refunded_at = NOW(), updated_at = NOW()
+		WHERE id = $2 AND status = $3`, models.OrderStatusRefunded, id, models.OrderStatusPaid)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark order refunded: %w", err)
+	}
+	return commandTag.RowsAffected() == 1, nil
+}
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+)
+
+// сколько похожих курсов хранится для каждого курса
+const similarCoursesLimit = 50
+
+type RecommendationRepositoryInterface interface {
+	RecomputeSimilarities(ctx context.Context) (int, error)
+	FindSimilar(ctx context.Context, courseIDs []int) ([]*models.CourseSimilarity, error)
+}
+
+type RecommendationRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRecommendationRepository(db *pgxpool.Pool) *RecommendationRepository {
+	return &RecommendationRepository{db: db}
+}
+
+// RecomputeSimilarities пересчитывает похожесть курсов по совместным записям студентов
+// и заменяет ею предыдущий расчёт. Возвращает число сохранённых пар.
+func (r *RecommendationRepository) RecomputeSimilarities(ctx context.Context) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM course_similarities`); err != nil {
+		return 0, fmt.Errorf("failed to clear course similarities: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `
+		WITH sizes AS (
+			SELECT course_id, COUNT(*) AS students FROM enrollments GROUP BY course_id
+		), pairs AS (
+			SELECT a.course_id, b.course_id AS similar_course_id, COUNT(*) AS co_enrolled
+			FROM enrollments a
+			JOIN enrollments b ON b.user_id = a.user_id AND b.course_id <> a.course_id
+			GROUP BY a.course_id, b.course_id
+		), ranked AS (
+			SELECT p.course_id, p.similar_course_id, p.co_enrolled,
+				p.co_enrolled::FLOAT / (sa.students + sb.students - p.co_enrolled) AS score
+			FROM pairs p
+			JOIN sizes sa ON sa.course_id = p.course_id
+			JOIN sizes sb ON sb.course_id = p.similar_course_id
+		)
+		INSERT INTO course_similarities (course_id, similar_course_id, score, co_enrolled)
+		SELECT course_id, similar_course_id, score, co_enrolled
+		FROM (
+			SELECT *, ROW_NUMBER() OVER (PARTITION BY course_id ORDER BY score DESC, similar_course_id) AS position
+			FROM ranked
+		) top
+		WHERE position <= $1`, similarCoursesLimit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute course similarities: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// FindSimilar возвращает курсы, похожие на любой из courseIDs
+func (r *RecommendationRepository) FindSimilar(ctx context.Context, courseIDs []int) ([]*models.CourseSimilarity, error) {
+	query := `
+		SELECT course_id, similar_course_id, score, co_enrolled, computed_at
+		FROM course_similarities
+		WHERE course_id = ANY($1)`
+	rows, err := r.db.Query(ctx, query, courseIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find course similarities: %w", err)
+	}
+	defer rows.Close()
+
+	var similarities []*models.CourseSimilarity
+	for rows.Next() {
+		var similarity models.CourseSimilarity
+		if err := rows.Scan(&similarity.CourseID, &similarity.SimilarCourseID, &similarity.Score, &similarity.CoEnrolled, &similarity.ComputedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan course similarity: %w", err)
+		}
+		similarities = append(similarities, &similarity)
+	}
+	return similarities, rows.Err()
+}
@@ -0,0 +1,262 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+)
+
+type ReviewRepositoryInterface interface {
+	Create(ctx context.Context, review *models.Review) error
+	FindByID(ctx context.Context, id int) (*models.Review, error)
+	FindByCourseAndUser(ctx context.Context, courseID, userID int) (*models.Review, error)
+	FindByCourse(ctx context.Context, courseID int, includeHidden bool) ([]*models.Review, error)
+	FindReported(ctx context.Context) ([]*models.Review, error)
+	Update(ctx context.Context, review *models.Review) error
+	Delete(ctx context.Context, id int) error
+	SetReply(ctx context.Context, id int, authorID int, reply *string) error
+	SetStatus(ctx context.Context, id int, status string) error
+	AddReport(ctx context.Context, report *models.ReviewReport) error
+}
+
+type ReviewRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewReviewRepository(db *pgxpool.Pool) *ReviewRepository {
+	return &ReviewRepository{db: db}
+}
+
+const reviewColumns = `r.id, r.course_id, r.user_id, u.username, r.rating, r.body, r.status,
+		r.reply, r.reply_author_id, r.replied_at,
+		(SELECT COUNT(*) FROM review_reports rr WHERE rr.review_id = r.id),
+		r.created_at, r.updated_at`
+
+func reviewScanFields(review *models.Review) []any {
+	return []any{
+		&review.ID, &review.CourseID, &review.UserID, &review.Username, &review.Rating, &review.Body, &review.Status,
+		&review.Reply, &review.ReplyAuthorID, &review.RepliedAt,
+		&review.ReportsCount,
+		&review.CreatedAt, &review.UpdatedAt,
+	}
+}
+
+// updateCourseRating пересчитывает средний рейтинг и число видимых отзывов курса
+func updateCourseRating(ctx context.Context, tx pgx.Tx, courseID int) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE courses SET
+			rating_average = COALESCE((SELECT ROUND(AVG(rating)::numeric, 2)::float8 FROM course_reviews WHERE course_id = $1 AND status = $2), 0),
+			rating_count = (SELECT COUNT(*) FROM course_reviews WHERE course_id = $1 AND status = $2)
+		WHERE id = $1`, courseID, models.ReviewStatusVisible)
+	if err != nil {
+		return fmt.Errorf("failed to update course rating: %w", err)
+	}
+	return nil
+}
+
+// Create добавляет отзыв и пересчитывает рейтинг курса
+func (r *ReviewRepository) Create(ctx context.Context, review *models.Review) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO course_reviews (course_id, user_id, rating, body, status)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at`,
+		review.CourseID, review.UserID, review.Rating, review.Body, review.Status).
+		Scan(&review.ID, &review.CreatedAt, &review.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create review: %w", err)
+	}
+
+	if err := updateCourseRating(ctx, tx, review.CourseID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *ReviewRepository) FindByID(ctx context.Context, id int) (*models.Review, error) {
+	review := &models.Review{}
+	err := r.db.QueryRow(ctx, `
+		SELECT `+reviewColumns+`
+		FROM course_reviews r
+		JOIN users u ON u.id = r.user_id
+		WHERE r.id = $1`, id).Scan(reviewScanFields(review)...)
+	if err != nil {
+		return nil, fmt.Errorf("review not found: %w", err)
+	}
+	return review, nil
+}
+
+// FindByCourseAndUser возвращает отзыв студента о курсе или nil, если его нет
+func (r *ReviewRepository) FindByCourseAndUser(ctx context.Context, courseID, userID int) (*models.Review, error) {
+	review := &models.Review{}
+	err := r.db.QueryRow(ctx, `
+		SELECT `+reviewColumns+`
+		FROM course_reviews r
+		JOIN users u ON u.id = r.user_id
+		WHERE r.course_id = $1 AND r.user_id = $2`, courseID, userID).Scan(reviewScanFields(review)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find review: %w", err)
+	}
+	return review, nil
+}
+
+// FindByCourse возвращает отзывы курса, новые первыми
+func (r *ReviewRepository) FindByCourse(ctx context.Context, courseID int, includeHidden bool) ([]*models.Review, error) {
+	return r.queryReviews(ctx, `
+		SELECT `+reviewColumns+`
+		FROM course_reviews r
+		JOIN users u ON u.id = r.user_id
+		WHERE r.course_id = $1 AND ($2 OR r.status = $3)
+		ORDER BY r.created_at DESC, r.id DESC`, courseID, includeHidden, models.ReviewStatusVisible)
+}
+
+// FindReported возвращает отзывы с жалобами для модерации, самые обжалованные первыми
+func (r *ReviewRepository) FindReported(ctx context.Context) ([]*models.Review, error) {
+	return r.queryReviews(ctx, `
+		SELECT `+reviewColumns+`
+		FROM course_reviews r
+		JOIN users u ON u.id = r.user_id
+		WHERE EXISTS (SELECT 1 FROM review_reports rr WHERE rr.review_id = r.id)
+		ORDER BY (SELECT COUNT(*) FROM review_reports rr WHERE rr.review_id = r.id) DESC, r.id`)
+}
+
+func (r *ReviewRepository) queryReviews(ctx context.Context, query string, args ...any) ([]*models.Review, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := []*models.Review{}
+	for rows.Next() {
+		review := &models.Review{}
+		if err := rows.Scan(reviewScanFields(review)...); err != nil {
+			return nil, fmt.Errorf("failed to scan review: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+// Update меняет оценку и текст отзыва и пересчитывает рейтинг курса
+func (r *ReviewRepository) Update(ctx context.Context, review *models.Review) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		UPDATE course_reviews SET rating = $1, body = $2, updated_at = NOW()
+		WHERE id = $3
+		RETURNING updated_at`, review.Rating, review.Body, review.ID).Scan(&review.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update review: %w", err)
+	}
+
+	if err := updateCourseRating(ctx, tx, review.CourseID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Delete удаляет отзыв и пересчитывает рейтинг курса
+func (r *ReviewRepository) Delete(ctx context.Context, id int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var courseID int
+	if err := tx.QueryRow(ctx, `DELETE FROM course_reviews WHERE id = $1 RETURNING course_id`, id).Scan(&courseID); err != nil {
+		return fmt.Errorf("review not found: %w", err)
+	}
+
+	if err := updateCourseRating(ctx, tx, courseID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// SetReply сохраняет ответ преподавателя, nil удаляет ответ
+func (r *ReviewRepository) SetReply(ctx context.Context, id int, authorID int, reply *string) error {
+	var err error
+	if reply == nil {
+		_, err = r.db.Exec(ctx, `
+			UPDATE course_reviews SET reply = NULL, reply_author_id = NULL, replied_at = NULL
+			WHERE id = $1`, id)
+	} else {
+		_, err = r.db.Exec(ctx, `
+			UPDATE course_reviews SET reply = $1, reply_author_id = $2, replied_at = NOW()
+			WHERE id = $3`, *reply, authorID, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save review reply: %w", err)
+	}
+	return nil
+}
+
+// SetStatus скрывает или показывает отзыв и пересчитывает рейтинг курса
+func (r *ReviewRepository) SetStatus(ctx context.Context, id int, status string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var courseID int
+	err = tx.QueryRow(ctx, `UPDATE course_reviews SET status = $1 WHERE id = $2 RETURNING course_id`, status, id).Scan(&courseID)
+	if err != nil {
+		return fmt.Errorf("review not found: %w", err)
+	}
+
+	if err := updateCourseRating(ctx, tx, courseID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// AddReport сохраняет жалобу, повторная жалоба того же пользователя обновляет причину
+func (r *ReviewRepository) AddReport(ctx context.Context, report *models.ReviewReport) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO review_reports (review_id, user_id, reason)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (review_id, user_id) DO UPDATE SET reason = EXCLUDED.reason
+		RETURNING created_at`, report.ReviewID, report.UserID, report.Reason).Scan(&report.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to report review: %w", err)
+	}
+	return nil
+}
@@ -3,8 +3,9 @@ package repositories
 import (
 	"context"
 	"fmt"
+	"strings"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"gitlab.com/w0ikid/study-platform/internal/domain/models"
 )
 
@@ -16,10 +17,12 @@ type UserRepositoryInterface interface {
 	Delete(ctx context.Context, id int) error
 	FindByNameLike(ctx context.Context, name string) ([]*models.User, error)
 	UpdateXpAndLevel(ctx context.Context, user *models.User) error
+	FindByUsernamesOrEmails(ctx context.Context, usernames, emails []string) ([]*models.User, error)
+	ImportUsers(ctx context.Context, entries []*models.UserImportEntry) error
 }
 
 type UserRepository struct {
-	db *pgx.Conn
+	db *pgxpool.Pool
 }
 
 // // xpRequiredForLevel — формула расчета XP для уровня
@@ -27,7 +30,7 @@ type UserRepository struct {
 // 	return 100 * level * level
 // }
 
-func NewUserRepository(db *pgx.Conn) *UserRepository {
+func NewUserRepository(db *pgxpool.Pool) *UserRepository {
 	return &UserRepository{db: db}
 }
 
@@ -116,4 +119,70 @@ func (r *UserRepository) UpdateXpAndLevel(ctx context.Context, user *models.User
 	
 	_, err := r.db.Exec(ctx, query, user.Xp, user.Level, user.ID)
 	return err
-}
\ No newline at end of file
+}
+
+// FindByUsernamesOrEmails возвращает пользователей, у которых совпадает username или email
+func (r *UserRepository) FindByUsernamesOrEmails(ctx context.Context, usernames, emails []string) ([]*models.User, error) {
+	query := `SELECT id, username, name, surname, email, password, role, created_at, updated_at FROM users
+	          WHERE username = ANY($1) OR LOWER(email) = ANY($2)`
+
+	lowerEmails := make([]string, len(emails))
+	for i, email := range emails {
+		lowerEmails[i] = strings.ToLower(email)
+	}
+
+	rows, err := r.db.Query(ctx, query, usernames, lowerEmails)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.ID, &user.Username, &user.Name, &user.Surname, &user.Email, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, &user)
+	}
+
+	return users, rows.Err()
+}
+
+// ImportUsers создаёт пользователей одной транзакцией: либо все, либо никто.
+// Пароли должны быть уже захешированы. Пользователь сразу добавляется в группу;
+// на курсы его зачисляет usecase по общим правилам записи.
+func (r *UserRepository) ImportUsers(ctx context.Context, entries []*models.UserImportEntry) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, entry := range entries {
+		user := entry.User
+		err := tx.QueryRow(ctx,
+			`INSERT INTO users (username, name, surname, email, password, role, created_at, updated_at)
+			 VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW()) RETURNING id, created_at`,
+			user.Username, user.Name, user.Surname, user.Email, user.Password, user.Role).
+			Scan(&user.ID, &user.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create user %s: %w", user.Username, err)
+		}
+
+		if entry.GroupID != 0 {
+			_, err := tx.Exec(ctx,
+				`INSERT INTO group_members (group_id, user_id, role) VALUES ($1, $2, $3)
+				 ON CONFLICT (group_id, user_id) DO NOTHING`,
+				entry.GroupID, user.ID, models.GroupRoleMember)
+			if err != nil {
+				return fmt.Errorf("failed to add user %s to group: %w", user.Username, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit user import: %w", err)
+	}
+	return nil
+}
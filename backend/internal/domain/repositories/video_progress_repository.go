@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+)
+
+type VideoProgressRepositoryInterface interface {
+	FindByUserAndLesson(ctx context.Context, userID, lessonID int) (*models.VideoProgress, error)
+	FindByUserAndCourse(ctx context.Context, userID, courseID int) ([]*models.VideoProgress, error)
+	FindByLesson(ctx context.Context, lessonID int) ([]*models.VideoProgress, error)
+	Update(ctx context.Context, userID, lessonID, courseID int, apply func(progress *models.VideoProgress) error) (*models.VideoProgress, error)
+	MarkCompleted(ctx context.Context, userID, lessonID int) error
+}
+
+type VideoProgressRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewVideoProgressRepository(db *pgxpool.Pool) *VideoProgressRepository {
+	return &VideoProgressRepository{db: db}
+}
+
+const videoProgressColumns = `p.user_id, p.lesson_id, p.course_id, p.position, p.duration, p.intervals, p.watched_seconds,
+	p.skip_count, p.skipped_seconds, p.rejected_seconds, p.credited_seconds, p.completed_at, p.first_heartbeat_at, p.last_heartbeat_at`
+
+func videoProgressScanFields(progress *models.VideoProgress) []any {
+	return []any{
+		&progress.UserID, &progress.LessonID, &progress.CourseID, &progress.Position, &progress.Duration, &progress.Intervals,
+		&progress.WatchedSeconds, &progress.SkipCount, &progress.SkippedSeconds, &progress.RejectedSeconds,
+		&progress.CreditedSeconds, &progress.CompletedAt, &progress.FirstHeartbeatAt, &progress.LastHeartbeatAt,
+	}
+}
+
+// FindByUserAndLesson возвращает прогресс просмотра; nil, если студент ещё не смотрел видео
+func (r *VideoProgressRepository) FindByUserAndLesson(ctx context.Context, userID, lessonID int) (*models.VideoProgress, error) {
+	query := `SELECT ` + videoProgressColumns + ` FROM video_progress p WHERE p.user_id = $1 AND p.lesson_id = $2`
+	var progress models.VideoProgress
+	err := r.db.QueryRow(ctx, query, userID, lessonID).Scan(videoProgressScanFields(&progress)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find video progress: %w", err)
+	}
+	return &progress, nil
+}
+
+// FindByUserAndCourse возвращает прогресс просмотра всех видео курса студентом
+func (r *VideoProgressRepository) FindByUserAndCourse(ctx context.Context, userID, courseID int) ([]*models.VideoProgress, error) {
+	query := `SELECT ` + videoProgressColumns + ` FROM video_progress p WHERE p.user_id = $1 AND p.course_id = $2`
+	return r.queryProgress(ctx, query, userID, courseID)
+}
+
+// FindByLesson возвращает прогресс просмотра видео урока всеми студентами
+func (r *VideoProgressRepository) FindByLesson(ctx context.Context, lessonID int) ([]*models.VideoProgress, error) {
+	query := `
+		SELECT ` + videoProgressColumns + `, u.username
+		FROM video_progress p
+		JOIN users u ON u.id = p.user_id
+		WHERE p.lesson_id = $1
+		ORDER BY u.username`
+	rows, err := r.db.Query(ctx, query, lessonID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch video progress: %w", err)
+	}
+	defer rows.Close()
+
+	result := []*models.VideoProgress{}
+	for rows.Next() {
+		var progress models.VideoProgress
+		if err := rows.Scan(append(videoProgressScanFields(&progress), &progress.Username)...); err != nil {
+			return nil, fmt.Errorf("failed to scan video progress: %w", err)
+		}
+		result = append(result, &progress)
+	}
+	return result, rows.Err()
+}
+
+// Update блокирует прогресс просмотра (создавая пустой, если студент ещё не смотрел видео),
+// изменяет его через apply и сохраняет в той же транзакции. Параллельные heartbeat
+// выполняются по очереди и не затирают отрезки друг друга; отметка completed_at не меняется.
+func (r *VideoProgressRepository) Update(ctx context.Context, userID, lessonID, courseID int, apply func(progress *models.VideoProgress) error) (*models.VideoProgress, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO video_progress (user_id, lesson_id, course_id) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, lesson_id) DO NOTHING`, userID, lessonID, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create video progress: %w", err)
+	}
+	query := `SELECT ` + videoProgressColumns + ` FROM video_progress p WHERE p.user_id = $1 AND p.lesson_id = $2 FOR UPDATE`
+	var progress models.VideoProgress
+	if err := tx.QueryRow(ctx, query, userID, lessonID).Scan(videoProgressScanFields(&progress)...); err != nil {
+		return nil, fmt.Errorf("failed to lock video progress: %w", err)
+	}
+
+	if err := apply(&progress); err != nil {
+		return nil, err
+	}
+
+	query = `
+		UPDATE video_progress SET
+			position = $3,
+			duration = $4,
+			intervals = $5,
+			watched_seconds = $6,
+			skip_count = $7,
+			skipped_seconds = $8,
+			rejected_seconds = $9,
+			credited_seconds = $10,
+			first_heartbeat_at = $11,
+			last_heartbeat_at = $12
+		WHERE user_id = $1 AND lesson_id = $2`
+	_, err = tx.Exec(ctx, query, userID, lessonID, progress.Position, progress.Duration, progress.Intervals,
+		progress.WatchedSeconds, progress.SkipCount, progress.SkippedSeconds, progress.RejectedSeconds,
+		progress.CreditedSeconds, progress.FirstHeartbeatAt, progress.LastHeartbeatAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save video progress: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return &progress, nil
+}
+
+// MarkCompleted запоминает, что просмотр засчитал урок
+func (r *VideoProgressRepository) MarkCompleted(ctx context.Context, userID, lessonID int) error {
+	query := `UPDATE video_progress SET completed_at = NOW() WHERE user_id = $1 AND lesson_id = $2 AND completed_at IS NULL`
+	if _, err := r.db.Exec(ctx, query, userID, lessonID); err != nil {
+		return fmt.Errorf("failed to complete video progress: %w", err)
+	}
+	return nil
+}
+
+func (r *VideoProgressRepository) queryProgress(ctx context.Context, query string, args ...any) ([]*models.VideoProgress, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch video progress: %w", err)
+	}
+	defer rows.Close()
+
+	result := []*models.VideoProgress{}
+	for rows.Next() {
+		var progress models.VideoProgress
+		if err := rows.Scan(videoProgressScanFields(&progress)...); err != nil {
+			return nil, fmt.Errorf("failed to scan video progress: %w", err)
+		}
+		result = append(result, &progress)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/repositories"
+)
+
+type AnalyticsServiceInterface interface {
+	GetEnrollmentsOverTime(ctx context.Context, filter models.AnalyticsFilter) ([]*models.PeriodCount, error)
+	GetLessonFunnel(ctx context.Context, filter models.AnalyticsFilter) ([]*models.LessonFunnelEntry, error)
+	GetCompletionSummary(ctx context.Context, filter models.AnalyticsFilter) (*models.CompletionSummary, error)
+	GetProgressDistribution(ctx context.Context, filter models.AnalyticsFilter) ([]*models.DistributionBucket, error)
+	GetTimeToCompleteDistribution(ctx context.Context, filter models.AnalyticsFilter) ([]*models.DistributionBucket, error)
+	GetMedianHoursBetweenLessons(ctx context.Context, filter models.AnalyticsFilter) (*float64, error)
+}
+
+type AnalyticsService struct {
+	repo repositories.AnalyticsRepositoryInterface
+}
+
+func NewAnalyticsService(repo repositories.AnalyticsRepositoryInterface) AnalyticsServiceInterface {
+	return &AnalyticsService{repo: repo}
+}
+
+// GetEnrollmentsOverTime возвращает число записей на курс по периодам
+func (s *AnalyticsService) GetEnrollmentsOverTime(ctx context.Context, filter models.AnalyticsFilter) ([]*models.PeriodCount, error) {
+	return s.repo.FindEnrollmentsOverTime(ctx, filter)
+}
+
+// GetLessonFunnel возвращает завершения и точки ухода по урокам курса
+func (s *AnalyticsService) GetLessonFunnel(ctx context.Context, filter models.AnalyticsFilter) ([]*models.LessonFunnelEntry, error) {
+	return s.repo.FindLessonFunnel(ctx, filter)
+}
+
+// GetCompletionSummary возвращает сводку по завершению курса
+func (s *AnalyticsService) GetCompletionSummary(ctx context.Context, filter models.AnalyticsFilter) (*models.CompletionSummary, error) {
+	return s.repo.FindCompletionSummary(ctx, filter)
+}
+
+// GetProgressDistribution возвращает распределение студентов по прогрессу
+func (s *AnalyticsService) GetProgressDistribution(ctx context.Context, filter models.AnalyticsFilter) ([]*models.DistributionBucket, error) {
+	return s.repo.FindProgressDistribution(ctx, filter)
+}
+
+// GetTimeToCompleteDistribution возвращает распределение по времени прохождения курса
+func (s *AnalyticsService) GetTimeToCompleteDistribution(ctx context.Context, filter models.AnalyticsFilter) ([]*models.DistributionBucket, error) {
+	return s.repo.FindTimeToCompleteDistribution(ctx, filter)
+}
+
+// GetMedianHoursBetweenLessons возвращает медиану времени между уроками
+func (s *AnalyticsService) GetMedianHoursBetweenLessons(ctx context.Context, filter models.AnalyticsFilter) (*float64, error) {
+	return s.repo.FindMedianHoursBetweenLessons(ctx, filter)
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/repositories"
+)
+
+type AnnouncementServiceInterface interface {
+	CreateAnnouncement(ctx context.Context, announcement *models.Announcement) error
+	GetAnnouncement(ctx context.Context, id, userID int) (*models.Announcement, error)
+	GetCourseAnnouncements(ctx context.Context, courseID, userID int, includeScheduled bool) ([]*models.Announcement, error)
+	GetFeed(ctx context.Context, userID int, unreadOnly bool) ([]*models.Announcement, error)
+	GetPendingEmails(ctx context.Context) ([]*models.Announcement, error)
+	ClaimEmail(ctx context.Context, id int) (bool, error)
+	UpdateAnnouncement(ctx context.Context, announcement *models.Announcement) error
+	DeleteAnnouncement(ctx context.Context, id int) error
+	MarkRead(ctx context.Context, id, userID int) error
+	GetReads(ctx context.Context, id int) ([]*models.AnnouncementRead, error)
+}
+
+type AnnouncementService struct {
+	repo repositories.AnnouncementRepositoryInterface
+}
+
+func NewAnnouncementService(repo repositories.AnnouncementRepositoryInterface) AnnouncementServiceInterface {
+	return &AnnouncementService{repo: repo}
+}
+
+// CreateAnnouncement сохраняет объявление
+func (s *AnnouncementService) CreateAnnouncement(ctx context.Context, announcement *models.Announcement) error {
+	return s.repo.Create(ctx, announcement)
+}
+
+// GetAnnouncement возвращает объявление с отметкой о прочтении пользователем
+func (s *AnnouncementService) GetAnnouncement(ctx context.Context, id, userID int) (*models.Announcement, error) {
+	return s.repo.FindByID(ctx, id, userID)
+}
+
+// GetCourseAnnouncements возвращает объявления курса
+func (s *AnnouncementService) GetCourseAnnouncements(ctx context.Context, courseID, userID int, includeScheduled bool) ([]*models.Announcement, error) {
+	return s.repo.FindByCourse(ctx, courseID, userID, includeScheduled)
+}
+
+// GetFeed возвращает ленту объявлений пользователя по всем его курсам
+func (s *AnnouncementService) GetFeed(ctx context.Context, userID int, unreadOnly bool) ([]*models.Announcement, error) {
+	return s.repo.FindFeed(ctx, userID, unreadOnly)
+}
+
+// GetPendingEmails возвращает опубликованные объявления, ожидающие рассылки
+func (s *AnnouncementService) GetPendingEmails(ctx context.Context) ([]*models.Announcement, error) {
+	return s.repo.FindPendingEmails(ctx)
+}
+
+// ClaimEmail отмечает рассылку объявления начатой
+func (s *AnnouncementService) ClaimEmail(ctx context.Context, id int) (bool, error) {
+	return s.repo.ClaimEmail(ctx, id)
+}
+
+// UpdateAnnouncement сохраняет изменения объявления
+func (s *AnnouncementService) UpdateAnnouncement(ctx context.Context, announcement *models.Announcement) error {
+	return s.repo.Update(ctx, announcement)
+}
+
+// DeleteAnnouncement удаляет объявление
+func (s *AnnouncementService) DeleteAnnouncement(ctx context.Context, id int) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// MarkRead отмечает объявление прочитанным
+func (s *AnnouncementService) MarkRead(ctx context.Context, id, userID int) error {
+	return s.repo.MarkRead(ctx, id, userID)
+}
+
+// GetReads возвращает отметки о прочтении
+func (s *AnnouncementService) GetReads(ctx context.Context, id int) ([]*models.AnnouncementRead, error) {
+	return s.repo.FindReads(ctx, id)
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/repositories"
+)
+
+type AssignmentServiceInterface interface {
+	GetLessonAssignment(ctx context.Context, lessonID int) (*models.Assignment, error)
+	GetCourseAssignments(ctx context.Context, courseID int) ([]*models.Assignment, error)
+	SaveAssignment(ctx context.Context, assignment *models.Assignment) error
+	DeleteAssignment(ctx context.Context, lessonID int) error
+	CreateSubmission(ctx context.Context, submission *models.AssignmentSubmission) error
+	GetSubmission(ctx context.Context, id int) (*models.AssignmentSubmission, error)
+	GetSubmissions(ctx context.Context, assignmentID int, userID *int) ([]*models.AssignmentSubmission, error)
+	GetGradingQueue(ctx context.Context, courseID int) ([]*models.AssignmentSubmission, error)
+	GetLatestGraded(ctx context.Context, assignmentID, userID int) (*models.AssignmentSubmission, error)
+	SaveGrade(ctx context.Context, submissionID int, grade *models.AssignmentGrade) error
+}
+
+type AssignmentService struct {
+	repo repositories.AssignmentRepositoryInterface
+}
+
+func NewAssignmentService(repo repositories.AssignmentRepositoryInterface) AssignmentServiceInterface {
+	return &AssignmentService{repo: repo}
+}
+
+// GetLessonAssignment возвращает задание урока или nil
+func (s *AssignmentService) GetLessonAssignment(ctx context.Context, lessonID int) (*models.Assignment, error) {
+	return s.repo.FindByLesson(ctx, lessonID)
+}
+
+// GetCourseAssignments возвращает задания всех уроков курса
+func (s *AssignmentService) GetCourseAssignments(ctx context.Context, courseID int) ([]*models.Assignment, error) {
+	return s.repo.FindByCourse(ctx, courseID)
+}
+
+// SaveAssignment создаёт или заменяет задание урока
+func (s *AssignmentService) SaveAssignment(ctx context.Context, assignment *models.Assignment) error {
+	return s.repo.Save(ctx, assignment)
+}
+
+// DeleteAssignment удаляет задание урока
+func (s *AssignmentService) DeleteAssignment(ctx context.Context, lessonID int) error {
+	return s.repo.Delete(ctx, lessonID)
+}
+
+// CreateSubmission сохраняет новую попытку сдачи
+func (s *AssignmentService) CreateSubmission(ctx context.Context, submission *models.AssignmentSubmission) error {
+	return s.repo.CreateSubmission(ctx, submission)
+}
+
+// GetSubmission возвращает попытку по ID
+func (s *AssignmentService) GetSubmission(ctx context.Context, id int) (*models.AssignmentSubmission, error) {
+	return s.repo.FindSubmission(ctx, id)
+}
+
+// GetSubmissions возвращает попытки по заданию, при userID - только одного студента
+func (s *AssignmentService) GetSubmissions(ctx context.Context, assignmentID int, userID *int) ([]*models.AssignmentSubmission, error) {
+	return s.repo.FindSubmissions(ctx, assignmentID, userID)
+}
+
+// GetGradingQueue возвращает непроверенные работы курса
+func (s *AssignmentService) GetGradingQueue(ctx context.Context, courseID int) ([]*models.AssignmentSubmission, error) {
+	return s.repo.FindGradingQueue(ctx, courseID)
+}
+
+// GetLatestGraded возвращает последнюю оценённую попытку студента или nil
+func (s *AssignmentService) GetLatestGraded(ctx context.Context, assignmentID, userID int) (*models.AssignmentSubmission, error) {
+	return s.repo.FindLatestGraded(ctx, assignmentID, userID)
+}
+
+// SaveGrade сохраняет оценку попытки
+func (s *AssignmentService) SaveGrade(ctx context.Context, submissionID int, grade *models.AssignmentGrade) error {
+	return s.repo.SaveGrade(ctx, submissionID, grade)
+}
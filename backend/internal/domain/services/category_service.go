@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/repositories"
+)
+
+type CategoryServiceInterface interface {
+	CreateCategory(ctx context.Context, category *models.Category) (*models.Category, error)
+	GetCategory(ctx context.Context, id int) (*models.Category, error)
+	GetAllCategories(ctx context.Context) ([]*models.Category, error)
+	UpdateCategory(ctx context.Context, category *models.Category) error
+	DeleteCategory(ctx context.Context, id int) error
+	IsDescendant(ctx context.Context, categoryID, ancestorID int) (bool, error)
+}
+
+type CategoryService struct {
+	repo repositories.CategoryRepositoryInterface
+}
+
+func NewCategoryService(repo repositories.CategoryRepositoryInterface) CategoryServiceInterface {
+	return &CategoryService{repo: repo}
+}
+
+// CreateCategory создаёт категорию
+func (s *CategoryService) CreateCategory(ctx context.Context, category *models.Category) (*models.Category, error) {
+	if err := s.repo.Create(ctx, category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// GetCategory возвращает категорию по ID
+func (s *CategoryService) GetCategory(ctx context.Context, id int) (*models.Category, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// GetAllCategories возвращает все категории
+func (s *CategoryService) GetAllCategories(ctx context.Context) ([]*models.Category, error) {
+	return s.repo.FindAll(ctx)
+}
+
+// UpdateCategory обновляет категорию
+func (s *CategoryService) UpdateCategory(ctx context.Context, category *models.Category) error {
+	return s.repo.Update(ctx, category)
+}
+
+// DeleteCategory удаляет категорию
+func (s *CategoryService) DeleteCategory(ctx context.Context, id int) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *CategoryService) IsDescendant(ctx context.Context, categoryID, ancestorID int) (bool, error) {
+	return s.repo.IsDescendant(ctx, categoryID, ancestorID)
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/repositories"
+)
+
+type CouponServiceInterface interface {
+	CreateCoupon(ctx context.Context, coupon *models.Coupon) error
+	GetCoupon(ctx context.Context, id int) (*models.Coupon, error)
+	GetCouponByCode(ctx context.Context, code string) (*models.Coupon, error)
+	GetAllCoupons(ctx context.Context) ([]*models.Coupon, error)
+	GetCourseCoupons(ctx context.Context, courseID int) ([]*models.Coupon, error)
+	DeleteCoupon(ctx context.Context, id int) error
+}
+
+type CouponService struct {
+	repo repositories.CouponRepositoryInterface
+}
+
+func NewCouponService(repo repositories.CouponRepositoryInterface) CouponServiceInterface {
+	return &CouponService{repo: repo}
+}
+
+// CreateCoupon сохраняет купон
+func (s *CouponService) CreateCoupon(ctx context.Context, coupon *models.Coupon) error {
+	return s.repo.Create(ctx, coupon)
+}
+
+// GetCoupon возвращает купон по ID
+func (s *CouponService) GetCoupon(ctx context.Context, id int) (*models.Coupon, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// GetCouponByCode возвращает купон по коду, nil - если такого нет
+func (s *CouponService) GetCouponByCode(ctx context.Context, code string) (*models.Coupon, error) {
+	return s.repo.FindByCode(ctx, code)
+}
+
+// GetAllCoupons возвращает все купоны
+func (s *CouponService) GetAllCoupons(ctx context.Context) ([]*models.Coupon, error) {
+	return s.repo.FindAll(ctx)
+}
+
+// GetCourseCoupons возвращает купоны курса
+func (s *CouponService) GetCourseCoupons(ctx context.Context, courseID int) ([]*models.Coupon, error) {
+	return s.repo.FindByCourse(ctx, courseID)
+}
+
+// DeleteCoupon удаляет купон
+func (s *CouponService) DeleteCoupon(ctx context.Context, id int) error {
+	return s.repo.Delete(ctx, id)
+}
@@ -10,8 +10,27 @@ type CourseServiceInterface interface {
 	CreateCourse(ctx context.Context, course *models.Course) (*models.Course, error)
 	GetCourse(ctx context.Context, id int) (*models.Course, error)
 	GetAllCourses(ctx context.Context) ([]models.Course, error)
+	GetCatalog(ctx context.Context, filter models.CourseFilter) ([]models.Course, error)
 	UpdateCourse(ctx context.Context, course *models.Course) error
 	DeleteCourse(ctx context.Context, id int) error
+	AddTags(ctx context.Context, courseID int, tags []string) error
+	RemoveTag(ctx context.Context, courseID int, tag string) error
+	GetAllTags(ctx context.Context) ([]models.TagCount, error)
+	GetPrerequisites(ctx context.Context, courseID int) ([]*models.CoursePrerequisite, error)
+	SetPrerequisites(ctx context.Context, courseID int, prerequisiteIDs []int, minUserLevel int) error
+	DependsOn(ctx context.Context, courseID, prerequisiteID int) (bool, error)
+	GetMissingPrerequisites(ctx context.Context, courseID, userID int) ([]*models.CoursePrerequisite, error)
+	GetStaff(ctx context.Context, courseID int) ([]*models.CourseStaff, error)
+	GetStaffMember(ctx context.Context, courseID, userID int) (*models.CourseStaff, error)
+	AddStaff(ctx context.Context, member *models.CourseStaff) (*models.CourseStaff, error)
+	RemoveStaff(ctx context.Context, courseID, userID int) error
+	TransferOwnership(ctx context.Context, courseID, newOwnerID int) error
+	GetRoster(ctx context.Context, courseID int) ([]*models.CourseRosterEntry, error)
+	CloneCourse(ctx context.Context, sourceID int, clone *models.Course, includeEnrollments bool) error
+	FindCoursesByName(ctx context.Context, name string) ([]models.Course, error)
+	UpdateSchedule(ctx context.Context, course *models.Course) error
+	UpdatePrice(ctx context.Context, course *models.Course) error
+	CreateCourseWithContent(ctx context.Context, course *models.Course, prerequisiteIDs []int, modules []*models.Module, lessons []*models.Lesson) error
 }
 
 type CourseService struct {
@@ -40,6 +59,11 @@ func (s *CourseService) GetAllCourses(ctx context.Context) ([]models.Course, err
 	return s.repo.FindAll(ctx)
 }
 
+// GetCatalog возвращает курсы, подходящие под фильтр
+func (s *CourseService) GetCatalog(ctx context.Context, filter models.CourseFilter) ([]models.Course, error) {
+	return s.repo.FindByFilter(ctx, filter)
+}
+
 // UpdateCourse обновляет курс
 func (s *CourseService) UpdateCourse(ctx context.Context, course *models.Course) error {
 	return s.repo.Update(ctx, course)
@@ -49,3 +73,96 @@ func (s *CourseService) UpdateCourse(ctx context.Context, course *models.Course)
 func (s *CourseService) DeleteCourse(ctx context.Context, id int) error {
 	return s.repo.Delete(ctx, id)
 }
+
+// AddTags добавляет теги курсу
+func (s *CourseService) AddTags(ctx context.Context, courseID int, tags []string) error {
+	return s.repo.AddTags(ctx, courseID, tags)
+}
+
+// RemoveTag удаляет тег курса
+func (s *CourseService) RemoveTag(ctx context.Context, courseID int, tag string) error {
+	return s.repo.RemoveTag(ctx, courseID, tag)
+}
+
+// GetAllTags возвращает все теги с количеством курсов
+func (s *CourseService) GetAllTags(ctx context.Context) ([]models.TagCount, error) {
+	return s.repo.FindAllTags(ctx)
+}
+
+// GetPrerequisites возвращает пререквизиты курса
+func (s *CourseService) GetPrerequisites(ctx context.Context, courseID int) ([]*models.CoursePrerequisite, error) {
+	return s.repo.FindPrerequisites(ctx, courseID)
+}
+
+// SetPrerequisites заменяет пререквизиты и минимальный уровень курса
+func (s *CourseService) SetPrerequisites(ctx context.Context, courseID int, prerequisiteIDs []int, minUserLevel int) error {
+	return s.repo.SetPrerequisites(ctx, courseID, prerequisiteIDs, minUserLevel)
+}
+
+// DependsOn проверяет, требует ли курс (прямо или транзитивно) завершения prerequisiteID
+func (s *CourseService) DependsOn(ctx context.Context, courseID, prerequisiteID int) (bool, error) {
+	return s.repo.HasPrerequisitePath(ctx, courseID, prerequisiteID)
+}
+
+// GetMissingPrerequisites возвращает незавершённые пользователем пререквизиты курса
+func (s *CourseService) GetMissingPrerequisites(ctx context.Context, courseID, userID int) ([]*models.CoursePrerequisite, error) {
+	return s.repo.FindMissingPrerequisites(ctx, courseID, userID)
+}
+
+// GetStaff возвращает команду курса
+func (s *CourseService) GetStaff(ctx context.Context, courseID int) ([]*models.CourseStaff, error) {
+	return s.repo.FindStaff(ctx, courseID)
+}
+
+// GetStaffMember возвращает участника команды курса или nil
+func (s *CourseService) GetStaffMember(ctx context.Context, courseID, userID int) (*models.CourseStaff, error) {
+	return s.repo.FindStaffMember(ctx, courseID, userID)
+}
+
+// AddStaff добавляет пользователя в команду курса
+func (s *CourseService) AddStaff(ctx context.Context, member *models.CourseStaff) (*models.CourseStaff, error) {
+	if err := s.repo.AddStaff(ctx, member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// RemoveStaff исключает пользователя из команды курса
+func (s *CourseService) RemoveStaff(ctx context.Context, courseID, userID int) error {
+	return s.repo.RemoveStaff(ctx, courseID, userID)
+}
+
+// TransferOwnership передаёт курс новому владельцу
+func (s *CourseService) TransferOwnership(ctx context.Context, courseID, newOwnerID int) error {
+	return s.repo.TransferOwnership(ctx, courseID, newOwnerID)
+}
+
+// GetRoster возвращает студентов курса
+func (s *CourseService) GetRoster(ctx context.Context, courseID int) ([]*models.CourseRosterEntry, error) {
+	return s.repo.FindRoster(ctx, courseID)
+}
+
+// CloneCourse создаёт копию курса со всем содержимым
+func (s *CourseService) CloneCourse(ctx context.Context, sourceID int, clone *models.Course, includeEnrollments bool) error {
+	return s.repo.Clone(ctx, sourceID, clone, includeEnrollments)
+}
+
+// FindCoursesByName ищет курсы по точному названию
+func (s *CourseService) FindCoursesByName(ctx context.Context, name string) ([]models.Course, error) {
+	return s.repo.FindByName(ctx, name)
+}
+
+// CreateCourseWithContent создаёт курс вместе с модулями и уроками
+func (s *CourseService) CreateCourseWithContent(ctx context.Context, course *models.Course, prerequisiteIDs []int, modules []*models.Module, lessons []*models.Lesson) error {
+	return s.repo.CreateWithContent(ctx, course, prerequisiteIDs, modules, lessons)
+}
+
+// UpdateSchedule сохраняет расписание курса и лимит мест
+func (s *CourseService) UpdateSchedule(ctx context.Context, course *models.Course) error {
+	return s.repo.UpdateSchedule(ctx, course)
+}
+
+// UpdatePrice сохраняет цену курса
+func (s *CourseService) UpdatePrice(ctx context.Context, course *models.Course) error {
+	return s.repo.UpdatePrice(ctx, course)
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/repositories"
+)
+
+type CourseVersionServiceInterface interface {
+	PublishVersion(ctx context.Context, version *models.CourseVersion) error
+	GetVersion(ctx context.Context, id int) (*models.CourseVersion, error)
+	GetLatestVersion(ctx context.Context, courseID int) (*models.CourseVersion, error)
+	GetVersions(ctx context.Context, courseID int) ([]*models.CourseVersion, error)
+	GetPinnedVersion(ctx context.Context, userID, courseID int) (*int, error)
+	UpgradeEnrollment(ctx context.Context, userID, courseID, versionID int, resetLessonIDs []int) error
+}
+
+type CourseVersionService struct {
+	repo repositories.CourseVersionRepositoryInterface
+}
+
+func NewCourseVersionService(repo repositories.CourseVersionRepositoryInterface) CourseVersionServiceInterface {
+	return &CourseVersionService{repo: repo}
+}
+
+// PublishVersion сохраняет новую версию курса
+func (s *CourseVersionService) PublishVersion(ctx context.Context, version *models.CourseVersion) error {
+	return s.repo.Create(ctx, version)
+}
+
+// GetVersion возвращает версию со снимком содержимого
+func (s *CourseVersionService) GetVersion(ctx context.Context, id int) (*models.CourseVersion, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// GetLatestVersion возвращает последнюю версию курса, nil - если версий нет
+func (s *CourseVersionService) GetLatestVersion(ctx context.Context, courseID int) (*models.CourseVersion, error) {
+	return s.repo.FindLatest(ctx, courseID)
+}
+
+// GetVersions возвращает историю версий курса
+func (s *CourseVersionService) GetVersions(ctx context.Context, courseID int) ([]*models.CourseVersion, error) {
+	return s.repo.FindByCourse(ctx, courseID)
+}
+
+// GetPinnedVersion возвращает ID версии, которую видит студент, закрепляя её при первом обращении
+func (s *CourseVersionService) GetPinnedVersion(ctx context.Context, userID, courseID int) (*int, error) {
+	return s.repo.PinEnrollment(ctx, userID, courseID)
+}
+
+// UpgradeEnrollment переводит студента на новую версию курса
+func (s *CourseVersionService) UpgradeEnrollment(ctx context.Context, userID, courseID, versionID int, resetLessonIDs []int) error {
+	return s.repo.UpgradeEnrollment(ctx, userID, courseID, versionID, resetLessonIDs)
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/repositories"
+)
+
+type DiscussionServiceInterface interface {
+	CreateThread(ctx context.Context, thread *models.DiscussionThread) error
+	GetThread(ctx context.Context, id, userID int) (*models.DiscussionThread, error)
+	GetThreads(ctx context.Context, courseID int, lessonID *int, userID int) ([]*models.DiscussionThread, error)
+	UpdateThread(ctx context.Context, thread *models.DiscussionThread, editorID int) error
+	DeleteThread(ctx context.Context, id int) error
+	SetAcceptedPost(ctx context.Context, threadID int, postID *int) error
+	VoteThread(ctx context.Context, threadID, userID int, upvote bool) error
+	CreatePost(ctx context.Context, post *models.DiscussionPost) error
+	GetPost(ctx context.Context, id, userID int) (*models.DiscussionPost, error)
+	GetPosts(ctx context.Context, threadID, userID int) ([]*models.DiscussionPost, error)
+	UpdatePost(ctx context.Context, post *models.DiscussionPost, editorID int) error
+	DeletePost(ctx context.Context, post *models.DiscussionPost) error
+	VotePost(ctx context.Context, postID, userID int, upvote bool) error
+	GetEdits(ctx context.Context, threadID int, postID *int) ([]*models.DiscussionEdit, error)
+}
+
+type DiscussionService struct {
+	repo repositories.DiscussionRepositoryInterface
+}
+
+func NewDiscussionService(repo repositories.DiscussionRepositoryInterface) DiscussionServiceInterface {
+	return &DiscussionService{repo: repo}
+}
+
+// CreateThread сохраняет тему обсуждения
+func (s *DiscussionService) CreateThread(ctx context.Context, thread *models.DiscussionThread) error {
+	return s.repo.CreateThread(ctx, thread)
+}
+
+// GetThread возвращает тему с голосом пользователя
+func (s *DiscussionService) GetThread(ctx context.Context, id, userID int) (*models.DiscussionThread, error) {
+	return s.repo.FindThreadByID(ctx, id, userID)
+}
+
+// GetThreads возвращает темы курса или урока
+func (s *DiscussionService) GetThreads(ctx context.Context, courseID int, lessonID *int, userID int) ([]*models.DiscussionThread, error) {
+	return s.repo.FindThreads(ctx, courseID, lessonID, userID)
+}
+
+// UpdateThread изменяет тему с сохранением истории
+func (s *DiscussionService) UpdateThread(ctx context.Context, thread *models.DiscussionThread, editorID int) error {
+	return s.repo.UpdateThread(ctx, thread, editorID)
+}
+
+// DeleteThread удаляет тему
+func (s *DiscussionService) DeleteThread(ctx context.Context, id int) error {
+	return s.repo.DeleteThread(ctx, id)
+}
+
+// SetAcceptedPost отмечает принятый ответ
+func (s *DiscussionService) SetAcceptedPost(ctx context.Context, threadID int, postID *int) error {
+	return s.repo.SetAcceptedPost(ctx, threadID, postID)
+}
+
+// VoteThread ставит или снимает голос за тему
+func (s *DiscussionService) VoteThread(ctx context.Context, threadID, userID int, upvote bool) error {
+	return s.repo.VoteThread(ctx, threadID, userID, upvote)
+}
+
+// CreatePost сохраняет ответ
+func (s *DiscussionService) CreatePost(ctx context.Context, post *models.DiscussionPost) error {
+	return s.repo.CreatePost(ctx, post)
+}
+
+// GetPost возвращает ответ с голосом пользователя
+func (s *DiscussionService) GetPost(ctx context.Context, id, userID int) (*models.DiscussionPost, error) {
+	return s.repo.FindPostByID(ctx, id, userID)
+}
+
+// GetPosts возвращает ответы темы списком
+func (s *DiscussionService) GetPosts(ctx context.Context, threadID, userID int) ([]*models.DiscussionPost, error) {
+	return s.repo.FindPosts(ctx, threadID, userID)
+}
+
+// UpdatePost изменяет ответ с сохранением истории
+func (s *DiscussionService) UpdatePost(ctx context.Context, post *models.DiscussionPost, editorID int) error {
+	return s.repo.UpdatePost(ctx, post, editorID)
+}
+
+// DeletePost удаляет ответ
+func (s *DiscussionService) DeletePost(ctx context.Context, post *models.DiscussionPost) error {
+	return s.repo.DeletePost(ctx, post)
+}
+
+// VotePost ставит или снимает голос за ответ
+func (s *DiscussionService) VotePost(ctx context.Context, postID, userID int, upvote bool) error {
+	return s.repo.VotePost(ctx, postID, userID, upvote)
+}
+
+// GetEdits возвращает историю правок темы или ответа
+func (s *DiscussionService) GetEdits(ctx context.Context, threadID int, postID *int) ([]*models.DiscussionEdit, error) {
+	return s.repo.FindEdits(ctx, threadID, postID)
+}
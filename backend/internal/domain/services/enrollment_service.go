@@ -13,10 +13,16 @@ type EnrollmentServiceInterface interface {
 	GetEnrollmentByID(ctx context.Context, id int) (*models.Enrollment, error)
 	IsUserEnrolled(ctx context.Context, userID, courseID int) (bool, error)
 	MarkAsCompleted(ctx context.Context, userID, courseID int) error
+	MarkAsActive(ctx context.Context, userID, courseID int) error
 	GetEnrollmentsByUser(ctx context.Context, userID int) ([]*models.Enrollment, error)
 	GetEnrollmentsByCourse(ctx context.Context, courseID int) ([]*models.Enrollment, error)
 	DeleteEnrollment(ctx context.Context, id int) error
 	GetEnrollmentByUserAndCourse(ctx context.Context, userID, courseID int) (*models.Enrollment, error)
+	Enroll(ctx context.Context, enrollment *models.Enrollment, joinWaitlist bool) (*models.EnrollmentResult, error)
+	PromoteWaitlisted(ctx context.Context, courseID int) ([]*models.WaitlistEntry, error)
+	GetWaitlist(ctx context.Context, courseID int) ([]*models.WaitlistEntry, error)
+	GetWaitlistEntry(ctx context.Context, courseID, userID int) (*models.WaitlistEntry, error)
+	LeaveWaitlist(ctx context.Context, courseID, userID int) error
 }
 type EnrollmentService struct {
 	repo repositories.EnrollmentRepositoryInterface
@@ -59,6 +65,15 @@ func (s *EnrollmentService) MarkAsCompleted(ctx context.Context, userID, courseI
 	return s.repo.UpdateStatus(ctx, enrollmentID.ID, "completed")
 }
 
+// MarkAsActive возвращает завершённую запись на курс в статус active
+func (s *EnrollmentService) MarkAsActive(ctx context.Context, userID, courseID int) error {
+	enrollment, err := s.repo.FindByUserAndCourseID(ctx, userID, courseID)
+	if err != nil {
+		return err
+	}
+	return s.repo.UpdateStatus(ctx, enrollment.ID, "active")
+}
+
 func (s *EnrollmentService) GetEnrollmentByUserAndCourse(ctx context.Context, userID, courseID int) (*models.Enrollment, error) {
 	return s.repo.FindByUserAndCourseID(ctx, userID, courseID)
 }
@@ -73,4 +88,26 @@ func (s *EnrollmentService) GetEnrollmentsByCourse(ctx context.Context, courseID
 
 func (s *EnrollmentService) DeleteEnrollment(ctx context.Context, id int) error {
 	return s.repo.Delete(ctx, id)
-}
\ No newline at end of file
+}
+
+// Enroll записывает на курс с учётом лимита мест, при нехватке мест - в лист ожидания
+func (s *EnrollmentService) Enroll(ctx context.Context, enrollment *models.Enrollment, joinWaitlist bool) (*models.EnrollmentResult, error) {
+	return s.repo.Enroll(ctx, enrollment, joinWaitlist)
+}
+
+// PromoteWaitlisted переводит студентов из листа ожидания на освободившиеся места
+func (s *EnrollmentService) PromoteWaitlisted(ctx context.Context, courseID int) ([]*models.WaitlistEntry, error) {
+	return s.repo.PromoteWaitlisted(ctx, courseID)
+}
+
+func (s *EnrollmentService) GetWaitlist(ctx context.Context, courseID int) ([]*models.WaitlistEntry, error) {
+	return s.repo.FindWaitlist(ctx, courseID)
+}
+
+func (s *EnrollmentService) GetWaitlistEntry(ctx context.Context, courseID, userID int) (*models.WaitlistEntry, error) {
+	return s.repo.FindWaitlistEntry(ctx, courseID, userID)
+}
+
+func (s *EnrollmentService) LeaveWaitlist(ctx context.Context, courseID, userID int) error {
+	return s.repo.DeleteWaitlistEntry(ctx, courseID, userID)
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/repositories"
+)
+
+type GroupServiceInterface interface {
+	CreateGroup(ctx context.Context, group *models.Group) (*models.Group, error)
+	GetGroup(ctx context.Context, id int) (*models.Group, error)
+	GetAllGroups(ctx context.Context) ([]*models.Group, error)
+	GetGroupsByUser(ctx context.Context, userID int) ([]*models.Group, error)
+	UpdateGroup(ctx context.Context, group *models.Group) error
+	DeleteGroup(ctx context.Context, id int) error
+
+	AddMember(ctx context.Context, groupID, userID int, role string) (*models.GroupMember, error)
+	RemoveMember(ctx context.Context, groupID, userID int) error
+	GetMember(ctx context.Context, groupID, userID int) (*models.GroupMember, error)
+	GetMembers(ctx context.Context, groupID int) ([]*models.GroupMember, error)
+
+	AddCourse(ctx context.Context, groupID, courseID int) (*models.GroupCourse, error)
+	RemoveCourse(ctx context.Context, groupID, courseID int) error
+	GetCourses(ctx context.Context, groupID int) ([]*models.GroupCourse, error)
+
+	GetMemberProgress(ctx context.Context, groupID, courseID int) ([]*models.GroupMemberProgress, error)
+}
+
+type GroupService struct {
+	repo repositories.GroupRepositoryInterface
+}
+
+func NewGroupService(repo repositories.GroupRepositoryInterface) GroupServiceInterface {
+	return &GroupService{repo: repo}
+}
+
+// CreateGroup создаёт новую группу
+func (s *GroupService) CreateGroup(ctx context.Context, group *models.Group) (*models.Group, error) {
+	if err := s.repo.Create(ctx, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// GetGroup возвращает группу по ID
+func (s *GroupService) GetGroup(ctx context.Context, id int) (*models.Group, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// GetAllGroups возвращает все группы
+func (s *GroupService) GetAllGroups(ctx context.Context) ([]*models.Group, error) {
+	return s.repo.FindAll(ctx)
+}
+
+// GetGroupsByUser возвращает группы пользователя
+func (s *GroupService) GetGroupsByUser(ctx context.Context, userID int) ([]*models.Group, error) {
+	return s.repo.FindByUserID(ctx, userID)
+}
+
+// UpdateGroup обновляет группу
+func (s *GroupService) UpdateGroup(ctx context.Context, group *models.Group) error {
+	return s.repo.Update(ctx, group)
+}
+
+// DeleteGroup удаляет группу
+func (s *GroupService) DeleteGroup(ctx context.Context, id int) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// AddMember добавляет пользователя в группу с указанной ролью
+func (s *GroupService) AddMember(ctx context.Context, groupID, userID int, role string) (*models.GroupMember, error) {
+	member := &models.GroupMember{
+		GroupID: groupID,
+		UserID:  userID,
+		Role:    role,
+	}
+	if err := s.repo.AddMember(ctx, member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+func (s *GroupService) RemoveMember(ctx context.Context, groupID, userID int) error {
+	return s.repo.RemoveMember(ctx, groupID, userID)
+}
+
+func (s *GroupService) GetMember(ctx context.Context, groupID, userID int) (*models.GroupMember, error) {
+	return s.repo.FindMember(ctx, groupID, userID)
+}
+
+func (s *GroupService) GetMembers(ctx context.Context, groupID int) ([]*models.GroupMember, error) {
+	return s.repo.FindMembers(ctx, groupID)
+}
+
+// AddCourse привязывает курс к группе
+func (s *GroupService) AddCourse(ctx context.Context, groupID, courseID int) (*models.GroupCourse, error) {
+	groupCourse := &models.GroupCourse{
+		GroupID:  groupID,
+		CourseID: courseID,
+	}
+	if err := s.repo.AddCourse(ctx, groupCourse); err != nil {
+		return nil, err
+	}
+	return groupCourse, nil
+}
+
+func (s *GroupService) RemoveCourse(ctx context.Context, groupID, courseID int) error {
+	return s.repo.RemoveCourse(ctx, groupID, courseID)
+}
+
+func (s *GroupService) GetCourses(ctx context.Context, groupID int) ([]*models.GroupCourse, error) {
+	return s.repo.FindCourses(ctx, groupID)
+}
+
+// GetMemberProgress возвращает прогресс студентов группы по курсу
+func (s *GroupService) GetMemberProgress(ctx context.Context, groupID, courseID int) ([]*models.GroupMemberProgress, error) {
+	return s.repo.FindMemberProgress(ctx, groupID, courseID)
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/repositories"
+)
+
+type LessonAttachmentServiceInterface interface {
+	CreateAttachment(ctx context.Context, attachment *models.LessonAttachment) error
+	GetAttachment(ctx context.Context, id int) (*models.LessonAttachment, error)
+	GetLessonAttachments(ctx context.Context, lessonID int) ([]*models.LessonAttachment, error)
+	UpdateAttachment(ctx context.Context, attachment *models.LessonAttachment) error
+	DeleteAttachment(ctx context.Context, id int) error
+	CountMediaAttachments(ctx context.Context, mediaID int) (int, error)
+	RecordDownload(ctx context.Context, id int) (int, error)
+}
+
+type LessonAttachmentService struct {
+	repo repositories.LessonAttachmentRepositoryInterface
+}
+
+func NewLessonAttachmentService(repo repositories.LessonAttachmentRepositoryInterface) LessonAttachmentServiceInterface {
+	return &LessonAttachmentService{repo: repo}
+}
+
+// CreateAttachment прикрепляет файл к уроку
+func (s *LessonAttachmentService) CreateAttachment(ctx context.Context, attachment *models.LessonAttachment) error {
+	return s.repo.Create(ctx, attachment)
+}
+
+// GetAttachment возвращает вложение по ID
+func (s *LessonAttachmentService) GetAttachment(ctx context.Context, id int) (*models.LessonAttachment, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// GetLessonAttachments возвращает вложения урока
+func (s *LessonAttachmentService) GetLessonAttachments(ctx context.Context, lessonID int) ([]*models.LessonAttachment, error) {
+	return s.repo.FindByLesson(ctx, lessonID)
+}
+
+// UpdateAttachment сохраняет название и признак водяного знака
+func (s *LessonAttachmentService) UpdateAttachment(ctx context.Context, attachment *models.LessonAttachment) error {
+	return s.repo.Update(ctx, attachment)
+}
+
+// DeleteAttachment открепляет файл от урока
+func (s *LessonAttachmentService) DeleteAttachment(ctx context.Context, id int) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// CountMediaAttachments - число вложений, использующих файл
+func (s *LessonAttachmentService) CountMediaAttachments(ctx context.Context, mediaID int) (int, error) {
+	return s.repo.CountByMedia(ctx, mediaID)
+}
+
+// RecordDownload учитывает скачивание вложения
+func (s *LessonAttachmentService) RecordDownload(ctx context.Context, id int) (int, error) {
+	return s.repo.IncrementDownloads(ctx, id)
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/repositories"
+)
+
+type LessonBlockServiceInterface interface {
+	CreateBlock(ctx context.Context, block *models.LessonBlock) error
+	GetBlock(ctx context.Context, id int) (*models.LessonBlock, error)
+	GetLessonBlocks(ctx context.Context, lessonID, userID int) ([]*models.LessonBlock, error)
+	UpdateBlock(ctx context.Context, block *models.LessonBlock) error
+	DeleteBlock(ctx context.Context, id int) error
+	ReorderBlocks(ctx context.Context, lessonID int, blockIDs []int) error
+	CompleteBlock(ctx context.Context, blockID, userID int) error
+	CountRemainingRequired(ctx context.Context, lessonID, userID int) (int, error)
+}
+
+type LessonBlockService struct {
+	repo repositories.LessonBlockRepositoryInterface
+}
+
+func NewLessonBlockService(repo repositories.LessonBlockRepositoryInterface) LessonBlockServiceInterface {
+	return &LessonBlockService{repo: repo}
+}
+
+// CreateBlock добавляет блок в конец урока
+func (s *LessonBlockService) CreateBlock(ctx context.Context, block *models.LessonBlock) error {
+	return s.repo.Create(ctx, block)
+}
+
+// GetBlock возвращает блок по ID
+func (s *LessonBlockService) GetBlock(ctx context.Context, id int) (*models.LessonBlock, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// GetLessonBlocks возвращает блоки урока с отметками о завершении пользователем
+func (s *LessonBlockService) GetLessonBlocks(ctx context.Context, lessonID, userID int) ([]*models.LessonBlock, error) {
+	return s.repo.FindByLesson(ctx, lessonID, userID)
+}
+
+// UpdateBlock обновляет блок
+func (s *LessonBlockService) UpdateBlock(ctx context.Context, block *models.LessonBlock) error {
+	return s.repo.Update(ctx, block)
+}
+
+// DeleteBlock удаляет блок
+func (s *LessonBlockService) DeleteBlock(ctx context.Context, id int) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// ReorderBlocks меняет порядок блоков урока
+func (s *LessonBlockService) ReorderBlocks(ctx context.Context, lessonID int, blockIDs []int) error {
+	return s.repo.Reorder(ctx, lessonID, blockIDs)
+}
+
+// CompleteBlock отмечает блок завершённым
+func (s *LessonBlockService) CompleteBlock(ctx context.Context, blockID, userID int) error {
+	return s.repo.MarkCompleted(ctx, blockID, userID)
+}
+
+// CountRemainingRequired возвращает число незавершённых обязательных блоков урока
+func (s *LessonBlockService) CountRemainingRequired(ctx context.Context, lessonID, userID int) (int, error) {
+	return s.repo.CountRemainingRequired(ctx, lessonID, userID)
+}
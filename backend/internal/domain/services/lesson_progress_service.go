@@ -13,6 +13,7 @@ type LessonProgressServiceInterface interface {
     MarkLessonCompleted(ctx context.Context, userID, lessonID, courseID int) error
     GetProgressByCourse(ctx context.Context, userID, courseID int) ([]*models.LessonProgress, error)
     GetProgressByLesson(ctx context.Context, userID, lessonID int) (*models.LessonProgress, error)
+    GetCompletedByCourse(ctx context.Context, courseID int) ([]*models.LessonProgress, error)
 }
 
 type LessonProgressService struct {
@@ -50,6 +51,11 @@ func (s *LessonProgressService) GetProgressByCourse(ctx context.Context, userID,
     return s.repo.FindByUserAndCourse(ctx, userID, courseID)
 }
 
+// GetCompletedByCourse возвращает пройденные уроки курса всеми студентами
+func (s *LessonProgressService) GetCompletedByCourse(ctx context.Context, courseID int) ([]*models.LessonProgress, error) {
+    return s.repo.FindCompletedByCourse(ctx, courseID)
+}
+
 func (s *LessonProgressService) GetProgressByLesson(ctx context.Context, userID, lessonID int) (*models.LessonProgress, error) {
     return s.repo.FindByUserAndLesson(ctx, userID, lessonID)
 }
\ No newline at end of file
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/repositories"
+)
+
+type LessonReleaseServiceInterface interface {
+	GetRule(ctx context.Context, lessonID int) (*models.LessonReleaseRule, error)
+	GetCourseRules(ctx context.Context, courseID int) ([]*models.LessonReleaseRule, error)
+	SaveRule(ctx context.Context, rule *models.LessonReleaseRule) error
+	DeleteRule(ctx context.Context, lessonID int) error
+	GetPendingUnlocks(ctx context.Context, lookbackDays int) ([]*models.LessonUnlock, error)
+	MarkNotified(ctx context.Context, userID int, lessonIDs []int) ([]int, error)
+}
+
+type LessonReleaseService struct {
+	repo repositories.LessonReleaseRepositoryInterface
+}
+
+func NewLessonReleaseService(repo repositories.LessonReleaseRepositoryInterface) LessonReleaseServiceInterface {
+	return &LessonReleaseService{repo: repo}
+}
+
+// GetRule возвращает правило открытия урока или nil
+func (s *LessonReleaseService) GetRule(ctx context.Context, lessonID int) (*models.LessonReleaseRule, error) {
+	return s.repo.FindByLesson(ctx, lessonID)
+}
+
+// GetCourseRules возвращает правила открытия уроков курса
+func (s *LessonReleaseService) GetCourseRules(ctx context.Context, courseID int) ([]*models.LessonReleaseRule, error) {
+	return s.repo.FindByCourse(ctx, courseID)
+}
+
+// SaveRule создаёт или заменяет правило открытия урока
+func (s *LessonReleaseService) SaveRule(ctx context.Context, rule *models.LessonReleaseRule) error {
+	return s.repo.Save(ctx, rule)
+}
+
+// DeleteRule убирает правило открытия урока
+func (s *LessonReleaseService) DeleteRule(ctx context.Context, lessonID int) error {
+	return s.repo.Delete(ctx, lessonID)
+}
+
+// GetPendingUnlocks возвращает открывшиеся уроки, о которых студенты ещё не уведомлены
+func (s *LessonReleaseService) GetPendingUnlocks(ctx context.Context, lookbackDays int) ([]*models.LessonUnlock, error) {
+	return s.repo.FindPendingUnlocks(ctx, lookbackDays)
+}
+
+// MarkNotified отмечает уведомление и возвращает уроки, отмеченные этим вызовом
+func (s *LessonReleaseService) MarkNotified(ctx context.Context, userID int, lessonIDs []int) ([]int, error) {
+	return s.repo.MarkNotified(ctx, userID, lessonIDs)
+}
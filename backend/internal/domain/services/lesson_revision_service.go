@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/repositories"
+)
+
+type LessonRevisionServiceInterface interface {
+	RecordRevision(ctx context.Context, lesson *models.Lesson, authorID int, restoredFrom *int) (*models.LessonRevision, error)
+	SaveLesson(ctx context.Context, lesson *models.Lesson, authorID int, restoredFrom *int) (*models.LessonRevision, error)
+	GetRevision(ctx context.Context, lessonID, number int) (*models.LessonRevision, error)
+	GetLessonRevisions(ctx context.Context, lessonID int) ([]*models.LessonRevision, error)
+}
+
+type LessonRevisionService struct {
+	repo repositories.LessonRevisionRepositoryInterface
+}
+
+func NewLessonRevisionService(repo repositories.LessonRevisionRepositoryInterface) LessonRevisionServiceInterface {
+	return &LessonRevisionService{repo: repo}
+}
+
+// RecordRevision сохраняет снимок урока после сохранения
+func (s *LessonRevisionService) RecordRevision(ctx context.Context, lesson *models.Lesson, authorID int, restoredFrom *int) (*models.LessonRevision, error) {
+	revision := newLessonRevision(lesson, authorID, restoredFrom)
+	if err := s.repo.Create(ctx, revision); err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+// SaveLesson сохраняет урок вместе с его новой ревизией в одной транзакции
+func (s *LessonRevisionService) SaveLesson(ctx context.Context, lesson *models.Lesson, authorID int, restoredFrom *int) (*models.LessonRevision, error) {
+	revision := newLessonRevision(lesson, authorID, restoredFrom)
+	if err := s.repo.UpdateLesson(ctx, lesson, revision); err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+func newLessonRevision(lesson *models.Lesson, authorID int, restoredFrom *int) *models.LessonRevision {
+	return &models.LessonRevision{
+		LessonID:     lesson.ID,
+		AuthorID:     &authorID,
+		Title:        lesson.Title,
+		Content:      lesson.Content,
+		VideoURL:     lesson.VideoURL,
+		RestoredFrom: restoredFrom,
+	}
+}
+
+// GetRevision возвращает ревизию урока по номеру
+func (s *LessonRevisionService) GetRevision(ctx context.Context, lessonID, number int) (*models.LessonRevision, error) {
+	return s.repo.FindByNumber(ctx, lessonID, number)
+}
+
+// GetLessonRevisions возвращает историю правок урока
+func (s *LessonRevisionService) GetLessonRevisions(ctx context.Context, lessonID int) ([]*models.LessonRevision, error) {
+	return s.repo.FindByLesson(ctx, lessonID)
+}
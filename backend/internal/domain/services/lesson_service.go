@@ -12,6 +12,10 @@ type LessonServiceInterface interface {
 	GetAllLessons(ctx context.Context, courseID int) ([]*models.Lesson, error)
 	UpdateLesson(ctx context.Context, lesson *models.Lesson) error
 	DeleteLesson(ctx context.Context, id int) error
+	RestoreLesson(ctx context.Context, courseID, id int) (*models.Lesson, error)
+	GetDeletedLessons(ctx context.Context, courseID int) ([]*models.Lesson, error)
+	GetUnrenderedLessons(ctx context.Context, limit int) ([]*models.Lesson, error)
+	UpdateLessonHTML(ctx context.Context, id int, contentHTML string) error
 }
 
 type LessonService struct {
@@ -40,11 +44,31 @@ func (s *LessonService) GetAllLessons(ctx context.Context, courseID int) ([]*mod
 	return s.repo.FindByCourseID(ctx, courseID)
 }
 
+// DeleteLesson переносит урок в корзину курса
 func (s *LessonService) DeleteLesson(ctx context.Context, id int) error {
 	return s.repo.Delete(ctx, id)
 }
 
+// RestoreLesson возвращает урок курса из корзины
+func (s *LessonService) RestoreLesson(ctx context.Context, courseID, id int) (*models.Lesson, error) {
+	return s.repo.Restore(ctx, courseID, id)
+}
+
+// GetDeletedLessons возвращает уроки в корзине курса
+func (s *LessonService) GetDeletedLessons(ctx context.Context, courseID int) ([]*models.Lesson, error) {
+	return s.repo.FindDeletedByCourseID(ctx, courseID)
+}
+
 // UpdateLesson обновляет урок
 func (s *LessonService) UpdateLesson(ctx context.Context, lesson *models.Lesson) error {
 	return s.repo.Update(ctx, lesson)
-}
\ No newline at end of file
+}
+// GetUnrenderedLessons возвращает уроки, для которых ещё не построен HTML
+func (s *LessonService) GetUnrenderedLessons(ctx context.Context, limit int) ([]*models.Lesson, error) {
+	return s.repo.FindUnrendered(ctx, limit)
+}
+
+// UpdateLessonHTML сохраняет HTML урока
+func (s *LessonService) UpdateLessonHTML(ctx context.Context, id int, contentHTML string) error {
+	return s.repo.UpdateContentHTML(ctx, id, contentHTML)
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/repositories"
+)
+
+type MediaServiceInterface interface {
+	CreateFile(ctx context.Context, file *models.MediaFile, quotaBytes int64) (bool, error)
+	GetFile(ctx context.Context, id int) (*models.MediaFile, error)
+	GetFileByStorageKey(ctx context.Context, key string) (*models.MediaFile, error)
+	GetOwnerFiles(ctx context.Context, ownerID int) ([]*models.MediaFile, error)
+	GetUsedBytes(ctx context.Context, ownerID int) (int64, error)
+	DeleteFile(ctx context.Context, id int) error
+}
+
+type MediaService struct {
+	repo repositories.MediaRepositoryInterface
+}
+
+func NewMediaService(repo repositories.MediaRepositoryInterface) MediaServiceInterface {
+	return &MediaService{repo: repo}
+}
+
+// CreateFile сохраняет файл, если владелец укладывается в квоту
+func (s *MediaService) CreateFile(ctx context.Context, file *models.MediaFile, quotaBytes int64) (bool, error) {
+	return s.repo.CreateWithinQuota(ctx, file, quotaBytes)
+}
+
+// GetFile возвращает файл по ID
+func (s *MediaService) GetFile(ctx context.Context, id int) (*models.MediaFile, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// GetFileByStorageKey возвращает файл по ключу в хранилище или nil
+func (s *MediaService) GetFileByStorageKey(ctx context.Context, key string) (*models.MediaFile, error) {
+	return s.repo.FindByStorageKey(ctx, key)
+}
+
+// GetOwnerFiles возвращает файлы пользователя
+func (s *MediaService) GetOwnerFiles(ctx context.Context, ownerID int) ([]*models.MediaFile, error) {
+	return s.repo.FindByOwner(ctx, ownerID)
+}
+
+// GetUsedBytes возвращает место, занятое файлами пользователя
+func (s *MediaService) GetUsedBytes(ctx context.Context, ownerID int) (int64, error) {
+	return s.repo.SumSizeByOwner(ctx, ownerID)
+}
+
+// DeleteFile удаляет запись о файле
+func (s *MediaService) DeleteFile(ctx context.Context, id int) error {
+	return s.repo.Delete(ctx, id)
+}
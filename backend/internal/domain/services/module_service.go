@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/repositories"
+)
+
+type ModuleServiceInterface interface {
+	CreateModule(ctx context.Context, module *models.Module) (*models.Module, error)
+	GetModule(ctx context.Context, id int) (*models.Module, error)
+	GetModulesByCourse(ctx context.Context, courseID int) ([]*models.Module, error)
+	UpdateModule(ctx context.Context, module *models.Module) error
+	DeleteModule(ctx context.Context, id int) error
+	MoveLesson(ctx context.Context, lessonID int, moduleID *int, position int) error
+	RecordCompletion(ctx context.Context, userID, courseID, moduleID int) (bool, error)
+	GetCompletions(ctx context.Context, userID, courseID int) (map[int]time.Time, error)
+	DeleteCompletions(ctx context.Context, courseID, moduleID int, userIDs []int) error
+}
+
+type ModuleService struct {
+	repo repositories.ModuleRepositoryInterface
+}
+
+func NewModuleService(repo repositories.ModuleRepositoryInterface) ModuleServiceInterface {
+	return &ModuleService{repo: repo}
+}
+
+// CreateModule создаёт модуль курса
+func (s *ModuleService) CreateModule(ctx context.Context, module *models.Module) (*models.Module, error) {
+	if err := s.repo.Create(ctx, module); err != nil {
+		return nil, err
+	}
+	return module, nil
+}
+
+// GetModule возвращает модуль по ID
+func (s *ModuleService) GetModule(ctx context.Context, id int) (*models.Module, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// GetModulesByCourse возвращает модули курса
+func (s *ModuleService) GetModulesByCourse(ctx context.Context, courseID int) ([]*models.Module, error) {
+	return s.repo.FindByCourseID(ctx, courseID)
+}
+
+// UpdateModule обновляет модуль
+func (s *ModuleService) UpdateModule(ctx context.Context, module *models.Module) error {
+	return s.repo.Update(ctx, module)
+}
+
+// DeleteModule удаляет модуль
+func (s *ModuleService) DeleteModule(ctx context.Context, id int) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// MoveLesson переносит урок в другой модуль или на другую позицию
+func (s *ModuleService) MoveLesson(ctx context.Context, lessonID int, moduleID *int, position int) error {
+	return s.repo.MoveLesson(ctx, lessonID, moduleID, position)
+}
+
+// RecordCompletion фиксирует завершение модуля
+func (s *ModuleService) RecordCompletion(ctx context.Context, userID, courseID, moduleID int) (bool, error) {
+	return s.repo.RecordCompletion(ctx, userID, courseID, moduleID)
+}
+
+// GetCompletions возвращает завершённые студентом модули курса
+func (s *ModuleService) GetCompletions(ctx context.Context, userID, courseID int) (map[int]time.Time, error) {
+	return s.repo.FindCompletions(ctx, userID, courseID)
+}
+
+// DeleteCompletions снимает отметку о завершении модуля с перечисленных студентов
+func (s *ModuleService) DeleteCompletions(ctx context.Context, courseID, moduleID int, userIDs []int) error {
+	return s.repo.DeleteCompletions(ctx, courseID, moduleID, userIDs)
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/repositories"
+)
+
+type OrderServiceInterface interface {
+	CreateOrder(ctx context.Context, order *models.Order) (*models.Order, error)
+	GetOrder(ctx context.Context, id int) (*models.Order, error)
+	GetOrderByPayment(ctx context.Context, provider, paymentID string) (*models.Order, error)
+	GetUserOrders(ctx context.Context, userID int) ([]*models.Order, error)
+	SetPayment(ctx context.Context, order *models.Order) error
+	MarkPaid(ctx context.Context, id int) (bool, error)
+	MarkFailed(ctx context.Context, id int) (bool, error)
+	MarkRefunded(ctx context.Context, id int) (bool, error)
+}
+
+type OrderService struct {
+	repo repositories.OrderRepositoryInterface
+}
+
+func NewOrderService(repo repositories.OrderRepositoryInterface) OrderServiceInterface {
+	return &OrderService{repo: repo}
+}
+
+// CreateOrder сохраняет новый заказ; если на курс уже есть ожидающий оплаты заказ, возвращает его
+func (s *OrderService) CreateOrder(ctx context.Context, order *models.Order) (*models.Order, error) {
+	return s.repo.Create(ctx, order)
+}
+
+// GetOrder возвращает заказ по ID
+func (s *OrderService) GetOrder(ctx context.Context, id int) (*models.Order, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// GetOrderByPayment возвращает заказ по платежу провайдера, nil - если такого нет
+func (s *OrderService) GetOrderByPayment(ctx context.Context, provider, paymentID string) (*models.Order, error) {
+	return s.repo.FindByPaymentID(ctx, provider, paymentID)
+}
+
+// GetUserOrders возвращает заказы пользователя
+func (s *OrderService) GetUserOrders(ctx context.Context, userID int) ([]*models.Order, error) {
+	return s.repo.FindByUser(ctx, userID)
+}
+
+// SetPayment привязывает к заказу платёж провайдера
+func (s *OrderService) SetPayment(ctx context.Context, order *models.Order) error {
+	return s.repo.SetPayment(ctx, order)
+}
+
+// MarkPaid отмечает заказ оплаченным, false - если он уже не ожидал оплаты
+func (s *OrderService) MarkPaid(ctx context.Context, id int) (bool, error) {
+	return s.repo.MarkPaid(ctx, id)
+}
+
+// MarkFailed отмечает неудачную оплату
+func (s *OrderService) MarkFailed(ctx context.Context, id int) (bool, error) {
+	return s.repo.MarkFailed(ctx, id)
+}
+
+// MarkRefunded отмечает возврат, false - если заказ не был оплачен
+func (s *OrderService) MarkRefunded(ctx context.Context, id int) (bool, error) {
+	return s.repo.MarkRefunded(ctx, id)
+}
@@ -0,0 +1,64 @@
+package pdfgen
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/jung-kurt/gofpdf"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+)
+
+// GenerateInvoicePDF формирует счёт по оплаченному заказу
+func GenerateInvoicePDF(order *models.Order, user *models.User) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(20, 20, 20)
+	pdf.AddPage()
+
+	pdf.SetFont("Arial", "B", 24)
+	pdf.CellFormat(170, 12, "Invoice", "0", 1, "L", false, 0, "")
+
+	pdf.SetFont("Arial", "", 11)
+	pdf.CellFormat(170, 6, fmt.Sprintf("Invoice #INV-%06d", order.ID), "0", 1, "L", false, 0, "")
+	pdf.CellFormat(170, 6, fmt.Sprintf("Date: %s", order.PaidAt.Format("2006-01-02")), "0", 1, "L", false, 0, "")
+	if order.Status == models.OrderStatusRefunded && order.RefundedAt != nil {
+		pdf.CellFormat(170, 6, fmt.Sprintf("Refunded: %s", order.RefundedAt.Format("2006-01-02")), "0", 1, "L", false, 0, "")
+	}
+	pdf.Ln(6)
+
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(170, 7, "Billed to", "0", 1, "L", false, 0, "")
+	pdf.SetFont("Arial", "", 11)
+	pdf.CellFormat(170, 6, user.Name+" "+user.Surname, "0", 1, "L", false, 0, "")
+	pdf.CellFormat(170, 6, user.Email, "0", 1, "L", false, 0, "")
+	pdf.Ln(8)
+
+	pdf.SetFont("Arial", "B", 11)
+	pdf.CellFormat(130, 8, "Description", "B", 0, "L", false, 0, "")
+	pdf.CellFormat(40, 8, "Amount", "B", 1, "R", false, 0, "")
+
+	pdf.SetFont("Arial", "", 11)
+	pdf.CellFormat(130, 8, "Course: "+order.CourseName, "0", 0, "L", false, 0, "")
+	pdf.CellFormat(40, 8, formatMoney(order.AmountCents, order.Currency), "0", 1, "R", false, 0, "")
+	if order.DiscountCents > 0 {
+		label := "Discount"
+		if order.CouponCode != nil {
+			label += " (" + *order.CouponCode + ")"
+		}
+		pdf.CellFormat(130, 8, label, "0", 0, "L", false, 0, "")
+		pdf.CellFormat(40, 8, "-"+formatMoney(order.DiscountCents, order.Currency), "0", 1, "R", false, 0, "")
+	}
+
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(130, 10, "Total", "T", 0, "L", false, 0, "")
+	pdf.CellFormat(40, 10, formatMoney(order.TotalCents, order.Currency), "T", 1, "R", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to generate invoice: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func formatMoney(cents int, currency string) string {
+	return fmt.Sprintf("%d.%02d %s", cents/100, cents%100, currency)
+}
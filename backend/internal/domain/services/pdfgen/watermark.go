@@ -0,0 +1,30 @@
+package pdfgen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func init() {
+	// pdfcpu по умолчанию создаёт каталог конфигурации в домашней папке; нам нужны только встроенные шрифты
+	api.DisableConfigDir()
+}
+
+// WatermarkPDF добавляет на каждую страницу PDF подпись с именем и email скачавшего
+func WatermarkPDF(rs io.ReadSeeker, name, email string) ([]byte, error) {
+	text := fmt.Sprintf("Downloaded by %s <%s>", name, email)
+	wm, err := api.TextWatermark(text, "font:Helvetica, points:9, pos:bc, off:0 12, scale:1 abs, rot:0, op:0.6, fillc:#808080", true, false, types.POINTS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare watermark: %w", err)
+	}
+
+	var out bytes.Buffer
+	if err := api.AddWatermarks(rs, &out, nil, wm, nil); err != nil {
+		return nil, fmt.Errorf("failed to watermark pdf: %w", err)
+	}
+	return out.Bytes(), nil
+}
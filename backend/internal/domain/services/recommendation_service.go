@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/repositories"
+)
+
+type RecommendationServiceInterface interface {
+	RecomputeSimilarities(ctx context.Context) (int, error)
+	GetSimilarCourses(ctx context.Context, courseIDs []int) ([]*models.CourseSimilarity, error)
+}
+
+type RecommendationService struct {
+	repo repositories.RecommendationRepositoryInterface
+}
+
+func NewRecommendationService(repo repositories.RecommendationRepositoryInterface) RecommendationServiceInterface {
+	return &RecommendationService{repo: repo}
+}
+
+// RecomputeSimilarities пересчитывает похожесть курсов по записям студентов
+func (s *RecommendationService) RecomputeSimilarities(ctx context.Context) (int, error) {
+	return s.repo.RecomputeSimilarities(ctx)
+}
+
+// GetSimilarCourses возвращает курсы, похожие на переданные
+func (s *RecommendationService) GetSimilarCourses(ctx context.Context, courseIDs []int) ([]*models.CourseSimilarity, error) {
+	if len(courseIDs) == 0 {
+		return nil, nil
+	}
+	return s.repo.FindSimilar(ctx, courseIDs)
+}
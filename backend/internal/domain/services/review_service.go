@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/repositories"
+)
+
+type ReviewServiceInterface interface {
+	CreateReview(ctx context.Context, review *models.Review) error
+	GetReview(ctx context.Context, id int) (*models.Review, error)
+	GetUserReview(ctx context.Context, courseID, userID int) (*models.Review, error)
+	GetCourseReviews(ctx context.Context, courseID int, includeHidden bool) ([]*models.Review, error)
+	GetReportedReviews(ctx context.Context) ([]*models.Review, error)
+	UpdateReview(ctx context.Context, review *models.Review) error
+	DeleteReview(ctx context.Context, id int) error
+	SetReply(ctx context.Context, id int, authorID int, reply *string) error
+	SetStatus(ctx context.Context, id int, status string) error
+	ReportReview(ctx context.Context, report *models.ReviewReport) error
+}
+
+type ReviewService struct {
+	repo repositories.ReviewRepositoryInterface
+}
+
+func NewReviewService(repo repositories.ReviewRepositoryInterface) ReviewServiceInterface {
+	return &ReviewService{repo: repo}
+}
+
+// CreateReview сохраняет новый отзыв
+func (s *ReviewService) CreateReview(ctx context.Context, review *models.Review) error {
+	return s.repo.Create(ctx, review)
+}
+
+// GetReview возвращает отзыв по ID
+func (s *ReviewService) GetReview(ctx context.Context, id int) (*models.Review, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// GetUserReview возвращает отзыв пользователя о курсе (nil, если его нет)
+func (s *ReviewService) GetUserReview(ctx context.Context, courseID, userID int) (*models.Review, error) {
+	return s.repo.FindByCourseAndUser(ctx, courseID, userID)
+}
+
+// GetCourseReviews возвращает отзывы курса
+func (s *ReviewService) GetCourseReviews(ctx context.Context, courseID int, includeHidden bool) ([]*models.Review, error) {
+	return s.repo.FindByCourse(ctx, courseID, includeHidden)
+}
+
+// GetReportedReviews возвращает отзывы с жалобами
+func (s *ReviewService) GetReportedReviews(ctx context.Context) ([]*models.Review, error) {
+	return s.repo.FindReported(ctx)
+}
+
+// UpdateReview меняет оценку и текст отзыва
+func (s *ReviewService) UpdateReview(ctx context.Context, review *models.Review) error {
+	return s.repo.Update(ctx, review)
+}
+
+// DeleteReview удаляет отзыв
+func (s *ReviewService) DeleteReview(ctx context.Context, id int) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// SetReply сохраняет или удаляет ответ преподавателя
+func (s *ReviewService) SetReply(ctx context.Context, id int, authorID int, reply *string) error {
+	return s.repo.SetReply(ctx, id, authorID, reply)
+}
+
+// SetStatus меняет статус модерации отзыва
+func (s *ReviewService) SetStatus(ctx context.Context, id int, status string) error {
+	return s.repo.SetStatus(ctx, id, status)
+}
+
+// ReportReview сохраняет жалобу на отзыв
+func (s *ReviewService) ReportReview(ctx context.Context, report *models.ReviewReport) error {
+	return s.repo.AddReport(ctx, report)
+}
@@ -16,6 +16,8 @@ type UserServiceInterface interface {
 	DeleteUser(ctx context.Context, id int) error
 	SearchUsers(ctx context.Context, name string) ([]*models.User, error)
 	UpdateXpAndLevel(ctx context.Context, user *models.User) error
+	FindExistingUsers(ctx context.Context, usernames, emails []string) ([]*models.User, error)
+	ImportUsers(ctx context.Context, entries []*models.UserImportEntry) error
 }
 
 type UserService struct {
@@ -86,4 +88,20 @@ func (s *UserService) SearchUsers(ctx context.Context, name string) ([]*models.U
 
 func (s *UserService) UpdateXpAndLevel(ctx context.Context, user *models.User) error {
 	return s.repo.UpdateXpAndLevel(ctx, user)
-}
\ No newline at end of file
+}
+
+func (s *UserService) FindExistingUsers(ctx context.Context, usernames, emails []string) ([]*models.User, error) {
+	return s.repo.FindByUsernamesOrEmails(ctx, usernames, emails)
+}
+
+// ImportUsers хеширует пароли и создаёт пользователей одной транзакцией
+func (s *UserService) ImportUsers(ctx context.Context, entries []*models.UserImportEntry) error {
+	for _, entry := range entries {
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(entry.User.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+		entry.User.Password = string(hashedPassword)
+	}
+	return s.repo.ImportUsers(ctx, entries)
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/repositories"
+)
+
+type VideoProgressServiceInterface interface {
+	GetProgress(ctx context.Context, userID, lessonID int) (*models.VideoProgress, error)
+	GetCourseProgress(ctx context.Context, userID, courseID int) ([]*models.VideoProgress, error)
+	GetLessonProgress(ctx context.Context, lessonID int) ([]*models.VideoProgress, error)
+	UpdateProgress(ctx context.Context, userID, lessonID, courseID int, apply func(progress *models.VideoProgress) error) (*models.VideoProgress, error)
+	MarkCompleted(ctx context.Context, userID, lessonID int) error
+}
+
+type VideoProgressService struct {
+	repo repositories.VideoProgressRepositoryInterface
+}
+
+func NewVideoProgressService(repo repositories.VideoProgressRepositoryInterface) VideoProgressServiceInterface {
+	return &VideoProgressService{repo: repo}
+}
+
+// GetProgress возвращает прогресс просмотра видео урока студентом или nil
+func (s *VideoProgressService) GetProgress(ctx context.Context, userID, lessonID int) (*models.VideoProgress, error) {
+	return s.repo.FindByUserAndLesson(ctx, userID, lessonID)
+}
+
+// GetCourseProgress возвращает прогресс просмотра видео всех уроков курса студентом
+func (s *VideoProgressService) GetCourseProgress(ctx context.Context, userID, courseID int) ([]*models.VideoProgress, error) {
+	return s.repo.FindByUserAndCourse(ctx, userID, courseID)
+}
+
+// GetLessonProgress возвращает прогресс просмотра видео урока всеми студентами
+func (s *VideoProgressService) GetLessonProgress(ctx context.Context, lessonID int) ([]*models.VideoProgress, error) {
+	return s.repo.FindByLesson(ctx, lessonID)
+}
+
+// UpdateProgress изменяет прогресс просмотра под блокировкой строки
+func (s *VideoProgressService) UpdateProgress(ctx context.Context, userID, lessonID, courseID int, apply func(progress *models.VideoProgress) error) (*models.VideoProgress, error) {
+	return s.repo.Update(ctx, userID, lessonID, courseID, apply)
+}
+
+// MarkCompleted отмечает, что просмотр засчитал урок
+func (s *VideoProgressService) MarkCompleted(ctx context.Context, userID, lessonID int) error {
+	return s.repo.MarkCompleted(ctx, userID, lessonID)
+}
@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+)
+
+// analyticsMaxDailyRange - самый длинный период, по которому можно запросить статистику по дням
+const analyticsMaxDailyRange = 366 * 24 * time.Hour
+
+type cachedAnalytics struct {
+	analytics *models.CourseAnalytics
+	expiresAt time.Time
+}
+
+type AnalyticsUseCase struct {
+	analyticsService services.AnalyticsServiceInterface
+	courseService    services.CourseServiceInterface
+	cacheTTL         time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedAnalytics
+}
+
+func NewAnalyticsUseCase(
+	analyticsService services.AnalyticsServiceInterface,
+	courseService services.CourseServiceInterface,
+	cacheTTL time.Duration,
+) *AnalyticsUseCase {
+	return &AnalyticsUseCase{
+		analyticsService: analyticsService,
+		courseService:    courseService,
+		cacheTTL:         cacheTTL,
+		cache:            make(map[string]cachedAnalytics),
+	}
+}
+
+// GetCourseAnalytics возвращает статистику прохождения курса команде курса.
+// Результат кешируется на cacheTTL для каждого сочетания курса, периода и шага.
+func (u *AnalyticsUseCase) GetCourseAnalytics(ctx context.Context, userID int, userRole string, filter models.AnalyticsFilter) (*models.CourseAnalytics, error) {
+	if filter.Interval == "" {
+		filter.Interval = models.AnalyticsIntervalDay
+	}
+	if err := validateAnalyticsFilter(filter); err != nil {
+		return nil, err
+	}
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, filter.CourseID, courseStaffRoles); err != nil {
+		return nil, err
+	}
+
+	key := analyticsCacheKey(filter)
+	if analytics := u.cached(key); analytics != nil {
+		return analytics, nil
+	}
+
+	analytics, err := u.buildAnalytics(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	u.store(key, analytics)
+	return analytics, nil
+}
+
+func (u *AnalyticsUseCase) buildAnalytics(ctx context.Context, filter models.AnalyticsFilter) (*models.CourseAnalytics, error) {
+	summary, err := u.analyticsService.GetCompletionSummary(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	enrollments, err := u.analyticsService.GetEnrollmentsOverTime(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	lessons, err := u.analyticsService.GetLessonFunnel(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := u.analyticsService.GetProgressDistribution(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	timeToComplete, err := u.analyticsService.GetTimeToCompleteDistribution(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	betweenLessons, err := u.analyticsService.GetMedianHoursBetweenLessons(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, lesson := range lessons {
+		lesson.CompletionRate = rate(lesson.Completed, summary.Students)
+		lesson.DropOffRate = rate(lesson.DropOff, summary.Students)
+	}
+
+	return &models.CourseAnalytics{
+		CourseID:                   filter.CourseID,
+		From:                       filter.From,
+		To:                         filter.To,
+		Interval:                   filter.Interval,
+		Students:                   summary.Students,
+		NotStarted:                 summary.NotStarted,
+		CompletedStudents:          summary.CompletedStudents,
+		CompletionRate:             rate(summary.CompletedStudents, summary.Students),
+		MedianHoursToComplete:      summary.MedianHoursToComplete,
+		MedianHoursBetweenLessons:  betweenLessons,
+		EnrollmentsOverTime:        enrollments,
+		Lessons:                    lessons,
+		ProgressDistribution:       progress,
+		TimeToCompleteDistribution: timeToComplete,
+		GeneratedAt:                time.Now(),
+	}, nil
+}
+
+func (u *AnalyticsUseCase) cached(key string) *models.CourseAnalytics {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	entry, ok := u.cache[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil
+	}
+	return entry.analytics
+}
+
+// store сохраняет результат и заодно выбрасывает устаревшие записи, чтобы кеш не рос бесконечно
+func (u *AnalyticsUseCase) store(key string, analytics *models.CourseAnalytics) {
+	if u.cacheTTL <= 0 {
+		return
+	}
+	now := time.Now()
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for k, entry := range u.cache {
+		if now.After(entry.expiresAt) {
+			delete(u.cache, k)
+		}
+	}
+	u.cache[key] = cachedAnalytics{analytics: analytics, expiresAt: now.Add(u.cacheTTL)}
+}
+
+func validateAnalyticsFilter(filter models.AnalyticsFilter) error {
+	switch filter.Interval {
+	case models.AnalyticsIntervalDay, models.AnalyticsIntervalWeek, models.AnalyticsIntervalMonth:
+	default:
+		return errors.New("validation failed: interval must be day, week or month")
+	}
+	if filter.From != nil && filter.To != nil {
+		if !filter.From.Before(*filter.To) {
+			return errors.New("validation failed: from must be before to")
+		}
+		if filter.Interval == models.AnalyticsIntervalDay && filter.To.Sub(*filter.From) > analyticsMaxDailyRange {
+			return errors.New("validation failed: daily interval is limited to one year, use week or month")
+		}
+	}
+	return nil
+}
+
+func analyticsCacheKey(filter models.AnalyticsFilter) string {
+	bound := func(t *time.Time) string {
+		if t == nil {
+			return "-"
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+	return fmt.Sprintf("%d|%s|%s|%s", filter.CourseID, bound(filter.From), bound(filter.To), filter.Interval)
+}
+
+func rate(count, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) / float64(total)
+}
@@ -0,0 +1,113 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+)
+
+// Mock для AnalyticsService
+type MockAnalyticsService struct {
+	mock.Mock
+	services.AnalyticsServiceInterface
+}
+
+func (m *MockAnalyticsService) GetEnrollmentsOverTime(ctx context.Context, filter models.AnalyticsFilter) ([]*models.PeriodCount, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*models.PeriodCount), args.Error(1)
+}
+
+func (m *MockAnalyticsService) GetLessonFunnel(ctx context.Context, filter models.AnalyticsFilter) ([]*models.LessonFunnelEntry, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*models.LessonFunnelEntry), args.Error(1)
+}
+
+func (m *MockAnalyticsService) GetCompletionSummary(ctx context.Context, filter models.AnalyticsFilter) (*models.CompletionSummary, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(*models.CompletionSummary), args.Error(1)
+}
+
+func (m *MockAnalyticsService) GetProgressDistribution(ctx context.Context, filter models.AnalyticsFilter) ([]*models.DistributionBucket, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*models.DistributionBucket), args.Error(1)
+}
+
+func (m *MockAnalyticsService) GetTimeToCompleteDistribution(ctx context.Context, filter models.AnalyticsFilter) ([]*models.DistributionBucket, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*models.DistributionBucket), args.Error(1)
+}
+
+func (m *MockAnalyticsService) GetMedianHoursBetweenLessons(ctx context.Context, filter models.AnalyticsFilter) (*float64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(*float64), args.Error(1)
+}
+
+func mockAnalytics(analyticsService *MockAnalyticsService, filter models.AnalyticsFilter) {
+	median := 36.0
+	analyticsService.On("GetCompletionSummary", mock.Anything, filter).Return(&models.CompletionSummary{Students: 4, NotStarted: 1, CompletedStudents: 1}, nil)
+	analyticsService.On("GetEnrollmentsOverTime", mock.Anything, filter).Return([]*models.PeriodCount{{Count: 4}}, nil)
+	analyticsService.On("GetLessonFunnel", mock.Anything, filter).Return([]*models.LessonFunnelEntry{
+		{LessonID: 10, Position: 1, Completed: 3, DropOff: 2},
+		{LessonID: 11, Position: 2, Completed: 1},
+	}, nil)
+	analyticsService.On("GetProgressDistribution", mock.Anything, filter).Return([]*models.DistributionBucket{}, nil)
+	analyticsService.On("GetTimeToCompleteDistribution", mock.Anything, filter).Return([]*models.DistributionBucket{}, nil)
+	analyticsService.On("GetMedianHoursBetweenLessons", mock.Anything, filter).Return(&median, nil)
+}
+
+func TestGetCourseAnalytics(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Computes rates for the cohort and caches the result", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		analyticsService := new(MockAnalyticsService)
+		useCase := usecase.NewAnalyticsUseCase(analyticsService, courseService, time.Minute)
+
+		filter := models.AnalyticsFilter{CourseID: 1, Interval: models.AnalyticsIntervalDay}
+		courseService.On("GetCourse", ctx, 1).Return(&models.Course{ID: 1}, nil)
+		courseService.On("GetStaffMember", ctx, 1, 5).Return(&models.CourseStaff{CourseID: 1, UserID: 5, Role: models.CourseStaffRoleTA}, nil)
+		mockAnalytics(analyticsService, filter)
+
+		analytics, err := useCase.GetCourseAnalytics(ctx, 5, "teacher", models.AnalyticsFilter{CourseID: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, 0.25, analytics.CompletionRate)
+		assert.Equal(t, 0.75, analytics.Lessons[0].CompletionRate)
+		assert.Equal(t, 0.5, analytics.Lessons[0].DropOffRate)
+
+		cached, err := useCase.GetCourseAnalytics(ctx, 5, "teacher", filter)
+		assert.NoError(t, err)
+		assert.Same(t, analytics, cached)
+		analyticsService.AssertNumberOfCalls(t, "GetCompletionSummary", 1)
+	})
+
+	t.Run("Student is denied", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		analyticsService := new(MockAnalyticsService)
+		useCase := usecase.NewAnalyticsUseCase(analyticsService, courseService, time.Minute)
+
+		courseService.On("GetCourse", ctx, 1).Return(&models.Course{ID: 1}, nil)
+		courseService.On("GetStaffMember", ctx, 1, 7).Return(nil, nil)
+
+		_, err := useCase.GetCourseAnalytics(ctx, 7, "student", models.AnalyticsFilter{CourseID: 1})
+
+		assert.ErrorIs(t, err, usecase.ErrPermissionDenied)
+		analyticsService.AssertNotCalled(t, "GetCompletionSummary", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invalid date range", func(t *testing.T) {
+		useCase := usecase.NewAnalyticsUseCase(nil, nil, time.Minute)
+		from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+		to := from.AddDate(0, 0, -1)
+
+		_, err := useCase.GetCourseAnalytics(ctx, 5, "admin", models.AnalyticsFilter{CourseID: 1, From: &from, To: &to})
+
+		assert.EqualError(t, err, "validation failed: from must be before to")
+	})
+}
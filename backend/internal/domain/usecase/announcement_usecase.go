@@ -0,0 +1,274 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+	"gitlab.com/w0ikid/study-platform/pkg/mailer"
+	"gitlab.com/w0ikid/study-platform/pkg/markdown"
+)
+
+type AnnouncementUseCase struct {
+	announcementService services.AnnouncementServiceInterface
+	courseService       services.CourseServiceInterface
+	enrollmentService   services.EnrollmentServiceInterface
+	userService         services.UserServiceInterface
+	mailer              mailer.Mailer
+	appURL              string
+}
+
+func NewAnnouncementUseCase(
+	announcementService services.AnnouncementServiceInterface,
+	courseService services.CourseServiceInterface,
+	enrollmentService services.EnrollmentServiceInterface,
+	userService services.UserServiceInterface,
+	mailer mailer.Mailer,
+	appURL string,
+) *AnnouncementUseCase {
+	return &AnnouncementUseCase{
+		announcementService: announcementService,
+		courseService:       courseService,
+		enrollmentService:   enrollmentService,
+		userService:         userService,
+		mailer:              mailer,
+		appURL:              appURL,
+	}
+}
+
+// CreateAnnouncement публикует объявление курса сразу или в указанное время.
+// Письма студентам рассылает DispatchEmails после публикации.
+func (u *AnnouncementUseCase) CreateAnnouncement(ctx context.Context, userID int, userRole string, courseID int, input *dto.AnnouncementRequest) (*models.Announcement, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseEditorRoles); err != nil {
+		return nil, err
+	}
+
+	announcement := &models.Announcement{
+		CourseID:  courseID,
+		AuthorID:  userID,
+		Title:     input.Title,
+		Body:      input.Body,
+		Pinned:    input.Pinned,
+		SendEmail: input.SendEmail,
+	}
+	if input.PublishAt != nil {
+		announcement.PublishAt = *input.PublishAt
+	}
+	if err := u.announcementService.CreateAnnouncement(ctx, announcement); err != nil {
+		return nil, err
+	}
+	created, err := u.announcementService.GetAnnouncement(ctx, announcement.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return created, renderAnnouncementBody(created)
+}
+
+// UpdateAnnouncement меняет объявление. Уже разосланные письма повторно не отправляются.
+func (u *AnnouncementUseCase) UpdateAnnouncement(ctx context.Context, userID int, userRole string, courseID, announcementID int, input *dto.AnnouncementRequest) (*models.Announcement, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	announcement, err := u.getCourseAnnouncement(ctx, userID, courseID, announcementID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseEditorRoles); err != nil {
+		return nil, err
+	}
+
+	announcement.Title = input.Title
+	announcement.Body = input.Body
+	announcement.Pinned = input.Pinned
+	announcement.SendEmail = input.SendEmail
+	if input.PublishAt != nil {
+		announcement.PublishAt = *input.PublishAt
+	}
+	if err := u.announcementService.UpdateAnnouncement(ctx, announcement); err != nil {
+		return nil, err
+	}
+	return announcement, renderAnnouncementBody(announcement)
+}
+
+// DeleteAnnouncement удаляет объявление
+func (u *AnnouncementUseCase) DeleteAnnouncement(ctx context.Context, userID int, userRole string, courseID, announcementID int) error {
+	if _, err := u.getCourseAnnouncement(ctx, userID, courseID, announcementID); err != nil {
+		return err
+	}
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseEditorRoles); err != nil {
+		return err
+	}
+	return u.announcementService.DeleteAnnouncement(ctx, announcementID)
+}
+
+// GetCourseAnnouncements возвращает объявления курса. Команда курса видит и запланированные.
+func (u *AnnouncementUseCase) GetCourseAnnouncements(ctx context.Context, userID int, userRole string, courseID int) ([]*models.Announcement, error) {
+	staff, err := u.isCourseStaff(ctx, userID, userRole, courseID)
+	if err != nil {
+		return nil, err
+	}
+	announcements, err := u.announcementService.GetCourseAnnouncements(ctx, courseID, userID, staff)
+	if err != nil {
+		return nil, err
+	}
+	return announcements, renderAnnouncementBodies(announcements)
+}
+
+// GetAnnouncement возвращает объявление курса; запланированное видит только команда курса
+func (u *AnnouncementUseCase) GetAnnouncement(ctx context.Context, userID int, userRole string, courseID, announcementID int) (*models.Announcement, error) {
+	announcement, err := u.getCourseAnnouncement(ctx, userID, courseID, announcementID)
+	if err != nil {
+		return nil, err
+	}
+	if !announcement.IsPublished(time.Now()) {
+		staff, err := u.isCourseStaff(ctx, userID, userRole, courseID)
+		if err != nil {
+			return nil, err
+		}
+		if !staff {
+			return nil, errors.New("announcement not found")
+		}
+	}
+	return announcement, renderAnnouncementBody(announcement)
+}
+
+// MarkRead отмечает опубликованное объявление прочитанным текущим пользователем
+func (u *AnnouncementUseCase) MarkRead(ctx context.Context, userID int, courseID, announcementID int) error {
+	announcement, err := u.getCourseAnnouncement(ctx, userID, courseID, announcementID)
+	if err != nil {
+		return err
+	}
+	if !announcement.IsPublished(time.Now()) {
+		return errors.New("announcement not found")
+	}
+	return u.announcementService.MarkRead(ctx, announcementID, userID)
+}
+
+// GetReads возвращает отметки о прочтении объявления команде курса
+func (u *AnnouncementUseCase) GetReads(ctx context.Context, userID int, userRole string, courseID, announcementID int) ([]*models.AnnouncementRead, error) {
+	if _, err := u.getCourseAnnouncement(ctx, userID, courseID, announcementID); err != nil {
+		return nil, err
+	}
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseStaffRoles); err != nil {
+		return nil, err
+	}
+	return u.announcementService.GetReads(ctx, announcementID)
+}
+
+// GetFeed возвращает ленту объявлений по всем курсам студента
+func (u *AnnouncementUseCase) GetFeed(ctx context.Context, userID int, unreadOnly bool) ([]*models.Announcement, error) {
+	announcements, err := u.announcementService.GetFeed(ctx, userID, unreadOnly)
+	if err != nil {
+		return nil, err
+	}
+	return announcements, renderAnnouncementBodies(announcements)
+}
+
+// DispatchEmails рассылает письма по опубликованным объявлениям всем записанным на курс студентам
+func (u *AnnouncementUseCase) DispatchEmails(ctx context.Context) error {
+	pending, err := u.announcementService.GetPendingEmails(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, announcement := range pending {
+		// отметка ставится до отправки, чтобы письма не ушли дважды
+		claimed, err := u.announcementService.ClaimEmail(ctx, announcement.ID)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		enrollments, err := u.enrollmentService.GetEnrollmentsByCourse(ctx, announcement.CourseID)
+		if err != nil {
+			return err
+		}
+		subject := fmt.Sprintf("[%s] %s", announcement.CourseName, announcement.Title)
+		for _, enrollment := range enrollments {
+			user, err := u.userService.GetUser(ctx, enrollment.UserID)
+			if err != nil {
+				log.Printf("announcements: failed to notify user %d: %v", enrollment.UserID, err)
+				continue
+			}
+			if err := u.mailer.Send(user.Email, subject, u.emailBody(announcement)); err != nil {
+				log.Printf("announcements: failed to notify user %d: %v", enrollment.UserID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// RunEmailDispatcher периодически рассылает письма по объявлениям, пока не отменён ctx
+func (u *AnnouncementUseCase) RunEmailDispatcher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := u.DispatchEmails(ctx); err != nil {
+			log.Printf("announcements: email dispatch failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (u *AnnouncementUseCase) emailBody(announcement *models.Announcement) string {
+	return fmt.Sprintf(`%s
+
+%s
+
+Posted by %s. Open the course at %s/courses/%d
+`, announcement.Title, announcement.Body, announcement.AuthorName, u.appURL, announcement.CourseID)
+}
+
+// renderAnnouncementBody строит body_html объявления из markdown тем же рендером, что и уроки
+func renderAnnouncementBody(announcement *models.Announcement) error {
+	bodyHTML, err := markdown.Render(announcement.Body)
+	if err != nil {
+		return err
+	}
+	announcement.BodyHTML = bodyHTML
+	return nil
+}
+
+func renderAnnouncementBodies(announcements []*models.Announcement) error {
+	for _, announcement := range announcements {
+		if err := renderAnnouncementBody(announcement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getCourseAnnouncement возвращает объявление, если оно относится к курсу
+func (u *AnnouncementUseCase) getCourseAnnouncement(ctx context.Context, userID, courseID, announcementID int) (*models.Announcement, error) {
+	announcement, err := u.announcementService.GetAnnouncement(ctx, announcementID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if announcement.CourseID != courseID {
+		return nil, errors.New("announcement not found")
+	}
+	return announcement, nil
+}
+
+func (u *AnnouncementUseCase) isCourseStaff(ctx context.Context, userID int, userRole string, courseID int) (bool, error) {
+	_, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseStaffRoles)
+	if errors.Is(err, ErrPermissionDenied) {
+		return false, nil
+	}
+	return err == nil, err
+}
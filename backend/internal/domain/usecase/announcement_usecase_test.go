@@ -0,0 +1,137 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+// Mock для AnnouncementService
+type MockAnnouncementService struct {
+	mock.Mock
+	services.AnnouncementServiceInterface
+}
+
+func (m *MockAnnouncementService) GetAnnouncement(ctx context.Context, id, userID int) (*models.Announcement, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Announcement), args.Error(1)
+}
+
+func (m *MockAnnouncementService) GetPendingEmails(ctx context.Context) ([]*models.Announcement, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*models.Announcement), args.Error(1)
+}
+
+func (m *MockAnnouncementService) ClaimEmail(ctx context.Context, id int) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEnrollmentService) GetEnrollmentsByCourse(ctx context.Context, courseID int) ([]*models.Enrollment, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]*models.Enrollment), args.Error(1)
+}
+
+func TestCreateAnnouncement(t *testing.T) {
+	ctx := context.Background()
+	input := &dto.AnnouncementRequest{Title: "Exam moved", Body: "The exam is on **Friday**"}
+
+	t.Run("TA cannot publish", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		announcementService := new(MockAnnouncementService)
+		useCase := usecase.NewAnnouncementUseCase(announcementService, courseService, nil, nil, nil, "")
+
+		courseService.On("GetCourse", ctx, 1).Return(&models.Course{ID: 1}, nil)
+		courseService.On("GetStaffMember", ctx, 1, 5).Return(&models.CourseStaff{CourseID: 1, UserID: 5, Role: models.CourseStaffRoleTA}, nil)
+
+		_, err := useCase.CreateAnnouncement(ctx, 5, "teacher", 1, input)
+
+		assert.ErrorIs(t, err, usecase.ErrPermissionDenied)
+		announcementService.AssertNotCalled(t, "CreateAnnouncement", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetAnnouncement(t *testing.T) {
+	ctx := context.Background()
+	scheduled := &models.Announcement{ID: 3, CourseID: 1, Title: "Soon", PublishAt: time.Now().Add(time.Hour)}
+
+	t.Run("Scheduled announcement is hidden from students", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		announcementService := new(MockAnnouncementService)
+		useCase := usecase.NewAnnouncementUseCase(announcementService, courseService, nil, nil, nil, "")
+
+		announcementService.On("GetAnnouncement", ctx, 3, 7).Return(scheduled, nil)
+		courseService.On("GetCourse", ctx, 1).Return(&models.Course{ID: 1}, nil)
+		courseService.On("GetStaffMember", ctx, 1, 7).Return(nil, nil)
+
+		_, err := useCase.GetAnnouncement(ctx, 7, "student", 1, 3)
+
+		assert.EqualError(t, err, "announcement not found")
+	})
+
+	t.Run("Renders markdown body", func(t *testing.T) {
+		announcementService := new(MockAnnouncementService)
+		useCase := usecase.NewAnnouncementUseCase(announcementService, nil, nil, nil, nil, "")
+
+		announcementService.On("GetAnnouncement", ctx, 3, 7).Return(&models.Announcement{ID: 3, CourseID: 1, Body: "# Exam\n<img src=x onerror=alert(1)>", PublishAt: time.Now()}, nil)
+
+		announcement, err := useCase.GetAnnouncement(ctx, 7, "student", 1, 3)
+
+		assert.NoError(t, err)
+		assert.Contains(t, announcement.BodyHTML, "Exam</h1>")
+		assert.NotContains(t, announcement.BodyHTML, "onerror")
+	})
+
+	t.Run("Announcement of another course", func(t *testing.T) {
+		announcementService := new(MockAnnouncementService)
+		useCase := usecase.NewAnnouncementUseCase(announcementService, nil, nil, nil, nil, "")
+
+		announcementService.On("GetAnnouncement", ctx, 3, 7).Return(&models.Announcement{ID: 3, CourseID: 2, PublishAt: time.Now()}, nil)
+
+		_, err := useCase.GetAnnouncement(ctx, 7, "student", 1, 3)
+
+		assert.EqualError(t, err, "announcement not found")
+	})
+}
+
+func TestDispatchEmails(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Emails enrolled students once per announcement", func(t *testing.T) {
+		announcementService := new(MockAnnouncementService)
+		enrollmentService := new(MockEnrollmentService)
+		userService := new(MockUserService)
+		mailer := new(MockMailer)
+		useCase := usecase.NewAnnouncementUseCase(announcementService, nil, enrollmentService, userService, mailer, "http://localhost")
+
+		announcementService.On("GetPendingEmails", ctx).Return([]*models.Announcement{
+			{ID: 1, CourseID: 10, CourseName: "Go", Title: "Welcome"},
+			{ID: 2, CourseID: 10, CourseName: "Go", Title: "Already sent"},
+		}, nil)
+		announcementService.On("ClaimEmail", ctx, 1).Return(true, nil)
+		announcementService.On("ClaimEmail", ctx, 2).Return(false, nil)
+		enrollmentService.On("GetEnrollmentsByCourse", ctx, 10).Return([]*models.Enrollment{{UserID: 7}, {UserID: 8}}, nil)
+		userService.On("GetUser", ctx, 7).Return(&models.User{ID: 7, Email: "a@example.com"}, nil)
+		userService.On("GetUser", ctx, 8).Return(&models.User{ID: 8, Email: "b@example.com"}, nil)
+		mailer.On("Send", "a@example.com", "[Go] Welcome", mock.Anything).Return(nil)
+		mailer.On("Send", "b@example.com", "[Go] Welcome", mock.Anything).Return(errors.New("smtp down"))
+
+		err := useCase.DispatchEmails(ctx)
+
+		assert.NoError(t, err)
+		mailer.AssertNumberOfCalls(t, "Send", 2)
+		enrollmentService.AssertNumberOfCalls(t, "GetEnrollmentsByCourse", 1)
+	})
+}
@@ -0,0 +1,433 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+	"gitlab.com/w0ikid/study-platform/pkg/markdown"
+	"gitlab.com/w0ikid/study-platform/pkg/storage"
+)
+
+type AssignmentUseCaseInterface interface {
+	GetAssignment(ctx context.Context, courseID, lessonID int) (*models.Assignment, error)
+	SetAssignment(ctx context.Context, userID int, userRole string, courseID, lessonID int, input *dto.AssignmentRequest) (*models.Assignment, error)
+	DeleteAssignment(ctx context.Context, userID int, userRole string, courseID, lessonID int) error
+	Submit(ctx context.Context, userID int, userRole string, courseID, lessonID int, input *dto.SubmitAssignmentRequest, filename string, size int64, r io.Reader) (*models.AssignmentSubmission, error)
+	ListSubmissions(ctx context.Context, userID int, userRole string, courseID, lessonID int, studentID *int) ([]*models.AssignmentSubmission, error)
+	DownloadSubmission(ctx context.Context, userID int, userRole string, courseID, lessonID, submissionID int) (*models.AssignmentSubmission, io.ReadCloser, error)
+	GradeSubmission(ctx context.Context, userID int, userRole string, courseID, lessonID, submissionID int, input *dto.GradeSubmissionRequest) (*models.AssignmentSubmission, error)
+	GradingQueue(ctx context.Context, userID int, userRole string, courseID int) ([]*models.AssignmentSubmission, error)
+	CheckPassed(ctx context.Context, userID int, lesson *models.Lesson) error
+}
+
+type AssignmentUseCase struct {
+	assignmentService services.AssignmentServiceInterface
+	lessonService     services.LessonServiceInterface
+	courseService     services.CourseServiceInterface
+	media             MediaUseCaseInterface
+	store             storage.BlobStore
+}
+
+func NewAssignmentUseCase(
+	assignmentService services.AssignmentServiceInterface,
+	lessonService services.LessonServiceInterface,
+	courseService services.CourseServiceInterface,
+	media MediaUseCaseInterface,
+	store storage.BlobStore,
+) *AssignmentUseCase {
+	return &AssignmentUseCase{
+		assignmentService: assignmentService,
+		lessonService:     lessonService,
+		courseService:     courseService,
+		media:             media,
+		store:             store,
+	}
+}
+
+// GetAssignment возвращает задание урока; доступ к курсу проверяется EnrollmentMiddleware
+func (u *AssignmentUseCase) GetAssignment(ctx context.Context, courseID, lessonID int) (*models.Assignment, error) {
+	if _, err := getCourseLesson(ctx, u.lessonService, courseID, lessonID); err != nil {
+		return nil, err
+	}
+	return u.getLessonAssignment(ctx, lessonID)
+}
+
+// SetAssignment создаёт или заменяет задание урока вместе с рубрикой
+func (u *AssignmentUseCase) SetAssignment(ctx context.Context, userID int, userRole string, courseID, lessonID int, input *dto.AssignmentRequest) (*models.Assignment, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseEditorRoles); err != nil {
+		return nil, err
+	}
+	if _, err := getCourseLesson(ctx, u.lessonService, courseID, lessonID); err != nil {
+		return nil, err
+	}
+
+	assignment := &models.Assignment{
+		CourseID:           courseID,
+		LessonID:           lessonID,
+		Title:              strings.TrimSpace(input.Title),
+		Instructions:       input.Instructions,
+		DueAt:              input.DueAt,
+		AllowedExtensions:  normalizeExtensions(input.AllowedExtensions),
+		MaxScore:           input.MaxScore,
+		PassingScore:       input.PassingScore,
+		RequirePassing:     input.RequirePassing,
+		LatePenaltyPercent: input.LatePenaltyPercent,
+		MaxLateDays:        input.MaxLateDays,
+		Criteria:           []*models.AssignmentCriterion{},
+	}
+	for _, criterion := range input.Criteria {
+		assignment.Criteria = append(assignment.Criteria, &models.AssignmentCriterion{
+			Title:       strings.TrimSpace(criterion.Title),
+			Description: criterion.Description,
+			MaxPoints:   criterion.MaxPoints,
+		})
+	}
+	if err := checkAssignment(assignment); err != nil {
+		return nil, err
+	}
+	html, err := markdown.Render(assignment.Instructions)
+	if err != nil {
+		return nil, err
+	}
+	assignment.InstructionsHTML = html
+
+	if err := u.assignmentService.SaveAssignment(ctx, assignment); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+// DeleteAssignment удаляет задание урока вместе со сданными работами
+func (u *AssignmentUseCase) DeleteAssignment(ctx context.Context, userID int, userRole string, courseID, lessonID int) error {
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseEditorRoles); err != nil {
+		return err
+	}
+	if _, err := getCourseLesson(ctx, u.lessonService, courseID, lessonID); err != nil {
+		return err
+	}
+	return u.assignmentService.DeleteAssignment(ctx, lessonID)
+}
+
+// Submit сдаёт работу новой попыткой. Файл загружается в личную медиатеку студента
+// (с проверкой типа, размера и квоты), предыдущие попытки сохраняются.
+func (u *AssignmentUseCase) Submit(ctx context.Context, userID int, userRole string, courseID, lessonID int, input *dto.SubmitAssignmentRequest, filename string, size int64, r io.Reader) (*models.AssignmentSubmission, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if _, err := getCourseLesson(ctx, u.lessonService, courseID, lessonID); err != nil {
+		return nil, err
+	}
+	assignment, err := u.getLessonAssignment(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(assignment.AllowedExtensions) > 0 {
+		ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".")
+		if !containsString(assignment.AllowedExtensions, ext) {
+			return nil, fmt.Errorf("validation failed: allowed file types are %s", strings.Join(assignment.AllowedExtensions, ", "))
+		}
+	}
+	daysLate := lateDays(assignment.DueAt, time.Now())
+	if daysLate > 0 && assignment.MaxLateDays != nil && daysLate > *assignment.MaxLateDays {
+		return nil, errors.New("submission deadline has passed")
+	}
+
+	file, err := u.media.Upload(ctx, userID, userRole, &dto.MediaUploadRequest{}, filename, size, r)
+	if err != nil {
+		return nil, err
+	}
+
+	submission := &models.AssignmentSubmission{
+		AssignmentID: assignment.ID,
+		UserID:       userID,
+		MediaID:      &file.ID,
+		Filename:     file.Filename,
+		ContentType:  file.ContentType,
+		Size:         file.Size,
+		StorageKey:   file.StorageKey,
+		Comment:      strings.TrimSpace(input.Comment),
+		DaysLate:     daysLate,
+	}
+	if err := u.assignmentService.CreateSubmission(ctx, submission); err != nil {
+		if deleteErr := u.media.DeleteFile(ctx, userID, userRole, file.ID); deleteErr != nil {
+			log.Printf("assignments: failed to remove media file %d: %v", file.ID, deleteErr)
+		}
+		return nil, err
+	}
+	return submission, nil
+}
+
+// ListSubmissions возвращает попытки по заданию: студенту - свои, команде курса - всех студентов
+// (studentID оставляет одного студента)
+func (u *AssignmentUseCase) ListSubmissions(ctx context.Context, userID int, userRole string, courseID, lessonID int, studentID *int) ([]*models.AssignmentSubmission, error) {
+	if _, err := getCourseLesson(ctx, u.lessonService, courseID, lessonID); err != nil {
+		return nil, err
+	}
+	assignment, err := u.getLessonAssignment(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	staff, err := u.isCourseStaff(ctx, userID, userRole, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if !staff {
+		studentID = &userID
+	}
+	return u.assignmentService.GetSubmissions(ctx, assignment.ID, studentID)
+}
+
+// DownloadSubmission открывает файл попытки; скачать его может автор или команда курса
+func (u *AssignmentUseCase) DownloadSubmission(ctx context.Context, userID int, userRole string, courseID, lessonID, submissionID int) (*models.AssignmentSubmission, io.ReadCloser, error) {
+	submission, err := u.getLessonSubmission(ctx, courseID, lessonID, submissionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if submission.UserID != userID {
+		if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseStaffRoles); err != nil {
+			return nil, nil, err
+		}
+	}
+	if submission.StorageKey == "" {
+		return nil, nil, errors.New("submission file not found")
+	}
+
+	content, err := u.store.Get(ctx, submission.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return submission, content, nil
+}
+
+// GradeSubmission оценивает попытку по рубрике (или общим баллом, если рубрики нет) и применяет
+// штраф за опоздание. Повторная оценка заменяет предыдущую. Оценивать могут и ассистенты.
+func (u *AssignmentUseCase) GradeSubmission(ctx context.Context, userID int, userRole string, courseID, lessonID, submissionID int, input *dto.GradeSubmissionRequest) (*models.AssignmentSubmission, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseStaffRoles); err != nil {
+		return nil, err
+	}
+	assignment, err := u.getLessonAssignment(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	submission, err := u.getLessonSubmission(ctx, courseID, lessonID, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	grade := &models.AssignmentGrade{Feedback: strings.TrimSpace(input.Feedback), GradedBy: &userID}
+	if len(assignment.Criteria) > 0 {
+		if input.Score != nil {
+			return nil, errors.New("validation failed: score is calculated from the rubric, grade each criterion instead")
+		}
+		if grade.Criteria, err = scoreCriteria(assignment.Criteria, input.Criteria); err != nil {
+			return nil, err
+		}
+		for _, score := range grade.Criteria {
+			grade.RawScore += score.Points
+		}
+	} else {
+		if len(input.Criteria) > 0 {
+			return nil, errors.New("validation failed: assignment has no rubric")
+		}
+		if input.Score == nil {
+			return nil, errors.New("validation failed: score is required")
+		}
+		if *input.Score > assignment.MaxScore {
+			return nil, fmt.Errorf("validation failed: score must not exceed %d", assignment.MaxScore)
+		}
+		grade.RawScore = *input.Score
+	}
+
+	grade.PenaltyPercent = min(100, submission.DaysLate*assignment.LatePenaltyPercent)
+	grade.Score = math.Round(float64(grade.RawScore)*float64(100-grade.PenaltyPercent)) / 100
+	if err := u.assignmentService.SaveGrade(ctx, submission.ID, grade); err != nil {
+		return nil, err
+	}
+	submission.Grade = grade
+	return submission, nil
+}
+
+// GradingQueue возвращает работы курса, ожидающие проверки, самые старые первыми
+func (u *AssignmentUseCase) GradingQueue(ctx context.Context, userID int, userRole string, courseID int) ([]*models.AssignmentSubmission, error) {
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseStaffRoles); err != nil {
+		return nil, err
+	}
+	return u.assignmentService.GetGradingQueue(ctx, courseID)
+}
+
+// CheckPassed - условие завершения урока (CompletionRequirement): если задание требует
+// зачёта, последняя оценённая попытка должна набрать не меньше проходного балла
+func (u *AssignmentUseCase) CheckPassed(ctx context.Context, userID int, lesson *models.Lesson) error {
+	assignment, err := u.assignmentService.GetLessonAssignment(ctx, lesson.ID)
+	if err != nil {
+		return err
+	}
+	if assignment == nil || !assignment.RequirePassing {
+		return nil
+	}
+	graded, err := u.assignmentService.GetLatestGraded(ctx, assignment.ID, userID)
+	if err != nil {
+		return err
+	}
+	if graded == nil {
+		return fmt.Errorf("%w: assignment %q has not been graded yet", ErrLessonRequirementsNotMet, assignment.Title)
+	}
+	if graded.Grade.Score < float64(assignment.PassingScore) {
+		return fmt.Errorf("%w: assignment %q needs at least %d points (scored %g)", ErrLessonRequirementsNotMet, assignment.Title, assignment.PassingScore, graded.Grade.Score)
+	}
+	return nil
+}
+
+func (u *AssignmentUseCase) getLessonAssignment(ctx context.Context, lessonID int) (*models.Assignment, error) {
+	assignment, err := u.assignmentService.GetLessonAssignment(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if assignment == nil {
+		return nil, errors.New("assignment not found")
+	}
+	return assignment, nil
+}
+
+// getLessonSubmission возвращает попытку, только если она сдана по заданию этого урока
+func (u *AssignmentUseCase) getLessonSubmission(ctx context.Context, courseID, lessonID, submissionID int) (*models.AssignmentSubmission, error) {
+	if _, err := getCourseLesson(ctx, u.lessonService, courseID, lessonID); err != nil {
+		return nil, err
+	}
+	assignment, err := u.getLessonAssignment(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	submission, err := u.assignmentService.GetSubmission(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+	if submission.AssignmentID != assignment.ID {
+		return nil, errors.New("assignment submission not found")
+	}
+	return submission, nil
+}
+
+func (u *AssignmentUseCase) isCourseStaff(ctx context.Context, userID int, userRole string, courseID int) (bool, error) {
+	_, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseStaffRoles)
+	if errors.Is(err, ErrPermissionDenied) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// checkAssignment проверяет согласованность баллов и рубрики; используется и при импорте курса
+func checkAssignment(assignment *models.Assignment) error {
+	switch {
+	case assignment.Title == "":
+		return errors.New("validation failed: title is required")
+	case assignment.MaxScore <= 0:
+		return errors.New("validation failed: max_score must be positive")
+	case assignment.PassingScore < 0 || assignment.PassingScore > assignment.MaxScore:
+		return errors.New("validation failed: passing_score must be between 0 and max_score")
+	case assignment.LatePenaltyPercent < 0 || assignment.LatePenaltyPercent > 100:
+		return errors.New("validation failed: late_penalty_percent must be between 0 and 100")
+	case assignment.MaxLateDays != nil && *assignment.MaxLateDays < 0:
+		return errors.New("validation failed: max_late_days must not be negative")
+	}
+	for _, ext := range assignment.AllowedExtensions {
+		if !safeExtension.MatchString("." + ext) {
+			return fmt.Errorf("validation failed: invalid file extension %q", ext)
+		}
+	}
+	if len(assignment.Criteria) == 0 {
+		return nil
+	}
+	total := 0
+	for _, criterion := range assignment.Criteria {
+		if criterion.Title == "" || criterion.MaxPoints <= 0 {
+			return errors.New("validation failed: every criterion needs a title and positive max_points")
+		}
+		total += criterion.MaxPoints
+	}
+	if total != assignment.MaxScore {
+		return fmt.Errorf("validation failed: criteria points add up to %d, max_score is %d", total, assignment.MaxScore)
+	}
+	return nil
+}
+
+// scoreCriteria сопоставляет баллы с рубрикой: каждый критерий оценивается ровно один раз
+func scoreCriteria(criteria []*models.AssignmentCriterion, input []dto.CriterionScoreRequest) ([]models.AssignmentCriterionScore, error) {
+	byID := make(map[int]dto.CriterionScoreRequest, len(input))
+	for _, score := range input {
+		if _, ok := byID[score.CriterionID]; ok {
+			return nil, fmt.Errorf("validation failed: criterion %d is scored more than once", score.CriterionID)
+		}
+		byID[score.CriterionID] = score
+	}
+
+	scores := make([]models.AssignmentCriterionScore, 0, len(criteria))
+	for _, criterion := range criteria {
+		score, ok := byID[criterion.ID]
+		if !ok {
+			return nil, fmt.Errorf("validation failed: criterion %q is not scored", criterion.Title)
+		}
+		if score.Points > criterion.MaxPoints {
+			return nil, fmt.Errorf("validation failed: criterion %q allows at most %d points", criterion.Title, criterion.MaxPoints)
+		}
+		delete(byID, criterion.ID)
+		scores = append(scores, models.AssignmentCriterionScore{
+			CriterionID: criterion.ID,
+			Title:       criterion.Title,
+			Points:      score.Points,
+			MaxPoints:   criterion.MaxPoints,
+			Comment:     strings.TrimSpace(score.Comment),
+		})
+	}
+	for id := range byID {
+		return nil, fmt.Errorf("validation failed: criterion %d does not belong to this assignment", id)
+	}
+	return scores, nil
+}
+
+// lateDays - число начатых суток после срока сдачи; 0, если срок не задан или не прошёл
+func lateDays(dueAt *time.Time, submittedAt time.Time) int {
+	if dueAt == nil || !submittedAt.After(*dueAt) {
+		return 0
+	}
+	return int(math.Ceil(submittedAt.Sub(*dueAt).Hours() / 24))
+}
+
+// normalizeExtensions приводит расширения к виду "pdf": без точки, в нижнем регистре, без повторов
+func normalizeExtensions(extensions []string) []string {
+	normalized := []string{}
+	for _, ext := range extensions {
+		ext = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(ext)), ".")
+		if ext != "" && !containsString(normalized, ext) {
+			normalized = append(normalized, ext)
+		}
+	}
+	return normalized
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,302 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+// Mock для AssignmentService
+type MockAssignmentService struct {
+	mock.Mock
+	services.AssignmentServiceInterface
+}
+
+func (m *MockAssignmentService) GetLessonAssignment(ctx context.Context, lessonID int) (*models.Assignment, error) {
+	args := m.Called(ctx, lessonID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Assignment), args.Error(1)
+}
+
+func (m *MockAssignmentService) GetCourseAssignments(ctx context.Context, courseID int) ([]*models.Assignment, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]*models.Assignment), args.Error(1)
+}
+
+func (m *MockAssignmentService) SaveAssignment(ctx context.Context, assignment *models.Assignment) error {
+	args := m.Called(ctx, assignment)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentService) CreateSubmission(ctx context.Context, submission *models.AssignmentSubmission) error {
+	args := m.Called(ctx, submission)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentService) GetSubmission(ctx context.Context, id int) (*models.AssignmentSubmission, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AssignmentSubmission), args.Error(1)
+}
+
+func (m *MockAssignmentService) GetSubmissions(ctx context.Context, assignmentID int, userID *int) ([]*models.AssignmentSubmission, error) {
+	args := m.Called(ctx, assignmentID, userID)
+	return args.Get(0).([]*models.AssignmentSubmission), args.Error(1)
+}
+
+func (m *MockAssignmentService) GetLatestGraded(ctx context.Context, assignmentID, userID int) (*models.AssignmentSubmission, error) {
+	args := m.Called(ctx, assignmentID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AssignmentSubmission), args.Error(1)
+}
+
+func (m *MockAssignmentService) SaveGrade(ctx context.Context, submissionID int, grade *models.AssignmentGrade) error {
+	args := m.Called(ctx, submissionID, grade)
+	return args.Error(0)
+}
+
+func essayAssignment() *models.Assignment {
+	return &models.Assignment{
+		ID: 40, CourseID: 10, LessonID: 20, Title: "Essay", MaxScore: 10, PassingScore: 6, LatePenaltyPercent: 10,
+		Criteria: []*models.AssignmentCriterion{
+			{ID: 1, Position: 1, Title: "Content", MaxPoints: 7},
+			{ID: 2, Position: 2, Title: "Style", MaxPoints: 3},
+		},
+	}
+}
+
+func TestSetAssignmentRejectsRubricNotMatchingMaxScore(t *testing.T) {
+	ctx := context.Background()
+	assignmentService := new(MockAssignmentService)
+	lessonService := new(MockLessonService)
+	courseService := new(MockCourseService)
+	useCase := usecase.NewAssignmentUseCase(assignmentService, lessonService, courseService, nil, nil)
+
+	courseService.On("GetCourse", ctx, 10).Return(&models.Course{ID: 10}, nil)
+	lessonService.On("GetLessonByID", ctx, 20).Return(&models.Lesson{ID: 20, CourseID: 10}, nil)
+
+	_, err := useCase.SetAssignment(ctx, 1, "admin", 10, 20, &dto.AssignmentRequest{
+		Title: "Essay", MaxScore: 10, PassingScore: 6,
+		Criteria: []dto.AssignmentCriterionRequest{{Title: "Content", MaxPoints: 7}, {Title: "Style", MaxPoints: 2}},
+	})
+
+	assert.EqualError(t, err, "validation failed: criteria points add up to 9, max_score is 10")
+	assignmentService.AssertNotCalled(t, "SaveAssignment", mock.Anything, mock.Anything)
+}
+
+func TestSubmitAssignment(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Rejects file type not allowed by the assignment", func(t *testing.T) {
+		assignmentService := new(MockAssignmentService)
+		lessonService := new(MockLessonService)
+		useCase := usecase.NewAssignmentUseCase(assignmentService, lessonService, nil, nil, nil)
+
+		assignment := essayAssignment()
+		assignment.AllowedExtensions = []string{"pdf", "docx"}
+		lessonService.On("GetLessonByID", ctx, 20).Return(&models.Lesson{ID: 20, CourseID: 10}, nil)
+		assignmentService.On("GetLessonAssignment", ctx, 20).Return(assignment, nil)
+
+		_, err := useCase.Submit(ctx, 3, "student", 10, 20, &dto.SubmitAssignmentRequest{}, "essay.png", int64(len(pngHeader)), bytes.NewReader(pngHeader))
+
+		assert.EqualError(t, err, "validation failed: allowed file types are pdf, docx")
+		assignmentService.AssertNotCalled(t, "CreateSubmission", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Rejects submission after the late window", func(t *testing.T) {
+		assignmentService := new(MockAssignmentService)
+		lessonService := new(MockLessonService)
+		useCase := usecase.NewAssignmentUseCase(assignmentService, lessonService, nil, nil, nil)
+
+		assignment := essayAssignment()
+		dueAt := time.Now().Add(-50 * time.Hour)
+		assignment.DueAt = &dueAt
+		assignment.MaxLateDays = intPtr(2)
+		lessonService.On("GetLessonByID", ctx, 20).Return(&models.Lesson{ID: 20, CourseID: 10}, nil)
+		assignmentService.On("GetLessonAssignment", ctx, 20).Return(assignment, nil)
+
+		_, err := useCase.Submit(ctx, 3, "student", 10, 20, &dto.SubmitAssignmentRequest{}, "essay.png", int64(len(pngHeader)), bytes.NewReader(pngHeader))
+
+		assert.EqualError(t, err, "submission deadline has passed")
+		assignmentService.AssertNotCalled(t, "CreateSubmission", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Stores a late attempt as a private file of the student", func(t *testing.T) {
+		assignmentService := new(MockAssignmentService)
+		lessonService := new(MockLessonService)
+		mediaService := new(MockMediaService)
+		store := newMemoryBlobStore()
+		media := usecase.NewMediaUseCase(mediaService, nil, nil, store, testMediaLimits())
+		useCase := usecase.NewAssignmentUseCase(assignmentService, lessonService, nil, media, store)
+
+		assignment := essayAssignment()
+		dueAt := time.Now().Add(-25 * time.Hour)
+		assignment.DueAt = &dueAt
+		assignment.AllowedExtensions = []string{"png"}
+		lessonService.On("GetLessonByID", ctx, 20).Return(&models.Lesson{ID: 20, CourseID: 10}, nil)
+		assignmentService.On("GetLessonAssignment", ctx, 20).Return(assignment, nil)
+		mediaService.On("GetUsedBytes", ctx, 3).Return(int64(0), nil)
+		mediaService.On("CreateFile", ctx, mock.MatchedBy(func(file *models.MediaFile) bool {
+			return file.OwnerID == 3 && file.CourseID == nil && !file.Public
+		}), int64(100)).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.MediaFile).ID = 7
+		}).Return(true, nil)
+		assignmentService.On("CreateSubmission", ctx, mock.MatchedBy(func(submission *models.AssignmentSubmission) bool {
+			return submission.AssignmentID == 40 && submission.UserID == 3 && *submission.MediaID == 7 &&
+				submission.DaysLate == 2 && submission.Comment == "second try"
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.AssignmentSubmission).Attempt = 2
+		}).Return(nil)
+
+		submission, err := useCase.Submit(ctx, 3, "student", 10, 20, &dto.SubmitAssignmentRequest{Comment: " second try "}, "Essay.PNG", int64(len(pngHeader)), bytes.NewReader(pngHeader))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, submission.Attempt)
+		assert.Equal(t, "Essay.PNG", submission.Filename)
+		assert.Len(t, store.blobs, 1)
+		assignmentService.AssertExpectations(t)
+	})
+}
+
+func TestGradeSubmission(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Teaching assistant grades by rubric with late penalty", func(t *testing.T) {
+		assignmentService := new(MockAssignmentService)
+		lessonService := new(MockLessonService)
+		courseService := new(MockCourseService)
+		useCase := usecase.NewAssignmentUseCase(assignmentService, lessonService, courseService, nil, nil)
+
+		courseService.On("GetCourse", ctx, 10).Return(&models.Course{ID: 10}, nil)
+		courseService.On("GetStaffMember", ctx, 10, 6).Return(&models.CourseStaff{Role: models.CourseStaffRoleTA}, nil)
+		lessonService.On("GetLessonByID", ctx, 20).Return(&models.Lesson{ID: 20, CourseID: 10}, nil)
+		assignmentService.On("GetLessonAssignment", ctx, 20).Return(essayAssignment(), nil)
+		assignmentService.On("GetSubmission", ctx, 50).Return(&models.AssignmentSubmission{ID: 50, AssignmentID: 40, UserID: 3, DaysLate: 2}, nil)
+		assignmentService.On("SaveGrade", ctx, 50, mock.MatchedBy(func(grade *models.AssignmentGrade) bool {
+			return grade.RawScore == 8 && grade.PenaltyPercent == 20 && grade.Score == 6.4 && *grade.GradedBy == 6 &&
+				len(grade.Criteria) == 2 && grade.Criteria[0].Title == "Content" && grade.Criteria[1].Comment == "Too informal"
+		})).Return(nil)
+
+		submission, err := useCase.GradeSubmission(ctx, 6, "student", 10, 20, 50, &dto.GradeSubmissionRequest{
+			Criteria: []dto.CriterionScoreRequest{{CriterionID: 2, Points: 2, Comment: "Too informal"}, {CriterionID: 1, Points: 6}},
+			Feedback: "Good work, but submitted late",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 6.4, submission.Grade.Score)
+		assert.Equal(t, "Good work, but submitted late", submission.Grade.Feedback)
+		assignmentService.AssertExpectations(t)
+	})
+
+	t.Run("Rejects incomplete or excessive rubric scores", func(t *testing.T) {
+		assignmentService := new(MockAssignmentService)
+		lessonService := new(MockLessonService)
+		courseService := new(MockCourseService)
+		useCase := usecase.NewAssignmentUseCase(assignmentService, lessonService, courseService, nil, nil)
+
+		courseService.On("GetCourse", ctx, 10).Return(&models.Course{ID: 10}, nil)
+		lessonService.On("GetLessonByID", ctx, 20).Return(&models.Lesson{ID: 20, CourseID: 10}, nil)
+		assignmentService.On("GetLessonAssignment", ctx, 20).Return(essayAssignment(), nil)
+		assignmentService.On("GetSubmission", ctx, 50).Return(&models.AssignmentSubmission{ID: 50, AssignmentID: 40, UserID: 3}, nil)
+
+		_, err := useCase.GradeSubmission(ctx, 1, "admin", 10, 20, 50, &dto.GradeSubmissionRequest{
+			Criteria: []dto.CriterionScoreRequest{{CriterionID: 1, Points: 7}},
+		})
+		assert.EqualError(t, err, `validation failed: criterion "Style" is not scored`)
+
+		_, err = useCase.GradeSubmission(ctx, 1, "admin", 10, 20, 50, &dto.GradeSubmissionRequest{
+			Criteria: []dto.CriterionScoreRequest{{CriterionID: 1, Points: 7}, {CriterionID: 2, Points: 4}},
+		})
+		assert.EqualError(t, err, `validation failed: criterion "Style" allows at most 3 points`)
+
+		_, err = useCase.GradeSubmission(ctx, 1, "admin", 10, 20, 50, &dto.GradeSubmissionRequest{Score: intPtr(9)})
+		assert.EqualError(t, err, "validation failed: score is calculated from the rubric, grade each criterion instead")
+
+		assignmentService.AssertNotCalled(t, "SaveGrade", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Student cannot grade", func(t *testing.T) {
+		assignmentService := new(MockAssignmentService)
+		courseService := new(MockCourseService)
+		useCase := usecase.NewAssignmentUseCase(assignmentService, nil, courseService, nil, nil)
+
+		courseService.On("GetCourse", ctx, 10).Return(&models.Course{ID: 10}, nil)
+		courseService.On("GetStaffMember", ctx, 10, 3).Return(nil, nil)
+
+		_, err := useCase.GradeSubmission(ctx, 3, "student", 10, 20, 50, &dto.GradeSubmissionRequest{Score: intPtr(10)})
+
+		assert.True(t, errors.Is(err, usecase.ErrPermissionDenied))
+		assignmentService.AssertNotCalled(t, "SaveGrade", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestListSubmissionsStudentSeesOnlyOwnAttempts(t *testing.T) {
+	ctx := context.Background()
+	assignmentService := new(MockAssignmentService)
+	lessonService := new(MockLessonService)
+	courseService := new(MockCourseService)
+	useCase := usecase.NewAssignmentUseCase(assignmentService, lessonService, courseService, nil, nil)
+
+	courseService.On("GetCourse", ctx, 10).Return(&models.Course{ID: 10}, nil)
+	courseService.On("GetStaffMember", ctx, 10, 3).Return(nil, nil)
+	lessonService.On("GetLessonByID", ctx, 20).Return(&models.Lesson{ID: 20, CourseID: 10}, nil)
+	assignmentService.On("GetLessonAssignment", ctx, 20).Return(essayAssignment(), nil)
+	assignmentService.On("GetSubmissions", ctx, 40, intPtr(3)).Return([]*models.AssignmentSubmission{{ID: 51, Attempt: 2}, {ID: 50, Attempt: 1}}, nil)
+
+	submissions, err := useCase.ListSubmissions(ctx, 3, "student", 10, 20, intPtr(4))
+
+	assert.NoError(t, err)
+	assert.Len(t, submissions, 2)
+	assignmentService.AssertExpectations(t)
+}
+
+func TestCheckAssignmentPassed(t *testing.T) {
+	ctx := context.Background()
+	lesson := &models.Lesson{ID: 20, CourseID: 10}
+
+	t.Run("Lessons without a required passing grade complete freely", func(t *testing.T) {
+		assignmentService := new(MockAssignmentService)
+		useCase := usecase.NewAssignmentUseCase(assignmentService, nil, nil, nil, nil)
+		assignmentService.On("GetLessonAssignment", ctx, 20).Return(essayAssignment(), nil)
+
+		assert.NoError(t, useCase.CheckPassed(ctx, 3, lesson))
+		assignmentService.AssertNotCalled(t, "GetLatestGraded", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Requires a graded attempt with a passing score", func(t *testing.T) {
+		assignmentService := new(MockAssignmentService)
+		useCase := usecase.NewAssignmentUseCase(assignmentService, nil, nil, nil, nil)
+		assignment := essayAssignment()
+		assignment.RequirePassing = true
+		assignmentService.On("GetLessonAssignment", ctx, 20).Return(assignment, nil)
+		assignmentService.On("GetLatestGraded", ctx, 40, 3).Return(nil, nil).Once()
+		assignmentService.On("GetLatestGraded", ctx, 40, 3).Return(&models.AssignmentSubmission{Grade: &models.AssignmentGrade{Score: 5.6}}, nil).Once()
+		assignmentService.On("GetLatestGraded", ctx, 40, 3).Return(&models.AssignmentSubmission{Grade: &models.AssignmentGrade{Score: 6}}, nil).Once()
+
+		err := useCase.CheckPassed(ctx, 3, lesson)
+		assert.True(t, errors.Is(err, usecase.ErrLessonRequirementsNotMet))
+		assert.ErrorContains(t, err, "has not been graded yet")
+
+		err = useCase.CheckPassed(ctx, 3, lesson)
+		assert.True(t, errors.Is(err, usecase.ErrLessonRequirementsNotMet))
+		assert.ErrorContains(t, err, "needs at least 6 points (scored 5.6)")
+
+		assert.NoError(t, useCase.CheckPassed(ctx, 3, lesson))
+	})
+}
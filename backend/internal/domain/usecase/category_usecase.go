@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+type CategoryUseCaseInterface interface {
+	CreateCategory(ctx context.Context, input *dto.CreateCategoryRequest) (*models.Category, error)
+	GetCategory(ctx context.Context, id int) (*models.Category, error)
+	GetCategoryTree(ctx context.Context) ([]*models.Category, error)
+	UpdateCategory(ctx context.Context, id int, input *dto.CreateCategoryRequest) (*models.Category, error)
+	DeleteCategory(ctx context.Context, id int) error
+}
+
+type CategoryUseCase struct {
+	categoryService services.CategoryServiceInterface
+}
+
+func NewCategoryUseCase(categoryService services.CategoryServiceInterface) *CategoryUseCase {
+	return &CategoryUseCase{categoryService: categoryService}
+}
+
+// CreateCategory создаёт категорию, при указании parent_id - подкатегорию
+func (u *CategoryUseCase) CreateCategory(ctx context.Context, input *dto.CreateCategoryRequest) (*models.Category, error) {
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if input.ParentID != nil {
+		if _, err := u.categoryService.GetCategory(ctx, *input.ParentID); err != nil {
+			return nil, errors.New("parent category not found")
+		}
+	}
+
+	return u.categoryService.CreateCategory(ctx, &models.Category{
+		Name:        input.Name,
+		Description: input.Description,
+		ParentID:    input.ParentID,
+	})
+}
+
+// GetCategory возвращает категорию вместе с прямыми подкатегориями
+func (u *CategoryUseCase) GetCategory(ctx context.Context, id int) (*models.Category, error) {
+	category, err := u.categoryService.GetCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := u.categoryService.GetAllCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range categories {
+		if c.ParentID != nil && *c.ParentID == category.ID {
+			category.Children = append(category.Children, c)
+		}
+	}
+	return category, nil
+}
+
+// GetCategoryTree возвращает все категории в виде дерева
+func (u *CategoryUseCase) GetCategoryTree(ctx context.Context) ([]*models.Category, error) {
+	categories, err := u.categoryService.GetAllCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return BuildCategoryTree(categories), nil
+}
+
+// UpdateCategory обновляет категорию; категорию нельзя переместить внутрь её собственного поддерева
+func (u *CategoryUseCase) UpdateCategory(ctx context.Context, id int, input *dto.CreateCategoryRequest) (*models.Category, error) {
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	category, err := u.categoryService.GetCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.ParentID != nil {
+		if _, err := u.categoryService.GetCategory(ctx, *input.ParentID); err != nil {
+			return nil, errors.New("parent category not found")
+		}
+		cycle, err := u.categoryService.IsDescendant(ctx, *input.ParentID, id)
+		if err != nil {
+			return nil, err
+		}
+		if cycle {
+			return nil, errors.New("category cannot be moved into itself or its subcategory")
+		}
+	}
+
+	category.Name = input.Name
+	category.Description = input.Description
+	category.ParentID = input.ParentID
+
+	if err := u.categoryService.UpdateCategory(ctx, category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// DeleteCategory удаляет категорию
+func (u *CategoryUseCase) DeleteCategory(ctx context.Context, id int) error {
+	if _, err := u.categoryService.GetCategory(ctx, id); err != nil {
+		return err
+	}
+	return u.categoryService.DeleteCategory(ctx, id)
+}
+
+// BuildCategoryTree собирает плоский список категорий в дерево, возвращает корневые категории
+func BuildCategoryTree(categories []*models.Category) []*models.Category {
+	byID := make(map[int]*models.Category, len(categories))
+	for _, category := range categories {
+		category.Children = nil
+		byID[category.ID] = category
+	}
+
+	roots := []*models.Category{}
+	for _, category := range categories {
+		if category.ParentID != nil {
+			if parent, ok := byID[*category.ParentID]; ok {
+				parent.Children = append(parent.Children, category)
+				continue
+			}
+		}
+		roots = append(roots, category)
+	}
+	return roots
+}
@@ -0,0 +1,115 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+func (m *MockCategoryService) GetCategory(ctx context.Context, id int) (*models.Category, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Category), args.Error(1)
+}
+
+func (m *MockCategoryService) IsDescendant(ctx context.Context, categoryID, ancestorID int) (bool, error) {
+	args := m.Called(ctx, categoryID, ancestorID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCategoryService) UpdateCategory(ctx context.Context, category *models.Category) error {
+	args := m.Called(ctx, category)
+	return args.Error(0)
+}
+
+func TestUpdateCategoryParent(t *testing.T) {
+	ctx := context.Background()
+	backend, golang, concurrency := 1, 2, 3
+
+	t.Run("Cannot move category into its subcategory", func(t *testing.T) {
+		categoryService := new(MockCategoryService)
+		useCase := usecase.NewCategoryUseCase(categoryService)
+
+		categoryService.On("GetCategory", ctx, golang).Return(&models.Category{ID: golang, Name: "Go", ParentID: &backend}, nil)
+		categoryService.On("GetCategory", ctx, concurrency).Return(&models.Category{ID: concurrency, Name: "Concurrency", ParentID: &golang}, nil)
+		categoryService.On("IsDescendant", ctx, concurrency, golang).Return(true, nil)
+
+		_, err := useCase.UpdateCategory(ctx, golang, &dto.CreateCategoryRequest{Name: "Go", ParentID: &concurrency})
+
+		assert.EqualError(t, err, "category cannot be moved into itself or its subcategory")
+		categoryService.AssertNotCalled(t, "UpdateCategory", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Cannot be its own parent", func(t *testing.T) {
+		categoryService := new(MockCategoryService)
+		useCase := usecase.NewCategoryUseCase(categoryService)
+
+		categoryService.On("GetCategory", ctx, golang).Return(&models.Category{ID: golang, Name: "Go"}, nil)
+		categoryService.On("IsDescendant", ctx, golang, golang).Return(true, nil)
+
+		_, err := useCase.UpdateCategory(ctx, golang, &dto.CreateCategoryRequest{Name: "Go", ParentID: &golang})
+
+		assert.EqualError(t, err, "category cannot be moved into itself or its subcategory")
+		categoryService.AssertNotCalled(t, "UpdateCategory", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Moves category under another branch", func(t *testing.T) {
+		categoryService := new(MockCategoryService)
+		useCase := usecase.NewCategoryUseCase(categoryService)
+
+		categoryService.On("GetCategory", ctx, concurrency).Return(&models.Category{ID: concurrency, Name: "Concurrency", ParentID: &golang}, nil)
+		categoryService.On("GetCategory", ctx, backend).Return(&models.Category{ID: backend, Name: "Backend"}, nil)
+		categoryService.On("IsDescendant", ctx, backend, concurrency).Return(false, nil)
+		categoryService.On("UpdateCategory", ctx, mock.MatchedBy(func(category *models.Category) bool {
+			return category.ID == concurrency && category.ParentID != nil && *category.ParentID == backend
+		})).Return(nil)
+
+		category, err := useCase.UpdateCategory(ctx, concurrency, &dto.CreateCategoryRequest{Name: "Concurrency", ParentID: &backend})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &backend, category.ParentID)
+		categoryService.AssertExpectations(t)
+	})
+
+	t.Run("Unknown parent", func(t *testing.T) {
+		categoryService := new(MockCategoryService)
+		useCase := usecase.NewCategoryUseCase(categoryService)
+		missing := 99
+
+		categoryService.On("GetCategory", ctx, golang).Return(&models.Category{ID: golang, Name: "Go"}, nil)
+		categoryService.On("GetCategory", ctx, missing).Return(nil, errors.New("category not found"))
+
+		_, err := useCase.UpdateCategory(ctx, golang, &dto.CreateCategoryRequest{Name: "Go", ParentID: &missing})
+
+		assert.EqualError(t, err, "parent category not found")
+		categoryService.AssertNotCalled(t, "IsDescendant", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestBuildCategoryTree(t *testing.T) {
+	backend, golang := 1, 2
+	categories := []*models.Category{
+		{ID: backend, Name: "Backend"},
+		{ID: golang, Name: "Go", ParentID: &backend},
+		{ID: 3, Name: "Concurrency", ParentID: &golang},
+		{ID: 4, Name: "Design"},
+	}
+
+	roots := usecase.BuildCategoryTree(categories)
+
+	assert.Len(t, roots, 2)
+	assert.Equal(t, "Backend", roots[0].Name)
+	assert.Len(t, roots[0].Children, 1)
+	assert.Equal(t, "Go", roots[0].Children[0].Name)
+	assert.Equal(t, "Concurrency", roots[0].Children[0].Children[0].Name)
+	assert.Empty(t, roots[1].Children)
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+)
+
+// Роли команды курса, которым разрешено действие
+var (
+	courseOwnerRoles  = []string{models.CourseStaffRoleOwner}
+	courseEditorRoles = []string{models.CourseStaffRoleOwner, models.CourseStaffRoleCoTeacher}
+	courseStaffRoles  = []string{models.CourseStaffRoleOwner, models.CourseStaffRoleCoTeacher, models.CourseStaffRoleTA}
+)
+
+// checkCourseStaff возвращает курс, если пользователь администратор или входит
+// в команду курса с одной из перечисленных ролей
+func checkCourseStaff(ctx context.Context, courseService services.CourseServiceInterface, userID int, userRole string, courseID int, roles []string) (*models.Course, error) {
+	course, err := courseService.GetCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if userRole == "admin" {
+		return course, nil
+	}
+
+	member, err := courseService.GetStaffMember(ctx, courseID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, fmt.Errorf("%w: user is not a staff member of this course", ErrPermissionDenied)
+	}
+	for _, role := range roles {
+		if member.Role == role {
+			return course, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: course role %q is not allowed to do this", ErrPermissionDenied, member.Role)
+}
+
+// getCourseLesson возвращает урок, только если он принадлежит курсу
+func getCourseLesson(ctx context.Context, lessonService services.LessonServiceInterface, courseID, lessonID int) (*models.Lesson, error) {
+	lesson, err := lessonService.GetLessonByID(ctx, lessonID)
+	if err != nil || lesson.CourseID != courseID {
+		return nil, errors.New("lesson not found")
+	}
+	return lesson, nil
+}
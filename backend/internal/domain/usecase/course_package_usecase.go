@@ -0,0 +1,729 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+	"gitlab.com/w0ikid/study-platform/pkg/markdown"
+	"gitlab.com/w0ikid/study-platform/pkg/storage"
+)
+
+// максимальный размер manifest.json внутри пакета
+const maxManifestSize = 10 << 20
+
+type CoursePackageUseCaseInterface interface {
+	ExportCourse(ctx context.Context, userID int, userRole string, courseID int) ([]byte, error)
+	ImportCourse(ctx context.Context, userID int, userRole string, r io.ReaderAt, size int64, dryRun bool) (*models.CourseImportReport, error)
+}
+
+type CoursePackageUseCase struct {
+	courseService     services.CourseServiceInterface
+	lessonService     services.LessonServiceInterface
+	categoryService   services.CategoryServiceInterface
+	moduleService     services.ModuleServiceInterface
+	blockService      services.LessonBlockServiceInterface
+	attachmentService services.LessonAttachmentServiceInterface
+	attachments       LessonAttachmentUseCaseInterface
+	releaseService    services.LessonReleaseServiceInterface
+	assignmentService services.AssignmentServiceInterface
+	store             storage.BlobStore
+}
+
+func NewCoursePackageUseCase(
+	courseService services.CourseServiceInterface,
+	lessonService services.LessonServiceInterface,
+	categoryService services.CategoryServiceInterface,
+	moduleService services.ModuleServiceInterface,
+	blockService services.LessonBlockServiceInterface,
+	attachmentService services.LessonAttachmentServiceInterface,
+	attachments LessonAttachmentUseCaseInterface,
+	releaseService services.LessonReleaseServiceInterface,
+	assignmentService services.AssignmentServiceInterface,
+	store storage.BlobStore,
+) *CoursePackageUseCase {
+	return &CoursePackageUseCase{
+		courseService:     courseService,
+		lessonService:     lessonService,
+		categoryService:   categoryService,
+		moduleService:     moduleService,
+		blockService:      blockService,
+		attachmentService: attachmentService,
+		attachments:       attachments,
+		releaseService:    releaseService,
+		assignmentService: assignmentService,
+		store:             store,
+	}
+}
+
+// ExportCourse собирает ZIP-пакет курса: manifest.json с курсом и уроками и файлы вложений уроков в assets/
+func (u *CoursePackageUseCase) ExportCourse(ctx context.Context, userID int, userRole string, courseID int) ([]byte, error) {
+	course, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseEditorRoles)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, assetKeys, err := u.buildManifest(ctx, course)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	file, err := archive.Create(models.CoursePackageManifestName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create package: %w", err)
+	}
+	if _, err := file.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+	for i, asset := range manifest.Assets {
+		if err := u.writeAsset(ctx, archive, asset.Path, assetKeys[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to create package: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// buildManifest возвращает манифест и ключи хранилища файлов в порядке manifest.Assets
+func (u *CoursePackageUseCase) buildManifest(ctx context.Context, course *models.Course) (*models.CoursePackageManifest, []string, error) {
+	prerequisites, err := u.courseService.GetPrerequisites(ctx, course.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	modules, err := u.moduleService.GetModulesByCourse(ctx, course.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	// уроки приходят в порядке прохождения: по модулям, внутри модуля по позиции
+	lessons, err := u.lessonService.GetAllLessons(ctx, course.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	manifest := &models.CoursePackageManifest{
+		Format:     models.CoursePackageFormat,
+		Version:    models.CoursePackageVersion,
+		ExportedAt: time.Now().UTC(),
+		Course: models.CoursePackageCourse{
+			ID:              course.ID,
+			Name:            course.Name,
+			Description:     course.Description,
+			ImageUrl:        course.ImageUrl,
+			Status:          course.Status,
+			Level:           course.Level,
+			DurationMinutes: course.DurationMinutes,
+			Language:        course.Language,
+			MinUserLevel:    course.MinUserLevel,
+			Tags:            course.Tags,
+			Prerequisites:   []models.CoursePackagePrerequisite{},
+			TeacherID:       course.TeacherID,
+		},
+		Lessons: make([]models.CoursePackageLesson, 0, len(lessons)),
+		Assets:  []models.CoursePackageAsset{},
+	}
+	var assetKeys []string
+	if manifest.Course.Tags == nil {
+		manifest.Course.Tags = []string{}
+	}
+
+	if course.CategoryID != nil {
+		categories, err := u.categoryService.GetAllCategories(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		manifest.Course.CategoryPath = categoryPath(categories, *course.CategoryID)
+	}
+
+	for _, prerequisite := range prerequisites {
+		manifest.Course.Prerequisites = append(manifest.Course.Prerequisites, models.CoursePackagePrerequisite{
+			ID:   prerequisite.PrerequisiteID,
+			Name: prerequisite.Name,
+		})
+	}
+
+	for _, module := range modules {
+		manifest.Modules = append(manifest.Modules, models.CoursePackageModule{
+			ID:          module.ID,
+			Position:    module.Position,
+			Title:       module.Title,
+			Description: module.Description,
+		})
+	}
+
+	rules, err := u.releaseService.GetCourseRules(ctx, course.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	releases := make(map[int]*models.CoursePackageRelease, len(rules))
+	for _, rule := range rules {
+		releases[rule.LessonID] = &models.CoursePackageRelease{
+			Type:                rule.Type,
+			ReleaseAt:           rule.ReleaseAt,
+			DaysAfterEnrollment: rule.DaysAfterEnrollment,
+			AfterLessonID:       rule.AfterLessonID,
+		}
+	}
+
+	courseAssignments, err := u.assignmentService.GetCourseAssignments(ctx, course.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	assignments := make(map[int]*models.CoursePackageAssignment, len(courseAssignments))
+	for _, assignment := range courseAssignments {
+		assignments[assignment.LessonID] = packageAssignment(assignment)
+	}
+
+	for i, lesson := range lessons {
+		blocks, err := u.blockService.GetLessonBlocks(ctx, lesson.ID, 0)
+		if err != nil {
+			return nil, nil, err
+		}
+		packaged := models.CoursePackageLesson{
+			ID:            lesson.ID,
+			ModuleID:      moduleKey(lesson.ModuleID),
+			Position:      i + 1,
+			Title:         lesson.Title,
+			Content:       lesson.Content,
+			VideoURL:      lesson.VideoURL,
+			VideoDuration: lesson.VideoDuration,
+			Release:       releases[lesson.ID],
+			Assignment:    assignments[lesson.ID],
+		}
+		for _, block := range blocks {
+			packaged.Blocks = append(packaged.Blocks, models.CoursePackageBlock{Type: block.Type, Data: block.Data, Required: block.Required})
+		}
+		manifest.Lessons = append(manifest.Lessons, packaged)
+
+		attachments, err := u.attachmentService.GetLessonAttachments(ctx, lesson.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, attachment := range attachments {
+			manifest.Assets = append(manifest.Assets, models.CoursePackageAsset{
+				Path:        fmt.Sprintf("assets/lessons/%d/%d/%s", lesson.ID, attachment.ID, attachment.Filename),
+				LessonID:    lesson.ID,
+				Title:       attachment.Title,
+				Watermark:   attachment.Watermark,
+				ContentType: attachment.ContentType,
+				Size:        attachment.Size,
+			})
+			assetKeys = append(assetKeys, attachment.StorageKey)
+		}
+	}
+
+	return manifest, assetKeys, nil
+}
+
+// writeAsset копирует файл из хранилища в архив
+func (u *CoursePackageUseCase) writeAsset(ctx context.Context, archive *zip.Writer, name, storageKey string) error {
+	content, err := u.store.Get(ctx, storageKey)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	file, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to create package: %w", err)
+	}
+	if _, err := io.Copy(file, content); err != nil {
+		return fmt.Errorf("failed to write asset %s: %w", name, err)
+	}
+	return nil
+}
+
+// ImportCourse проверяет пакет и создаёт из него новый черновик курса, владельцем которого
+// становится импортирующий пользователь. Ошибки в манифесте отменяют импорт целиком;
+// категории и пререквизиты, которых нет в этой инсталляции, попадают в conflicts и пропускаются.
+func (u *CoursePackageUseCase) ImportCourse(ctx context.Context, userID int, userRole string, r io.ReaderAt, size int64, dryRun bool) (*models.CourseImportReport, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: package is not a valid zip archive: %w", err)
+	}
+
+	manifest, err := readManifest(archive)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.CourseImportReport{
+		DryRun:         dryRun,
+		Version:        manifest.Version,
+		SourceCourseID: manifest.Course.ID,
+	}
+
+	report.Errors = validateManifest(manifest, archive)
+	if len(report.Errors) > 0 {
+		return report, nil
+	}
+
+	course := &models.Course{
+		Name:            strings.TrimSpace(manifest.Course.Name),
+		Description:     manifest.Course.Description,
+		ImageUrl:        manifest.Course.ImageUrl,
+		TeacherID:       userID,
+		Status:          models.CourseStatusDraft,
+		Level:           manifest.Course.Level,
+		DurationMinutes: manifest.Course.DurationMinutes,
+		Language:        manifest.Course.Language,
+		MinUserLevel:    manifest.Course.MinUserLevel,
+	}
+
+	seenTags := make(map[string]bool)
+	for _, tag := range manifest.Course.Tags {
+		tag = normalizeTag(tag)
+		if tag != "" && !seenTags[tag] {
+			seenTags[tag] = true
+			course.Tags = append(course.Tags, tag)
+		}
+	}
+
+	existing, err := u.courseService.FindCoursesByName(ctx, course.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		report.AddConflict("course.name", fmt.Sprintf("a course named %q already exists (id %d), the import is created as a separate draft", course.Name, existing[0].ID))
+	}
+
+	if len(manifest.Course.CategoryPath) > 0 {
+		categories, err := u.categoryService.GetAllCategories(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if id, ok := findCategoryByPath(categories, manifest.Course.CategoryPath); ok {
+			course.CategoryID = &id
+		} else {
+			report.AddConflict("course.category_path", fmt.Sprintf("category %q not found, the course is imported without a category", strings.Join(manifest.Course.CategoryPath, " / ")))
+		}
+	}
+
+	var prerequisiteIDs []int
+	for _, prerequisite := range manifest.Course.Prerequisites {
+		matches, err := u.courseService.FindCoursesByName(ctx, prerequisite.Name)
+		if err != nil {
+			return nil, err
+		}
+		switch len(matches) {
+		case 1:
+			prerequisiteIDs = append(prerequisiteIDs, matches[0].ID)
+		case 0:
+			report.AddConflict("course.prerequisites", fmt.Sprintf("prerequisite %q not found, skipped", prerequisite.Name))
+		default:
+			report.AddConflict("course.prerequisites", fmt.Sprintf("prerequisite %q matches %d courses, skipped", prerequisite.Name, len(matches)))
+		}
+	}
+
+	for _, asset := range manifest.Assets {
+		if asset.LessonID == 0 {
+			report.AddConflict("assets", fmt.Sprintf("%s is not attached to a lesson, skipped", asset.Path))
+		}
+	}
+
+	sourceLessons := append([]models.CoursePackageLesson(nil), manifest.Lessons...)
+	sort.SliceStable(sourceLessons, func(i, j int) bool { return sourceLessons[i].Position < sourceLessons[j].Position })
+
+	modules := make([]*models.Module, 0, len(manifest.Modules))
+	for _, module := range manifest.Modules {
+		modules = append(modules, &models.Module{
+			ID:          module.ID,
+			Title:       strings.TrimSpace(module.Title),
+			Description: module.Description,
+			Position:    module.Position,
+		})
+	}
+	sourceModuleIDs := make([]int, len(modules))
+	for i, module := range modules {
+		sourceModuleIDs[i] = module.ID
+	}
+
+	lessons := make([]*models.Lesson, 0, len(sourceLessons))
+	for _, lesson := range sourceLessons {
+		imported := &models.Lesson{
+			Title:         strings.TrimSpace(lesson.Title),
+			Content:       lesson.Content,
+			VideoURL:      lesson.VideoURL,
+			VideoDuration: lesson.VideoDuration,
+		}
+		if err := renderLessonContent(imported); err != nil {
+			return nil, err
+		}
+		for i, packaged := range lesson.Blocks {
+			block := &models.LessonBlock{Type: packaged.Type, Data: packaged.Data, Required: packaged.Required}
+			mediaID, err := prepareLessonBlock(block)
+			if err != nil {
+				return nil, err
+			}
+			// файлы исходной инсталляции в пакет не входят
+			if mediaID != 0 {
+				report.AddConflict("lessons.blocks", fmt.Sprintf("lesson %q: %s block %d references media file %d of the source instance, skipped", imported.Title, block.Type, i+1, mediaID))
+				continue
+			}
+			imported.Blocks = append(imported.Blocks, block)
+		}
+		if lesson.ModuleID != 0 {
+			moduleID := lesson.ModuleID
+			imported.ModuleID = &moduleID
+		}
+		lessons = append(lessons, imported)
+	}
+
+	if dryRun {
+		return report, nil
+	}
+
+	if err := u.courseService.CreateCourseWithContent(ctx, course, prerequisiteIDs, modules, lessons); err != nil {
+		return nil, err
+	}
+
+	if len(modules) > 0 {
+		report.ModuleIDs = make(map[int]int, len(modules))
+		for i, module := range modules {
+			report.ModuleIDs[sourceModuleIDs[i]] = module.ID
+		}
+	}
+
+	report.LessonIDs = make(map[int]int, len(lessons))
+	for i, lesson := range lessons {
+		report.LessonIDs[sourceLessons[i].ID] = lesson.ID
+	}
+
+	// правила открытия, задания и вложения ссылаются на уроки манифеста, поэтому сохраняются
+	// после создания уроков. Если что-то из них не сохранилось, курс удаляется целиком,
+	// чтобы импорт не оставил наполовину собранный курс.
+	var uploaded []*models.LessonAttachment
+	fail := func(err error) (*models.CourseImportReport, error) {
+		u.discardImport(ctx, userID, userRole, course.ID, uploaded)
+		return nil, err
+	}
+
+	for _, lesson := range sourceLessons {
+		if lesson.Release == nil {
+			continue
+		}
+		rule := &models.LessonReleaseRule{
+			LessonID:            report.LessonIDs[lesson.ID],
+			Type:                lesson.Release.Type,
+			ReleaseAt:           lesson.Release.ReleaseAt,
+			DaysAfterEnrollment: lesson.Release.DaysAfterEnrollment,
+		}
+		if lesson.Release.AfterLessonID != nil {
+			afterLessonID := report.LessonIDs[*lesson.Release.AfterLessonID]
+			rule.AfterLessonID = &afterLessonID
+		}
+		if err := u.releaseService.SaveRule(ctx, rule); err != nil {
+			return fail(fmt.Errorf("lesson %q: release rule is not imported: %w", lesson.Title, err))
+		}
+	}
+
+	for _, lesson := range sourceLessons {
+		if lesson.Assignment == nil {
+			continue
+		}
+		assignment := importedAssignment(lesson.Assignment)
+		assignment.LessonID = report.LessonIDs[lesson.ID]
+		html, err := markdown.Render(assignment.Instructions)
+		if err == nil {
+			assignment.InstructionsHTML = html
+			err = u.assignmentService.SaveAssignment(ctx, assignment)
+		}
+		if err != nil {
+			return fail(fmt.Errorf("lesson %q: assignment is not imported: %w", lesson.Title, err))
+		}
+	}
+
+	// вложения загружаются в медиатеку импортирующего с обычными проверками типа и квоты
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+	for _, asset := range manifest.Assets {
+		if asset.LessonID == 0 {
+			continue
+		}
+		attachment, err := u.importAsset(ctx, userID, userRole, course.ID, report.LessonIDs[asset.LessonID], asset, files[asset.Path])
+		if err != nil {
+			return fail(fmt.Errorf("%s is not imported: %w", asset.Path, err))
+		}
+		uploaded = append(uploaded, attachment)
+	}
+
+	report.Course, err = u.courseService.GetCourse(ctx, course.ID)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// importAsset прикрепляет файл из архива к импортированному уроку
+func (u *CoursePackageUseCase) importAsset(ctx context.Context, userID int, userRole string, courseID, lessonID int, asset models.CoursePackageAsset, file *zip.File) (*models.LessonAttachment, error) {
+	content, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	input := &dto.LessonAttachmentUploadRequest{Title: asset.Title, Watermark: asset.Watermark}
+	return u.attachments.UploadAttachment(ctx, userID, userRole, courseID, lessonID, input, path.Base(asset.Path), int64(file.UncompressedSize64), content)
+}
+
+// discardImport удаляет курс неудавшегося импорта вместе с загруженными вложениями;
+// файлы вложений удаляются из медиатеки и хранилища, а не остаются без курса
+func (u *CoursePackageUseCase) discardImport(ctx context.Context, userID int, userRole string, courseID int, uploaded []*models.LessonAttachment) {
+	for _, attachment := range uploaded {
+		if err := u.attachments.DeleteAttachment(ctx, userID, userRole, courseID, attachment.LessonID, attachment.ID); err != nil {
+			log.Printf("course import: failed to remove attachment %d: %v", attachment.ID, err)
+		}
+	}
+	if err := u.courseService.DeleteCourse(ctx, courseID); err != nil {
+		log.Printf("course import: failed to remove course %d: %v", courseID, err)
+	}
+}
+
+func readManifest(archive *zip.Reader) (*models.CoursePackageManifest, error) {
+	for _, file := range archive.File {
+		if file.Name != models.CoursePackageManifestName {
+			continue
+		}
+		if file.UncompressedSize64 > maxManifestSize {
+			return nil, errors.New("validation failed: manifest is too large")
+		}
+
+		f, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("validation failed: cannot read manifest: %w", err)
+		}
+		defer f.Close()
+
+		var manifest models.CoursePackageManifest
+		if err := json.NewDecoder(io.LimitReader(f, maxManifestSize)).Decode(&manifest); err != nil {
+			return nil, fmt.Errorf("validation failed: invalid manifest: %w", err)
+		}
+		return &manifest, nil
+	}
+	return nil, fmt.Errorf("validation failed: package has no %s", models.CoursePackageManifestName)
+}
+
+// validateManifest возвращает список ошибок манифеста, пустой список - манифест корректен
+func validateManifest(manifest *models.CoursePackageManifest, archive *zip.Reader) []string {
+	var errs []string
+	if manifest.Format != models.CoursePackageFormat {
+		errs = append(errs, fmt.Sprintf("format: expected %q, got %q", models.CoursePackageFormat, manifest.Format))
+	}
+	if manifest.Version < 1 || manifest.Version > models.CoursePackageVersion {
+		errs = append(errs, fmt.Sprintf("version: unsupported version %d (supported: 1-%d)", manifest.Version, models.CoursePackageVersion))
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	course := manifest.Course
+	if strings.TrimSpace(course.Name) == "" {
+		errs = append(errs, "course.name: required")
+	}
+	if len(course.Name) > 255 {
+		errs = append(errs, "course.name: longer than 255 characters")
+	}
+	if models.CourseLevelName(course.Level) == "" {
+		errs = append(errs, fmt.Sprintf("course.level: unknown level %d", course.Level))
+	}
+	if course.MinUserLevel < 0 || course.MinUserLevel > models.CourseLevelAdvanced {
+		errs = append(errs, fmt.Sprintf("course.min_user_level: unknown level %d", course.MinUserLevel))
+	}
+	if course.DurationMinutes < 0 {
+		errs = append(errs, "course.duration_minutes: must not be negative")
+	}
+	for _, tag := range course.Tags {
+		if len(normalizeTag(tag)) > 32 {
+			errs = append(errs, fmt.Sprintf("course.tags: tag %q is longer than 32 characters", tag))
+		}
+	}
+
+	moduleIDs := make(map[int]bool)
+	for i, module := range manifest.Modules {
+		if strings.TrimSpace(module.Title) == "" {
+			errs = append(errs, fmt.Sprintf("modules[%d].title: required", i))
+		}
+		if len(module.Title) > 255 {
+			errs = append(errs, fmt.Sprintf("modules[%d].title: longer than 255 characters", i))
+		}
+		if module.ID <= 0 {
+			errs = append(errs, fmt.Sprintf("modules[%d].id: must be positive", i))
+		} else if moduleIDs[module.ID] {
+			errs = append(errs, fmt.Sprintf("modules[%d].id: duplicate module id %d", i, module.ID))
+		}
+		moduleIDs[module.ID] = true
+	}
+
+	lessonIDs := make(map[int]bool)
+	for i, lesson := range manifest.Lessons {
+		if strings.TrimSpace(lesson.Title) == "" {
+			errs = append(errs, fmt.Sprintf("lessons[%d].title: required", i))
+		}
+		if lesson.ModuleID != 0 && !moduleIDs[lesson.ModuleID] {
+			errs = append(errs, fmt.Sprintf("lessons[%d].module_id: unknown module %d", i, lesson.ModuleID))
+		}
+		// по ID урока строятся lesson_ids отчёта, правила открытия, задания и вложения
+		if lesson.ID <= 0 {
+			errs = append(errs, fmt.Sprintf("lessons[%d].id: must be positive", i))
+		} else if lessonIDs[lesson.ID] {
+			errs = append(errs, fmt.Sprintf("lessons[%d].id: duplicate lesson id %d", i, lesson.ID))
+		}
+		lessonIDs[lesson.ID] = true
+		for j, block := range lesson.Blocks {
+			if err := validateLessonBlockData(block.Type, block.Data); err != nil {
+				errs = append(errs, fmt.Sprintf("lessons[%d].blocks[%d]: %s", i, j, strings.TrimPrefix(err.Error(), "validation failed: ")))
+			}
+		}
+	}
+	for i, lesson := range manifest.Lessons {
+		release := lesson.Release
+		if release == nil {
+			continue
+		}
+		rule := &models.LessonReleaseRule{Type: release.Type, ReleaseAt: release.ReleaseAt, DaysAfterEnrollment: release.DaysAfterEnrollment, AfterLessonID: release.AfterLessonID}
+		if err := checkReleaseRule(rule); err != nil {
+			errs = append(errs, fmt.Sprintf("lessons[%d].release: %s", i, strings.TrimPrefix(err.Error(), "validation failed: ")))
+		} else if release.AfterLessonID != nil && (!lessonIDs[*release.AfterLessonID] || *release.AfterLessonID == lesson.ID) {
+			errs = append(errs, fmt.Sprintf("lessons[%d].release.after_lesson_id: unknown lesson %d", i, *release.AfterLessonID))
+		}
+	}
+	for i, lesson := range manifest.Lessons {
+		if lesson.Assignment == nil {
+			continue
+		}
+		if err := checkAssignment(importedAssignment(lesson.Assignment)); err != nil {
+			errs = append(errs, fmt.Sprintf("lessons[%d].assignment: %s", i, strings.TrimPrefix(err.Error(), "validation failed: ")))
+		}
+	}
+
+	files := make(map[string]bool)
+	for _, file := range archive.File {
+		files[file.Name] = true
+	}
+	for i, asset := range manifest.Assets {
+		clean := path.Clean(asset.Path)
+		if !strings.HasPrefix(clean, "assets/") || clean != asset.Path {
+			errs = append(errs, fmt.Sprintf("assets[%d].path: must be a clean path inside assets/", i))
+		} else if !files[asset.Path] {
+			errs = append(errs, fmt.Sprintf("assets[%d].path: file %q is missing from the package", i, asset.Path))
+		}
+		if asset.LessonID != 0 && !lessonIDs[asset.LessonID] {
+			errs = append(errs, fmt.Sprintf("assets[%d].lesson_id: unknown lesson %d", i, asset.LessonID))
+		}
+	}
+
+	return errs
+}
+
+// categoryPath возвращает названия категорий от корня до указанной
+func categoryPath(categories []*models.Category, categoryID int) []string {
+	byID := make(map[int]*models.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+
+	var names []string
+	for id, depth := categoryID, 0; depth < len(categories); depth++ {
+		category, ok := byID[id]
+		if !ok {
+			break
+		}
+		names = append([]string{category.Name}, names...)
+		if category.ParentID == nil {
+			break
+		}
+		id = *category.ParentID
+	}
+	return names
+}
+
+// findCategoryByPath ищет категорию, путь к которой совпадает с названиями (без учёта регистра)
+func findCategoryByPath(categories []*models.Category, names []string) (int, bool) {
+	for _, category := range categories {
+		candidate := categoryPath(categories, category.ID)
+		if len(candidate) != len(names) {
+			continue
+		}
+		match := true
+		for i := range names {
+			if !strings.EqualFold(strings.TrimSpace(candidate[i]), strings.TrimSpace(names[i])) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return category.ID, true
+		}
+	}
+	return 0, false
+}
+
+// packageAssignment переводит задание урока в формат пакета
+func packageAssignment(assignment *models.Assignment) *models.CoursePackageAssignment {
+	packaged := &models.CoursePackageAssignment{
+		Title:              assignment.Title,
+		Instructions:       assignment.Instructions,
+		DueAt:              assignment.DueAt,
+		AllowedExtensions:  assignment.AllowedExtensions,
+		MaxScore:           assignment.MaxScore,
+		PassingScore:       assignment.PassingScore,
+		RequirePassing:     assignment.RequirePassing,
+		LatePenaltyPercent: assignment.LatePenaltyPercent,
+		MaxLateDays:        assignment.MaxLateDays,
+	}
+	for _, criterion := range assignment.Criteria {
+		packaged.Criteria = append(packaged.Criteria, models.CoursePackageCriterion{
+			Title:       criterion.Title,
+			Description: criterion.Description,
+			MaxPoints:   criterion.MaxPoints,
+		})
+	}
+	return packaged
+}
+
+// importedAssignment создаёт задание из пакета; LessonID заполняет вызывающий
+func importedAssignment(packaged *models.CoursePackageAssignment) *models.Assignment {
+	assignment := &models.Assignment{
+		Title:              strings.TrimSpace(packaged.Title),
+		Instructions:       packaged.Instructions,
+		DueAt:              packaged.DueAt,
+		AllowedExtensions:  normalizeExtensions(packaged.AllowedExtensions),
+		MaxScore:           packaged.MaxScore,
+		PassingScore:       packaged.PassingScore,
+		RequirePassing:     packaged.RequirePassing,
+		LatePenaltyPercent: packaged.LatePenaltyPercent,
+		MaxLateDays:        packaged.MaxLateDays,
+		Criteria:           []*models.AssignmentCriterion{},
+	}
+	for _, criterion := range packaged.Criteria {
+		assignment.Criteria = append(assignment.Criteria, &models.AssignmentCriterion{
+			Title:       strings.TrimSpace(criterion.Title),
+			Description: criterion.Description,
+			MaxPoints:   criterion.MaxPoints,
+		})
+	}
+	return assignment
+}
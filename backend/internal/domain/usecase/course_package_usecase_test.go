@@ -0,0 +1,284 @@
+package usecase_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+// Mock для LessonService
+type MockLessonService struct {
+	mock.Mock
+	services.LessonServiceInterface
+}
+
+func (m *MockLessonService) GetAllLessons(ctx context.Context, courseID int) ([]*models.Lesson, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]*models.Lesson), args.Error(1)
+}
+
+// Mock для CategoryService
+type MockCategoryService struct {
+	mock.Mock
+	services.CategoryServiceInterface
+}
+
+func (m *MockCategoryService) GetAllCategories(ctx context.Context) ([]*models.Category, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*models.Category), args.Error(1)
+}
+
+func (m *MockCourseService) GetPrerequisites(ctx context.Context, courseID int) ([]*models.CoursePrerequisite, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]*models.CoursePrerequisite), args.Error(1)
+}
+
+func (m *MockCourseService) FindCoursesByName(ctx context.Context, name string) ([]models.Course, error) {
+	args := m.Called(ctx, name)
+	return args.Get(0).([]models.Course), args.Error(1)
+}
+
+func (m *MockCourseService) CreateCourseWithContent(ctx context.Context, course *models.Course, prerequisiteIDs []int, modules []*models.Module, lessons []*models.Lesson) error {
+	args := m.Called(ctx, course, prerequisiteIDs, modules, lessons)
+	return args.Error(0)
+}
+
+// Mock для LessonAttachmentUseCase
+type MockLessonAttachmentUseCase struct {
+	mock.Mock
+	usecase.LessonAttachmentUseCaseInterface
+}
+
+func (m *MockLessonAttachmentUseCase) UploadAttachment(ctx context.Context, userID int, userRole string, courseID, lessonID int, input *dto.LessonAttachmentUploadRequest, filename string, size int64, r io.Reader) (*models.LessonAttachment, error) {
+	content, _ := io.ReadAll(r)
+	args := m.Called(ctx, userID, userRole, courseID, lessonID, input, filename, size, string(content))
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LessonAttachment), args.Error(1)
+}
+
+func (m *MockLessonAttachmentUseCase) DeleteAttachment(ctx context.Context, userID int, userRole string, courseID, lessonID, attachmentID int) error {
+	args := m.Called(ctx, userID, userRole, courseID, lessonID, attachmentID)
+	return args.Error(0)
+}
+
+func (m *MockCourseService) DeleteCourse(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestCoursePackageRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	parentID := 1
+	categoryID := 2
+	categories := []*models.Category{
+		{ID: parentID, Name: "Programming"},
+		{ID: categoryID, Name: "Go", ParentID: &parentID},
+	}
+
+	// экспорт на исходной инсталляции
+	courseService := new(MockCourseService)
+	lessonService := new(MockLessonService)
+	categoryService := new(MockCategoryService)
+	moduleService := new(MockModuleService)
+	blockService := new(MockLessonBlockService)
+	attachmentService := new(MockLessonAttachmentService)
+	releaseService := new(MockLessonReleaseService)
+	assignmentService := new(MockAssignmentService)
+	store := newMemoryBlobStore()
+	useCase := usecase.NewCoursePackageUseCase(courseService, lessonService, categoryService, moduleService, blockService, attachmentService, nil, releaseService, assignmentService, store)
+
+	courseService.On("GetCourse", ctx, 10).Return(&models.Course{
+		ID: 10, Name: "Go 101", TeacherID: 5, Status: models.CourseStatusActive,
+		CategoryID: &categoryID, Level: models.CourseLevelIntermediate, Tags: []string{"go"},
+	}, nil)
+	courseService.On("GetPrerequisites", ctx, 10).Return([]*models.CoursePrerequisite{{CourseID: 10, PrerequisiteID: 3, Name: "Programming Basics"}}, nil)
+	moduleID := 30
+	moduleService.On("GetModulesByCourse", ctx, 10).Return([]*models.Module{{ID: moduleID, CourseID: 10, Title: "Basics", Position: 1}}, nil)
+	lessonService.On("GetAllLessons", ctx, 10).Return([]*models.Lesson{
+		{ID: 21, CourseID: 10, Title: "Types"},
+		{ID: 20, CourseID: 10, Title: "Hello", ModuleID: &moduleID},
+	}, nil)
+	blockService.On("GetLessonBlocks", ctx, 21, 0).Return([]*models.LessonBlock{
+		{ID: 1, LessonID: 21, Type: models.LessonBlockFile, Data: json.RawMessage(`{"media_id": 4}`), Required: true},
+	}, nil)
+	blockService.On("GetLessonBlocks", ctx, 20, 0).Return([]*models.LessonBlock{
+		{ID: 2, LessonID: 20, Type: models.LessonBlockText, Data: json.RawMessage(`{"markdown": "**Hi**"}`), Required: true},
+	}, nil)
+	attachmentService.On("GetLessonAttachments", ctx, 21).Return([]*models.LessonAttachment{}, nil)
+	attachmentService.On("GetLessonAttachments", ctx, 20).Return([]*models.LessonAttachment{
+		{ID: 8, LessonID: 20, Title: "Slides", Filename: "slides.pdf", ContentType: "application/pdf", Size: 8, Watermark: true, StorageKey: "5/abc.pdf"},
+	}, nil)
+	store.blobs["5/abc.pdf"] = []byte("%PDF-1.4")
+	releaseService.On("GetCourseRules", ctx, 10).Return([]*models.LessonReleaseRule{
+		{LessonID: 20, Type: models.LessonReleaseAfterLesson, AfterLessonID: intPtr(21)},
+	}, nil)
+	assignmentService.On("GetCourseAssignments", ctx, 10).Return([]*models.Assignment{{
+		ID: 40, LessonID: 21, Title: "Essay", Instructions: "Write *one* page", AllowedExtensions: []string{"pdf"},
+		MaxScore: 10, PassingScore: 6, RequirePassing: true, LatePenaltyPercent: 10,
+		Criteria: []*models.AssignmentCriterion{{ID: 1, Title: "Content", MaxPoints: 7}, {ID: 2, Title: "Style", MaxPoints: 3}},
+	}}, nil)
+	categoryService.On("GetAllCategories", ctx).Return(categories, nil)
+
+	data, err := useCase.ExportCourse(ctx, 5, "admin", 10)
+	assert.NoError(t, err)
+
+	// импорт на другой инсталляции
+	courseService = new(MockCourseService)
+	categoryService = new(MockCategoryService)
+	attachments := new(MockLessonAttachmentUseCase)
+	releaseService = new(MockLessonReleaseService)
+	assignmentService = new(MockAssignmentService)
+	useCase = usecase.NewCoursePackageUseCase(courseService, nil, categoryService, nil, nil, nil, attachments, releaseService, assignmentService, nil)
+
+	categoryService.On("GetAllCategories", ctx).Return(categories, nil)
+	courseService.On("FindCoursesByName", ctx, "Go 101").Return([]models.Course{}, nil)
+	courseService.On("FindCoursesByName", ctx, "Programming Basics").Return([]models.Course{}, nil)
+	courseService.On("CreateCourseWithContent", ctx, mock.MatchedBy(func(course *models.Course) bool {
+		return course.TeacherID == 7 && course.Status == models.CourseStatusDraft && *course.CategoryID == categoryID
+	}), []int(nil), mock.Anything, mock.MatchedBy(func(lessons []*models.Lesson) bool {
+		return len(lessons) == 2 && lessons[0].ModuleID == nil && *lessons[1].ModuleID == moduleID &&
+			len(lessons[0].Blocks) == 0 && len(lessons[1].Blocks) == 1 && lessons[1].Blocks[0].HTML == "<p><strong>Hi</strong></p>\n"
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Course).ID = 100
+		for _, module := range args.Get(3).([]*models.Module) {
+			module.ID = 300
+		}
+		for i, lesson := range args.Get(4).([]*models.Lesson) {
+			lesson.ID = 200 + i
+		}
+	}).Return(nil).Once()
+	courseService.On("GetCourse", ctx, 100).Return(&models.Course{ID: 100, Name: "Go 101", TeacherID: 7}, nil)
+
+	attachments.On("UploadAttachment", ctx, 7, "teacher", 100, 201, &dto.LessonAttachmentUploadRequest{Title: "Slides", Watermark: true},
+		"slides.pdf", int64(8), "%PDF-1.4").Return(&models.LessonAttachment{ID: 80}, nil).Once()
+	releaseService.On("SaveRule", ctx, mock.MatchedBy(func(rule *models.LessonReleaseRule) bool {
+		return rule.LessonID == 201 && rule.Type == models.LessonReleaseAfterLesson && *rule.AfterLessonID == 200
+	})).Return(nil).Once()
+	assignmentService.On("SaveAssignment", ctx, mock.MatchedBy(func(assignment *models.Assignment) bool {
+		return assignment.LessonID == 200 && assignment.Title == "Essay" && assignment.RequirePassing &&
+			assignment.InstructionsHTML == "<p>Write <em>one</em> page</p>\n" && len(assignment.Criteria) == 2 &&
+			assignment.Criteria[1].Title == "Style" && assignment.Criteria[1].MaxPoints == 3
+	})).Return(nil).Once()
+
+	report, err := useCase.ImportCourse(ctx, 7, "teacher", bytes.NewReader(data), int64(len(data)), false)
+
+	assert.NoError(t, err)
+	assert.Empty(t, report.Errors)
+	assert.Equal(t, 10, report.SourceCourseID)
+	assert.Equal(t, 100, report.Course.ID)
+	assert.Equal(t, map[int]int{moduleID: 300}, report.ModuleIDs)
+	assert.Equal(t, map[int]int{21: 200, 20: 201}, report.LessonIDs)
+	assert.Len(t, report.Conflicts, 2)
+	assert.Equal(t, "course.prerequisites", report.Conflicts[0].Field)
+	assert.Equal(t, "lessons.blocks", report.Conflicts[1].Field)
+	courseService.AssertExpectations(t)
+	attachments.AssertExpectations(t)
+	releaseService.AssertExpectations(t)
+	assignmentService.AssertExpectations(t)
+}
+
+func TestImportCourseRejectsInvalidManifest(t *testing.T) {
+	ctx := context.Background()
+	courseService := new(MockCourseService)
+	useCase := usecase.NewCoursePackageUseCase(courseService, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	manifest := models.CoursePackageManifest{
+		Format:  models.CoursePackageFormat,
+		Version: models.CoursePackageVersion,
+		Course:  models.CoursePackageCourse{Name: "Go 101", Level: 7},
+		Lessons: []models.CoursePackageLesson{
+			{ID: 1, Title: "Hello", ModuleID: 9},
+			{ID: 1},
+			{ID: 2, Title: "Next", Release: &models.CoursePackageRelease{Type: models.LessonReleaseAfterLesson, AfterLessonID: intPtr(5)}},
+			{ID: 3, Title: "Later", Release: &models.CoursePackageRelease{Type: models.LessonReleaseEnrollmentDays}},
+			{ID: 4, Title: "Essay", Assignment: &models.CoursePackageAssignment{
+				Title: "Essay", MaxScore: 10, Criteria: []models.CoursePackageCriterion{{Title: "Content", MaxPoints: 6}},
+			}},
+			{Title: "Without id"},
+		},
+		Assets: []models.CoursePackageAsset{{Path: "assets/slides.pdf"}},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	file, _ := archive.Create(models.CoursePackageManifestName)
+	assert.NoError(t, json.NewEncoder(file).Encode(manifest))
+	assert.NoError(t, archive.Close())
+
+	report, err := useCase.ImportCourse(ctx, 7, "teacher", bytes.NewReader(buf.Bytes()), int64(buf.Len()), false)
+
+	assert.NoError(t, err)
+	assert.Contains(t, report.Errors, "course.level: unknown level 7")
+	assert.Contains(t, report.Errors, "lessons[1].title: required")
+	assert.Contains(t, report.Errors, "lessons[0].module_id: unknown module 9")
+	assert.Contains(t, report.Errors, "lessons[1].id: duplicate lesson id 1")
+	assert.Contains(t, report.Errors, "lessons[2].release.after_lesson_id: unknown lesson 5")
+	assert.Contains(t, report.Errors, "lessons[3].release: days_after_enrollment is required for enrollment_days rules")
+	assert.Contains(t, report.Errors, "lessons[4].assignment: criteria points add up to 6, max_score is 10")
+	assert.Contains(t, report.Errors, "lessons[5].id: must be positive")
+	assert.Contains(t, report.Errors, `assets[0].path: file "assets/slides.pdf" is missing from the package`)
+	courseService.AssertNotCalled(t, "CreateCourseWithContent", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestImportCourseRemovesCourseWhenPartFails(t *testing.T) {
+	ctx := context.Background()
+	courseService := new(MockCourseService)
+	categoryService := new(MockCategoryService)
+	attachments := new(MockLessonAttachmentUseCase)
+	useCase := usecase.NewCoursePackageUseCase(courseService, nil, categoryService, nil, nil, nil, attachments, nil, nil, nil)
+
+	manifest := models.CoursePackageManifest{
+		Format:  models.CoursePackageFormat,
+		Version: models.CoursePackageVersion,
+		Course:  models.CoursePackageCourse{Name: "Go 101", Level: models.CourseLevelBeginner},
+		Lessons: []models.CoursePackageLesson{{ID: 1, Title: "Hello"}},
+		Assets: []models.CoursePackageAsset{
+			{Path: "assets/slides.pdf", LessonID: 1, Title: "Slides"},
+			{Path: "assets/video.exe", LessonID: 1, Title: "Video"},
+		},
+	}
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	file, _ := archive.Create(models.CoursePackageManifestName)
+	assert.NoError(t, json.NewEncoder(file).Encode(manifest))
+	file, _ = archive.Create("assets/slides.pdf")
+	file.Write([]byte("%PDF-1.4"))
+	file, _ = archive.Create("assets/video.exe")
+	file.Write([]byte("MZ"))
+	assert.NoError(t, archive.Close())
+
+	categoryService.On("GetAllCategories", ctx).Return([]*models.Category{}, nil)
+	courseService.On("FindCoursesByName", ctx, "Go 101").Return([]models.Course{}, nil)
+	courseService.On("CreateCourseWithContent", ctx, mock.Anything, []int(nil), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Course).ID = 100
+		args.Get(4).([]*models.Lesson)[0].ID = 200
+	}).Return(nil)
+	attachments.On("UploadAttachment", ctx, 7, "teacher", 100, 200, mock.Anything, "slides.pdf", int64(8), "%PDF-1.4").
+		Return(&models.LessonAttachment{ID: 80, LessonID: 200}, nil)
+	attachments.On("UploadAttachment", ctx, 7, "teacher", 100, 200, mock.Anything, "video.exe", int64(2), "MZ").
+		Return(nil, errors.New("validation failed: file type is not allowed"))
+	attachments.On("DeleteAttachment", ctx, 7, "teacher", 100, 200, 80).Return(nil).Once()
+	courseService.On("DeleteCourse", ctx, 100).Return(nil).Once()
+
+	report, err := useCase.ImportCourse(ctx, 7, "teacher", bytes.NewReader(buf.Bytes()), int64(buf.Len()), false)
+
+	assert.Nil(t, report)
+	assert.EqualError(t, err, "assets/video.exe is not imported: validation failed: file type is not allowed")
+	attachments.AssertExpectations(t)
+	courseService.AssertExpectations(t)
+	courseService.AssertNotCalled(t, "GetCourse", mock.Anything, mock.Anything)
+}
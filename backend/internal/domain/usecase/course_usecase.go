@@ -2,24 +2,49 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
 	"gitlab.com/w0ikid/study-platform/internal/domain/models"
-	"gitlab.com/w0ikid/study-platform/internal/domain/services"	
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
 )
 
 type CourseUseCaseInterface interface {
 	CreateCourse(ctx context.Context, input CreateCourseInput) (*models.Course, error)
 	GetCourseByID(ctx context.Context, id int) (*models.Course, error)
 	GetAllCourses(ctx context.Context) ([]models.Course, error)
+	GetCatalog(ctx context.Context, filter models.CourseFilter) ([]models.Course, error)
 	UpdateCourse(ctx context.Context, course *models.Course) error
-	DeleteCourse(ctx context.Context, id int) error
+	UpdateCourseDetails(ctx context.Context, userID int, userRole string, courseID int, input *dto.CreateCourseRequest) (*models.Course, error)
+	DeleteCourse(ctx context.Context, userID int, userRole string, id int) error
+	AddTags(ctx context.Context, userID int, userRole string, courseID int, tags []string) (*models.Course, error)
+	RemoveTag(ctx context.Context, userID int, userRole string, courseID int, tag string) error
+	GetAllTags(ctx context.Context) ([]models.TagCount, error)
+	GetPrerequisites(ctx context.Context, courseID int) (*models.CoursePrerequisites, error)
+	SetPrerequisites(ctx context.Context, userID int, userRole string, courseID int, input *dto.SetPrerequisitesRequest) (*models.CoursePrerequisites, error)
+	GetStaff(ctx context.Context, userID int, userRole string, courseID int) ([]*models.CourseStaff, error)
+	AddStaff(ctx context.Context, userID int, userRole string, courseID int, input *dto.CourseStaffRequest) (*models.CourseStaff, error)
+	RemoveStaff(ctx context.Context, userID int, userRole string, courseID, staffUserID int) error
+	TransferOwnership(ctx context.Context, userID int, userRole string, courseID int, input *dto.TransferOwnershipRequest) (*models.Course, error)
+	GetRoster(ctx context.Context, userID int, userRole string, courseID int) ([]*models.CourseRosterEntry, error)
+	CloneCourse(ctx context.Context, userID int, userRole string, courseID int, input *dto.CloneCourseRequest) (*models.Course, error)
 }
 
 type CourseUseCase struct {
-	courseService services.CourseServiceInterface
+	courseService   services.CourseServiceInterface
+	categoryService services.CategoryServiceInterface
+	userService     services.UserServiceInterface
 }
 
-func NewCourseUseCase(courseService services.CourseServiceInterface) *CourseUseCase {
-	return &CourseUseCase{courseService: courseService}
+func NewCourseUseCase(
+	courseService services.CourseServiceInterface,
+	categoryService services.CategoryServiceInterface,
+	userService services.UserServiceInterface,
+) *CourseUseCase {
+	return &CourseUseCase{courseService: courseService, categoryService: categoryService, userService: userService}
 }
 
 type CreateCourseInput struct {
@@ -28,6 +53,10 @@ type CreateCourseInput struct {
 	TeacherID   int
 	Status      string
 	ImageURL 	string
+	CategoryID      *int
+	Level           int
+	DurationMinutes int
+	Language        string
 }
 
 // CreateCourse создает новый курс
@@ -40,6 +69,14 @@ func (u *CourseUseCase) CreateCourse(ctx context.Context, input CreateCourseInpu
 		TeacherID:   input.TeacherID,
 		Status:      input.Status,
 		ImageUrl:    input.ImageURL,
+		CategoryID:      input.CategoryID,
+		Level:           input.Level,
+		DurationMinutes: input.DurationMinutes,
+		Language:        input.Language,
+	}
+
+	if err := u.checkCategory(ctx, input.CategoryID); err != nil {
+		return nil, err
 	}
 	
 	// Вызов сервиса для создания курса
@@ -62,14 +99,317 @@ func (u *CourseUseCase) GetAllCourses(ctx context.Context) ([]models.Course, err
 	return u.courseService.GetAllCourses(ctx)
 }
 
+// GetCatalog возвращает каталог курсов с фильтрацией по категории, тегам, уровню, языку и длительности
+func (u *CourseUseCase) GetCatalog(ctx context.Context, filter models.CourseFilter) ([]models.Course, error) {
+	for i, tag := range filter.Tags {
+		filter.Tags[i] = normalizeTag(tag)
+	}
+	return u.courseService.GetCatalog(ctx, filter)
+}
+
 // UpdateCourse обновляет курс
 func (u *CourseUseCase) UpdateCourse(ctx context.Context, course *models.Course) error {
 	// Дополнительная логика перед обновлением курса (например, проверка прав доступа)
 	return u.courseService.UpdateCourse(ctx, course)
 }
 
-// DeleteCourse удаляет курс
-func (u *CourseUseCase) DeleteCourse(ctx context.Context, id int) error {
-	// Дополнительная логика перед удалением курса (например, проверка прав доступа)
+// DeleteCourse удаляет курс. Доступно только владельцу курса и администратору.
+func (u *CourseUseCase) DeleteCourse(ctx context.Context, userID int, userRole string, id int) error {
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, id, courseOwnerRoles); err != nil {
+		return err
+	}
 	return u.courseService.DeleteCourse(ctx, id)
-}
\ No newline at end of file
+}
+
+// UpdateCourseDetails обновляет описание и метаданные курса. Доступно владельцу курса и администратору.
+func (u *CourseUseCase) UpdateCourseDetails(ctx context.Context, userID int, userRole string, courseID int, input *dto.CreateCourseRequest) (*models.Course, error) {
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	course, err := u.getEditableCourse(ctx, userID, userRole, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.checkCategory(ctx, input.CategoryID); err != nil {
+		return nil, err
+	}
+
+	course.Name = input.Name
+	course.Description = input.Description
+	course.ImageUrl = input.ImageUrl
+	course.CategoryID = input.CategoryID
+	course.DurationMinutes = input.DurationMinutes
+	course.Language = input.Language
+	if input.Level != "" {
+		course.Level, _ = models.CourseLevelFromName(input.Level)
+	}
+	if input.Status != "" {
+		course.Status = input.Status
+	}
+
+	if err := u.courseService.UpdateCourse(ctx, course); err != nil {
+		return nil, err
+	}
+	return course, nil
+}
+
+// AddTags добавляет курсу свободные теги (приводятся к нижнему регистру)
+func (u *CourseUseCase) AddTags(ctx context.Context, userID int, userRole string, courseID int, tags []string) (*models.Course, error) {
+	validate := validator.New()
+	if err := validate.Struct(&dto.CourseTagsRequest{Tags: tags}); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if _, err := u.getEditableCourse(ctx, userID, userRole, courseID); err != nil {
+		return nil, err
+	}
+
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = normalizeTag(tag); tag != "" {
+			normalized = append(normalized, tag)
+		}
+	}
+	if len(normalized) == 0 {
+		return nil, errors.New("validation failed: tags cannot be empty")
+	}
+
+	if err := u.courseService.AddTags(ctx, courseID, normalized); err != nil {
+		return nil, err
+	}
+	return u.courseService.GetCourse(ctx, courseID)
+}
+
+// RemoveTag удаляет тег курса
+func (u *CourseUseCase) RemoveTag(ctx context.Context, userID int, userRole string, courseID int, tag string) error {
+	if _, err := u.getEditableCourse(ctx, userID, userRole, courseID); err != nil {
+		return err
+	}
+	return u.courseService.RemoveTag(ctx, courseID, normalizeTag(tag))
+}
+
+// GetAllTags возвращает теги каталога с количеством курсов
+func (u *CourseUseCase) GetAllTags(ctx context.Context) ([]models.TagCount, error) {
+	return u.courseService.GetAllTags(ctx)
+}
+
+// GetPrerequisites возвращает требования курса: пререквизиты и минимальный уровень
+func (u *CourseUseCase) GetPrerequisites(ctx context.Context, courseID int) (*models.CoursePrerequisites, error) {
+	course, err := u.courseService.GetCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	prerequisites, err := u.courseService.GetPrerequisites(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CoursePrerequisites{
+		CourseID:     course.ID,
+		MinUserLevel: course.MinUserLevel,
+		Courses:      prerequisites,
+	}, nil
+}
+
+// SetPrerequisites заменяет пререквизиты курса. Сохранение отклоняется,
+// если новый пререквизит сам (прямо или через цепочку) требует этот курс.
+func (u *CourseUseCase) SetPrerequisites(ctx context.Context, userID int, userRole string, courseID int, input *dto.SetPrerequisitesRequest) (*models.CoursePrerequisites, error) {
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if _, err := u.getEditableCourse(ctx, userID, userRole, courseID); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int]bool)
+	var prerequisiteIDs []int
+	for _, prerequisiteID := range input.CourseIDs {
+		if seen[prerequisiteID] {
+			continue
+		}
+		seen[prerequisiteID] = true
+
+		if prerequisiteID == courseID {
+			return nil, errors.New("course cannot be a prerequisite of itself")
+		}
+		prerequisite, err := u.courseService.GetCourse(ctx, prerequisiteID)
+		if err != nil {
+			return nil, fmt.Errorf("prerequisite course %d not found", prerequisiteID)
+		}
+
+		cycle, err := u.courseService.DependsOn(ctx, prerequisiteID, courseID)
+		if err != nil {
+			return nil, err
+		}
+		if cycle {
+			return nil, fmt.Errorf("prerequisite cycle: course %q already requires this course", prerequisite.Name)
+		}
+		prerequisiteIDs = append(prerequisiteIDs, prerequisiteID)
+	}
+
+	if err := u.courseService.SetPrerequisites(ctx, courseID, prerequisiteIDs, input.MinUserLevel); err != nil {
+		return nil, err
+	}
+	return u.GetPrerequisites(ctx, courseID)
+}
+
+// GetStaff возвращает команду курса. Доступно участникам команды и администратору.
+func (u *CourseUseCase) GetStaff(ctx context.Context, userID int, userRole string, courseID int) ([]*models.CourseStaff, error) {
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseStaffRoles); err != nil {
+		return nil, err
+	}
+	return u.courseService.GetStaff(ctx, courseID)
+}
+
+// AddStaff добавляет соавтора или ассистента в команду курса (или меняет его роль).
+// Соавтором может быть только преподаватель, ассистентом - любой пользователь.
+func (u *CourseUseCase) AddStaff(ctx context.Context, userID int, userRole string, courseID int, input *dto.CourseStaffRequest) (*models.CourseStaff, error) {
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseOwnerRoles); err != nil {
+		return nil, err
+	}
+
+	user, err := u.userService.GetUser(ctx, input.UserID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if input.Role == models.CourseStaffRoleCoTeacher && user.Role != "teacher" && user.Role != "admin" {
+		return nil, errors.New("only teachers can be co-teachers")
+	}
+
+	existing, err := u.courseService.GetStaffMember(ctx, courseID, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Role == models.CourseStaffRoleOwner {
+		return nil, errors.New("course owner role can only be changed by transferring ownership")
+	}
+
+	return u.courseService.AddStaff(ctx, &models.CourseStaff{
+		CourseID: courseID,
+		UserID:   input.UserID,
+		Role:     input.Role,
+	})
+}
+
+// RemoveStaff исключает пользователя из команды курса. Владелец может исключить любого,
+// остальные участники - только себя.
+func (u *CourseUseCase) RemoveStaff(ctx context.Context, userID int, userRole string, courseID, staffUserID int) error {
+	roles := courseOwnerRoles
+	if staffUserID == userID {
+		roles = courseStaffRoles
+	}
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, roles); err != nil {
+		return err
+	}
+
+	member, err := u.courseService.GetStaffMember(ctx, courseID, staffUserID)
+	if err != nil {
+		return err
+	}
+	if member == nil {
+		return errors.New("staff member not found")
+	}
+	if member.Role == models.CourseStaffRoleOwner {
+		return errors.New("course owner cannot be removed, transfer ownership first")
+	}
+	return u.courseService.RemoveStaff(ctx, courseID, staffUserID)
+}
+
+// TransferOwnership передаёт курс другому преподавателю, прежний владелец становится соавтором
+func (u *CourseUseCase) TransferOwnership(ctx context.Context, userID int, userRole string, courseID int, input *dto.TransferOwnershipRequest) (*models.Course, error) {
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	course, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseOwnerRoles)
+	if err != nil {
+		return nil, err
+	}
+	if course.TeacherID == input.UserID {
+		return nil, errors.New("user already owns this course")
+	}
+
+	user, err := u.userService.GetUser(ctx, input.UserID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.Role != "teacher" && user.Role != "admin" {
+		return nil, errors.New("course can only be transferred to a teacher")
+	}
+
+	if err := u.courseService.TransferOwnership(ctx, courseID, input.UserID); err != nil {
+		return nil, err
+	}
+	return u.courseService.GetCourse(ctx, courseID)
+}
+
+// GetRoster возвращает студентов курса. Доступно всей команде курса, включая ассистентов.
+func (u *CourseUseCase) GetRoster(ctx context.Context, userID int, userRole string, courseID int) ([]*models.CourseRosterEntry, error) {
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseStaffRoles); err != nil {
+		return nil, err
+	}
+	return u.courseService.GetRoster(ctx, courseID)
+}
+
+// CloneCourse копирует курс вместе с уроками, тегами, пререквизитами и ценой в новый черновик,
+// владельцем которого становится вызывающий пользователь
+func (u *CourseUseCase) CloneCourse(ctx context.Context, userID int, userRole string, courseID int, input *dto.CloneCourseRequest) (*models.Course, error) {
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	source, err := u.getEditableCourse(ctx, userID, userRole, courseID)
+	if err != nil {
+		return nil, err
+	}
+	// студенты платного курса платили за него, а не за копию: перенос записал бы их без заказа
+	if input.IncludeEnrollments && source.IsPaid() {
+		return nil, errors.New("validation failed: enrollments of a paid course cannot be copied")
+	}
+
+	clone := *source
+	clone.ID = 0
+	clone.TeacherID = userID
+	clone.Status = models.CourseStatusDraft
+	clone.Name = strings.TrimSpace(input.Name)
+	if clone.Name == "" {
+		clone.Name = source.Name + " (copy)"
+	}
+
+	if err := u.courseService.CloneCourse(ctx, source.ID, &clone, input.IncludeEnrollments); err != nil {
+		return nil, err
+	}
+	return u.courseService.GetCourse(ctx, clone.ID)
+}
+
+// getEditableCourse возвращает курс, если пользователь может его редактировать (владелец, соавтор или администратор)
+func (u *CourseUseCase) getEditableCourse(ctx context.Context, userID int, userRole string, courseID int) (*models.Course, error) {
+	return checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseEditorRoles)
+}
+
+func (u *CourseUseCase) checkCategory(ctx context.Context, categoryID *int) error {
+	if categoryID == nil {
+		return nil
+	}
+	if _, err := u.categoryService.GetCategory(ctx, *categoryID); err != nil {
+		return errors.New("category not found")
+	}
+	return nil
+}
+
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
@@ -0,0 +1,300 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+func (m *MockCourseService) GetStaffMember(ctx context.Context, courseID, userID int) (*models.CourseStaff, error) {
+	args := m.Called(ctx, courseID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CourseStaff), args.Error(1)
+}
+
+func (m *MockCourseService) GetRoster(ctx context.Context, courseID int) ([]*models.CourseRosterEntry, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]*models.CourseRosterEntry), args.Error(1)
+}
+
+func (m *MockCourseService) CloneCourse(ctx context.Context, sourceID int, clone *models.Course, includeEnrollments bool) error {
+	args := m.Called(ctx, sourceID, clone, includeEnrollments)
+	return args.Error(0)
+}
+
+func (m *MockCourseService) AddTags(ctx context.Context, courseID int, tags []string) error {
+	args := m.Called(ctx, courseID, tags)
+	return args.Error(0)
+}
+
+func (m *MockCourseService) RemoveTag(ctx context.Context, courseID int, tag string) error {
+	args := m.Called(ctx, courseID, tag)
+	return args.Error(0)
+}
+
+func (m *MockCourseService) DependsOn(ctx context.Context, courseID, prerequisiteID int) (bool, error) {
+	args := m.Called(ctx, courseID, prerequisiteID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseService) SetPrerequisites(ctx context.Context, courseID int, prerequisiteIDs []int, minUserLevel int) error {
+	args := m.Called(ctx, courseID, prerequisiteIDs, minUserLevel)
+	return args.Error(0)
+}
+
+func TestCourseStaffAccess(t *testing.T) {
+	ctx := context.Background()
+	courseID, taID := 10, 7
+
+	t.Run("TA can view roster", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		useCase := usecase.NewCourseUseCase(courseService, nil, nil)
+
+		courseService.On("GetCourse", ctx, courseID).Return(&models.Course{ID: courseID, TeacherID: 1}, nil)
+		courseService.On("GetStaffMember", ctx, courseID, taID).Return(&models.CourseStaff{UserID: taID, Role: models.CourseStaffRoleTA}, nil)
+		courseService.On("GetRoster", ctx, courseID).Return([]*models.CourseRosterEntry{{UserID: 3}}, nil)
+
+		roster, err := useCase.GetRoster(ctx, taID, "student", courseID)
+
+		assert.NoError(t, err)
+		assert.Len(t, roster, 1)
+	})
+
+	t.Run("TA cannot edit course", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		useCase := usecase.NewCourseUseCase(courseService, nil, nil)
+
+		courseService.On("GetCourse", ctx, courseID).Return(&models.Course{ID: courseID, TeacherID: 1}, nil)
+		courseService.On("GetStaffMember", ctx, courseID, taID).Return(&models.CourseStaff{UserID: taID, Role: models.CourseStaffRoleTA}, nil)
+
+		_, err := useCase.UpdateCourseDetails(ctx, taID, "teacher", courseID, &dto.CreateCourseRequest{Name: "Go", Description: "Intro"})
+
+		assert.ErrorIs(t, err, usecase.ErrPermissionDenied)
+	})
+
+	t.Run("Co-teacher cannot delete course", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		useCase := usecase.NewCourseUseCase(courseService, nil, nil)
+
+		courseService.On("GetCourse", ctx, courseID).Return(&models.Course{ID: courseID, TeacherID: 1}, nil)
+		courseService.On("GetStaffMember", ctx, courseID, 2).Return(&models.CourseStaff{UserID: 2, Role: models.CourseStaffRoleCoTeacher}, nil)
+
+		err := useCase.DeleteCourse(ctx, 2, "teacher", courseID)
+
+		assert.ErrorIs(t, err, usecase.ErrPermissionDenied)
+		courseService.AssertNotCalled(t, "DeleteCourse", ctx, courseID)
+	})
+
+	t.Run("Outsider teacher is rejected", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		useCase := usecase.NewCourseUseCase(courseService, nil, nil)
+
+		courseService.On("GetCourse", ctx, courseID).Return(&models.Course{ID: courseID, TeacherID: 1}, nil)
+		courseService.On("GetStaffMember", ctx, courseID, 99).Return(nil, nil)
+
+		_, err := useCase.GetRoster(ctx, 99, "teacher", courseID)
+
+		assert.ErrorIs(t, err, usecase.ErrPermissionDenied)
+	})
+}
+
+func TestCloneCourse(t *testing.T) {
+	ctx := context.Background()
+	courseID, coTeacherID := 10, 2
+
+	courseService := new(MockCourseService)
+	useCase := usecase.NewCourseUseCase(courseService, nil, nil)
+
+	source := &models.Course{ID: courseID, Name: "Go 101", TeacherID: 1, Status: models.CourseStatusActive, Tags: []string{"go"}}
+	courseService.On("GetCourse", ctx, courseID).Return(source, nil).Once()
+	courseService.On("GetStaffMember", ctx, courseID, coTeacherID).Return(&models.CourseStaff{UserID: coTeacherID, Role: models.CourseStaffRoleCoTeacher}, nil)
+	courseService.On("CloneCourse", ctx, courseID, mock.MatchedBy(func(clone *models.Course) bool {
+		return clone.Name == "Go 101 (copy)" && clone.TeacherID == coTeacherID && clone.Status == models.CourseStatusDraft
+	}), false).Run(func(args mock.Arguments) {
+		args.Get(2).(*models.Course).ID = 11
+	}).Return(nil).Once()
+	courseService.On("GetCourse", ctx, 11).Return(&models.Course{ID: 11, Name: "Go 101 (copy)", TeacherID: coTeacherID, Status: models.CourseStatusDraft}, nil).Once()
+
+	clone, err := useCase.CloneCourse(ctx, coTeacherID, "teacher", courseID, &dto.CloneCourseRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 11, clone.ID)
+	assert.Equal(t, models.CourseStatusActive, source.Status)
+	courseService.AssertExpectations(t)
+}
+
+func TestCloneCourseKeepsPriceAndRejectsPaidEnrollments(t *testing.T) {
+	ctx := context.Background()
+	courseID, ownerID := 10, 1
+
+	courseService := new(MockCourseService)
+	useCase := usecase.NewCourseUseCase(courseService, nil, nil)
+
+	source := &models.Course{ID: courseID, Name: "Go 101", TeacherID: ownerID, Status: models.CourseStatusActive, PriceCents: 4900, Currency: "USD"}
+	courseService.On("GetCourse", ctx, courseID).Return(source, nil)
+	courseService.On("GetStaffMember", ctx, courseID, ownerID).Return(&models.CourseStaff{UserID: ownerID, Role: models.CourseStaffRoleOwner}, nil)
+
+	_, err := useCase.CloneCourse(ctx, ownerID, "teacher", courseID, &dto.CloneCourseRequest{IncludeEnrollments: true})
+	assert.EqualError(t, err, "validation failed: enrollments of a paid course cannot be copied")
+	courseService.AssertNotCalled(t, "CloneCourse", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	courseService.On("CloneCourse", ctx, courseID, mock.MatchedBy(func(clone *models.Course) bool {
+		return clone.PriceCents == 4900 && clone.Currency == "USD"
+	}), false).Run(func(args mock.Arguments) {
+		args.Get(2).(*models.Course).ID = 11
+	}).Return(nil).Once()
+	courseService.On("GetCourse", ctx, 11).Return(&models.Course{ID: 11, PriceCents: 4900, Currency: "USD"}, nil).Once()
+
+	clone, err := useCase.CloneCourse(ctx, ownerID, "teacher", courseID, &dto.CloneCourseRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, 4900, clone.PriceCents)
+	courseService.AssertExpectations(t)
+}
+
+func TestGetCatalog(t *testing.T) {
+	ctx := context.Background()
+	level := 1
+
+	courseService := new(MockCourseService)
+	useCase := usecase.NewCourseUseCase(courseService, nil, nil)
+
+	// теги фильтра приводятся к тому же виду, в котором сохраняются
+	courseService.On("GetCatalog", ctx, models.CourseFilter{
+		CategoryID:  3,
+		Tags:        []string{"golang", "web"},
+		Level:       &level,
+		Language:    "en",
+		MaxDuration: 120,
+		Sort:        models.CourseSortRating,
+	}).Return([]models.Course{{ID: 10, Tags: []string{"golang", "web"}}}, nil)
+
+	courses, err := useCase.GetCatalog(ctx, models.CourseFilter{
+		CategoryID:  3,
+		Tags:        []string{" GoLang ", "WEB"},
+		Level:       &level,
+		Language:    "en",
+		MaxDuration: 120,
+		Sort:        models.CourseSortRating,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, courses, 1)
+	courseService.AssertExpectations(t)
+}
+
+func TestCourseTags(t *testing.T) {
+	ctx := context.Background()
+	courseID, ownerID := 10, 1
+
+	t.Run("Tags are trimmed and lowercased", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		useCase := usecase.NewCourseUseCase(courseService, nil, nil)
+
+		courseService.On("GetCourse", ctx, courseID).Return(&models.Course{ID: courseID, TeacherID: ownerID}, nil)
+		courseService.On("GetStaffMember", ctx, courseID, ownerID).Return(&models.CourseStaff{UserID: ownerID, Role: models.CourseStaffRoleOwner}, nil)
+		courseService.On("AddTags", ctx, courseID, []string{"golang", "web dev"}).Return(nil)
+
+		_, err := useCase.AddTags(ctx, ownerID, "teacher", courseID, []string{"  GoLang", "Web Dev ", "   "})
+
+		assert.NoError(t, err)
+		courseService.AssertExpectations(t)
+	})
+
+	t.Run("Only blank tags", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		useCase := usecase.NewCourseUseCase(courseService, nil, nil)
+
+		courseService.On("GetCourse", ctx, courseID).Return(&models.Course{ID: courseID, TeacherID: ownerID}, nil)
+		courseService.On("GetStaffMember", ctx, courseID, ownerID).Return(&models.CourseStaff{UserID: ownerID, Role: models.CourseStaffRoleOwner}, nil)
+
+		_, err := useCase.AddTags(ctx, ownerID, "teacher", courseID, []string{" ", "\t"})
+
+		assert.EqualError(t, err, "validation failed: tags cannot be empty")
+		courseService.AssertNotCalled(t, "AddTags", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Tag longer than 32 characters", func(t *testing.T) {
+		useCase := usecase.NewCourseUseCase(nil, nil, nil)
+
+		_, err := useCase.AddTags(ctx, ownerID, "teacher", courseID, []string{strings.Repeat("a", 33)})
+
+		assert.ErrorContains(t, err, "validation failed")
+	})
+
+	t.Run("Removed tag is normalized", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		useCase := usecase.NewCourseUseCase(courseService, nil, nil)
+
+		courseService.On("GetCourse", ctx, courseID).Return(&models.Course{ID: courseID, TeacherID: ownerID}, nil)
+		courseService.On("RemoveTag", ctx, courseID, "golang").Return(nil)
+
+		err := useCase.RemoveTag(ctx, 2, "admin", courseID, " GoLang ")
+
+		assert.NoError(t, err)
+		courseService.AssertExpectations(t)
+	})
+}
+
+func TestSetPrerequisites(t *testing.T) {
+	ctx := context.Background()
+	basics, golang, advanced := 1, 2, 3
+
+	t.Run("Rejects a prerequisite that already requires the course", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		useCase := usecase.NewCourseUseCase(courseService, nil, nil)
+
+		courseService.On("GetCourse", ctx, golang).Return(&models.Course{ID: golang, Name: "Go"}, nil)
+		courseService.On("GetCourse", ctx, advanced).Return(&models.Course{ID: advanced, Name: "Advanced Go"}, nil)
+		// Advanced Go требует Go, поэтому Go не может требовать Advanced Go
+		courseService.On("DependsOn", ctx, advanced, golang).Return(true, nil)
+
+		_, err := useCase.SetPrerequisites(ctx, 1, "admin", golang, &dto.SetPrerequisitesRequest{CourseIDs: []int{advanced}})
+
+		assert.EqualError(t, err, `prerequisite cycle: course "Advanced Go" already requires this course`)
+		courseService.AssertNotCalled(t, "SetPrerequisites", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Course cannot require itself", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		useCase := usecase.NewCourseUseCase(courseService, nil, nil)
+
+		courseService.On("GetCourse", ctx, golang).Return(&models.Course{ID: golang, Name: "Go"}, nil)
+
+		_, err := useCase.SetPrerequisites(ctx, 1, "admin", golang, &dto.SetPrerequisitesRequest{CourseIDs: []int{golang}})
+
+		assert.EqualError(t, err, "course cannot be a prerequisite of itself")
+		courseService.AssertNotCalled(t, "SetPrerequisites", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Saves deduplicated prerequisites", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		useCase := usecase.NewCourseUseCase(courseService, nil, nil)
+
+		courseService.On("GetCourse", ctx, advanced).Return(&models.Course{ID: advanced, Name: "Advanced Go", MinUserLevel: 2}, nil)
+		courseService.On("GetCourse", ctx, basics).Return(&models.Course{ID: basics, Name: "Basics"}, nil)
+		courseService.On("GetCourse", ctx, golang).Return(&models.Course{ID: golang, Name: "Go"}, nil)
+		courseService.On("DependsOn", ctx, basics, advanced).Return(false, nil)
+		courseService.On("DependsOn", ctx, golang, advanced).Return(false, nil)
+		courseService.On("SetPrerequisites", ctx, advanced, []int{basics, golang}, 2).Return(nil)
+		courseService.On("GetPrerequisites", ctx, advanced).Return([]*models.CoursePrerequisite{
+			{CourseID: advanced, PrerequisiteID: basics, Name: "Basics"},
+			{CourseID: advanced, PrerequisiteID: golang, Name: "Go"},
+		}, nil)
+
+		prerequisites, err := useCase.SetPrerequisites(ctx, 1, "admin", advanced, &dto.SetPrerequisitesRequest{CourseIDs: []int{basics, golang, basics}, MinUserLevel: 2})
+
+		assert.NoError(t, err)
+		assert.Len(t, prerequisites.Courses, 2)
+		assert.Equal(t, 2, prerequisites.MinUserLevel)
+		courseService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+type CourseVersionUseCase struct {
+	versionService services.CourseVersionServiceInterface
+	courseService  services.CourseServiceInterface
+	moduleService  services.ModuleServiceInterface
+	lessonService  services.LessonServiceInterface
+}
+
+func NewCourseVersionUseCase(
+	versionService services.CourseVersionServiceInterface,
+	courseService services.CourseServiceInterface,
+	moduleService services.ModuleServiceInterface,
+	lessonService services.LessonServiceInterface,
+) *CourseVersionUseCase {
+	return &CourseVersionUseCase{
+		versionService: versionService,
+		courseService:  courseService,
+		moduleService:  moduleService,
+		lessonService:  lessonService,
+	}
+}
+
+// PublishVersion сохраняет текущий черновик модулей и уроков курса как новую неизменяемую версию
+func (u *CourseVersionUseCase) PublishVersion(ctx context.Context, userID int, userRole string, courseID int, input *dto.PublishVersionRequest) (*models.CourseVersion, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseEditorRoles); err != nil {
+		return nil, err
+	}
+
+	modules, err := u.moduleService.GetModulesByCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	lessons, err := u.lessonService.GetAllLessons(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if len(lessons) == 0 {
+		return nil, errors.New("cannot publish a course without lessons")
+	}
+	if modules == nil {
+		modules = []*models.Module{}
+	}
+
+	version := &models.CourseVersion{
+		CourseID:    courseID,
+		Notes:       input.Notes,
+		LessonCount: len(lessons),
+		PublishedBy: userID,
+		Content:     &models.CourseVersionContent{Modules: modules, Lessons: lessons},
+	}
+	if err := u.versionService.PublishVersion(ctx, version); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// GetVersions возвращает историю версий курса команде курса
+func (u *CourseVersionUseCase) GetVersions(ctx context.Context, userID int, userRole string, courseID int) ([]*models.CourseVersion, error) {
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseStaffRoles); err != nil {
+		return nil, err
+	}
+	return u.versionService.GetVersions(ctx, courseID)
+}
+
+// GetVersion возвращает версию курса со снимком содержимого команде курса
+func (u *CourseVersionUseCase) GetVersion(ctx context.Context, userID int, userRole string, courseID, versionID int) (*models.CourseVersion, error) {
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseStaffRoles); err != nil {
+		return nil, err
+	}
+	version, err := u.versionService.GetVersion(ctx, versionID)
+	if err != nil {
+		return nil, err
+	}
+	if version.CourseID != courseID {
+		return nil, errors.New("course version not found")
+	}
+	return version, nil
+}
+
+// GetStudentVersion возвращает версию, за которой закреплён студент, и изменения в последней версии
+func (u *CourseVersionUseCase) GetStudentVersion(ctx context.Context, userID, courseID int) (*models.StudentVersionInfo, error) {
+	current, latest, err := u.studentVersions(ctx, userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &models.StudentVersionInfo{Current: current, Latest: latest}
+	if current != nil && latest != nil && latest.ID != current.ID {
+		info.UpgradeAvailable = true
+		info.Changes = models.DiffLessons(current.Content.Lessons, latest.Content.Lessons)
+	}
+	return info, nil
+}
+
+// UpgradeVersion переводит студента на последнюю версию курса. Прогресс по урокам,
+// которые не изменились, сохраняется; по изменённым - сбрасывается.
+func (u *CourseVersionUseCase) UpgradeVersion(ctx context.Context, userID, courseID int) (*models.StudentVersionInfo, error) {
+	current, latest, err := u.studentVersions(ctx, userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil || latest == nil {
+		return nil, errors.New("course has no published versions")
+	}
+	if latest.ID == current.ID {
+		return nil, errors.New("already on the latest version")
+	}
+
+	changes := models.DiffLessons(current.Content.Lessons, latest.Content.Lessons)
+	if err := u.versionService.UpgradeEnrollment(ctx, userID, courseID, latest.ID, changes.Changed); err != nil {
+		return nil, err
+	}
+	return &models.StudentVersionInfo{Current: latest, Latest: latest, Changes: changes}, nil
+}
+
+// studentVersions возвращает закреплённую за студентом и последнюю версии курса (nil - курс не публиковался)
+func (u *CourseVersionUseCase) studentVersions(ctx context.Context, userID, courseID int) (*models.CourseVersion, *models.CourseVersion, error) {
+	pinned, err := u.versionService.GetPinnedVersion(ctx, userID, courseID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pinned == nil {
+		return nil, nil, nil
+	}
+
+	current, err := u.versionService.GetVersion(ctx, *pinned)
+	if err != nil {
+		return nil, nil, err
+	}
+	latest, err := u.versionService.GetLatestVersion(ctx, courseID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return current, latest, nil
+}
+
+// loadCourseContent возвращает модули и уроки курса в том виде, в каком их видит пользователь:
+// записанный студент - из закреплённой за ним версии, команда курса и студенты курсов
+// без опубликованных версий - из рабочего черновика
+func loadCourseContent(
+	ctx context.Context,
+	versionService services.CourseVersionServiceInterface,
+	moduleService services.ModuleServiceInterface,
+	lessonService services.LessonServiceInterface,
+	userID, courseID int,
+) ([]*models.Module, []*models.Lesson, error) {
+	pinned, err := versionService.GetPinnedVersion(ctx, userID, courseID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pinned != nil {
+		version, err := versionService.GetVersion(ctx, *pinned)
+		if err != nil {
+			return nil, nil, err
+		}
+		// версии, опубликованные до появления HTML, хранят только markdown
+		for _, lesson := range version.Content.Lessons {
+			if lesson.ContentHTML == "" && lesson.Content != "" {
+				if err := renderLessonContent(lesson); err != nil {
+					return nil, nil, err
+				}
+			}
+		}
+		return version.Content.Modules, version.Content.Lessons, nil
+	}
+
+	modules, err := moduleService.GetModulesByCourse(ctx, courseID)
+	if err != nil {
+		return nil, nil, err
+	}
+	lessons, err := lessonService.GetAllLessons(ctx, courseID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return modules, lessons, nil
+}
@@ -0,0 +1,160 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+)
+
+// Mock для CourseVersionService
+type MockCourseVersionService struct {
+	mock.Mock
+	services.CourseVersionServiceInterface
+}
+
+func (m *MockCourseVersionService) GetVersion(ctx context.Context, id int) (*models.CourseVersion, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CourseVersion), args.Error(1)
+}
+
+func (m *MockCourseVersionService) GetLatestVersion(ctx context.Context, courseID int) (*models.CourseVersion, error) {
+	args := m.Called(ctx, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CourseVersion), args.Error(1)
+}
+
+func (m *MockCourseVersionService) GetPinnedVersion(ctx context.Context, userID, courseID int) (*int, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*int), args.Error(1)
+}
+
+func (m *MockCourseVersionService) UpgradeEnrollment(ctx context.Context, userID, courseID, versionID int, resetLessonIDs []int) error {
+	args := m.Called(ctx, userID, courseID, versionID, resetLessonIDs)
+	return args.Error(0)
+}
+
+func courseVersion(id, number int, lessons ...*models.Lesson) *models.CourseVersion {
+	return &models.CourseVersion{
+		ID:       id,
+		CourseID: 5,
+		Version:  number,
+		Content:  &models.CourseVersionContent{Modules: []*models.Module{}, Lessons: lessons},
+	}
+}
+
+func TestUpgradeVersion(t *testing.T) {
+	ctx := context.Background()
+	pinned := 1
+	v1 := courseVersion(1, 1,
+		&models.Lesson{ID: 10, CourseID: 5, Title: "Intro", Content: "Hello"},
+		&models.Lesson{ID: 11, CourseID: 5, Title: "Types", Content: "int"},
+		&models.Lesson{ID: 12, CourseID: 5, Title: "Old", Content: "removed"},
+	)
+	v2 := courseVersion(2, 2,
+		&models.Lesson{ID: 10, CourseID: 5, Title: "Intro", Content: "Hello", Position: 2},
+		&models.Lesson{ID: 11, CourseID: 5, Title: "Types", Content: "int and string"},
+		&models.Lesson{ID: 13, CourseID: 5, Title: "New", Content: "added"},
+	)
+
+	t.Run("Keeps progress for unchanged lessons", func(t *testing.T) {
+		versionService := new(MockCourseVersionService)
+		useCase := usecase.NewCourseVersionUseCase(versionService, nil, nil, nil)
+
+		versionService.On("GetPinnedVersion", ctx, 7, 5).Return(&pinned, nil)
+		versionService.On("GetVersion", ctx, 1).Return(v1, nil)
+		versionService.On("GetLatestVersion", ctx, 5).Return(v2, nil)
+		versionService.On("UpgradeEnrollment", ctx, 7, 5, 2, []int{11}).Return(nil)
+
+		info, err := useCase.UpgradeVersion(ctx, 7, 5)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, info.Current.Version)
+		assert.Equal(t, []int{10}, info.Changes.Unchanged)
+		assert.Equal(t, []int{11}, info.Changes.Changed)
+		assert.Equal(t, []int{13}, info.Changes.Added)
+		assert.Equal(t, []int{12}, info.Changes.Removed)
+		versionService.AssertExpectations(t)
+	})
+
+	t.Run("Already on the latest version", func(t *testing.T) {
+		versionService := new(MockCourseVersionService)
+		useCase := usecase.NewCourseVersionUseCase(versionService, nil, nil, nil)
+
+		versionService.On("GetPinnedVersion", ctx, 7, 5).Return(&pinned, nil)
+		versionService.On("GetVersion", ctx, 1).Return(v1, nil)
+		versionService.On("GetLatestVersion", ctx, 5).Return(v1, nil)
+
+		_, err := useCase.UpgradeVersion(ctx, 7, 5)
+
+		assert.EqualError(t, err, "already on the latest version")
+		versionService.AssertNotCalled(t, "UpgradeEnrollment", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestCourseProgressUsesPinnedVersion(t *testing.T) {
+	ctx := context.Background()
+	pinned := 1
+
+	lessonService := new(MockLessonService)
+	moduleService := new(MockModuleService)
+	progressService := new(MockLessonProgressService)
+	versionService := new(MockCourseVersionService)
+	useCase := usecase.NewLessonProgressUseCase(progressService, lessonService, nil, nil, nil, moduleService, versionService)
+
+	// в черновике уже три урока, но студент закреплён за версией с двумя
+	versionService.On("GetPinnedVersion", ctx, 7, 5).Return(&pinned, nil)
+	versionService.On("GetVersion", ctx, 1).Return(courseVersion(1, 1,
+		&models.Lesson{ID: 10, CourseID: 5},
+		&models.Lesson{ID: 11, CourseID: 5},
+	), nil)
+	moduleService.On("GetCompletions", ctx, 7, 5).Return(map[int]time.Time{}, nil)
+	progressService.On("GetProgressByCourse", ctx, 7, 5).Return([]*models.LessonProgress{
+		{LessonID: 10, IsCompleted: true},
+	}, nil)
+
+	progress, err := useCase.GetCourseProgress(ctx, 7, 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, progress.TotalLessons)
+	assert.Equal(t, 50.0, progress.Progress)
+	lessonService.AssertNotCalled(t, "GetAllLessons", mock.Anything, mock.Anything)
+}
+
+func TestVersionSnapshotHoldsOnlyModulesAndLessons(t *testing.T) {
+	t.Run("Snapshot has no blocks, attachments or assignments", func(t *testing.T) {
+		data, err := json.Marshal(&models.CourseVersionContent{Modules: []*models.Module{}, Lessons: []*models.Lesson{}})
+		assert.NoError(t, err)
+
+		var keys map[string]json.RawMessage
+		assert.NoError(t, json.Unmarshal(data, &keys))
+		assert.Len(t, keys, 2)
+		assert.Contains(t, keys, "modules")
+		assert.Contains(t, keys, "lessons")
+	})
+
+	t.Run("Editing blocks does not change a versioned lesson", func(t *testing.T) {
+		before := &models.Lesson{ID: 10, Title: "Intro", Content: "Hello"}
+		after := &models.Lesson{ID: 10, Title: "Intro", Content: "Hello", Blocks: []*models.LessonBlock{{ID: 1, LessonID: 10}}}
+
+		changes := models.DiffLessons([]*models.Lesson{before}, []*models.Lesson{after})
+
+		assert.Equal(t, []int{10}, changes.Unchanged)
+		assert.Empty(t, changes.Changed)
+	})
+}
@@ -0,0 +1,374 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/go-playground/validator/v10"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+	"gitlab.com/w0ikid/study-platform/pkg/mailer"
+	"gitlab.com/w0ikid/study-platform/pkg/markdown"
+)
+
+type DiscussionUseCase struct {
+	discussionService services.DiscussionServiceInterface
+	courseService     services.CourseServiceInterface
+	lessonService     services.LessonServiceInterface
+	userService       services.UserServiceInterface
+	enrollment        EnrollmentUseCaseInterface
+	mailer            mailer.Mailer
+	appURL            string
+}
+
+func NewDiscussionUseCase(
+	discussionService services.DiscussionServiceInterface,
+	courseService services.CourseServiceInterface,
+	lessonService services.LessonServiceInterface,
+	userService services.UserServiceInterface,
+	enrollment EnrollmentUseCaseInterface,
+	mailer mailer.Mailer,
+	appURL string,
+) *DiscussionUseCase {
+	return &DiscussionUseCase{
+		discussionService: discussionService,
+		courseService:     courseService,
+		lessonService:     lessonService,
+		userService:       userService,
+		enrollment:        enrollment,
+		mailer:            mailer,
+		appURL:            appURL,
+	}
+}
+
+// CreateThread открывает тему в курсе или, если указан урок, в уроке курса.
+// Упомянутым через @username участникам курса уходит письмо.
+func (u *DiscussionUseCase) CreateThread(ctx context.Context, userID, courseID int, input *dto.DiscussionThreadRequest) (*models.DiscussionThread, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if input.LessonID != nil {
+		lesson, err := u.lessonService.GetLessonByID(ctx, *input.LessonID)
+		if err != nil {
+			return nil, err
+		}
+		if lesson.CourseID != courseID {
+			return nil, errors.New("lesson not found")
+		}
+	}
+
+	thread := &models.DiscussionThread{
+		CourseID: courseID,
+		LessonID: input.LessonID,
+		AuthorID: userID,
+		Title:    input.Title,
+		Body:     input.Body,
+	}
+	if err := u.discussionService.CreateThread(ctx, thread); err != nil {
+		return nil, err
+	}
+
+	u.notifyMentions(ctx, userID, thread, thread.Body)
+	created, err := u.discussionService.GetThread(ctx, thread.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return created, renderThreadBody(created)
+}
+
+// GetThreads возвращает темы курса; с lessonID - только темы этого урока
+func (u *DiscussionUseCase) GetThreads(ctx context.Context, userID, courseID int, lessonID *int) ([]*models.DiscussionThread, error) {
+	threads, err := u.discussionService.GetThreads(ctx, courseID, lessonID, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, thread := range threads {
+		if err := renderThreadBody(thread); err != nil {
+			return nil, err
+		}
+	}
+	return threads, nil
+}
+
+// GetThread возвращает тему с деревом ответов
+func (u *DiscussionUseCase) GetThread(ctx context.Context, userID, courseID, threadID int) (*models.DiscussionThread, error) {
+	thread, err := u.getCourseThread(ctx, userID, courseID, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := u.discussionService.GetPosts(ctx, threadID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := renderThreadBody(thread); err != nil {
+		return nil, err
+	}
+	for _, post := range posts {
+		if err := renderPostBody(post); err != nil {
+			return nil, err
+		}
+	}
+	thread.Posts = models.BuildPostTree(posts)
+	return thread, nil
+}
+
+// UpdateThread изменяет тему; править может автор или команда курса
+func (u *DiscussionUseCase) UpdateThread(ctx context.Context, userID int, userRole string, courseID, threadID int, input *dto.DiscussionThreadRequest) (*models.DiscussionThread, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	thread, err := u.getCourseThread(ctx, userID, courseID, threadID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.checkAuthorOrStaff(ctx, userID, userRole, courseID, thread.AuthorID); err != nil {
+		return nil, err
+	}
+
+	thread.Title = input.Title
+	thread.Body = input.Body
+	if err := u.discussionService.UpdateThread(ctx, thread, userID); err != nil {
+		return nil, err
+	}
+	return thread, renderThreadBody(thread)
+}
+
+// DeleteThread удаляет тему; удалить может автор или команда курса
+func (u *DiscussionUseCase) DeleteThread(ctx context.Context, userID int, userRole string, courseID, threadID int) error {
+	thread, err := u.getCourseThread(ctx, userID, courseID, threadID)
+	if err != nil {
+		return err
+	}
+	if err := u.checkAuthorOrStaff(ctx, userID, userRole, courseID, thread.AuthorID); err != nil {
+		return err
+	}
+	return u.discussionService.DeleteThread(ctx, threadID)
+}
+
+// SetAcceptedAnswer отмечает ответ темы принятым; отмечает команда курса, nil снимает отметку
+func (u *DiscussionUseCase) SetAcceptedAnswer(ctx context.Context, userID int, userRole string, courseID, threadID int, input *dto.AcceptedAnswerRequest) (*models.DiscussionThread, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	thread, err := u.getCourseThread(ctx, userID, courseID, threadID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseStaffRoles); err != nil {
+		return nil, err
+	}
+	if input.PostID != nil {
+		if _, err := u.getThreadPost(ctx, userID, threadID, *input.PostID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := u.discussionService.SetAcceptedPost(ctx, threadID, input.PostID); err != nil {
+		return nil, err
+	}
+	thread.AcceptedPostID = input.PostID
+	return thread, renderThreadBody(thread)
+}
+
+// VoteThread ставит (upvote) или снимает голос текущего пользователя за тему
+func (u *DiscussionUseCase) VoteThread(ctx context.Context, userID, courseID, threadID int, upvote bool) error {
+	if _, err := u.getCourseThread(ctx, userID, courseID, threadID); err != nil {
+		return err
+	}
+	return u.discussionService.VoteThread(ctx, threadID, userID, upvote)
+}
+
+// CreatePost добавляет ответ в тему, при parent_id - вложенный ответ
+func (u *DiscussionUseCase) CreatePost(ctx context.Context, userID, courseID, threadID int, input *dto.DiscussionPostRequest) (*models.DiscussionPost, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	thread, err := u.getCourseThread(ctx, userID, courseID, threadID)
+	if err != nil {
+		return nil, err
+	}
+	if input.ParentID != nil {
+		if _, err := u.getThreadPost(ctx, userID, threadID, *input.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	post := &models.DiscussionPost{
+		ThreadID: threadID,
+		ParentID: input.ParentID,
+		AuthorID: userID,
+		Body:     input.Body,
+	}
+	if err := u.discussionService.CreatePost(ctx, post); err != nil {
+		return nil, err
+	}
+
+	u.notifyMentions(ctx, userID, thread, post.Body)
+	created, err := u.discussionService.GetPost(ctx, post.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return created, renderPostBody(created)
+}
+
+// UpdatePost изменяет ответ; править может автор или команда курса
+func (u *DiscussionUseCase) UpdatePost(ctx context.Context, userID int, userRole string, courseID, threadID, postID int, input *dto.DiscussionPostRequest) (*models.DiscussionPost, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	post, err := u.getCoursePost(ctx, userID, courseID, threadID, postID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.checkAuthorOrStaff(ctx, userID, userRole, courseID, post.AuthorID); err != nil {
+		return nil, err
+	}
+
+	post.Body = input.Body
+	if err := u.discussionService.UpdatePost(ctx, post, userID); err != nil {
+		return nil, err
+	}
+	return post, renderPostBody(post)
+}
+
+// DeletePost удаляет ответ вместе с вложенными; удалить может автор или команда курса
+func (u *DiscussionUseCase) DeletePost(ctx context.Context, userID int, userRole string, courseID, threadID, postID int) error {
+	post, err := u.getCoursePost(ctx, userID, courseID, threadID, postID)
+	if err != nil {
+		return err
+	}
+	if err := u.checkAuthorOrStaff(ctx, userID, userRole, courseID, post.AuthorID); err != nil {
+		return err
+	}
+	return u.discussionService.DeletePost(ctx, post)
+}
+
+// VotePost ставит (upvote) или снимает голос текущего пользователя за ответ
+func (u *DiscussionUseCase) VotePost(ctx context.Context, userID, courseID, threadID, postID int, upvote bool) error {
+	if _, err := u.getCoursePost(ctx, userID, courseID, threadID, postID); err != nil {
+		return err
+	}
+	return u.discussionService.VotePost(ctx, postID, userID, upvote)
+}
+
+// GetThreadHistory возвращает предыдущие редакции темы
+func (u *DiscussionUseCase) GetThreadHistory(ctx context.Context, userID, courseID, threadID int) ([]*models.DiscussionEdit, error) {
+	if _, err := u.getCourseThread(ctx, userID, courseID, threadID); err != nil {
+		return nil, err
+	}
+	return u.discussionService.GetEdits(ctx, threadID, nil)
+}
+
+// GetPostHistory возвращает предыдущие редакции ответа
+func (u *DiscussionUseCase) GetPostHistory(ctx context.Context, userID, courseID, threadID, postID int) ([]*models.DiscussionEdit, error) {
+	if _, err := u.getCoursePost(ctx, userID, courseID, threadID, postID); err != nil {
+		return nil, err
+	}
+	return u.discussionService.GetEdits(ctx, threadID, &postID)
+}
+
+// renderThreadBody строит body_html темы из markdown тем же рендером, что и уроки
+func renderThreadBody(thread *models.DiscussionThread) error {
+	bodyHTML, err := markdown.Render(thread.Body)
+	if err != nil {
+		return err
+	}
+	thread.BodyHTML = bodyHTML
+	return nil
+}
+
+// renderPostBody строит body_html ответа из markdown
+func renderPostBody(post *models.DiscussionPost) error {
+	bodyHTML, err := markdown.Render(post.Body)
+	if err != nil {
+		return err
+	}
+	post.BodyHTML = bodyHTML
+	return nil
+}
+
+// notifyMentions отправляет письмо упомянутым в тексте пользователям, у которых есть доступ к курсу.
+// Ошибки отправки не мешают публикации.
+func (u *DiscussionUseCase) notifyMentions(ctx context.Context, authorID int, thread *models.DiscussionThread, body string) {
+	usernames := models.ParseMentions(body)
+	if len(usernames) == 0 {
+		return
+	}
+
+	users, err := u.userService.FindExistingUsers(ctx, usernames, []string{})
+	if err != nil {
+		log.Printf("discussions: failed to resolve mentions in thread %d: %v", thread.ID, err)
+		return
+	}
+
+	author, err := u.userService.GetUser(ctx, authorID)
+	if err != nil {
+		log.Printf("discussions: failed to resolve mentions in thread %d: %v", thread.ID, err)
+		return
+	}
+
+	subject := fmt.Sprintf("%s mentioned you in \"%s\"", author.Username, thread.Title)
+	message := fmt.Sprintf(`%s mentioned you in a discussion:
+
+%s
+
+Open the discussion at %s/courses/%d/discussions/%d
+`, author.Username, body, u.appURL, thread.CourseID, thread.ID)
+
+	for _, user := range users {
+		if user.ID == authorID {
+			continue
+		}
+		access, err := u.enrollment.HasCourseAccess(ctx, user.ID, thread.CourseID)
+		if err != nil || !access {
+			continue
+		}
+		if err := u.mailer.Send(user.Email, subject, message); err != nil {
+			log.Printf("discussions: failed to notify user %d: %v", user.ID, err)
+		}
+	}
+}
+
+// getCourseThread возвращает тему, если она относится к курсу
+func (u *DiscussionUseCase) getCourseThread(ctx context.Context, userID, courseID, threadID int) (*models.DiscussionThread, error) {
+	thread, err := u.discussionService.GetThread(ctx, threadID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if thread.CourseID != courseID {
+		return nil, errors.New("discussion thread not found")
+	}
+	return thread, nil
+}
+
+// getThreadPost возвращает ответ, если он относится к теме
+func (u *DiscussionUseCase) getThreadPost(ctx context.Context, userID, threadID, postID int) (*models.DiscussionPost, error) {
+	post, err := u.discussionService.GetPost(ctx, postID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if post.ThreadID != threadID {
+		return nil, errors.New("discussion post not found")
+	}
+	return post, nil
+}
+
+func (u *DiscussionUseCase) getCoursePost(ctx context.Context, userID, courseID, threadID, postID int) (*models.DiscussionPost, error) {
+	if _, err := u.getCourseThread(ctx, userID, courseID, threadID); err != nil {
+		return nil, err
+	}
+	return u.getThreadPost(ctx, userID, threadID, postID)
+}
+
+// checkAuthorOrStaff разрешает действие автору записи и команде курса
+func (u *DiscussionUseCase) checkAuthorOrStaff(ctx context.Context, userID int, userRole string, courseID, authorID int) error {
+	if userID == authorID {
+		return nil
+	}
+	_, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseStaffRoles)
+	return err
+}
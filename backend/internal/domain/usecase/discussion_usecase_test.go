@@ -0,0 +1,180 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+// Mock для DiscussionService
+type MockDiscussionService struct {
+	mock.Mock
+	services.DiscussionServiceInterface
+}
+
+func (m *MockDiscussionService) GetThread(ctx context.Context, id, userID int) (*models.DiscussionThread, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DiscussionThread), args.Error(1)
+}
+
+func (m *MockDiscussionService) GetPost(ctx context.Context, id, userID int) (*models.DiscussionPost, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DiscussionPost), args.Error(1)
+}
+
+func (m *MockDiscussionService) GetPosts(ctx context.Context, threadID, userID int) ([]*models.DiscussionPost, error) {
+	args := m.Called(ctx, threadID, userID)
+	return args.Get(0).([]*models.DiscussionPost), args.Error(1)
+}
+
+func (m *MockDiscussionService) CreatePost(ctx context.Context, post *models.DiscussionPost) error {
+	args := m.Called(ctx, post)
+	post.ID = 50
+	return args.Error(0)
+}
+
+func (m *MockDiscussionService) SetAcceptedPost(ctx context.Context, threadID int, postID *int) error {
+	args := m.Called(ctx, threadID, postID)
+	return args.Error(0)
+}
+
+func (m *MockEnrollmentUseCase) HasCourseAccess(ctx context.Context, userID, courseID int) (bool, error) {
+	args := m.Called(ctx, userID, courseID)
+	return args.Bool(0), args.Error(1)
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestGetThread(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Builds nested replies", func(t *testing.T) {
+		discussionService := new(MockDiscussionService)
+		useCase := usecase.NewDiscussionUseCase(discussionService, nil, nil, nil, nil, nil, "")
+
+		discussionService.On("GetThread", ctx, 1, 7).Return(&models.DiscussionThread{ID: 1, CourseID: 10}, nil)
+		discussionService.On("GetPosts", ctx, 1, 7).Return([]*models.DiscussionPost{
+			{ID: 2, ThreadID: 1},
+			{ID: 3, ThreadID: 1, ParentID: intPtr(2)},
+			{ID: 4, ThreadID: 1},
+			{ID: 5, ThreadID: 1, ParentID: intPtr(3)},
+		}, nil)
+
+		thread, err := useCase.GetThread(ctx, 7, 10, 1)
+
+		assert.NoError(t, err)
+		assert.Len(t, thread.Posts, 2)
+		assert.Equal(t, 3, thread.Posts[0].Replies[0].ID)
+		assert.Equal(t, 5, thread.Posts[0].Replies[0].Replies[0].ID)
+		assert.Empty(t, thread.Posts[1].Replies)
+	})
+
+	t.Run("Renders markdown bodies", func(t *testing.T) {
+		discussionService := new(MockDiscussionService)
+		useCase := usecase.NewDiscussionUseCase(discussionService, nil, nil, nil, nil, nil, "")
+
+		discussionService.On("GetThread", ctx, 1, 7).Return(&models.DiscussionThread{ID: 1, CourseID: 10, Body: "**Help**<script>alert(1)</script>"}, nil)
+		discussionService.On("GetPosts", ctx, 1, 7).Return([]*models.DiscussionPost{
+			{ID: 2, ThreadID: 1, Body: "[link](javascript:alert(1)) `code`"},
+		}, nil)
+
+		thread, err := useCase.GetThread(ctx, 7, 10, 1)
+
+		assert.NoError(t, err)
+		assert.Contains(t, thread.BodyHTML, "<strong>Help</strong>")
+		assert.NotContains(t, thread.BodyHTML, "<script")
+		assert.Contains(t, thread.Posts[0].BodyHTML, "<code>code</code>")
+		assert.NotContains(t, thread.Posts[0].BodyHTML, "javascript:")
+	})
+
+	t.Run("Thread of another course", func(t *testing.T) {
+		discussionService := new(MockDiscussionService)
+		useCase := usecase.NewDiscussionUseCase(discussionService, nil, nil, nil, nil, nil, "")
+
+		discussionService.On("GetThread", ctx, 1, 7).Return(&models.DiscussionThread{ID: 1, CourseID: 11}, nil)
+
+		_, err := useCase.GetThread(ctx, 7, 10, 1)
+
+		assert.EqualError(t, err, "discussion thread not found")
+	})
+}
+
+func TestSetAcceptedAnswer(t *testing.T) {
+	ctx := context.Background()
+	thread := &models.DiscussionThread{ID: 1, CourseID: 10, AuthorID: 7}
+
+	t.Run("Student cannot accept an answer", func(t *testing.T) {
+		discussionService := new(MockDiscussionService)
+		courseService := new(MockCourseService)
+		useCase := usecase.NewDiscussionUseCase(discussionService, courseService, nil, nil, nil, nil, "")
+
+		discussionService.On("GetThread", ctx, 1, 7).Return(thread, nil)
+		courseService.On("GetCourse", ctx, 10).Return(&models.Course{ID: 10}, nil)
+		courseService.On("GetStaffMember", ctx, 10, 7).Return(nil, nil)
+
+		_, err := useCase.SetAcceptedAnswer(ctx, 7, "student", 10, 1, &dto.AcceptedAnswerRequest{PostID: intPtr(2)})
+
+		assert.ErrorIs(t, err, usecase.ErrPermissionDenied)
+		discussionService.AssertNotCalled(t, "SetAcceptedPost", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Post must belong to the thread", func(t *testing.T) {
+		discussionService := new(MockDiscussionService)
+		courseService := new(MockCourseService)
+		useCase := usecase.NewDiscussionUseCase(discussionService, courseService, nil, nil, nil, nil, "")
+
+		discussionService.On("GetThread", ctx, 1, 3).Return(thread, nil)
+		courseService.On("GetCourse", ctx, 10).Return(&models.Course{ID: 10}, nil)
+		courseService.On("GetStaffMember", ctx, 10, 3).Return(&models.CourseStaff{CourseID: 10, UserID: 3, Role: models.CourseStaffRoleTA}, nil)
+		discussionService.On("GetPost", ctx, 2, 3).Return(&models.DiscussionPost{ID: 2, ThreadID: 9}, nil)
+
+		_, err := useCase.SetAcceptedAnswer(ctx, 3, "student", 10, 1, &dto.AcceptedAnswerRequest{PostID: intPtr(2)})
+
+		assert.EqualError(t, err, "discussion post not found")
+	})
+}
+
+func TestCreatePost(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Notifies mentioned course members only", func(t *testing.T) {
+		discussionService := new(MockDiscussionService)
+		userService := new(MockUserService)
+		enrollment := new(MockEnrollmentUseCase)
+		mailer := new(MockMailer)
+		useCase := usecase.NewDiscussionUseCase(discussionService, nil, nil, userService, enrollment, mailer, "http://localhost")
+
+		discussionService.On("GetThread", ctx, 1, 7).Return(&models.DiscussionThread{ID: 1, CourseID: 10, Title: "Closures"}, nil)
+		discussionService.On("CreatePost", ctx, mock.Anything).Return(nil)
+		discussionService.On("GetPost", ctx, 50, 7).Return(&models.DiscussionPost{ID: 50, ThreadID: 1}, nil)
+		userService.On("FindExistingUsers", ctx, []string{"bob", "eve"}, []string{}).Return([]*models.User{
+			{ID: 8, Username: "bob", Email: "bob@example.com"},
+			{ID: 9, Username: "eve", Email: "eve@example.com"},
+		}, nil)
+		userService.On("GetUser", ctx, 7).Return(&models.User{ID: 7, Username: "alice"}, nil)
+		enrollment.On("HasCourseAccess", ctx, 8, 10).Return(true, nil)
+		enrollment.On("HasCourseAccess", ctx, 9, 10).Return(false, nil)
+		mailer.On("Send", "bob@example.com", `alice mentioned you in "Closures"`, mock.Anything).Return(nil)
+
+		post, err := useCase.CreatePost(ctx, 7, 10, 1, &dto.DiscussionPostRequest{Body: "@bob see this, cc @eve and @bob"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 50, post.ID)
+		mailer.AssertNumberOfCalls(t, "Send", 1)
+	})
+}
@@ -3,16 +3,30 @@ package usecase
 import (
 	"context"
 	"errors"
-	_"log"
+	"fmt"
+	"log"
+	"time"
 
+	"github.com/go-playground/validator/v10"
 	"gitlab.com/w0ikid/study-platform/internal/domain/models"
 	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+	"gitlab.com/w0ikid/study-platform/pkg/mailer"
 )
 
 type EnrollmentUseCaseInterface interface {
 	EnrollStudent(ctx context.Context, userID, courseID int) error
+	JoinCourse(ctx context.Context, userID, courseID int) (*models.EnrollmentResult, error)
+	CheckEnrollment(ctx context.Context, userID, courseID int) (*models.Course, error)
+	EnrollPaid(ctx context.Context, userID, courseID int) (*models.EnrollmentResult, error)
+	DropCourse(ctx context.Context, userID, courseID int) error
+	LeaveWaitlist(ctx context.Context, userID, courseID int) error
+	GetWaitlist(ctx context.Context, userID int, userRole string, courseID int) ([]*models.WaitlistEntry, error)
+	UpdateSchedule(ctx context.Context, userID int, userRole string, courseID int, input *dto.CourseScheduleRequest) (*models.Course, error)
+	AdminEnrollStudent(ctx context.Context, userID, courseID int, overridePrerequisites bool) error
 	GetEnrollmentByID(ctx context.Context, id int) (*models.Enrollment, error)
 	IsUserEnrolled(ctx context.Context, userID, courseID int) (bool, error)
+	HasCourseAccess(ctx context.Context, userID, courseID int) (bool, error)
 	GetStudentEnrollments(ctx context.Context, userID int) ([]*models.Enrollment, error)
 	GetCourseEnrollments(ctx context.Context, courseID int) ([]*models.Enrollment, error)
 	DeleteEnrollment(ctx context.Context, id int) error
@@ -21,49 +35,227 @@ type EnrollmentUseCaseInterface interface {
 type EnrollmentUseCase struct {
 	enrollmentService services.EnrollmentServiceInterface
 	courseService     services.CourseServiceInterface
+	userService       services.UserServiceInterface
+	mailer            mailer.Mailer
+	appURL            string
 }
 func NewEnrollmentUseCase(
 	enrollmentService services.EnrollmentServiceInterface,
 	courseService services.CourseServiceInterface,
+	userService services.UserServiceInterface,
+	mailer mailer.Mailer,
+	appURL string,
 ) *EnrollmentUseCase {
 	return &EnrollmentUseCase{
 		enrollmentService: enrollmentService,
 		courseService:     courseService,
+		userService:       userService,
+		mailer:            mailer,
+		appURL:            appURL,
 	}
 }
+
+// enrollOptions - какие ограничения курса проверяются при записи
+type enrollOptions struct {
+	checkPrerequisites bool
+	checkWindow        bool // окно записи и дата окончания курса
+	joinWaitlist       bool // при нехватке мест ставить в лист ожидания, иначе ErrCourseFull
+	requirePayment     bool // платный курс нельзя занять без оплаченного заказа
+}
+
+// EnrollStudent записывает студента на курс; если мест нет, возвращает ErrCourseFull
 func (u *EnrollmentUseCase) EnrollStudent(ctx context.Context, userID, courseID int) error {
+	_, err := u.enroll(ctx, userID, courseID, enrollOptions{checkPrerequisites: true, checkWindow: true})
+	return err
+}
+
+// JoinCourse - самостоятельная запись студента: при нехватке мест студент попадает в лист ожидания.
+// На платный курс так записаться нельзя - возвращается ErrPaymentRequired.
+func (u *EnrollmentUseCase) JoinCourse(ctx context.Context, userID, courseID int) (*models.EnrollmentResult, error) {
+	return u.enroll(ctx, userID, courseID, enrollOptions{checkPrerequisites: true, checkWindow: true, joinWaitlist: true, requirePayment: true})
+}
+
+// CheckEnrollment проверяет, может ли студент записаться на курс, не записывая его.
+// Используется перед оформлением заказа на платный курс.
+func (u *EnrollmentUseCase) CheckEnrollment(ctx context.Context, userID, courseID int) (*models.Course, error) {
+	return u.checkEnrollment(ctx, userID, courseID, enrollOptions{checkPrerequisites: true, checkWindow: true})
+}
+
+// EnrollPaid записывает студента по оплаченному заказу. Условия записи проверялись при оформлении
+// заказа, поэтому повторно проверяется только лимит мест: при его нехватке возвращается ErrCourseFull.
+func (u *EnrollmentUseCase) EnrollPaid(ctx context.Context, userID, courseID int) (*models.EnrollmentResult, error) {
+	return u.enroll(ctx, userID, courseID, enrollOptions{})
+}
+
+// AdminEnrollStudent - запись студента администратором, с возможностью пропустить проверку пререквизитов.
+// Окно записи администратора не ограничивает, лимит мест - ограничивает.
+func (u *EnrollmentUseCase) AdminEnrollStudent(ctx context.Context, userID, courseID int, overridePrerequisites bool) error {
+	if _, err := u.userService.GetUser(ctx, userID); err != nil {
+		return errors.New("user not found")
+	}
+	_, err := u.enroll(ctx, userID, courseID, enrollOptions{checkPrerequisites: !overridePrerequisites})
+	return err
+}
+
+func (u *EnrollmentUseCase) enroll(ctx context.Context, userID, courseID int, options enrollOptions) (*models.EnrollmentResult, error) {
+	if _, err := u.checkEnrollment(ctx, userID, courseID, options); err != nil {
+		return nil, err
+	}
+
+	// Create enrollment: места проверяются атомарно в репозитории
+	enrollment := &models.Enrollment{
+		UserID:   userID,
+		CourseID: courseID,
+		Status:   "active",
+	}
+
+	result, err := u.enrollmentService.Enroll(ctx, enrollment, options.joinWaitlist)
+	if err != nil {
+		return nil, err
+	}
+	if result.Status == models.EnrollmentResultFull {
+		return nil, ErrCourseFull
+	}
+	return result, nil
+}
+
+// checkEnrollment проверяет ограничения курса для записи студента и возвращает курс
+func (u *EnrollmentUseCase) checkEnrollment(ctx context.Context, userID, courseID int, options enrollOptions) (*models.Course, error) {
 	// check if course exists
 	course, err := u.courseService.GetCourse(ctx, courseID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if course == nil {
-		return errors.New("course not found")
+		return nil, errors.New("course not found")
+	}
+	if course.Status == models.CourseStatusDraft {
+		return nil, errors.New("course is not published yet")
+	}
+	if options.checkWindow {
+		if reason := course.EnrollmentWindowError(time.Now()); reason != "" {
+			return nil, errors.New(reason)
+		}
+	}
+	if options.requirePayment && course.IsPaid() {
+		return nil, ErrPaymentRequired
 	}
 	
-	if course.TeacherID == userID {
-		return errors.New("teacher cannot enroll in their own course")
+	staff, err := u.courseService.GetStaffMember(ctx, courseID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if staff != nil {
+		return nil, errors.New("course staff cannot enroll in their own course")
 	}
 	
 	// check if already enrolled
 	enrolled, err := u.enrollmentService.IsUserEnrolled(ctx, userID, courseID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if enrolled {
-		return errors.New("student already enrolled in this course")
+		return nil, errors.New("student already enrolled in this course")
 	} 
 
-	
-	// Create enrollment
-	enrollment := &models.Enrollment{
-		UserID:   userID,
-		CourseID: courseID,
-		Status:   "active",
+	if options.checkPrerequisites {
+		if err := u.checkPrerequisites(ctx, userID, course); err != nil {
+			return nil, err
+		}
 	}
+	return course, nil
+}
 
-	_, err = u.enrollmentService.CreateEnrollment(ctx, enrollment)
-	return err
+// DropCourse - студент сам отписывается от курса; освободившееся место получает следующий из листа ожидания
+func (u *EnrollmentUseCase) DropCourse(ctx context.Context, userID, courseID int) error {
+	enrollment, err := u.enrollmentService.GetEnrollmentByUserAndCourse(ctx, userID, courseID)
+	if err != nil {
+		return errors.New("enrollment not found")
+	}
+	if err := u.enrollmentService.DeleteEnrollment(ctx, enrollment.ID); err != nil {
+		return err
+	}
+	return u.promoteWaitlisted(ctx, courseID)
+}
+
+// LeaveWaitlist убирает студента из листа ожидания курса
+func (u *EnrollmentUseCase) LeaveWaitlist(ctx context.Context, userID, courseID int) error {
+	return u.enrollmentService.LeaveWaitlist(ctx, courseID, userID)
+}
+
+// GetWaitlist возвращает лист ожидания курса команде курса
+func (u *EnrollmentUseCase) GetWaitlist(ctx context.Context, userID int, userRole string, courseID int) ([]*models.WaitlistEntry, error) {
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseStaffRoles); err != nil {
+		return nil, err
+	}
+	return u.enrollmentService.GetWaitlist(ctx, courseID)
+}
+
+// UpdateSchedule задаёт даты курса, окно записи и лимит мест. При увеличении лимита
+// студенты из листа ожидания сразу переводятся на свободные места.
+func (u *EnrollmentUseCase) UpdateSchedule(ctx context.Context, userID int, userRole string, courseID int, input *dto.CourseScheduleRequest) (*models.Course, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if input.StartsAt != nil && input.EndsAt != nil && !input.EndsAt.After(*input.StartsAt) {
+		return nil, errors.New("validation failed: ends_at must be after starts_at")
+	}
+	if input.EnrollmentOpensAt != nil && input.EnrollmentClosesAt != nil && !input.EnrollmentClosesAt.After(*input.EnrollmentOpensAt) {
+		return nil, errors.New("validation failed: enrollment_closes_at must be after enrollment_opens_at")
+	}
+
+	course, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseEditorRoles)
+	if err != nil {
+		return nil, err
+	}
+
+	course.StartsAt = input.StartsAt
+	course.EndsAt = input.EndsAt
+	course.EnrollmentOpensAt = input.EnrollmentOpensAt
+	course.EnrollmentClosesAt = input.EnrollmentClosesAt
+	course.Capacity = input.Capacity
+	if err := u.courseService.UpdateSchedule(ctx, course); err != nil {
+		return nil, err
+	}
+
+	if err := u.promoteWaitlisted(ctx, courseID); err != nil {
+		return nil, err
+	}
+	return u.courseService.GetCourse(ctx, courseID)
+}
+
+// promoteWaitlisted переводит студентов из листа ожидания на свободные места и оповещает их
+func (u *EnrollmentUseCase) promoteWaitlisted(ctx context.Context, courseID int) error {
+	promoted, err := u.enrollmentService.PromoteWaitlisted(ctx, courseID)
+	if err != nil || len(promoted) == 0 {
+		return err
+	}
+
+	course, err := u.courseService.GetCourse(ctx, courseID)
+	if err != nil {
+		return err
+	}
+	for _, entry := range promoted {
+		user, err := u.userService.GetUser(ctx, entry.UserID)
+		if err != nil {
+			log.Printf("waitlist: failed to notify user %d: %v", entry.UserID, err)
+			continue
+		}
+		// запись уже создана, поэтому ошибка почты только логируется
+		if err := u.mailer.Send(user.Email, "You are enrolled in "+course.Name, u.promotedBody(user, course)); err != nil {
+			log.Printf("waitlist: failed to notify user %d: %v", entry.UserID, err)
+		}
+	}
+	return nil
+}
+
+func (u *EnrollmentUseCase) promotedBody(user *models.User, course *models.Course) string {
+	return fmt.Sprintf(`Hello %s,
+
+A seat has opened up in "%s" and you have been enrolled from the waitlist.
+
+Open the course at %s/courses/%d
+`, user.Username, course.Name, u.appURL, course.ID)
 }
 
 // GetEnrollmentByID gets an enrollment record by ID
@@ -75,6 +267,18 @@ func (u *EnrollmentUseCase) IsUserEnrolled(ctx context.Context, userID, courseID
 	return u.enrollmentService.IsUserEnrolled(ctx, userID, courseID)
 }
 
+// HasCourseAccess - есть ли у пользователя доступ к материалам курса: он записан на курс или входит в команду курса
+func (u *EnrollmentUseCase) HasCourseAccess(ctx context.Context, userID, courseID int) (bool, error) {
+	staff, err := u.courseService.GetStaffMember(ctx, courseID, userID)
+	if err != nil {
+		return false, err
+	}
+	if staff != nil {
+		return true, nil
+	}
+	return u.enrollmentService.IsUserEnrolled(ctx, userID, courseID)
+}
+
 func (u *EnrollmentUseCase) GetStudentEnrollments(ctx context.Context, userID int) ([]*models.Enrollment, error) {
 	return u.enrollmentService.GetEnrollmentsByUser(ctx, userID)
 }
@@ -83,6 +287,42 @@ func (u *EnrollmentUseCase) GetCourseEnrollments(ctx context.Context, courseID i
 	return u.enrollmentService.GetEnrollmentsByCourse(ctx, courseID)
 }
 
+// DeleteEnrollment удаляет запись на курс; освободившееся место получает следующий из листа ожидания
 func (u *EnrollmentUseCase) DeleteEnrollment(ctx context.Context, id int) error {
-	return u.enrollmentService.DeleteEnrollment(ctx, id)
-}
\ No newline at end of file
+	enrollment, err := u.enrollmentService.GetEnrollmentByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := u.enrollmentService.DeleteEnrollment(ctx, id); err != nil {
+		return err
+	}
+	return u.promoteWaitlisted(ctx, enrollment.CourseID)
+}
+
+// checkPrerequisites проверяет, что студент завершил все пререквизиты курса и достиг нужного уровня
+func (u *EnrollmentUseCase) checkPrerequisites(ctx context.Context, userID int, course *models.Course) error {
+	missing, err := u.courseService.GetMissingPrerequisites(ctx, course.ID, userID)
+	if err != nil {
+		return err
+	}
+
+	userLevel := 0
+	if course.MinUserLevel > 0 {
+		user, err := u.userService.GetUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		userLevel = user.Level
+	}
+
+	if len(missing) == 0 && userLevel >= course.MinUserLevel {
+		return nil
+	}
+
+	return &PrerequisitesNotMetError{
+		CourseID:       course.ID,
+		MissingCourses: missing,
+		RequiredLevel:  course.MinUserLevel,
+		UserLevel:      userLevel,
+	}
+}
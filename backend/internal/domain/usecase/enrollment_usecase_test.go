@@ -0,0 +1,266 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+)
+
+func (m *MockEnrollmentService) Enroll(ctx context.Context, enrollment *models.Enrollment, joinWaitlist bool) (*models.EnrollmentResult, error) {
+	args := m.Called(ctx, enrollment, joinWaitlist)
+	return args.Get(0).(*models.EnrollmentResult), args.Error(1)
+}
+
+func (m *MockEnrollmentService) PromoteWaitlisted(ctx context.Context, courseID int) ([]*models.WaitlistEntry, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]*models.WaitlistEntry), args.Error(1)
+}
+
+func (m *MockEnrollmentService) GetEnrollmentByUserAndCourse(ctx context.Context, userID, courseID int) (*models.Enrollment, error) {
+	args := m.Called(ctx, userID, courseID)
+	return args.Get(0).(*models.Enrollment), args.Error(1)
+}
+
+func (m *MockEnrollmentService) DeleteEnrollment(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCourseService) GetMissingPrerequisites(ctx context.Context, courseID, userID int) ([]*models.CoursePrerequisite, error) {
+	args := m.Called(ctx, courseID, userID)
+	return args.Get(0).([]*models.CoursePrerequisite), args.Error(1)
+}
+
+func TestEnrollmentScheduling(t *testing.T) {
+	ctx := context.Background()
+	capacity := 1
+
+	t.Run("Enrollment window closed", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		enrollmentService := new(MockEnrollmentService)
+		useCase := usecase.NewEnrollmentUseCase(enrollmentService, courseService, nil, nil, "")
+
+		closed := time.Now().Add(-time.Hour)
+		courseService.On("GetCourse", ctx, 1).Return(&models.Course{ID: 1, Status: models.CourseStatusActive, EnrollmentClosesAt: &closed}, nil)
+
+		_, err := useCase.JoinCourse(ctx, 7, 1)
+
+		assert.EqualError(t, err, "enrollment is closed")
+		enrollmentService.AssertNotCalled(t, "Enroll", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Paid course requires an order", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		enrollmentService := new(MockEnrollmentService)
+		useCase := usecase.NewEnrollmentUseCase(enrollmentService, courseService, nil, nil, "")
+
+		courseService.On("GetCourse", ctx, 1).Return(&models.Course{ID: 1, Status: models.CourseStatusActive, PriceCents: 1000}, nil)
+
+		_, err := useCase.JoinCourse(ctx, 7, 1)
+
+		assert.ErrorIs(t, err, usecase.ErrPaymentRequired)
+		enrollmentService.AssertNotCalled(t, "Enroll", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Full course puts student on the waitlist", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		enrollmentService := new(MockEnrollmentService)
+		useCase := usecase.NewEnrollmentUseCase(enrollmentService, courseService, nil, nil, "")
+
+		courseService.On("GetCourse", ctx, 1).Return(&models.Course{ID: 1, Status: models.CourseStatusActive, Capacity: &capacity}, nil)
+		courseService.On("GetStaffMember", ctx, 1, 7).Return(nil, nil)
+		courseService.On("GetMissingPrerequisites", ctx, 1, 7).Return([]*models.CoursePrerequisite{}, nil)
+		enrollmentService.On("IsUserEnrolled", ctx, 7, 1).Return(false, nil)
+		enrollmentService.On("Enroll", ctx, mock.Anything, true).Return(&models.EnrollmentResult{
+			Status:   models.EnrollmentResultWaitlisted,
+			Waitlist: &models.WaitlistEntry{CourseID: 1, UserID: 7, Position: 2},
+		}, nil)
+
+		result, err := useCase.JoinCourse(ctx, 7, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.EnrollmentResultWaitlisted, result.Status)
+		assert.Equal(t, 2, result.Waitlist.Position)
+	})
+
+	t.Run("Full course without waitlist", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		enrollmentService := new(MockEnrollmentService)
+		useCase := usecase.NewEnrollmentUseCase(enrollmentService, courseService, nil, nil, "")
+
+		courseService.On("GetCourse", ctx, 1).Return(&models.Course{ID: 1, Status: models.CourseStatusActive, Capacity: &capacity}, nil)
+		courseService.On("GetStaffMember", ctx, 1, 7).Return(nil, nil)
+		courseService.On("GetMissingPrerequisites", ctx, 1, 7).Return([]*models.CoursePrerequisite{}, nil)
+		enrollmentService.On("IsUserEnrolled", ctx, 7, 1).Return(false, nil)
+		enrollmentService.On("Enroll", ctx, mock.Anything, false).Return(&models.EnrollmentResult{Status: models.EnrollmentResultFull}, nil)
+
+		err := useCase.EnrollStudent(ctx, 7, 1)
+
+		assert.ErrorIs(t, err, usecase.ErrCourseFull)
+	})
+
+	t.Run("Drop promotes and notifies the next student", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		enrollmentService := new(MockEnrollmentService)
+		userService := new(MockUserService)
+		mailer := new(MockMailer)
+		useCase := usecase.NewEnrollmentUseCase(enrollmentService, courseService, userService, mailer, "http://localhost:4200")
+
+		enrollmentService.On("GetEnrollmentByUserAndCourse", ctx, 7, 1).Return(&models.Enrollment{ID: 10, UserID: 7, CourseID: 1}, nil)
+		enrollmentService.On("DeleteEnrollment", ctx, 10).Return(nil)
+		enrollmentService.On("PromoteWaitlisted", ctx, 1).Return([]*models.WaitlistEntry{{CourseID: 1, UserID: 8}}, nil)
+		courseService.On("GetCourse", ctx, 1).Return(&models.Course{ID: 1, Name: "Go 101"}, nil)
+		userService.On("GetUser", ctx, 8).Return(&models.User{ID: 8, Username: "bob", Email: "bob@example.com"}, nil)
+		mailer.On("Send", "bob@example.com", "You are enrolled in Go 101", mock.MatchedBy(func(body string) bool {
+			return assert.Contains(t, body, "http://localhost:4200/courses/1")
+		})).Return(nil)
+
+		err := useCase.DropCourse(ctx, 7, 1)
+
+		assert.NoError(t, err)
+		mailer.AssertExpectations(t)
+	})
+}
+
+func TestEnrollmentPrerequisites(t *testing.T) {
+	ctx := context.Background()
+	basics := &models.CoursePrerequisite{CourseID: 2, PrerequisiteID: 1, Name: "Go Basics"}
+
+	t.Run("Missing prerequisite course", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		enrollmentService := new(MockEnrollmentService)
+		useCase := usecase.NewEnrollmentUseCase(enrollmentService, courseService, nil, nil, "")
+
+		courseService.On("GetCourse", ctx, 2).Return(&models.Course{ID: 2, Status: models.CourseStatusActive}, nil)
+		courseService.On("GetStaffMember", ctx, 2, 7).Return(nil, nil)
+		courseService.On("GetMissingPrerequisites", ctx, 2, 7).Return([]*models.CoursePrerequisite{basics}, nil)
+		enrollmentService.On("IsUserEnrolled", ctx, 7, 2).Return(false, nil)
+
+		_, err := useCase.JoinCourse(ctx, 7, 2)
+
+		var prerequisitesErr *usecase.PrerequisitesNotMetError
+		assert.True(t, errors.As(err, &prerequisitesErr))
+		assert.Equal(t, []*models.CoursePrerequisite{basics}, prerequisitesErr.MissingCourses)
+		assert.EqualError(t, err, `prerequisites not met: complete prerequisite courses "Go Basics"`)
+		enrollmentService.AssertNotCalled(t, "Enroll", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("User level too low", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		enrollmentService := new(MockEnrollmentService)
+		userService := new(MockUserService)
+		useCase := usecase.NewEnrollmentUseCase(enrollmentService, courseService, userService, nil, "")
+
+		courseService.On("GetCourse", ctx, 2).Return(&models.Course{ID: 2, Status: models.CourseStatusActive, MinUserLevel: 3}, nil)
+		courseService.On("GetStaffMember", ctx, 2, 7).Return(nil, nil)
+		courseService.On("GetMissingPrerequisites", ctx, 2, 7).Return([]*models.CoursePrerequisite{}, nil)
+		enrollmentService.On("IsUserEnrolled", ctx, 7, 2).Return(false, nil)
+		userService.On("GetUser", ctx, 7).Return(&models.User{ID: 7, Level: 1}, nil)
+
+		err := useCase.EnrollStudent(ctx, 7, 2)
+
+		var prerequisitesErr *usecase.PrerequisitesNotMetError
+		assert.True(t, errors.As(err, &prerequisitesErr))
+		assert.Equal(t, 3, prerequisitesErr.RequiredLevel)
+		assert.Equal(t, 1, prerequisitesErr.UserLevel)
+		assert.EqualError(t, err, "prerequisites not met: reach level 3 (current level 1)")
+		enrollmentService.AssertNotCalled(t, "Enroll", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Prerequisites met", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		enrollmentService := new(MockEnrollmentService)
+		userService := new(MockUserService)
+		useCase := usecase.NewEnrollmentUseCase(enrollmentService, courseService, userService, nil, "")
+
+		courseService.On("GetCourse", ctx, 2).Return(&models.Course{ID: 2, Status: models.CourseStatusActive, MinUserLevel: 3}, nil)
+		courseService.On("GetStaffMember", ctx, 2, 7).Return(nil, nil)
+		courseService.On("GetMissingPrerequisites", ctx, 2, 7).Return([]*models.CoursePrerequisite{}, nil)
+		enrollmentService.On("IsUserEnrolled", ctx, 7, 2).Return(false, nil)
+		userService.On("GetUser", ctx, 7).Return(&models.User{ID: 7, Level: 3}, nil)
+		enrollmentService.On("Enroll", ctx, mock.Anything, false).Return(&models.EnrollmentResult{Status: models.EnrollmentResultEnrolled}, nil)
+
+		err := useCase.EnrollStudent(ctx, 7, 2)
+
+		assert.NoError(t, err)
+		enrollmentService.AssertExpectations(t)
+	})
+
+	t.Run("Admin enrollment checks prerequisites by default", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		enrollmentService := new(MockEnrollmentService)
+		userService := new(MockUserService)
+		useCase := usecase.NewEnrollmentUseCase(enrollmentService, courseService, userService, nil, "")
+
+		userService.On("GetUser", ctx, 7).Return(&models.User{ID: 7}, nil)
+		courseService.On("GetCourse", ctx, 2).Return(&models.Course{ID: 2, Status: models.CourseStatusActive}, nil)
+		courseService.On("GetStaffMember", ctx, 2, 7).Return(nil, nil)
+		courseService.On("GetMissingPrerequisites", ctx, 2, 7).Return([]*models.CoursePrerequisite{basics}, nil)
+		enrollmentService.On("IsUserEnrolled", ctx, 7, 2).Return(false, nil)
+
+		err := useCase.AdminEnrollStudent(ctx, 7, 2, false)
+
+		var prerequisitesErr *usecase.PrerequisitesNotMetError
+		assert.True(t, errors.As(err, &prerequisitesErr))
+		enrollmentService.AssertNotCalled(t, "Enroll", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Admin override skips prerequisites but not the window", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		enrollmentService := new(MockEnrollmentService)
+		userService := new(MockUserService)
+		useCase := usecase.NewEnrollmentUseCase(enrollmentService, courseService, userService, nil, "")
+
+		closed := time.Now().Add(-time.Hour)
+		userService.On("GetUser", ctx, 7).Return(&models.User{ID: 7}, nil)
+		courseService.On("GetCourse", ctx, 2).Return(&models.Course{ID: 2, Status: models.CourseStatusActive, MinUserLevel: 5, EnrollmentClosesAt: &closed}, nil)
+		courseService.On("GetStaffMember", ctx, 2, 7).Return(nil, nil)
+		enrollmentService.On("IsUserEnrolled", ctx, 7, 2).Return(false, nil)
+		enrollmentService.On("Enroll", ctx, mock.MatchedBy(func(enrollment *models.Enrollment) bool {
+			return enrollment.UserID == 7 && enrollment.CourseID == 2 && enrollment.Status == "active"
+		}), false).Return(&models.EnrollmentResult{Status: models.EnrollmentResultEnrolled}, nil)
+
+		err := useCase.AdminEnrollStudent(ctx, 7, 2, true)
+
+		assert.NoError(t, err)
+		enrollmentService.AssertExpectations(t)
+		courseService.AssertNotCalled(t, "GetMissingPrerequisites", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Admin override still respects capacity", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		enrollmentService := new(MockEnrollmentService)
+		userService := new(MockUserService)
+		useCase := usecase.NewEnrollmentUseCase(enrollmentService, courseService, userService, nil, "")
+
+		userService.On("GetUser", ctx, 7).Return(&models.User{ID: 7}, nil)
+		courseService.On("GetCourse", ctx, 2).Return(&models.Course{ID: 2, Status: models.CourseStatusActive}, nil)
+		courseService.On("GetStaffMember", ctx, 2, 7).Return(nil, nil)
+		enrollmentService.On("IsUserEnrolled", ctx, 7, 2).Return(false, nil)
+		enrollmentService.On("Enroll", ctx, mock.Anything, false).Return(&models.EnrollmentResult{Status: models.EnrollmentResultFull}, nil)
+
+		err := useCase.AdminEnrollStudent(ctx, 7, 2, true)
+
+		assert.ErrorIs(t, err, usecase.ErrCourseFull)
+	})
+
+	t.Run("Admin enrollment of unknown user", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		userService := new(MockUserService)
+		useCase := usecase.NewEnrollmentUseCase(nil, courseService, userService, nil, "")
+
+		userService.On("GetUser", ctx, 99).Return(nil, errors.New("no rows"))
+
+		err := useCase.AdminEnrollStudent(ctx, 99, 2, true)
+
+		assert.EqualError(t, err, "user not found")
+		courseService.AssertNotCalled(t, "GetCourse", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+)
+
+// ErrPermissionDenied возвращается, когда у пользователя нет прав на действие.
+// Хендлеры сопоставляют её с 403 Forbidden.
+var ErrPermissionDenied = errors.New("permission denied")
+
+// ErrCourseFull возвращается, когда на курсе не осталось мест, а лист ожидания не используется
+var ErrCourseFull = errors.New("course is full")
+
+// ErrPaymentRequired возвращается при попытке записаться на платный курс без оплаченного заказа
+var ErrPaymentRequired = errors.New("payment required")
+
+// ErrFileTooLarge возвращается, если загружаемый файл больше допустимого размера
+var ErrFileTooLarge = errors.New("file is too large")
+
+// ErrQuotaExceeded возвращается, если файл не помещается в квоту пользователя
+var ErrQuotaExceeded = errors.New("storage quota exceeded")
+
+// ErrLessonRequirementsNotMet возвращается, если студент ещё не выполнил условия завершения урока
+// (например, не досмотрел видео). Хендлеры сопоставляют её с 409 Conflict.
+var ErrLessonRequirementsNotMet = errors.New("lesson completion requirements are not met")
+
+// ErrLessonAlreadyCompleted возвращается при повторном завершении урока; автоматическое
+// завершение (блоки, просмотр видео) считает её успехом
+var ErrLessonAlreadyCompleted = errors.New("lesson is already completed")
+
+// PrerequisitesNotMetError описывает, чего не хватает студенту для записи на курс
+type PrerequisitesNotMetError struct {
+	CourseID       int                          `json:"course_id"`
+	MissingCourses []*models.CoursePrerequisite `json:"missing_courses"`
+	RequiredLevel  int                          `json:"required_level,omitempty"`
+	UserLevel      int                          `json:"user_level"`
+}
+
+func (e *PrerequisitesNotMetError) Error() string {
+	var missing []string
+	for _, course := range e.MissingCourses {
+		missing = append(missing, fmt.Sprintf("%q", course.Name))
+	}
+
+	var reasons []string
+	if len(missing) > 0 {
+		reasons = append(reasons, "complete prerequisite courses "+strings.Join(missing, ", "))
+	}
+	if e.RequiredLevel > e.UserLevel {
+		reasons = append(reasons, fmt.Sprintf("reach level %d (current level %d)", e.RequiredLevel, e.UserLevel))
+	}
+	return "prerequisites not met: " + strings.Join(reasons, " and ")
+}
@@ -0,0 +1,399 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+type GroupUseCaseInterface interface {
+	CreateGroup(ctx context.Context, userID int, input *dto.CreateGroupRequest) (*models.Group, error)
+	GetGroup(ctx context.Context, userID int, userRole string, groupID int) (*models.Group, error)
+	GetGroups(ctx context.Context, userID int, userRole string) ([]*models.Group, error)
+	UpdateGroup(ctx context.Context, userID int, userRole string, groupID int, input *dto.CreateGroupRequest) (*models.Group, error)
+	DeleteGroup(ctx context.Context, userID int, userRole string, groupID int) error
+	AddMember(ctx context.Context, userID int, userRole string, groupID int, input *dto.GroupMemberRequest) (*models.GroupMember, []*models.BulkEnrollmentResult, error)
+	RemoveMember(ctx context.Context, userID int, userRole string, groupID, memberID int) error
+	GetMembers(ctx context.Context, userID int, userRole string, groupID int) ([]*models.GroupMember, error)
+	EnrollGroup(ctx context.Context, userID int, userRole string, groupID, courseID int) ([]*models.BulkEnrollmentResult, error)
+	RemoveCourse(ctx context.Context, userID int, userRole string, groupID, courseID int) error
+	GetCourses(ctx context.Context, userID int, userRole string, groupID int) ([]*models.GroupCourse, error)
+	GetProgressReport(ctx context.Context, userID int, userRole string, groupID, courseID int) (*models.GroupProgressReport, error)
+}
+
+type GroupUseCase struct {
+	groupService      services.GroupServiceInterface
+	userService       services.UserServiceInterface
+	courseService     services.CourseServiceInterface
+	lessonService     services.LessonServiceInterface
+	enrollmentService services.EnrollmentServiceInterface
+	enrollment        EnrollmentUseCaseInterface
+}
+
+func NewGroupUseCase(
+	groupService services.GroupServiceInterface,
+	userService services.UserServiceInterface,
+	courseService services.CourseServiceInterface,
+	lessonService services.LessonServiceInterface,
+	enrollmentService services.EnrollmentServiceInterface,
+	enrollment EnrollmentUseCaseInterface,
+) *GroupUseCase {
+	return &GroupUseCase{
+		groupService:      groupService,
+		userService:       userService,
+		courseService:     courseService,
+		lessonService:     lessonService,
+		enrollmentService: enrollmentService,
+		enrollment:        enrollment,
+	}
+}
+
+// CreateGroup создаёт группу, создатель становится её владельцем
+func (u *GroupUseCase) CreateGroup(ctx context.Context, userID int, input *dto.CreateGroupRequest) (*models.Group, error) {
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	group, err := u.groupService.CreateGroup(ctx, &models.Group{
+		Name:        input.Name,
+		Description: input.Description,
+		CreatedBy:   userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := u.groupService.AddMember(ctx, group.ID, userID, models.GroupRoleOwner); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// GetGroup возвращает группу участнику группы или администратору
+func (u *GroupUseCase) GetGroup(ctx context.Context, userID int, userRole string, groupID int) (*models.Group, error) {
+	group, err := u.groupService.GetGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if userRole == "admin" {
+		return group, nil
+	}
+
+	member, err := u.groupService.GetMember(ctx, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, fmt.Errorf("%w: you are not a member of this group", ErrPermissionDenied)
+	}
+	return group, nil
+}
+
+// GetGroups возвращает все группы для администратора и группы пользователя для остальных
+func (u *GroupUseCase) GetGroups(ctx context.Context, userID int, userRole string) ([]*models.Group, error) {
+	if userRole == "admin" {
+		return u.groupService.GetAllGroups(ctx)
+	}
+	return u.groupService.GetGroupsByUser(ctx, userID)
+}
+
+// UpdateGroup меняет название и описание группы
+func (u *GroupUseCase) UpdateGroup(ctx context.Context, userID int, userRole string, groupID int, input *dto.CreateGroupRequest) (*models.Group, error) {
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := u.checkGroupOwner(ctx, groupID, userID, userRole); err != nil {
+		return nil, err
+	}
+
+	group, err := u.groupService.GetGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	group.Name = input.Name
+	group.Description = input.Description
+
+	if err := u.groupService.UpdateGroup(ctx, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// DeleteGroup удаляет группу, зачисления участников на курсы сохраняются
+func (u *GroupUseCase) DeleteGroup(ctx context.Context, userID int, userRole string, groupID int) error {
+	if err := u.checkGroupOwner(ctx, groupID, userID, userRole); err != nil {
+		return err
+	}
+	return u.groupService.DeleteGroup(ctx, groupID)
+}
+
+// AddMember добавляет пользователя в группу. Новый студент автоматически
+// зачисляется на все курсы группы, которые добавляющий может выдавать группе (см. checkGroupCourse).
+func (u *GroupUseCase) AddMember(ctx context.Context, userID int, userRole string, groupID int, input *dto.GroupMemberRequest) (*models.GroupMember, []*models.BulkEnrollmentResult, error) {
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return nil, nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := u.checkGroupOwner(ctx, groupID, userID, userRole); err != nil {
+		return nil, nil, err
+	}
+
+	user, err := u.userService.GetUser(ctx, input.UserID)
+	if err != nil {
+		return nil, nil, errors.New("user not found")
+	}
+
+	role := input.Role
+	if role == "" {
+		role = models.GroupRoleMember
+	}
+	if role == models.GroupRoleOwner && user.Role != "teacher" && user.Role != "admin" {
+		return nil, nil, errors.New("only teachers can be group owners")
+	}
+
+	existing, err := u.groupService.GetMember(ctx, groupID, user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if existing != nil && existing.Role == models.GroupRoleOwner && role != models.GroupRoleOwner {
+		if err := u.checkOtherOwners(ctx, groupID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	member, err := u.groupService.AddMember(ctx, groupID, user.ID, role)
+	if err != nil {
+		return nil, nil, err
+	}
+	member.Username = user.Username
+	member.Name = user.Name
+	member.Surname = user.Surname
+
+	if role != models.GroupRoleMember {
+		return member, nil, nil
+	}
+
+	groupCourses, err := u.groupService.GetCourses(ctx, groupID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var results []*models.BulkEnrollmentResult
+	for _, groupCourse := range groupCourses {
+		if err := u.checkGroupCourse(ctx, userID, userRole, groupCourse.CourseID); err != nil {
+			results = append(results, &models.BulkEnrollmentResult{UserID: user.ID, CourseID: groupCourse.CourseID, Error: err.Error()})
+			continue
+		}
+		results = append(results, u.enrollMember(ctx, user.ID, groupCourse.CourseID))
+	}
+
+	return member, results, nil
+}
+
+// RemoveMember исключает пользователя из группы
+func (u *GroupUseCase) RemoveMember(ctx context.Context, userID int, userRole string, groupID, memberID int) error {
+	if err := u.checkGroupOwner(ctx, groupID, userID, userRole); err != nil {
+		return err
+	}
+
+	member, err := u.groupService.GetMember(ctx, groupID, memberID)
+	if err != nil {
+		return err
+	}
+	if member == nil {
+		return errors.New("user is not a member of this group")
+	}
+
+	if member.Role == models.GroupRoleOwner {
+		if err := u.checkOtherOwners(ctx, groupID); err != nil {
+			return err
+		}
+	}
+
+	return u.groupService.RemoveMember(ctx, groupID, memberID)
+}
+
+// checkOtherOwners не даёт исключить или понизить последнего владельца группы
+func (u *GroupUseCase) checkOtherOwners(ctx context.Context, groupID int) error {
+	members, err := u.groupService.GetMembers(ctx, groupID)
+	if err != nil {
+		return err
+	}
+	owners := 0
+	for _, m := range members {
+		if m.Role == models.GroupRoleOwner {
+			owners++
+		}
+	}
+	if owners <= 1 {
+		return errors.New("group must have at least one owner")
+	}
+	return nil
+}
+
+// GetMembers возвращает состав группы
+func (u *GroupUseCase) GetMembers(ctx context.Context, userID int, userRole string, groupID int) ([]*models.GroupMember, error) {
+	if err := u.checkGroupOwner(ctx, groupID, userID, userRole); err != nil {
+		return nil, err
+	}
+	return u.groupService.GetMembers(ctx, groupID)
+}
+
+// EnrollGroup привязывает курс к группе и зачисляет на него всех студентов группы.
+// Ошибка зачисления одного студента не прерывает зачисление остальных.
+func (u *GroupUseCase) EnrollGroup(ctx context.Context, userID int, userRole string, groupID, courseID int) ([]*models.BulkEnrollmentResult, error) {
+	if err := u.checkGroupOwner(ctx, groupID, userID, userRole); err != nil {
+		return nil, err
+	}
+	if err := u.checkGroupCourse(ctx, userID, userRole, courseID); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.groupService.AddCourse(ctx, groupID, courseID); err != nil {
+		return nil, err
+	}
+
+	members, err := u.groupService.GetMembers(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*models.BulkEnrollmentResult{}
+	for _, member := range members {
+		if member.Role != models.GroupRoleMember {
+			continue
+		}
+		results = append(results, u.enrollMember(ctx, member.UserID, courseID))
+	}
+
+	return results, nil
+}
+
+// RemoveCourse отвязывает курс от группы, уже созданные зачисления не удаляются
+func (u *GroupUseCase) RemoveCourse(ctx context.Context, userID int, userRole string, groupID, courseID int) error {
+	if err := u.checkGroupOwner(ctx, groupID, userID, userRole); err != nil {
+		return err
+	}
+	return u.groupService.RemoveCourse(ctx, groupID, courseID)
+}
+
+// GetCourses возвращает курсы группы
+func (u *GroupUseCase) GetCourses(ctx context.Context, userID int, userRole string, groupID int) ([]*models.GroupCourse, error) {
+	if _, err := u.GetGroup(ctx, userID, userRole, groupID); err != nil {
+		return nil, err
+	}
+	return u.groupService.GetCourses(ctx, groupID)
+}
+
+// GetProgressReport собирает прогресс студентов группы по курсу на основе lesson_progress.
+// Прогресс по курсу видит только его команда: владелец группы сам выбирает её участников.
+func (u *GroupUseCase) GetProgressReport(ctx context.Context, userID int, userRole string, groupID, courseID int) (*models.GroupProgressReport, error) {
+	if err := u.checkGroupOwner(ctx, groupID, userID, userRole); err != nil {
+		return nil, err
+	}
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseStaffRoles); err != nil {
+		return nil, err
+	}
+
+	lessons, err := u.lessonService.GetAllLessons(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	progresses, err := u.groupService.GetMemberProgress(ctx, groupID, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.GroupProgressReport{
+		GroupID:      groupID,
+		CourseID:     courseID,
+		TotalLessons: len(lessons),
+		MembersCount: len(progresses),
+		Members:      progresses,
+	}
+	if report.Members == nil {
+		report.Members = []*models.GroupMemberProgress{}
+	}
+
+	var total float64
+	for _, progress := range progresses {
+		if len(lessons) > 0 {
+			progress.Progress = float64(progress.CompletedLessons) / float64(len(lessons)) * 100
+		}
+		if progress.EnrollmentStatus == "completed" {
+			report.CompletedCount++
+		}
+		total += progress.Progress
+	}
+	if len(progresses) > 0 {
+		report.AverageProgress = total / float64(len(progresses))
+	}
+
+	return report, nil
+}
+
+// checkGroupCourse проверяет, что пользователь может зачислять группу на курс: это преподаватель курса
+// (владелец или соавтор) или администратор. Платный курс группе не выдаётся - каждый студент оплачивает его сам.
+func (u *GroupUseCase) checkGroupCourse(ctx context.Context, userID int, userRole string, courseID int) error {
+	course, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseEditorRoles)
+	if err != nil {
+		return err
+	}
+	if course.IsPaid() {
+		return fmt.Errorf("%w: paid courses cannot be assigned to a group", ErrPaymentRequired)
+	}
+	return nil
+}
+
+// enrollMember зачисляет студента на курс через общие правила зачисления
+func (u *GroupUseCase) enrollMember(ctx context.Context, memberID, courseID int) *models.BulkEnrollmentResult {
+	result := &models.BulkEnrollmentResult{UserID: memberID, CourseID: courseID}
+
+	enrolled, err := u.enrollmentService.IsUserEnrolled(ctx, memberID, courseID)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if enrolled {
+		result.Enrolled = true
+		result.AlreadyEnrolled = true
+		return result
+	}
+
+	if err := u.enrollment.EnrollStudent(ctx, memberID, courseID); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Enrolled = true
+	return result
+}
+
+// checkGroupOwner проверяет, что пользователь владеет группой или является администратором
+func (u *GroupUseCase) checkGroupOwner(ctx context.Context, groupID, userID int, userRole string) error {
+	if _, err := u.groupService.GetGroup(ctx, groupID); err != nil {
+		return err
+	}
+	if userRole == "admin" {
+		return nil
+	}
+
+	member, err := u.groupService.GetMember(ctx, groupID, userID)
+	if err != nil {
+		return err
+	}
+	if member == nil || member.Role != models.GroupRoleOwner {
+		return fmt.Errorf("%w: only group owners can manage the group", ErrPermissionDenied)
+	}
+	return nil
+}
@@ -0,0 +1,292 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+// Mock для GroupService
+type MockGroupService struct {
+	mock.Mock
+	services.GroupServiceInterface
+}
+
+func (m *MockGroupService) GetGroup(ctx context.Context, id int) (*models.Group, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Group), args.Error(1)
+}
+
+func (m *MockGroupService) GetMember(ctx context.Context, groupID, userID int) (*models.GroupMember, error) {
+	args := m.Called(ctx, groupID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GroupMember), args.Error(1)
+}
+
+func (m *MockGroupService) GetMembers(ctx context.Context, groupID int) ([]*models.GroupMember, error) {
+	args := m.Called(ctx, groupID)
+	return args.Get(0).([]*models.GroupMember), args.Error(1)
+}
+
+func (m *MockGroupService) AddCourse(ctx context.Context, groupID, courseID int) (*models.GroupCourse, error) {
+	args := m.Called(ctx, groupID, courseID)
+	return args.Get(0).(*models.GroupCourse), args.Error(1)
+}
+
+// Mock для CourseService
+type MockCourseService struct {
+	mock.Mock
+	services.CourseServiceInterface
+}
+
+func (m *MockCourseService) GetCourse(ctx context.Context, id int) (*models.Course, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Course), args.Error(1)
+}
+
+// Mock для EnrollmentService
+type MockEnrollmentService struct {
+	mock.Mock
+	services.EnrollmentServiceInterface
+}
+
+func (m *MockEnrollmentService) IsUserEnrolled(ctx context.Context, userID, courseID int) (bool, error) {
+	args := m.Called(ctx, userID, courseID)
+	return args.Bool(0), args.Error(1)
+}
+
+// Mock для EnrollmentUseCase
+type MockEnrollmentUseCase struct {
+	mock.Mock
+	usecase.EnrollmentUseCaseInterface
+}
+
+func (m *MockEnrollmentUseCase) EnrollStudent(ctx context.Context, userID, courseID int) error {
+	args := m.Called(ctx, userID, courseID)
+	return args.Error(0)
+}
+
+func TestEnrollGroup(t *testing.T) {
+	ctx := context.Background()
+	groupID, courseID, ownerID := 1, 10, 100
+
+	t.Run("Enrolls members and skips owners", func(t *testing.T) {
+		groupService := new(MockGroupService)
+		courseService := new(MockCourseService)
+		enrollmentService := new(MockEnrollmentService)
+		enrollment := new(MockEnrollmentUseCase)
+		useCase := usecase.NewGroupUseCase(groupService, nil, courseService, nil, enrollmentService, enrollment)
+
+		groupService.On("GetGroup", ctx, groupID).Return(&models.Group{ID: groupID}, nil)
+		groupService.On("GetMember", ctx, groupID, ownerID).Return(&models.GroupMember{UserID: ownerID, Role: models.GroupRoleOwner}, nil)
+		courseService.On("GetCourse", ctx, courseID).Return(&models.Course{ID: courseID}, nil)
+		courseService.On("GetStaffMember", ctx, courseID, ownerID).Return(&models.CourseStaff{UserID: ownerID, Role: models.CourseStaffRoleCoTeacher}, nil)
+		groupService.On("AddCourse", ctx, groupID, courseID).Return(&models.GroupCourse{GroupID: groupID, CourseID: courseID}, nil)
+		groupService.On("GetMembers", ctx, groupID).Return([]*models.GroupMember{
+			{UserID: ownerID, Role: models.GroupRoleOwner},
+			{UserID: 1, Role: models.GroupRoleMember},
+			{UserID: 2, Role: models.GroupRoleMember},
+			{UserID: 3, Role: models.GroupRoleMember},
+		}, nil)
+
+		enrollmentService.On("IsUserEnrolled", ctx, 1, courseID).Return(false, nil)
+		enrollmentService.On("IsUserEnrolled", ctx, 2, courseID).Return(true, nil)
+		enrollmentService.On("IsUserEnrolled", ctx, 3, courseID).Return(false, nil)
+		enrollment.On("EnrollStudent", ctx, 1, courseID).Return(nil)
+		enrollment.On("EnrollStudent", ctx, 3, courseID).Return(errors.New("course is full"))
+
+		results, err := useCase.EnrollGroup(ctx, ownerID, "teacher", groupID, courseID)
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 3)
+		assert.True(t, results[0].Enrolled)
+		assert.True(t, results[1].AlreadyEnrolled)
+		assert.False(t, results[2].Enrolled)
+		assert.Equal(t, "course is full", results[2].Error)
+		enrollment.AssertNotCalled(t, "EnrollStudent", ctx, ownerID, courseID)
+		enrollment.AssertNotCalled(t, "EnrollStudent", ctx, 2, courseID)
+	})
+
+	t.Run("Group owner must teach the course", func(t *testing.T) {
+		groupService := new(MockGroupService)
+		courseService := new(MockCourseService)
+		useCase := usecase.NewGroupUseCase(groupService, nil, courseService, nil, nil, nil)
+
+		groupService.On("GetGroup", ctx, groupID).Return(&models.Group{ID: groupID}, nil)
+		groupService.On("GetMember", ctx, groupID, ownerID).Return(&models.GroupMember{UserID: ownerID, Role: models.GroupRoleOwner}, nil)
+		courseService.On("GetCourse", ctx, courseID).Return(&models.Course{ID: courseID}, nil)
+		courseService.On("GetStaffMember", ctx, courseID, ownerID).Return(nil, nil)
+
+		results, err := useCase.EnrollGroup(ctx, ownerID, "teacher", groupID, courseID)
+
+		assert.Nil(t, results)
+		assert.ErrorIs(t, err, usecase.ErrPermissionDenied)
+		groupService.AssertNotCalled(t, "AddCourse", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Paid course is rejected even for admins", func(t *testing.T) {
+		groupService := new(MockGroupService)
+		courseService := new(MockCourseService)
+		useCase := usecase.NewGroupUseCase(groupService, nil, courseService, nil, nil, nil)
+
+		groupService.On("GetGroup", ctx, groupID).Return(&models.Group{ID: groupID}, nil)
+		courseService.On("GetCourse", ctx, courseID).Return(&models.Course{ID: courseID, PriceCents: 4900}, nil)
+
+		results, err := useCase.EnrollGroup(ctx, 1, "admin", groupID, courseID)
+
+		assert.Nil(t, results)
+		assert.ErrorIs(t, err, usecase.ErrPaymentRequired)
+		groupService.AssertNotCalled(t, "AddCourse", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Non-owner is rejected", func(t *testing.T) {
+		groupService := new(MockGroupService)
+		useCase := usecase.NewGroupUseCase(groupService, nil, nil, nil, nil, nil)
+
+		groupService.On("GetGroup", ctx, groupID).Return(&models.Group{ID: groupID}, nil)
+		groupService.On("GetMember", ctx, groupID, 5).Return(&models.GroupMember{UserID: 5, Role: models.GroupRoleMember}, nil)
+
+		results, err := useCase.EnrollGroup(ctx, 5, "teacher", groupID, courseID)
+
+		assert.Nil(t, results)
+		assert.ErrorIs(t, err, usecase.ErrPermissionDenied)
+	})
+}
+
+func TestAddMemberCannotDemoteLastOwner(t *testing.T) {
+	ctx := context.Background()
+	groupID, ownerID := 1, 100
+
+	groupService := new(MockGroupService)
+	userService := new(MockUserService)
+	useCase := usecase.NewGroupUseCase(groupService, userService, nil, nil, nil, nil)
+
+	groupService.On("GetGroup", ctx, groupID).Return(&models.Group{ID: groupID}, nil)
+	groupService.On("GetMember", ctx, groupID, ownerID).Return(&models.GroupMember{GroupID: groupID, UserID: ownerID, Role: models.GroupRoleOwner}, nil)
+	groupService.On("GetMembers", ctx, groupID).Return([]*models.GroupMember{
+		{UserID: ownerID, Role: models.GroupRoleOwner},
+		{UserID: 1, Role: models.GroupRoleMember},
+	}, nil)
+	userService.On("GetUser", ctx, ownerID).Return(&models.User{ID: ownerID, Role: "teacher"}, nil)
+
+	member, results, err := useCase.AddMember(ctx, ownerID, "teacher", groupID, &dto.GroupMemberRequest{UserID: ownerID, Role: models.GroupRoleMember})
+
+	assert.Nil(t, member)
+	assert.Nil(t, results)
+	assert.EqualError(t, err, "group must have at least one owner")
+	groupService.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (m *MockGroupService) AddMember(ctx context.Context, groupID, userID int, role string) (*models.GroupMember, error) {
+	args := m.Called(ctx, groupID, userID, role)
+	return args.Get(0).(*models.GroupMember), args.Error(1)
+}
+
+func (m *MockGroupService) GetMemberProgress(ctx context.Context, groupID, courseID int) ([]*models.GroupMemberProgress, error) {
+	args := m.Called(ctx, groupID, courseID)
+	return args.Get(0).([]*models.GroupMemberProgress), args.Error(1)
+}
+
+func TestAddMemberEnrollsOnlyIntoAllowedCourses(t *testing.T) {
+	ctx := context.Background()
+	groupID, ownerID, studentID := 1, 100, 7
+	taught, foreign, paid := 10, 11, 12
+
+	groupService := new(MockGroupService)
+	userService := new(MockUserService)
+	courseService := new(MockCourseService)
+	enrollmentService := new(MockEnrollmentService)
+	enrollment := new(MockEnrollmentUseCase)
+	useCase := usecase.NewGroupUseCase(groupService, userService, courseService, nil, enrollmentService, enrollment)
+
+	groupService.On("GetGroup", ctx, groupID).Return(&models.Group{ID: groupID}, nil)
+	groupService.On("GetMember", ctx, groupID, ownerID).Return(&models.GroupMember{UserID: ownerID, Role: models.GroupRoleOwner}, nil)
+	groupService.On("GetMember", ctx, groupID, studentID).Return(nil, nil)
+	userService.On("GetUser", ctx, studentID).Return(&models.User{ID: studentID, Role: "student"}, nil)
+	groupService.On("AddMember", ctx, groupID, studentID, models.GroupRoleMember).Return(&models.GroupMember{GroupID: groupID, UserID: studentID, Role: models.GroupRoleMember}, nil)
+	// курс, привязанный другим владельцем группы, и курс, ставший платным после привязки
+	groupService.On("GetCourses", ctx, groupID).Return([]*models.GroupCourse{
+		{GroupID: groupID, CourseID: taught},
+		{GroupID: groupID, CourseID: foreign},
+		{GroupID: groupID, CourseID: paid},
+	}, nil)
+	courseService.On("GetCourse", ctx, taught).Return(&models.Course{ID: taught}, nil)
+	courseService.On("GetCourse", ctx, foreign).Return(&models.Course{ID: foreign}, nil)
+	courseService.On("GetCourse", ctx, paid).Return(&models.Course{ID: paid, PriceCents: 1000}, nil)
+	courseService.On("GetStaffMember", ctx, taught, ownerID).Return(&models.CourseStaff{UserID: ownerID, Role: models.CourseStaffRoleOwner}, nil)
+	courseService.On("GetStaffMember", ctx, foreign, ownerID).Return(nil, nil)
+	courseService.On("GetStaffMember", ctx, paid, ownerID).Return(&models.CourseStaff{UserID: ownerID, Role: models.CourseStaffRoleOwner}, nil)
+	enrollmentService.On("IsUserEnrolled", ctx, studentID, taught).Return(false, nil)
+	enrollment.On("EnrollStudent", ctx, studentID, taught).Return(nil)
+
+	member, results, err := useCase.AddMember(ctx, ownerID, "teacher", groupID, &dto.GroupMemberRequest{UserID: studentID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, studentID, member.UserID)
+	assert.Len(t, results, 3)
+	assert.True(t, results[0].Enrolled)
+	assert.Equal(t, foreign, results[1].CourseID)
+	assert.False(t, results[1].Enrolled)
+	assert.Contains(t, results[1].Error, "permission denied")
+	assert.Equal(t, paid, results[2].CourseID)
+	assert.Contains(t, results[2].Error, "payment required")
+	enrollment.AssertNotCalled(t, "EnrollStudent", ctx, studentID, foreign)
+	enrollment.AssertNotCalled(t, "EnrollStudent", ctx, studentID, paid)
+}
+
+func TestGetProgressReportRequiresCourseStaff(t *testing.T) {
+	ctx := context.Background()
+	groupID, courseID, ownerID := 1, 10, 100
+
+	t.Run("Group owner outside the course", func(t *testing.T) {
+		groupService := new(MockGroupService)
+		courseService := new(MockCourseService)
+		useCase := usecase.NewGroupUseCase(groupService, nil, courseService, nil, nil, nil)
+
+		groupService.On("GetGroup", ctx, groupID).Return(&models.Group{ID: groupID}, nil)
+		groupService.On("GetMember", ctx, groupID, ownerID).Return(&models.GroupMember{UserID: ownerID, Role: models.GroupRoleOwner}, nil)
+		courseService.On("GetCourse", ctx, courseID).Return(&models.Course{ID: courseID}, nil)
+		courseService.On("GetStaffMember", ctx, courseID, ownerID).Return(nil, nil)
+
+		report, err := useCase.GetProgressReport(ctx, ownerID, "teacher", groupID, courseID)
+
+		assert.Nil(t, report)
+		assert.ErrorIs(t, err, usecase.ErrPermissionDenied)
+		groupService.AssertNotCalled(t, "GetMemberProgress", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Teaching assistant of the course", func(t *testing.T) {
+		groupService := new(MockGroupService)
+		courseService := new(MockCourseService)
+		lessonService := new(MockLessonService)
+		useCase := usecase.NewGroupUseCase(groupService, nil, courseService, lessonService, nil, nil)
+
+		groupService.On("GetGroup", ctx, groupID).Return(&models.Group{ID: groupID}, nil)
+		groupService.On("GetMember", ctx, groupID, ownerID).Return(&models.GroupMember{UserID: ownerID, Role: models.GroupRoleOwner}, nil)
+		courseService.On("GetCourse", ctx, courseID).Return(&models.Course{ID: courseID}, nil)
+		courseService.On("GetStaffMember", ctx, courseID, ownerID).Return(&models.CourseStaff{UserID: ownerID, Role: models.CourseStaffRoleTA}, nil)
+		lessonService.On("GetAllLessons", ctx, courseID).Return([]*models.Lesson{{ID: 1}, {ID: 2}}, nil)
+		groupService.On("GetMemberProgress", ctx, groupID, courseID).Return([]*models.GroupMemberProgress{{UserID: 7, CompletedLessons: 1}}, nil)
+
+		report, err := useCase.GetProgressReport(ctx, ownerID, "teacher", groupID, courseID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 50.0, report.AverageProgress)
+	})
+}
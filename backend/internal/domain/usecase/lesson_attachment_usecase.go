@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services/pdfgen"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+	"gitlab.com/w0ikid/study-platform/pkg/storage"
+)
+
+type LessonAttachmentUseCaseInterface interface {
+	GetAttachments(ctx context.Context, courseID, lessonID int) ([]*models.LessonAttachment, error)
+	UploadAttachment(ctx context.Context, userID int, userRole string, courseID, lessonID int, input *dto.LessonAttachmentUploadRequest, filename string, size int64, r io.Reader) (*models.LessonAttachment, error)
+	UpdateAttachment(ctx context.Context, userID int, userRole string, courseID, lessonID, attachmentID int, input *dto.UpdateLessonAttachmentRequest) (*models.LessonAttachment, error)
+	DeleteAttachment(ctx context.Context, userID int, userRole string, courseID, lessonID, attachmentID int) error
+	DownloadAttachment(ctx context.Context, userID, courseID, lessonID, attachmentID int) (*models.LessonAttachment, io.ReadCloser, error)
+}
+
+type LessonAttachmentUseCase struct {
+	attachmentService services.LessonAttachmentServiceInterface
+	lessonService     services.LessonServiceInterface
+	courseService     services.CourseServiceInterface
+	userService       services.UserServiceInterface
+	mediaService      services.MediaServiceInterface
+	media             MediaUseCaseInterface
+	store             storage.BlobStore
+}
+
+func NewLessonAttachmentUseCase(
+	attachmentService services.LessonAttachmentServiceInterface,
+	lessonService services.LessonServiceInterface,
+	courseService services.CourseServiceInterface,
+	userService services.UserServiceInterface,
+	mediaService services.MediaServiceInterface,
+	media MediaUseCaseInterface,
+	store storage.BlobStore,
+) *LessonAttachmentUseCase {
+	return &LessonAttachmentUseCase{
+		attachmentService: attachmentService,
+		lessonService:     lessonService,
+		courseService:     courseService,
+		userService:       userService,
+		mediaService:      mediaService,
+		media:             media,
+		store:             store,
+	}
+}
+
+// GetAttachments возвращает вложения урока; доступ к курсу проверяется EnrollmentMiddleware
+func (u *LessonAttachmentUseCase) GetAttachments(ctx context.Context, courseID, lessonID int) ([]*models.LessonAttachment, error) {
+	if _, err := getCourseLesson(ctx, u.lessonService, courseID, lessonID); err != nil {
+		return nil, err
+	}
+	return u.attachmentService.GetLessonAttachments(ctx, lessonID)
+}
+
+// UploadAttachment загружает файл в медиатеку курса (с проверкой типа, размера и квоты) и прикрепляет его к уроку
+func (u *LessonAttachmentUseCase) UploadAttachment(ctx context.Context, userID int, userRole string, courseID, lessonID int, input *dto.LessonAttachmentUploadRequest, filename string, size int64, r io.Reader) (*models.LessonAttachment, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseEditorRoles); err != nil {
+		return nil, err
+	}
+	if _, err := getCourseLesson(ctx, u.lessonService, courseID, lessonID); err != nil {
+		return nil, err
+	}
+
+	file, err := u.media.Upload(ctx, userID, userRole, &dto.MediaUploadRequest{CourseID: &courseID}, filename, size, r)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := &models.LessonAttachment{
+		LessonID:    lessonID,
+		MediaID:     file.ID,
+		Title:       strings.TrimSpace(input.Title),
+		Filename:    file.Filename,
+		ContentType: file.ContentType,
+		Size:        file.Size,
+		Watermark:   input.Watermark,
+		StorageKey:  file.StorageKey,
+	}
+	if attachment.Title == "" {
+		attachment.Title = file.Filename
+	}
+	if attachment.Watermark && !attachment.IsPDF() {
+		err = errors.New("validation failed: watermark is only supported for PDF files")
+	} else {
+		err = u.attachmentService.CreateAttachment(ctx, attachment)
+	}
+	if err != nil {
+		u.removeMedia(ctx, file.ID, file.StorageKey)
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// UpdateAttachment меняет название вложения и признак водяного знака
+func (u *LessonAttachmentUseCase) UpdateAttachment(ctx context.Context, userID int, userRole string, courseID, lessonID, attachmentID int, input *dto.UpdateLessonAttachmentRequest) (*models.LessonAttachment, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	attachment, err := u.getEditableAttachment(ctx, userID, userRole, courseID, lessonID, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Title != nil {
+		title := strings.TrimSpace(*input.Title)
+		if title == "" {
+			return nil, errors.New("validation failed: title must not be empty")
+		}
+		attachment.Title = title
+	}
+	if input.Watermark != nil {
+		if *input.Watermark && !attachment.IsPDF() {
+			return nil, errors.New("validation failed: watermark is only supported for PDF files")
+		}
+		attachment.Watermark = *input.Watermark
+	}
+
+	if err := u.attachmentService.UpdateAttachment(ctx, attachment); err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// DeleteAttachment открепляет файл от урока. Файл удаляется из хранилища,
+// когда на него не ссылается ни одно вложение (копии курса используют общий файл).
+func (u *LessonAttachmentUseCase) DeleteAttachment(ctx context.Context, userID int, userRole string, courseID, lessonID, attachmentID int) error {
+	attachment, err := u.getEditableAttachment(ctx, userID, userRole, courseID, lessonID, attachmentID)
+	if err != nil {
+		return err
+	}
+	if err := u.attachmentService.DeleteAttachment(ctx, attachment.ID); err != nil {
+		return err
+	}
+
+	remaining, err := u.attachmentService.CountMediaAttachments(ctx, attachment.MediaID)
+	if err != nil {
+		return err
+	}
+	if remaining == 0 {
+		u.removeMedia(ctx, attachment.MediaID, attachment.StorageKey)
+	}
+	return nil
+}
+
+// DownloadAttachment открывает содержимое вложения и учитывает скачивание.
+// PDF с водяным знаком подписывается именем и email скачавшего.
+func (u *LessonAttachmentUseCase) DownloadAttachment(ctx context.Context, userID, courseID, lessonID, attachmentID int) (*models.LessonAttachment, io.ReadCloser, error) {
+	if _, err := getCourseLesson(ctx, u.lessonService, courseID, lessonID); err != nil {
+		return nil, nil, err
+	}
+	attachment, err := u.attachmentService.GetAttachment(ctx, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attachment.LessonID != lessonID {
+		return nil, nil, errors.New("lesson attachment not found")
+	}
+
+	content, err := u.store.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attachment.Watermark && attachment.IsPDF() {
+		content, err = u.watermark(ctx, userID, attachment, content)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	count, err := u.attachmentService.RecordDownload(ctx, attachment.ID)
+	if err != nil {
+		content.Close()
+		return nil, nil, err
+	}
+	attachment.DownloadCount = count
+	return attachment, content, nil
+}
+
+// watermark читает PDF целиком и возвращает копию с подписью пользователя; Size вложения меняется на размер копии
+func (u *LessonAttachmentUseCase) watermark(ctx context.Context, userID int, attachment *models.LessonAttachment, content io.ReadCloser) (io.ReadCloser, error) {
+	defer content.Close()
+
+	user, err := u.userService.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	original, err := io.ReadAll(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	marked, err := pdfgen.WatermarkPDF(bytes.NewReader(original), strings.TrimSpace(user.Name+" "+user.Surname), user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment.Size = int64(len(marked))
+	return io.NopCloser(bytes.NewReader(marked)), nil
+}
+
+func (u *LessonAttachmentUseCase) getEditableAttachment(ctx context.Context, userID int, userRole string, courseID, lessonID, attachmentID int) (*models.LessonAttachment, error) {
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseEditorRoles); err != nil {
+		return nil, err
+	}
+	if _, err := getCourseLesson(ctx, u.lessonService, courseID, lessonID); err != nil {
+		return nil, err
+	}
+	attachment, err := u.attachmentService.GetAttachment(ctx, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if attachment.LessonID != lessonID {
+		return nil, errors.New("lesson attachment not found")
+	}
+	return attachment, nil
+}
+
+// removeMedia удаляет файл медиатеки вместе с содержимым; ошибки только логируются
+func (u *LessonAttachmentUseCase) removeMedia(ctx context.Context, mediaID int, storageKey string) {
+	if err := u.mediaService.DeleteFile(ctx, mediaID); err != nil {
+		log.Printf("attachments: failed to remove media file %d: %v", mediaID, err)
+		return
+	}
+	if err := u.store.Delete(ctx, storageKey); err != nil {
+		log.Printf("attachments: failed to remove blob %s: %v", storageKey, err)
+	}
+}
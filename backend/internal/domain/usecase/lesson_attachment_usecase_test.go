@@ -0,0 +1,214 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+// Mock для LessonAttachmentService
+type MockLessonAttachmentService struct {
+	mock.Mock
+	services.LessonAttachmentServiceInterface
+}
+
+func (m *MockLessonAttachmentService) CreateAttachment(ctx context.Context, attachment *models.LessonAttachment) error {
+	args := m.Called(ctx, attachment)
+	return args.Error(0)
+}
+
+func (m *MockLessonAttachmentService) GetAttachment(ctx context.Context, id int) (*models.LessonAttachment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LessonAttachment), args.Error(1)
+}
+
+func (m *MockLessonAttachmentService) GetLessonAttachments(ctx context.Context, lessonID int) ([]*models.LessonAttachment, error) {
+	args := m.Called(ctx, lessonID)
+	return args.Get(0).([]*models.LessonAttachment), args.Error(1)
+}
+
+func (m *MockLessonAttachmentService) DeleteAttachment(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockLessonAttachmentService) CountMediaAttachments(ctx context.Context, mediaID int) (int, error) {
+	args := m.Called(ctx, mediaID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockLessonAttachmentService) RecordDownload(ctx context.Context, id int) (int, error) {
+	args := m.Called(ctx, id)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMediaService) DeleteFile(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func testPDF(t *testing.T) []byte {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Arial", "", 12)
+	pdf.Cell(40, 10, "Lecture notes")
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestUploadAttachmentRejectsWatermarkOnNonPDF(t *testing.T) {
+	ctx := context.Background()
+	attachmentService := new(MockLessonAttachmentService)
+	lessonService := new(MockLessonService)
+	courseService := new(MockCourseService)
+	mediaService := new(MockMediaService)
+	store := newMemoryBlobStore()
+	media := usecase.NewMediaUseCase(mediaService, courseService, nil, store, testMediaLimits())
+	useCase := usecase.NewLessonAttachmentUseCase(attachmentService, lessonService, courseService, nil, mediaService, media, store)
+
+	courseService.On("GetCourse", ctx, 10).Return(&models.Course{ID: 10, TeacherID: 5}, nil)
+	lessonService.On("GetLessonByID", ctx, 20).Return(&models.Lesson{ID: 20, CourseID: 10}, nil)
+	mediaService.On("CreateFile", ctx, mock.AnythingOfType("*models.MediaFile"), int64(0)).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.MediaFile).ID = 7
+	}).Return(true, nil)
+	mediaService.On("DeleteFile", ctx, 7).Return(nil).Once()
+
+	input := &dto.LessonAttachmentUploadRequest{Title: "Diagram", Watermark: true}
+	attachment, err := useCase.UploadAttachment(ctx, 1, "admin", 10, 20, input, "diagram.png", int64(len(pngHeader)), bytes.NewReader(pngHeader))
+
+	assert.Nil(t, attachment)
+	assert.ErrorContains(t, err, "validation failed: watermark is only supported for PDF files")
+	assert.Empty(t, store.blobs)
+	attachmentService.AssertNotCalled(t, "CreateAttachment", mock.Anything, mock.Anything)
+	mediaService.AssertExpectations(t)
+}
+
+func TestUploadAttachmentDefaultsTitleToFilename(t *testing.T) {
+	ctx := context.Background()
+	attachmentService := new(MockLessonAttachmentService)
+	lessonService := new(MockLessonService)
+	courseService := new(MockCourseService)
+	mediaService := new(MockMediaService)
+	store := newMemoryBlobStore()
+	media := usecase.NewMediaUseCase(mediaService, courseService, nil, store, testMediaLimits())
+	useCase := usecase.NewLessonAttachmentUseCase(attachmentService, lessonService, courseService, nil, mediaService, media, store)
+
+	content := testPDF(t)
+	courseService.On("GetCourse", ctx, 10).Return(&models.Course{ID: 10, TeacherID: 5}, nil)
+	lessonService.On("GetLessonByID", ctx, 20).Return(&models.Lesson{ID: 20, CourseID: 10}, nil)
+	mediaService.On("CreateFile", ctx, mock.MatchedBy(func(file *models.MediaFile) bool {
+		return *file.CourseID == 10 && !file.Public
+	}), int64(0)).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.MediaFile).ID = 7
+	}).Return(true, nil)
+	attachmentService.On("CreateAttachment", ctx, mock.MatchedBy(func(attachment *models.LessonAttachment) bool {
+		return attachment.LessonID == 20 && attachment.MediaID == 7 && attachment.Title == "notes.pdf" && attachment.Watermark
+	})).Return(nil)
+
+	input := &dto.LessonAttachmentUploadRequest{Title: "  ", Watermark: true}
+	attachment, err := useCase.UploadAttachment(ctx, 1, "admin", 10, 20, input, "notes.pdf", int64(len(content)), bytes.NewReader(content))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "application/pdf", attachment.ContentType)
+	assert.Equal(t, int64(len(content)), attachment.Size)
+	attachmentService.AssertExpectations(t)
+}
+
+func TestDownloadAttachmentWatermarksPDF(t *testing.T) {
+	ctx := context.Background()
+	attachmentService := new(MockLessonAttachmentService)
+	lessonService := new(MockLessonService)
+	userService := new(MockUserService)
+	store := newMemoryBlobStore()
+	useCase := usecase.NewLessonAttachmentUseCase(attachmentService, lessonService, nil, userService, nil, nil, store)
+
+	original := testPDF(t)
+	store.blobs["5/notes.pdf"] = original
+	lessonService.On("GetLessonByID", ctx, 20).Return(&models.Lesson{ID: 20, CourseID: 10}, nil)
+	attachmentService.On("GetAttachment", ctx, 3).Return(&models.LessonAttachment{
+		ID: 3, LessonID: 20, MediaID: 7, Filename: "notes.pdf", ContentType: "application/pdf",
+		Size: int64(len(original)), Watermark: true, StorageKey: "5/notes.pdf",
+	}, nil)
+	userService.On("GetUser", ctx, 9).Return(&models.User{ID: 9, Name: "Ann", Surname: "Lee", Email: "ann@example.com"}, nil)
+	attachmentService.On("RecordDownload", ctx, 3).Return(4, nil)
+
+	attachment, content, err := useCase.DownloadAttachment(ctx, 9, 10, 20, 3)
+	assert.NoError(t, err)
+	defer content.Close()
+	data, _ := io.ReadAll(content)
+
+	assert.Equal(t, 4, attachment.DownloadCount)
+	assert.Equal(t, int64(len(data)), attachment.Size)
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF")))
+	assert.NotEqual(t, original, data)
+	assert.Equal(t, original, store.blobs["5/notes.pdf"])
+}
+
+func TestDownloadAttachmentFromAnotherLesson(t *testing.T) {
+	ctx := context.Background()
+	attachmentService := new(MockLessonAttachmentService)
+	lessonService := new(MockLessonService)
+	useCase := usecase.NewLessonAttachmentUseCase(attachmentService, lessonService, nil, nil, nil, nil, newMemoryBlobStore())
+
+	lessonService.On("GetLessonByID", ctx, 20).Return(&models.Lesson{ID: 20, CourseID: 10}, nil)
+	attachmentService.On("GetAttachment", ctx, 3).Return(&models.LessonAttachment{ID: 3, LessonID: 21}, nil)
+
+	_, _, err := useCase.DownloadAttachment(ctx, 9, 10, 20, 3)
+
+	assert.EqualError(t, err, "lesson attachment not found")
+	attachmentService.AssertNotCalled(t, "RecordDownload", mock.Anything, mock.Anything)
+}
+
+func TestDeleteAttachmentKeepsFileSharedWithClone(t *testing.T) {
+	ctx := context.Background()
+	attachmentService := new(MockLessonAttachmentService)
+	lessonService := new(MockLessonService)
+	courseService := new(MockCourseService)
+	mediaService := new(MockMediaService)
+	store := newMemoryBlobStore()
+	useCase := usecase.NewLessonAttachmentUseCase(attachmentService, lessonService, courseService, nil, mediaService, nil, store)
+
+	store.blobs["5/notes.pdf"] = []byte("%PDF-1.4")
+	courseService.On("GetCourse", ctx, 10).Return(&models.Course{ID: 10, TeacherID: 5}, nil)
+	courseService.On("GetStaffMember", ctx, 10, 5).Return(&models.CourseStaff{CourseID: 10, UserID: 5, Role: models.CourseStaffRoleOwner}, nil)
+	lessonService.On("GetLessonByID", ctx, 20).Return(&models.Lesson{ID: 20, CourseID: 10}, nil)
+	attachmentService.On("GetAttachment", ctx, 3).Return(&models.LessonAttachment{ID: 3, LessonID: 20, MediaID: 7, StorageKey: "5/notes.pdf"}, nil)
+	attachmentService.On("DeleteAttachment", ctx, 3).Return(nil)
+	attachmentService.On("CountMediaAttachments", ctx, 7).Return(1, nil)
+
+	err := useCase.DeleteAttachment(ctx, 5, "teacher", 10, 20, 3)
+
+	assert.NoError(t, err)
+	assert.Contains(t, store.blobs, "5/notes.pdf")
+	mediaService.AssertNotCalled(t, "DeleteFile", mock.Anything, mock.Anything)
+}
+
+func TestDeleteAttachmentRequiresEditor(t *testing.T) {
+	ctx := context.Background()
+	courseService := new(MockCourseService)
+	useCase := usecase.NewLessonAttachmentUseCase(new(MockLessonAttachmentService), new(MockLessonService), courseService, nil, nil, nil, newMemoryBlobStore())
+
+	courseService.On("GetCourse", ctx, 10).Return(&models.Course{ID: 10, TeacherID: 5}, nil)
+	courseService.On("GetStaffMember", ctx, 10, 6).Return(&models.CourseStaff{CourseID: 10, UserID: 6, Role: models.CourseStaffRoleTA}, nil)
+
+	err := useCase.DeleteAttachment(ctx, 6, "teacher", 10, 20, 3)
+
+	assert.True(t, errors.Is(err, usecase.ErrPermissionDenied))
+}
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+)
+
+// LessonBlockSchemas - JSON-схемы поля data по типам блоков; отдаются клиенту для редактора уроков
+var LessonBlockSchemas = map[string]json.RawMessage{
+	models.LessonBlockText: json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"markdown": {"type": "string", "minLength": 1, "maxLength": 100000}
+		},
+		"required": ["markdown"],
+		"additionalProperties": false
+	}`),
+	models.LessonBlockVideo: json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"url": {"type": "string", "format": "uri", "pattern": "^https?://"},
+			"caption": {"type": "string", "maxLength": 500},
+			"duration_seconds": {"type": "integer", "minimum": 0}
+		},
+		"required": ["url"],
+		"additionalProperties": false
+	}`),
+	models.LessonBlockImage: json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"media_id": {"type": "integer", "minimum": 1},
+			"url": {"type": "string", "format": "uri", "pattern": "^https?://"},
+			"alt": {"type": "string", "minLength": 1, "maxLength": 500},
+			"caption": {"type": "string", "maxLength": 500}
+		},
+		"required": ["alt"],
+		"oneOf": [{"required": ["media_id"]}, {"required": ["url"]}],
+		"additionalProperties": false
+	}`),
+	models.LessonBlockCode: json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"language": {"type": "string", "pattern": "^[A-Za-z0-9_+#-]{1,30}$"},
+			"code": {"type": "string", "minLength": 1, "maxLength": 50000},
+			"filename": {"type": "string", "maxLength": 200}
+		},
+		"required": ["language", "code"],
+		"additionalProperties": false
+	}`),
+	models.LessonBlockCallout: json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"variant": {"enum": ["info", "tip", "warning", "danger"]},
+			"title": {"type": "string", "maxLength": 200},
+			"markdown": {"type": "string", "minLength": 1, "maxLength": 20000}
+		},
+		"required": ["variant", "markdown"],
+		"additionalProperties": false
+	}`),
+	models.LessonBlockFile: json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"media_id": {"type": "integer", "minimum": 1},
+			"title": {"type": "string", "maxLength": 200}
+		},
+		"required": ["media_id"],
+		"additionalProperties": false
+	}`),
+	models.LessonBlockQuiz: json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"quiz_id": {"type": "integer", "minimum": 1},
+			"title": {"type": "string", "maxLength": 200},
+			"pass_score": {"type": "number", "minimum": 0, "maximum": 100}
+		},
+		"required": ["quiz_id"],
+		"additionalProperties": false
+	}`),
+}
+
+var compiledLessonBlockSchemas = compileLessonBlockSchemas()
+
+func compileLessonBlockSchemas() map[string]*jsonschema.Schema {
+	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat()
+	schemas := make(map[string]*jsonschema.Schema, len(LessonBlockSchemas))
+	for blockType, raw := range LessonBlockSchemas {
+		doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+		if err != nil {
+			panic(fmt.Sprintf("invalid %s block schema: %v", blockType, err))
+		}
+		url := "lesson-block-" + blockType + ".json"
+		if err := compiler.AddResource(url, doc); err != nil {
+			panic(fmt.Sprintf("invalid %s block schema: %v", blockType, err))
+		}
+		if schemas[blockType], err = compiler.Compile(url); err != nil {
+			panic(fmt.Sprintf("invalid %s block schema: %v", blockType, err))
+		}
+	}
+	return schemas
+}
+
+// validateLessonBlockData проверяет data блока по схеме его типа
+func validateLessonBlockData(blockType string, data json.RawMessage) error {
+	schema, ok := compiledLessonBlockSchemas[blockType]
+	if !ok {
+		return fmt.Errorf("validation failed: unknown block type %q", blockType)
+	}
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("validation failed: data is not valid JSON: %w", err)
+	}
+	if err := schema.Validate(doc); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+	"gitlab.com/w0ikid/study-platform/pkg/markdown"
+)
+
+type LessonBlockUseCase struct {
+	blockService  services.LessonBlockServiceInterface
+	lessonService services.LessonServiceInterface
+	courseService services.CourseServiceInterface
+	mediaService  services.MediaServiceInterface
+	progress      LessonProgressUseCaseInterface
+}
+
+func NewLessonBlockUseCase(
+	blockService services.LessonBlockServiceInterface,
+	lessonService services.LessonServiceInterface,
+	courseService services.CourseServiceInterface,
+	mediaService services.MediaServiceInterface,
+	progress LessonProgressUseCaseInterface,
+) *LessonBlockUseCase {
+	return &LessonBlockUseCase{
+		blockService:  blockService,
+		lessonService: lessonService,
+		courseService: courseService,
+		mediaService:  mediaService,
+		progress:      progress,
+	}
+}
+
+// GetBlocks возвращает блоки урока с отметками о завершении текущим пользователем
+func (u *LessonBlockUseCase) GetBlocks(ctx context.Context, userID, courseID, lessonID int) ([]*models.LessonBlock, error) {
+	if _, err := getCourseLesson(ctx, u.lessonService, courseID, lessonID); err != nil {
+		return nil, err
+	}
+	return u.blockService.GetLessonBlocks(ctx, lessonID, userID)
+}
+
+// CreateBlock добавляет блок в конец урока
+func (u *LessonBlockUseCase) CreateBlock(ctx context.Context, userID int, userRole string, courseID, lessonID int, input *dto.LessonBlockRequest) (*models.LessonBlock, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseEditorRoles); err != nil {
+		return nil, err
+	}
+	if _, err := getCourseLesson(ctx, u.lessonService, courseID, lessonID); err != nil {
+		return nil, err
+	}
+
+	block := &models.LessonBlock{LessonID: lessonID, Type: input.Type, Required: true}
+	if err := u.applyInput(ctx, courseID, block, input); err != nil {
+		return nil, err
+	}
+	if err := u.blockService.CreateBlock(ctx, block); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// UpdateBlock меняет содержимое блока; тип блока изменить нельзя
+func (u *LessonBlockUseCase) UpdateBlock(ctx context.Context, userID int, userRole string, courseID, lessonID, blockID int, input *dto.LessonBlockRequest) (*models.LessonBlock, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	block, err := u.getEditableBlock(ctx, userID, userRole, courseID, lessonID, blockID)
+	if err != nil {
+		return nil, err
+	}
+	if input.Type != block.Type {
+		return nil, errors.New("validation failed: block type cannot be changed")
+	}
+
+	if err := u.applyInput(ctx, courseID, block, input); err != nil {
+		return nil, err
+	}
+	if err := u.blockService.UpdateBlock(ctx, block); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// DeleteBlock удаляет блок урока
+func (u *LessonBlockUseCase) DeleteBlock(ctx context.Context, userID int, userRole string, courseID, lessonID, blockID int) error {
+	if _, err := u.getEditableBlock(ctx, userID, userRole, courseID, lessonID, blockID); err != nil {
+		return err
+	}
+	return u.blockService.DeleteBlock(ctx, blockID)
+}
+
+// ReorderBlocks расставляет блоки урока в переданном порядке
+func (u *LessonBlockUseCase) ReorderBlocks(ctx context.Context, userID int, userRole string, courseID, lessonID int, input *dto.ReorderLessonBlocksRequest) ([]*models.LessonBlock, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseEditorRoles); err != nil {
+		return nil, err
+	}
+	if _, err := getCourseLesson(ctx, u.lessonService, courseID, lessonID); err != nil {
+		return nil, err
+	}
+
+	if err := u.blockService.ReorderBlocks(ctx, lessonID, input.BlockIDs); err != nil {
+		return nil, err
+	}
+	return u.blockService.GetLessonBlocks(ctx, lessonID, userID)
+}
+
+// CompleteBlock отмечает блок завершённым студентом. Когда завершены все обязательные блоки,
+// урок отмечается пройденным через LessonProgressUseCase (с начислением опыта и завершением модуля).
+func (u *LessonBlockUseCase) CompleteBlock(ctx context.Context, userID, courseID, lessonID, blockID int) (*models.LessonBlockCompletion, error) {
+	if _, err := getCourseLesson(ctx, u.lessonService, courseID, lessonID); err != nil {
+		return nil, err
+	}
+	block, err := u.blockService.GetBlock(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+	if block.LessonID != lessonID {
+		return nil, errors.New("lesson block not found")
+	}
+
+	if err := u.blockService.CompleteBlock(ctx, blockID, userID); err != nil {
+		return nil, err
+	}
+	remaining, err := u.blockService.CountRemainingRequired(ctx, lessonID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.LessonBlockCompletion{BlockID: blockID, RemainingRequired: remaining}
+	if remaining == 0 {
+		err := u.progress.MarkLessonCompleted(ctx, userID, lessonID, courseID)
+		switch {
+		case errors.Is(err, ErrLessonRequirementsNotMet):
+			// урок завершится, когда будут выполнены остальные условия, например просмотр видео
+		case err != nil && !errors.Is(err, ErrLessonAlreadyCompleted):
+			return nil, err
+		default:
+			result.LessonCompleted = true
+		}
+	}
+	return result, nil
+}
+
+// CheckBlocksCompleted - условие завершения урока (CompletionRequirement): все обязательные
+// блоки урока должны быть завершены, иначе урок нельзя отметить пройденным напрямую
+func (u *LessonBlockUseCase) CheckBlocksCompleted(ctx context.Context, userID int, lesson *models.Lesson) error {
+	remaining, err := u.blockService.CountRemainingRequired(ctx, lesson.ID, userID)
+	if err != nil {
+		return err
+	}
+	if remaining > 0 {
+		return fmt.Errorf("%w: %d required blocks are not completed", ErrLessonRequirementsNotMet, remaining)
+	}
+	return nil
+}
+
+// applyInput проверяет data по схеме типа и ссылки на файлы, строит HTML для markdown-блоков
+func (u *LessonBlockUseCase) applyInput(ctx context.Context, courseID int, block *models.LessonBlock, input *dto.LessonBlockRequest) error {
+	block.Data = input.Data
+	mediaID, err := prepareLessonBlock(block)
+	if err != nil {
+		return err
+	}
+	if mediaID != 0 {
+		if err := u.checkMedia(ctx, courseID, block.Type, mediaID); err != nil {
+			return err
+		}
+	}
+	if input.Required != nil {
+		block.Required = *input.Required
+	}
+	return nil
+}
+
+// prepareLessonBlock проверяет Data по схеме типа блока и строит HTML из markdown.
+// Возвращает ID файла, на который ссылается блок (0 - без файла).
+func prepareLessonBlock(block *models.LessonBlock) (int, error) {
+	if err := validateLessonBlockData(block.Type, block.Data); err != nil {
+		return 0, err
+	}
+
+	var fields struct {
+		Markdown string `json:"markdown"`
+		MediaID  int    `json:"media_id"`
+	}
+	if err := json.Unmarshal(block.Data, &fields); err != nil {
+		return 0, fmt.Errorf("validation failed: %w", err)
+	}
+
+	block.HTML = ""
+	if fields.Markdown != "" {
+		html, err := markdown.Render(fields.Markdown)
+		if err != nil {
+			return 0, err
+		}
+		block.HTML = html
+	}
+	return fields.MediaID, nil
+}
+
+// checkMedia проверяет, что студенты курса смогут открыть файл блока
+func (u *LessonBlockUseCase) checkMedia(ctx context.Context, courseID int, blockType string, mediaID int) error {
+	file, err := u.mediaService.GetFile(ctx, mediaID)
+	if err != nil {
+		return err
+	}
+	if !file.Public && (file.CourseID == nil || *file.CourseID != courseID) {
+		return errors.New("validation failed: media file must be public or uploaded to this course")
+	}
+	if blockType == models.LessonBlockImage && !file.IsImage() {
+		return errors.New("validation failed: media file is not an image")
+	}
+	return nil
+}
+
+func (u *LessonBlockUseCase) getEditableBlock(ctx context.Context, userID int, userRole string, courseID, lessonID, blockID int) (*models.LessonBlock, error) {
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseEditorRoles); err != nil {
+		return nil, err
+	}
+	if _, err := getCourseLesson(ctx, u.lessonService, courseID, lessonID); err != nil {
+		return nil, err
+	}
+	block, err := u.blockService.GetBlock(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+	if block.LessonID != lessonID {
+		return nil, errors.New("lesson block not found")
+	}
+	return block, nil
+}
@@ -0,0 +1,219 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+// Mock для LessonBlockService
+type MockLessonBlockService struct {
+	mock.Mock
+	services.LessonBlockServiceInterface
+}
+
+func (m *MockLessonBlockService) CreateBlock(ctx context.Context, block *models.LessonBlock) error {
+	args := m.Called(ctx, block)
+	return args.Error(0)
+}
+
+func (m *MockLessonBlockService) GetBlock(ctx context.Context, id int) (*models.LessonBlock, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LessonBlock), args.Error(1)
+}
+
+func (m *MockLessonBlockService) GetLessonBlocks(ctx context.Context, lessonID, userID int) ([]*models.LessonBlock, error) {
+	args := m.Called(ctx, lessonID, userID)
+	return args.Get(0).([]*models.LessonBlock), args.Error(1)
+}
+
+func (m *MockLessonBlockService) CompleteBlock(ctx context.Context, blockID, userID int) error {
+	args := m.Called(ctx, blockID, userID)
+	return args.Error(0)
+}
+
+func (m *MockLessonBlockService) CountRemainingRequired(ctx context.Context, lessonID, userID int) (int, error) {
+	args := m.Called(ctx, lessonID, userID)
+	return args.Int(0), args.Error(1)
+}
+
+// Mock для LessonProgressUseCase
+type MockLessonProgressUseCase struct {
+	mock.Mock
+	usecase.LessonProgressUseCaseInterface
+}
+
+func (m *MockLessonProgressUseCase) MarkLessonCompleted(ctx context.Context, userID, lessonID, courseID int) error {
+	args := m.Called(ctx, userID, lessonID, courseID)
+	return args.Error(0)
+}
+
+func (m *MockLessonService) GetLessonByID(ctx context.Context, id int) (*models.Lesson, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Lesson), args.Error(1)
+}
+
+func TestCreateLessonBlock(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Callout is validated and rendered", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		lessonService := new(MockLessonService)
+		blockService := new(MockLessonBlockService)
+		useCase := usecase.NewLessonBlockUseCase(blockService, lessonService, courseService, nil, nil)
+
+		courseService.On("GetCourse", ctx, 1).Return(&models.Course{ID: 1}, nil)
+		courseService.On("GetStaffMember", ctx, 1, 5).Return(&models.CourseStaff{CourseID: 1, UserID: 5, Role: models.CourseStaffRoleCoTeacher}, nil)
+		lessonService.On("GetLessonByID", ctx, 10).Return(&models.Lesson{ID: 10, CourseID: 1}, nil)
+		blockService.On("CreateBlock", ctx, mock.Anything).Return(nil)
+
+		optional := false
+		block, err := useCase.CreateBlock(ctx, 5, "teacher", 1, 10, &dto.LessonBlockRequest{
+			Type:     models.LessonBlockCallout,
+			Data:     json.RawMessage(`{"variant": "warning", "markdown": "Do **not** <script>x()</script>"}`),
+			Required: &optional,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "<p>Do <strong>not</strong> </p>\n", block.HTML)
+		assert.False(t, block.Required)
+	})
+
+	t.Run("Data must match the schema of the block type", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		lessonService := new(MockLessonService)
+		blockService := new(MockLessonBlockService)
+		useCase := usecase.NewLessonBlockUseCase(blockService, lessonService, courseService, nil, nil)
+
+		courseService.On("GetCourse", ctx, 1).Return(&models.Course{ID: 1}, nil)
+		lessonService.On("GetLessonByID", ctx, 10).Return(&models.Lesson{ID: 10, CourseID: 1}, nil)
+
+		_, err := useCase.CreateBlock(ctx, 1, "admin", 1, 10, &dto.LessonBlockRequest{
+			Type: models.LessonBlockVideo,
+			Data: json.RawMessage(`{"url": "javascript:alert(1)", "autoplay": true}`),
+		})
+
+		assert.ErrorContains(t, err, "validation failed")
+		blockService.AssertNotCalled(t, "CreateBlock", mock.Anything, mock.Anything)
+	})
+
+	t.Run("TA cannot edit blocks", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		useCase := usecase.NewLessonBlockUseCase(nil, nil, courseService, nil, nil)
+
+		courseService.On("GetCourse", ctx, 1).Return(&models.Course{ID: 1}, nil)
+		courseService.On("GetStaffMember", ctx, 1, 6).Return(&models.CourseStaff{CourseID: 1, UserID: 6, Role: models.CourseStaffRoleTA}, nil)
+
+		_, err := useCase.CreateBlock(ctx, 6, "teacher", 1, 10, &dto.LessonBlockRequest{
+			Type: models.LessonBlockText,
+			Data: json.RawMessage(`{"markdown": "hi"}`),
+		})
+
+		assert.ErrorIs(t, err, usecase.ErrPermissionDenied)
+	})
+}
+
+func TestCompleteLessonBlock(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(remaining int) (*usecase.LessonBlockUseCase, *MockLessonBlockService, *MockLessonProgressUseCase) {
+		lessonService := new(MockLessonService)
+		blockService := new(MockLessonBlockService)
+		progress := new(MockLessonProgressUseCase)
+		useCase := usecase.NewLessonBlockUseCase(blockService, lessonService, nil, nil, progress)
+
+		lessonService.On("GetLessonByID", ctx, 10).Return(&models.Lesson{ID: 10, CourseID: 1}, nil)
+		blockService.On("GetBlock", ctx, 3).Return(&models.LessonBlock{ID: 3, LessonID: 10, Required: true}, nil)
+		blockService.On("CompleteBlock", ctx, 3, 7).Return(nil)
+		blockService.On("CountRemainingRequired", ctx, 10, 7).Return(remaining, nil)
+		return useCase, blockService, progress
+	}
+
+	t.Run("Last required block completes the lesson", func(t *testing.T) {
+		useCase, _, progress := setup(0)
+		progress.On("MarkLessonCompleted", ctx, 7, 10, 1).Return(nil)
+
+		result, err := useCase.CompleteBlock(ctx, 7, 1, 10, 3)
+
+		assert.NoError(t, err)
+		assert.True(t, result.LessonCompleted)
+		progress.AssertExpectations(t)
+	})
+
+	t.Run("Already completed lesson is not an error", func(t *testing.T) {
+		useCase, _, progress := setup(0)
+		progress.On("MarkLessonCompleted", ctx, 7, 10, 1).Return(usecase.ErrLessonAlreadyCompleted)
+
+		result, err := useCase.CompleteBlock(ctx, 7, 1, 10, 3)
+
+		assert.NoError(t, err)
+		assert.True(t, result.LessonCompleted)
+	})
+
+	t.Run("Remaining required blocks keep the lesson open", func(t *testing.T) {
+		useCase, _, progress := setup(2)
+
+		result, err := useCase.CompleteBlock(ctx, 7, 1, 10, 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.RemainingRequired)
+		assert.False(t, result.LessonCompleted)
+		progress.AssertNotCalled(t, "MarkLessonCompleted", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func (m *MockLessonProgressService) GetProgressByLesson(ctx context.Context, userID, lessonID int) (*models.LessonProgress, error) {
+	args := m.Called(ctx, userID, lessonID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LessonProgress), args.Error(1)
+}
+
+func TestCheckBlocksCompleted(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Direct completion is rejected while required blocks are open", func(t *testing.T) {
+		lessonService := new(MockLessonService)
+		moduleService := new(MockModuleService)
+		progressService := new(MockLessonProgressService)
+		versionService := new(MockCourseVersionService)
+		blockService := new(MockLessonBlockService)
+		progress := usecase.NewLessonProgressUseCase(progressService, lessonService, nil, nil, nil, moduleService, versionService)
+		blocks := usecase.NewLessonBlockUseCase(blockService, lessonService, nil, nil, progress)
+		progress.AddCompletionRequirement(blocks.CheckBlocksCompleted)
+
+		versionService.On("GetPinnedVersion", ctx, 7, 1).Return(nil, nil)
+		moduleService.On("GetModulesByCourse", ctx, 1).Return([]*models.Module{}, nil)
+		lessonService.On("GetAllLessons", ctx, 1).Return([]*models.Lesson{{ID: 10, CourseID: 1}}, nil)
+		progressService.On("GetProgressByLesson", ctx, 7, 10).Return(nil, nil)
+		blockService.On("CountRemainingRequired", ctx, 10, 7).Return(2, nil)
+
+		err := progress.MarkLessonCompleted(ctx, 7, 10, 1)
+
+		assert.ErrorIs(t, err, usecase.ErrLessonRequirementsNotMet)
+		progressService.AssertNotCalled(t, "MarkLessonCompleted", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Lesson without open required blocks passes", func(t *testing.T) {
+		blockService := new(MockLessonBlockService)
+		blocks := usecase.NewLessonBlockUseCase(blockService, nil, nil, nil, nil)
+		blockService.On("CountRemainingRequired", ctx, 10, 7).Return(0, nil)
+
+		assert.NoError(t, blocks.CheckBlocksCompleted(ctx, 7, &models.Lesson{ID: 10, CourseID: 1}))
+	})
+}
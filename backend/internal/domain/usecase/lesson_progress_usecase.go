@@ -4,21 +4,37 @@ import (
 	"context"
 	"errors"
 	_ "log"
+	"time"
 
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
 	"gitlab.com/w0ikid/study-platform/internal/domain/services"
 )
 
 type LessonProgressUseCaseInterface interface {
     MarkLessonCompleted(ctx context.Context, userID, lessonID, courseID int) error
-    GetCourseProgress(ctx context.Context, userID, courseID int) (float64, error)
+    GetCourseProgress(ctx context.Context, userID, courseID int) (*models.CourseProgress, error)
+    OnModuleCompleted(handler ModuleCompletedHandler)
+    AddCompletionRequirement(requirement CompletionRequirement)
+    RecalculateCourseProgress(ctx context.Context, courseID int, moduleID *int) error
 }
 
+// ModuleCompletedHandler вызывается один раз, когда студент завершил все уроки модуля
+type ModuleCompletedHandler func(ctx context.Context, event models.ModuleCompletedEvent)
+
+// CompletionRequirement проверяет дополнительное условие завершения урока;
+// ошибка (обычно ErrLessonRequirementsNotMet) отменяет завершение
+type CompletionRequirement func(ctx context.Context, userID int, lesson *models.Lesson) error
+
 type LessonProgressUseCase struct {
     lessonProgressService services.LessonProgressServiceInterface
     lessonService         services.LessonServiceInterface
     enrollmentService     services.EnrollmentServiceInterface
     courseService         services.CourseServiceInterface
     userService           services.UserServiceInterface
+    moduleService         services.ModuleServiceInterface
+    versionService        services.CourseVersionServiceInterface
+    moduleHandlers        []ModuleCompletedHandler
+    requirements          []CompletionRequirement
 }
 
 func NewLessonProgressUseCase(
@@ -27,6 +43,8 @@ func NewLessonProgressUseCase(
     enrollmentService services.EnrollmentServiceInterface,
     courseService services.CourseServiceInterface,
     userService services.UserServiceInterface,
+    moduleService services.ModuleServiceInterface,
+    versionService services.CourseVersionServiceInterface,
 ) *LessonProgressUseCase {
     return &LessonProgressUseCase{
         lessonProgressService: lessonProgressService,
@@ -34,9 +52,22 @@ func NewLessonProgressUseCase(
         enrollmentService:     enrollmentService,
         courseService: courseService,
         userService: userService,
+        moduleService: moduleService,
+        versionService: versionService,
     }
 }
 
+// OnModuleCompleted подписывает обработчик на завершение модулей. Обработчики вызываются синхронно,
+// в том числе при пересчёте прогресса всех студентов после удаления урока, поэтому должны быть быстрыми.
+func (uc *LessonProgressUseCase) OnModuleCompleted(handler ModuleCompletedHandler) {
+    uc.moduleHandlers = append(uc.moduleHandlers, handler)
+}
+
+// AddCompletionRequirement добавляет условие, которое проверяется перед каждым завершением урока
+func (uc *LessonProgressUseCase) AddCompletionRequirement(requirement CompletionRequirement) {
+    uc.requirements = append(uc.requirements, requirement)
+}
+
 func calculateLevel(xp int) int {
 	return (xp / 100) + 1
 }
@@ -52,13 +83,19 @@ func (uc *LessonProgressUseCase) MarkLessonCompleted(ctx context.Context, userID
     //     return errors.New("user is not enrolled in the course")
     // }
     
-    // Проверяем, что урок принадлежит курсу
-    lesson, err := uc.lessonService.GetLessonByID(ctx, lessonID)
+    // Проверяем, что урок есть в версии курса, которую видит студент
+    _, lessons, err := loadCourseContent(ctx, uc.versionService, uc.moduleService, uc.lessonService, userID, courseID)
     if err != nil {
         return err
     }
-    
-    if lesson.CourseID != courseID {
+    var lesson *models.Lesson
+    for _, courseLesson := range lessons {
+        if courseLesson.ID == lessonID {
+            lesson = courseLesson
+            break
+        }
+    }
+    if lesson == nil {
         return errors.New("lesson does not belong to the course")
     }
 
@@ -68,7 +105,13 @@ func (uc *LessonProgressUseCase) MarkLessonCompleted(ctx context.Context, userID
     }
 
     if lessonProg != nil && lessonProg.IsCompleted {
-        return errors.New("lesson is already completed")
+        return ErrLessonAlreadyCompleted
+    }
+
+    for _, requirement := range uc.requirements {
+        if err := requirement(ctx, userID, lesson); err != nil {
+            return err
+        }
     }
 
     const xpPerLesson = 10
@@ -85,45 +128,209 @@ func (uc *LessonProgressUseCase) MarkLessonCompleted(ctx context.Context, userID
     uc.userService.UpdateXpAndLevel(ctx, user)
     // Отмечаем урок как завершенный
     
-    return uc.lessonProgressService.MarkLessonCompleted(ctx, userID, lessonID, courseID)
-}
+    if err := uc.lessonProgressService.MarkLessonCompleted(ctx, userID, lessonID, courseID); err != nil {
+        return err
+    }
 
-func (uc *LessonProgressUseCase) GetCourseProgress(ctx context.Context, userID, courseID int) (float64, error) {
-    // Проверяем, что пользователь записан на курс
-    // enrolled, err := uc.enrollmentService.IsUserEnrolled(ctx, userID, courseID)
-    // if err != nil {
-    //     return 0, err
-    // }
-    // if !enrolled {
-    //     return 0, errors.New("user is not enrolled in the course")
-    // }
+    return uc.checkModuleCompleted(ctx, userID, courseID, lessons, lesson.ModuleID)
+}
 
-    // Получаем все уроки курса
-    lessons, err := uc.lessonService.GetAllLessons(ctx, courseID)
+// checkModuleCompleted фиксирует завершение модуля урока и оповещает подписчиков,
+// если все уроки модуля пройдены
+func (uc *LessonProgressUseCase) checkModuleCompleted(ctx context.Context, userID, courseID int, lessons []*models.Lesson, lessonModuleID *int) error {
+    progresses, err := uc.lessonProgressService.GetProgressByCourse(ctx, userID, courseID)
     if err != nil {
-        return 0, err
+        return err
+    }
+    completed := completedLessonIDs(progresses)
+
+    moduleID := moduleKey(lessonModuleID)
+    for _, lesson := range lessons {
+        if moduleKey(lesson.ModuleID) == moduleID && !completed[lesson.ID] {
+            return nil
+        }
+    }
+
+    return uc.recordModuleCompleted(ctx, userID, courseID, moduleID)
+}
+
+// recordModuleCompleted сохраняет завершение модуля и оповещает подписчиков, если оно ещё не было записано
+func (uc *LessonProgressUseCase) recordModuleCompleted(ctx context.Context, userID, courseID, moduleID int) error {
+    recorded, err := uc.moduleService.RecordCompletion(ctx, userID, courseID, moduleID)
+    if err != nil || !recorded {
+        return err
     }
-    if len(lessons) == 0 {
-        return 0, nil
+
+    event := models.ModuleCompletedEvent{
+        UserID:      userID,
+        CourseID:    courseID,
+        ModuleID:    moduleID,
+        CompletedAt: time.Now(),
+    }
+    for _, handler := range uc.moduleHandlers {
+        handler(ctx, event)
+    }
+    return nil
+}
+
+// GetCourseProgress возвращает прогресс по курсу в целом и по каждому модулю
+func (uc *LessonProgressUseCase) GetCourseProgress(ctx context.Context, userID, courseID int) (*models.CourseProgress, error) {
+    // Получаем модули и уроки версии курса, которую видит студент
+    modules, lessons, err := loadCourseContent(ctx, uc.versionService, uc.moduleService, uc.lessonService, userID, courseID)
+    if err != nil {
+        return nil, err
     }
 
     // Получаем прогресс пользователя по курсу
     progresses, err := uc.lessonProgressService.GetProgressByCourse(ctx, userID, courseID)
     if err != nil {
-        return 0, err
+        return nil, err
+    }
+    completions, err := uc.moduleService.GetCompletions(ctx, userID, courseID)
+    if err != nil {
+        return nil, err
+    }
+    completed := completedLessonIDs(progresses)
+
+    result := &models.CourseProgress{
+        CourseID: courseID,
+        Modules:  []*models.ModuleProgress{},
+    }
+    for _, module := range GroupLessonsByModule(courseID, modules, lessons) {
+        moduleProgress := &models.ModuleProgress{
+            ModuleID:     module.ID,
+            Title:        module.Title,
+            Position:     module.Position,
+            TotalLessons: len(module.Lessons),
+        }
+        for _, lesson := range module.Lessons {
+            if completed[lesson.ID] {
+                moduleProgress.CompletedLessons++
+            }
+        }
+        moduleProgress.Progress = percent(moduleProgress.CompletedLessons, moduleProgress.TotalLessons)
+        if completedAt, ok := completions[module.ID]; ok {
+            moduleProgress.CompletedAt = &completedAt
+        }
+
+        result.TotalLessons += moduleProgress.TotalLessons
+        result.CompletedLessons += moduleProgress.CompletedLessons
+        result.Modules = append(result.Modules, moduleProgress)
+    }
+    result.Progress = percent(result.CompletedLessons, result.TotalLessons)
+
+    if result.TotalLessons > 0 && result.CompletedLessons == result.TotalLessons {
+        uc.enrollmentService.MarkAsCompleted(ctx, userID, courseID)
+    }
+
+    return result, nil
+}
+
+// RecalculateCourseProgress пересчитывает прогресс записанных студентов после удаления или восстановления урока:
+// если уроки модуля или всего курса пройдены, модуль и курс считаются завершёнными,
+// если нет (в модуль вернулся непройденный урок) - завершение снимается.
+// У опубликованного курса все студенты видят уроки версии (незакреплённые закрепляются за последней
+// при первом обращении), поэтому правка черновика их прогресс не меняет и пересчёт не нужен.
+// Прогресс всех студентов читается одним запросом. Подписчики OnModuleCompleted вызываются так же,
+// как при прохождении урока, - синхронно для каждого студента, которому пересчёт впервые засчитал модуль.
+func (uc *LessonProgressUseCase) RecalculateCourseProgress(ctx context.Context, courseID int, moduleID *int) error {
+    versions, err := uc.versionService.GetVersions(ctx, courseID)
+    if err != nil {
+        return err
+    }
+    if len(versions) > 0 {
+        return nil
+    }
+
+    enrollments, err := uc.enrollmentService.GetEnrollmentsByCourse(ctx, courseID)
+    if err != nil {
+        return err
+    }
+    lessons, err := uc.lessonService.GetAllLessons(ctx, courseID)
+    if err != nil {
+        return err
+    }
+    progresses, err := uc.lessonProgressService.GetCompletedByCourse(ctx, courseID)
+    if err != nil {
+        return err
+    }
+    completedByUser := make(map[int]map[int]bool)
+    for _, progress := range progresses {
+        if completedByUser[progress.UserID] == nil {
+            completedByUser[progress.UserID] = make(map[int]bool)
+        }
+        completedByUser[progress.UserID][progress.LessonID] = true
+    }
+
+    var reopened []int
+    for _, enrollment := range enrollments {
+        if enrollment.Status != "active" && enrollment.Status != "completed" {
+            continue
+        }
+        completed := completedByUser[enrollment.UserID]
+
+        // модуль без уроков не считается пройденным
+        moduleLessons, moduleCompleted := 0, 0
+        courseCompleted := 0
+        for _, lesson := range lessons {
+            if completed[lesson.ID] {
+                courseCompleted++
+            }
+            if moduleKey(lesson.ModuleID) == moduleKey(moduleID) {
+                moduleLessons++
+                if completed[lesson.ID] {
+                    moduleCompleted++
+                }
+            }
+        }
+        if moduleLessons > 0 && moduleCompleted == moduleLessons {
+            if err := uc.recordModuleCompleted(ctx, enrollment.UserID, courseID, moduleKey(moduleID)); err != nil {
+                return err
+            }
+        } else {
+            reopened = append(reopened, enrollment.UserID)
+        }
+
+        courseDone := len(lessons) > 0 && courseCompleted == len(lessons)
+        switch {
+        case courseDone && enrollment.Status == "active":
+            if err := uc.enrollmentService.MarkAsCompleted(ctx, enrollment.UserID, courseID); err != nil {
+                return err
+            }
+        case !courseDone && enrollment.Status == "completed":
+            if err := uc.enrollmentService.MarkAsActive(ctx, enrollment.UserID, courseID); err != nil {
+                return err
+            }
+        }
     }
 
-    // Считаем процент завершения
-    completedCount := 0
+    if len(reopened) > 0 {
+        return uc.moduleService.DeleteCompletions(ctx, courseID, moduleKey(moduleID), reopened)
+    }
+    return nil
+}
+
+func completedLessonIDs(progresses []*models.LessonProgress) map[int]bool {
+    completed := make(map[int]bool, len(progresses))
     for _, progress := range progresses {
         if progress.IsCompleted {
-            completedCount++
+            completed[progress.LessonID] = true
         }
     }
+    return completed
+}
 
-    if completedCount == len(lessons) {
-        uc.enrollmentService.MarkAsCompleted(ctx, userID, courseID)
+// moduleKey - ID модуля урока, 0 для модуля по умолчанию
+func moduleKey(moduleID *int) int {
+    if moduleID == nil {
+        return 0
     }
+    return *moduleID
+}
 
-    return float64(completedCount) / float64(len(lessons)) * 100, nil
-}
\ No newline at end of file
+func percent(part, total int) float64 {
+    if total == 0 {
+        return 0
+    }
+    return float64(part) / float64(total) * 100
+}
@@ -0,0 +1,410 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+	"gitlab.com/w0ikid/study-platform/pkg/mailer"
+)
+
+// за сколько дней назад рассылка ищет открывшиеся уроки, если задача какое-то время не запускалась
+const lessonUnlockLookbackDays = 7
+
+type LessonReleaseUseCaseInterface interface {
+	GetRules(ctx context.Context, userID int, userRole string, courseID int) ([]*models.LessonReleaseRule, error)
+	SetRule(ctx context.Context, userID int, userRole string, courseID, lessonID int, input *dto.LessonReleaseRuleRequest) (*models.LessonReleaseRule, error)
+	DeleteRule(ctx context.Context, userID int, userRole string, courseID, lessonID int) error
+	LockedLessons(ctx context.Context, userID int, userRole string, courseID int) (map[int]*models.LessonLock, error)
+	CheckLessonReleased(ctx context.Context, userID int, userRole string, courseID, lessonID int) error
+	CheckReleased(ctx context.Context, userID int, lesson *models.Lesson) error
+	DispatchUnlockNotifications(ctx context.Context) error
+}
+
+type LessonReleaseUseCase struct {
+	releaseService    services.LessonReleaseServiceInterface
+	lessonService     services.LessonServiceInterface
+	courseService     services.CourseServiceInterface
+	enrollmentService services.EnrollmentServiceInterface
+	progressService   services.LessonProgressServiceInterface
+	mailer            mailer.Mailer
+	appURL            string
+}
+
+func NewLessonReleaseUseCase(
+	releaseService services.LessonReleaseServiceInterface,
+	lessonService services.LessonServiceInterface,
+	courseService services.CourseServiceInterface,
+	enrollmentService services.EnrollmentServiceInterface,
+	progressService services.LessonProgressServiceInterface,
+	mailer mailer.Mailer,
+	appURL string,
+) *LessonReleaseUseCase {
+	return &LessonReleaseUseCase{
+		releaseService:    releaseService,
+		lessonService:     lessonService,
+		courseService:     courseService,
+		enrollmentService: enrollmentService,
+		progressService:   progressService,
+		mailer:            mailer,
+		appURL:            appURL,
+	}
+}
+
+// GetRules возвращает правила открытия уроков курса для команды курса
+func (u *LessonReleaseUseCase) GetRules(ctx context.Context, userID int, userRole string, courseID int) ([]*models.LessonReleaseRule, error) {
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseStaffRoles); err != nil {
+		return nil, err
+	}
+	return u.releaseService.GetCourseRules(ctx, courseID)
+}
+
+// SetRule задаёт или заменяет правило открытия урока
+func (u *LessonReleaseUseCase) SetRule(ctx context.Context, userID int, userRole string, courseID, lessonID int, input *dto.LessonReleaseRuleRequest) (*models.LessonReleaseRule, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseEditorRoles); err != nil {
+		return nil, err
+	}
+	if _, err := getCourseLesson(ctx, u.lessonService, courseID, lessonID); err != nil {
+		return nil, err
+	}
+
+	rule := &models.LessonReleaseRule{
+		LessonID:            lessonID,
+		Type:                input.Type,
+		ReleaseAt:           input.ReleaseAt,
+		DaysAfterEnrollment: input.DaysAfterEnrollment,
+		AfterLessonID:       input.AfterLessonID,
+	}
+	if err := checkReleaseRule(rule); err != nil {
+		return nil, err
+	}
+	if rule.Type == models.LessonReleaseAfterLesson {
+		if err := u.checkAfterLesson(ctx, courseID, lessonID, *rule.AfterLessonID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := u.releaseService.SaveRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// DeleteRule убирает правило, урок становится доступен сразу
+func (u *LessonReleaseUseCase) DeleteRule(ctx context.Context, userID int, userRole string, courseID, lessonID int) error {
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseEditorRoles); err != nil {
+		return err
+	}
+	if _, err := getCourseLesson(ctx, u.lessonService, courseID, lessonID); err != nil {
+		return err
+	}
+	return u.releaseService.DeleteRule(ctx, lessonID)
+}
+
+// LockedLessons возвращает закрытые для пользователя уроки курса. Администратору и команде курса доступно всё.
+func (u *LessonReleaseUseCase) LockedLessons(ctx context.Context, userID int, userRole string, courseID int) (map[int]*models.LessonLock, error) {
+	rules, err := u.releaseService.GetCourseRules(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return map[int]*models.LessonLock{}, nil
+	}
+	staff, err := u.isCourseStaff(ctx, userID, userRole, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if staff {
+		return map[int]*models.LessonLock{}, nil
+	}
+	return u.lessonLocks(ctx, userID, courseID, rules)
+}
+
+// CheckLessonReleased возвращает ErrPermissionDenied, если урок ещё закрыт для пользователя
+func (u *LessonReleaseUseCase) CheckLessonReleased(ctx context.Context, userID int, userRole string, courseID, lessonID int) error {
+	lock, err := u.lessonLock(ctx, userID, userRole, courseID, lessonID)
+	if err != nil {
+		return err
+	}
+	if lock != nil {
+		return fmt.Errorf("%w: %s", ErrPermissionDenied, lockMessage(lock))
+	}
+	return nil
+}
+
+// CheckReleased - условие завершения урока для LessonProgressUseCase: закрытый урок завершить нельзя
+func (u *LessonReleaseUseCase) CheckReleased(ctx context.Context, userID int, lesson *models.Lesson) error {
+	lock, err := u.lessonLock(ctx, userID, "", lesson.CourseID, lesson.ID)
+	if err != nil {
+		return err
+	}
+	if lock != nil {
+		return fmt.Errorf("%w: %s", ErrLessonRequirementsNotMet, lockMessage(lock))
+	}
+	return nil
+}
+
+// DispatchUnlockNotifications отправляет студентам письма об уроках, открывшихся по дате.
+// Уроки одного курса собираются в одно письмо; уроки, открытые завершением другого урока,
+// студент открывает сам, и о них письмо не отправляется.
+func (u *LessonReleaseUseCase) DispatchUnlockNotifications(ctx context.Context) error {
+	unlocks, err := u.releaseService.GetPendingUnlocks(ctx, lessonUnlockLookbackDays)
+	if err != nil {
+		return err
+	}
+
+	// уроки отсортированы по студенту и курсу
+	for start := 0; start < len(unlocks); {
+		end := start + 1
+		for end < len(unlocks) && unlocks[end].UserID == unlocks[start].UserID && unlocks[end].CourseID == unlocks[start].CourseID {
+			end++
+		}
+		if err := u.notifyUnlocks(ctx, unlocks[start:end]); err != nil {
+			return err
+		}
+		start = end
+	}
+	return nil
+}
+
+// RunUnlockNotifier периодически рассылает письма об открывшихся уроках, пока не отменён ctx
+func (u *LessonReleaseUseCase) RunUnlockNotifier(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := u.DispatchUnlockNotifications(ctx); err != nil {
+			log.Printf("lesson release: unlock notifications failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// notifyUnlocks отправляет одно письмо студенту об уроках курса
+func (u *LessonReleaseUseCase) notifyUnlocks(ctx context.Context, unlocks []*models.LessonUnlock) error {
+	lessonIDs := make([]int, len(unlocks))
+	for i, unlock := range unlocks {
+		lessonIDs[i] = unlock.LessonID
+	}
+	// отметка ставится до отправки, чтобы письма не ушли дважды
+	claimed, err := u.releaseService.MarkNotified(ctx, unlocks[0].UserID, lessonIDs)
+	if err != nil {
+		return err
+	}
+	claimedIDs := make(map[int]bool, len(claimed))
+	for _, lessonID := range claimed {
+		claimedIDs[lessonID] = true
+	}
+
+	var titles []string
+	for _, unlock := range unlocks {
+		if claimedIDs[unlock.LessonID] {
+			titles = append(titles, "- "+unlock.LessonTitle)
+		}
+	}
+	if len(titles) == 0 {
+		return nil
+	}
+
+	first := unlocks[0]
+	subject := fmt.Sprintf("[%s] New lessons are available", first.CourseName)
+	body := fmt.Sprintf(`Hello %s,
+
+New lessons are now available in %s:
+
+%s
+
+Open the course at %s/courses/%d
+`, first.Name, first.CourseName, strings.Join(titles, "\n"), u.appURL, first.CourseID)
+	if err := u.mailer.Send(first.Email, subject, body); err != nil {
+		log.Printf("lesson release: failed to notify user %d: %v", first.UserID, err)
+	}
+	return nil
+}
+
+// lessonLock возвращает причину, по которой урок закрыт для пользователя, или nil
+func (u *LessonReleaseUseCase) lessonLock(ctx context.Context, userID int, userRole string, courseID, lessonID int) (*models.LessonLock, error) {
+	rule, err := u.releaseService.GetRule(ctx, lessonID)
+	if err != nil || rule == nil {
+		return nil, err
+	}
+	staff, err := u.isCourseStaff(ctx, userID, userRole, courseID)
+	if err != nil || staff {
+		return nil, err
+	}
+	locks, err := u.lessonLocks(ctx, userID, courseID, []*models.LessonReleaseRule{rule})
+	if err != nil {
+		return nil, err
+	}
+	return locks[lessonID], nil
+}
+
+// lessonLocks применяет правила к записи студента на курс и его завершённым урокам
+func (u *LessonReleaseUseCase) lessonLocks(ctx context.Context, userID, courseID int, rules []*models.LessonReleaseRule) (map[int]*models.LessonLock, error) {
+	var needEnrollment, needProgress bool
+	for _, rule := range rules {
+		needEnrollment = needEnrollment || rule.Type == models.LessonReleaseEnrollmentDays
+		needProgress = needProgress || rule.Type == models.LessonReleaseAfterLesson
+	}
+
+	var enrolledAt *time.Time
+	if needEnrollment {
+		enrolled, err := u.enrollmentService.IsUserEnrolled(ctx, userID, courseID)
+		if err != nil {
+			return nil, err
+		}
+		if enrolled {
+			enrollment, err := u.enrollmentService.GetEnrollmentByUserAndCourse(ctx, userID, courseID)
+			if err != nil {
+				return nil, err
+			}
+			enrolledAt = &enrollment.CreatedAt
+		}
+	}
+	completed := map[int]bool{}
+	if needProgress {
+		progresses, err := u.progressService.GetProgressByCourse(ctx, userID, courseID)
+		if err != nil {
+			return nil, err
+		}
+		completed = completedLessonIDs(progresses)
+	}
+
+	now := time.Now()
+	locks := make(map[int]*models.LessonLock)
+	for _, rule := range rules {
+		if lock := releaseLock(rule, enrolledAt, completed, now); lock != nil {
+			locks[rule.LessonID] = lock
+		}
+	}
+	return locks, nil
+}
+
+// isCourseStaff - администратор и команда курса видят уроки без ограничений
+func (u *LessonReleaseUseCase) isCourseStaff(ctx context.Context, userID int, userRole string, courseID int) (bool, error) {
+	if userRole == "admin" {
+		return true, nil
+	}
+	member, err := u.courseService.GetStaffMember(ctx, courseID, userID)
+	if err != nil {
+		return false, err
+	}
+	return member != nil, nil
+}
+
+// checkAfterLesson проверяет, что урок-условие из того же курса и правила не образуют цикл
+func (u *LessonReleaseUseCase) checkAfterLesson(ctx context.Context, courseID, lessonID, afterLessonID int) error {
+	if afterLessonID == lessonID {
+		return errors.New("validation failed: lesson cannot be released after itself")
+	}
+	if _, err := getCourseLesson(ctx, u.lessonService, courseID, afterLessonID); err != nil {
+		return errors.New("validation failed: after_lesson_id must be a lesson of this course")
+	}
+
+	rules, err := u.releaseService.GetCourseRules(ctx, courseID)
+	if err != nil {
+		return err
+	}
+	after := make(map[int]int, len(rules))
+	for _, rule := range rules {
+		if rule.Type == models.LessonReleaseAfterLesson && rule.AfterLessonID != nil {
+			after[rule.LessonID] = *rule.AfterLessonID
+		}
+	}
+	for current, steps := afterLessonID, 0; steps <= len(after); steps++ {
+		next, ok := after[current]
+		if !ok {
+			return nil
+		}
+		if next == lessonID {
+			return errors.New("validation failed: release rules must not form a cycle")
+		}
+		current = next
+	}
+	return nil
+}
+
+// checkReleaseRule проверяет, что у правила заполнено ровно поле его типа
+func checkReleaseRule(rule *models.LessonReleaseRule) error {
+	var field string
+	var set, others bool
+	switch rule.Type {
+	case models.LessonReleaseDate:
+		field, set, others = "release_at", rule.ReleaseAt != nil, rule.DaysAfterEnrollment != nil || rule.AfterLessonID != nil
+	case models.LessonReleaseEnrollmentDays:
+		field, set, others = "days_after_enrollment", rule.DaysAfterEnrollment != nil, rule.ReleaseAt != nil || rule.AfterLessonID != nil
+		if set && (*rule.DaysAfterEnrollment < 0 || *rule.DaysAfterEnrollment > 3650) {
+			return errors.New("validation failed: days_after_enrollment must be between 0 and 3650")
+		}
+	case models.LessonReleaseAfterLesson:
+		field, set, others = "after_lesson_id", rule.AfterLessonID != nil, rule.ReleaseAt != nil || rule.DaysAfterEnrollment != nil
+	default:
+		return fmt.Errorf("validation failed: unknown release rule type %q", rule.Type)
+	}
+	if !set {
+		return fmt.Errorf("validation failed: %s is required for %s rules", field, rule.Type)
+	}
+	if others {
+		return fmt.Errorf("validation failed: only %s can be set for %s rules", field, rule.Type)
+	}
+	return nil
+}
+
+// releaseLock возвращает причину, по которой правило закрывает урок в момент now, или nil
+func releaseLock(rule *models.LessonReleaseRule, enrolledAt *time.Time, completed map[int]bool, now time.Time) *models.LessonLock {
+	switch rule.Type {
+	case models.LessonReleaseDate:
+		if rule.ReleaseAt != nil && now.Before(*rule.ReleaseAt) {
+			return &models.LessonLock{Type: rule.Type, AvailableAt: rule.ReleaseAt}
+		}
+	case models.LessonReleaseEnrollmentDays:
+		if rule.DaysAfterEnrollment == nil {
+			return nil
+		}
+		if enrolledAt == nil {
+			return &models.LessonLock{Type: rule.Type}
+		}
+		availableAt := enrolledAt.AddDate(0, 0, *rule.DaysAfterEnrollment)
+		if now.Before(availableAt) {
+			return &models.LessonLock{Type: rule.Type, AvailableAt: &availableAt}
+		}
+	case models.LessonReleaseAfterLesson:
+		if rule.AfterLessonID != nil && !completed[*rule.AfterLessonID] {
+			return &models.LessonLock{Type: rule.Type, AfterLessonID: rule.AfterLessonID}
+		}
+	}
+	return nil
+}
+
+func lockMessage(lock *models.LessonLock) string {
+	switch {
+	case lock.AvailableAt != nil:
+		return "lesson is locked until " + lock.AvailableAt.Format(time.RFC3339)
+	case lock.AfterLessonID != nil:
+		return fmt.Sprintf("lesson is locked until lesson %d is completed", *lock.AfterLessonID)
+	default:
+		return "lesson is not released yet"
+	}
+}
+
+// lessonTeaser оставляет у закрытого урока только название и место в курсе
+func lessonTeaser(lesson *models.Lesson, lock *models.LessonLock) {
+	lesson.Content = ""
+	lesson.ContentHTML = ""
+	lesson.VideoURL = ""
+	lesson.ResumePosition = nil
+	lesson.Lock = lock
+}
@@ -0,0 +1,223 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+// Mock для LessonReleaseService
+type MockLessonReleaseService struct {
+	mock.Mock
+	services.LessonReleaseServiceInterface
+}
+
+func (m *MockLessonReleaseService) GetRule(ctx context.Context, lessonID int) (*models.LessonReleaseRule, error) {
+	args := m.Called(ctx, lessonID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LessonReleaseRule), args.Error(1)
+}
+
+func (m *MockLessonReleaseService) GetCourseRules(ctx context.Context, courseID int) ([]*models.LessonReleaseRule, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]*models.LessonReleaseRule), args.Error(1)
+}
+
+func (m *MockLessonReleaseService) SaveRule(ctx context.Context, rule *models.LessonReleaseRule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *MockLessonReleaseService) GetPendingUnlocks(ctx context.Context, lookbackDays int) ([]*models.LessonUnlock, error) {
+	args := m.Called(ctx, lookbackDays)
+	return args.Get(0).([]*models.LessonUnlock), args.Error(1)
+}
+
+func (m *MockLessonReleaseService) MarkNotified(ctx context.Context, userID int, lessonIDs []int) ([]int, error) {
+	args := m.Called(ctx, userID, lessonIDs)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockVideoProgressService) GetCourseProgress(ctx context.Context, userID, courseID int) ([]*models.VideoProgress, error) {
+	args := m.Called(ctx, userID, courseID)
+	return args.Get(0).([]*models.VideoProgress), args.Error(1)
+}
+
+func after(d time.Duration) *time.Time {
+	t := time.Now().Add(d)
+	return &t
+}
+
+func TestGetLessonsForStudentReturnsLockedLessonsAsTeasers(t *testing.T) {
+	ctx := context.Background()
+	releaseService := new(MockLessonReleaseService)
+	courseService := new(MockCourseService)
+	enrollmentService := new(MockEnrollmentService)
+	progressService := new(MockLessonProgressService)
+	lessonService := new(MockLessonService)
+	moduleService := new(MockModuleService)
+	versionService := new(MockCourseVersionService)
+	videoService := new(MockVideoProgressService)
+	release := usecase.NewLessonReleaseUseCase(releaseService, lessonService, courseService, enrollmentService, progressService, nil, "")
+	useCase := usecase.NewLessonUseCase(lessonService, enrollmentService, courseService, moduleService, versionService, videoService, release, nil, nil)
+
+	releaseAt := after(48 * time.Hour)
+	releaseService.On("GetCourseRules", ctx, 10).Return([]*models.LessonReleaseRule{
+		{LessonID: 1, Type: models.LessonReleaseDate, ReleaseAt: ago(time.Hour)},
+		{LessonID: 2, Type: models.LessonReleaseDate, ReleaseAt: releaseAt},
+		{LessonID: 3, Type: models.LessonReleaseEnrollmentDays, DaysAfterEnrollment: intPtr(3)},
+		{LessonID: 4, Type: models.LessonReleaseEnrollmentDays, DaysAfterEnrollment: intPtr(1)},
+		{LessonID: 5, Type: models.LessonReleaseAfterLesson, AfterLessonID: intPtr(1)},
+		{LessonID: 6, Type: models.LessonReleaseAfterLesson, AfterLessonID: intPtr(5)},
+	}, nil)
+	courseService.On("GetStaffMember", ctx, 10, 7).Return(nil, nil)
+	enrollmentService.On("IsUserEnrolled", ctx, 7, 10).Return(true, nil)
+	enrolledAt := time.Now().Add(-36 * time.Hour)
+	enrollmentService.On("GetEnrollmentByUserAndCourse", ctx, 7, 10).Return(&models.Enrollment{UserID: 7, CourseID: 10, CreatedAt: enrolledAt}, nil)
+	progressService.On("GetProgressByCourse", ctx, 7, 10).Return([]*models.LessonProgress{{LessonID: 1, IsCompleted: true}}, nil)
+
+	versionService.On("GetPinnedVersion", ctx, 7, 10).Return(nil, nil)
+	moduleService.On("GetModulesByCourse", ctx, 10).Return([]*models.Module{}, nil)
+	lessons := []*models.Lesson{{ID: 7, CourseID: 10, Title: "Welcome", Content: "hi", ContentHTML: "<p>hi</p>"}}
+	for id := 1; id <= 6; id++ {
+		lessons = append(lessons, &models.Lesson{ID: id, CourseID: 10, Title: "Lesson", Content: "text", ContentHTML: "<p>text</p>", VideoURL: "https://video.example.com"})
+	}
+	lessonService.On("GetAllLessons", ctx, 10).Return(lessons, nil)
+	videoService.On("GetCourseProgress", ctx, 7, 10).Return([]*models.VideoProgress{{LessonID: 2, Position: 30, Duration: 100}}, nil)
+
+	result, err := useCase.GetLessonsForStudent(ctx, 7, "student", 10)
+
+	assert.NoError(t, err)
+	locked := map[int]*models.Lesson{}
+	for _, lesson := range result {
+		if lesson.Lock != nil {
+			locked[lesson.ID] = lesson
+		}
+	}
+	assert.Len(t, locked, 3)
+	assert.Equal(t, releaseAt, locked[2].Lock.AvailableAt)
+	assert.Empty(t, locked[2].Content)
+	assert.Empty(t, locked[2].ContentHTML)
+	assert.Empty(t, locked[2].VideoURL)
+	assert.Nil(t, locked[2].ResumePosition)
+	assert.Equal(t, "Lesson", locked[2].Title)
+	assert.WithinDuration(t, enrolledAt.AddDate(0, 0, 3), *locked[3].Lock.AvailableAt, time.Second)
+	assert.Equal(t, 5, *locked[6].Lock.AfterLessonID)
+	assert.Nil(t, locked[6].Lock.AvailableAt)
+}
+
+func TestLockedLessonsSkipsCourseStaff(t *testing.T) {
+	ctx := context.Background()
+	releaseService := new(MockLessonReleaseService)
+	courseService := new(MockCourseService)
+	enrollmentService := new(MockEnrollmentService)
+	useCase := usecase.NewLessonReleaseUseCase(releaseService, nil, courseService, enrollmentService, nil, nil, "")
+
+	releaseService.On("GetCourseRules", ctx, 10).Return([]*models.LessonReleaseRule{
+		{LessonID: 2, Type: models.LessonReleaseEnrollmentDays, DaysAfterEnrollment: intPtr(3)},
+	}, nil)
+	courseService.On("GetStaffMember", ctx, 10, 6).Return(&models.CourseStaff{CourseID: 10, UserID: 6, Role: models.CourseStaffRoleTA}, nil)
+
+	locks, err := useCase.LockedLessons(ctx, 6, "student", 10)
+
+	assert.NoError(t, err)
+	assert.Empty(t, locks)
+	enrollmentService.AssertNotCalled(t, "IsUserEnrolled", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCheckLessonReleased(t *testing.T) {
+	ctx := context.Background()
+	releaseService := new(MockLessonReleaseService)
+	courseService := new(MockCourseService)
+	useCase := usecase.NewLessonReleaseUseCase(releaseService, nil, courseService, nil, nil, nil, "")
+
+	releaseService.On("GetRule", ctx, 2).Return(&models.LessonReleaseRule{LessonID: 2, Type: models.LessonReleaseDate, ReleaseAt: after(time.Hour)}, nil)
+	releaseService.On("GetRule", ctx, 3).Return(nil, nil)
+	courseService.On("GetStaffMember", ctx, 10, 7).Return(nil, nil)
+
+	err := useCase.CheckLessonReleased(ctx, 7, "student", 10, 2)
+	assert.True(t, errors.Is(err, usecase.ErrPermissionDenied))
+	assert.ErrorContains(t, err, "lesson is locked until")
+
+	assert.NoError(t, useCase.CheckLessonReleased(ctx, 7, "student", 10, 3))
+	assert.NoError(t, useCase.CheckLessonReleased(ctx, 1, "admin", 10, 2))
+
+	err = useCase.CheckReleased(ctx, 7, &models.Lesson{ID: 2, CourseID: 10})
+	assert.True(t, errors.Is(err, usecase.ErrLessonRequirementsNotMet))
+}
+
+func TestSetRuleRejectsCycle(t *testing.T) {
+	ctx := context.Background()
+	releaseService := new(MockLessonReleaseService)
+	lessonService := new(MockLessonService)
+	courseService := new(MockCourseService)
+	useCase := usecase.NewLessonReleaseUseCase(releaseService, lessonService, courseService, nil, nil, nil, "")
+
+	courseService.On("GetCourse", ctx, 10).Return(&models.Course{ID: 10, TeacherID: 5}, nil)
+	lessonService.On("GetLessonByID", ctx, 20).Return(&models.Lesson{ID: 20, CourseID: 10}, nil)
+	lessonService.On("GetLessonByID", ctx, 21).Return(&models.Lesson{ID: 21, CourseID: 10}, nil)
+	releaseService.On("GetCourseRules", ctx, 10).Return([]*models.LessonReleaseRule{
+		{LessonID: 21, Type: models.LessonReleaseAfterLesson, AfterLessonID: intPtr(20)},
+	}, nil)
+
+	input := &dto.LessonReleaseRuleRequest{Type: models.LessonReleaseAfterLesson, AfterLessonID: intPtr(21)}
+	rule, err := useCase.SetRule(ctx, 1, "admin", 10, 20, input)
+
+	assert.Nil(t, rule)
+	assert.EqualError(t, err, "validation failed: release rules must not form a cycle")
+	releaseService.AssertNotCalled(t, "SaveRule", mock.Anything, mock.Anything)
+}
+
+func TestSetRuleRequiresOnlyFieldOfType(t *testing.T) {
+	ctx := context.Background()
+	lessonService := new(MockLessonService)
+	courseService := new(MockCourseService)
+	useCase := usecase.NewLessonReleaseUseCase(new(MockLessonReleaseService), lessonService, courseService, nil, nil, nil, "")
+
+	courseService.On("GetCourse", ctx, 10).Return(&models.Course{ID: 10, TeacherID: 5}, nil)
+	lessonService.On("GetLessonByID", ctx, 20).Return(&models.Lesson{ID: 20, CourseID: 10}, nil)
+
+	_, err := useCase.SetRule(ctx, 1, "admin", 10, 20, &dto.LessonReleaseRuleRequest{Type: models.LessonReleaseDate})
+	assert.EqualError(t, err, "validation failed: release_at is required for date rules")
+
+	input := &dto.LessonReleaseRuleRequest{Type: models.LessonReleaseDate, ReleaseAt: after(time.Hour), DaysAfterEnrollment: intPtr(2)}
+	_, err = useCase.SetRule(ctx, 1, "admin", 10, 20, input)
+	assert.EqualError(t, err, "validation failed: only release_at can be set for date rules")
+}
+
+func TestDispatchUnlockNotificationsSendsOneEmailPerCourse(t *testing.T) {
+	ctx := context.Background()
+	releaseService := new(MockLessonReleaseService)
+	mail := new(MockMailer)
+	useCase := usecase.NewLessonReleaseUseCase(releaseService, nil, nil, nil, nil, mail, "http://localhost")
+
+	releaseService.On("GetPendingUnlocks", ctx, 7).Return([]*models.LessonUnlock{
+		{UserID: 5, Email: "ann@example.com", Name: "Ann", CourseID: 10, CourseName: "Go 101", LessonID: 1, LessonTitle: "Channels"},
+		{UserID: 5, Email: "ann@example.com", Name: "Ann", CourseID: 10, CourseName: "Go 101", LessonID: 2, LessonTitle: "Select"},
+		{UserID: 6, Email: "bob@example.com", Name: "Bob", CourseID: 10, CourseName: "Go 101", LessonID: 1, LessonTitle: "Channels"},
+	}, nil)
+	releaseService.On("MarkNotified", ctx, 5, []int{1, 2}).Return([]int{1, 2}, nil)
+	// уведомление уже отправлено другим экземпляром задачи
+	releaseService.On("MarkNotified", ctx, 6, []int{1}).Return([]int{}, nil)
+	mail.On("Send", "ann@example.com", "[Go 101] New lessons are available", mock.MatchedBy(func(body string) bool {
+		return strings.Contains(body, "- Channels\n- Select") && strings.Contains(body, "http://localhost/courses/10")
+	})).Return(nil).Once()
+
+	err := useCase.DispatchUnlockNotifications(ctx)
+
+	assert.NoError(t, err)
+	mail.AssertExpectations(t)
+	mail.AssertNotCalled(t, "Send", "bob@example.com", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+)
+
+type LessonRevisionUseCaseInterface interface {
+	ListRevisions(ctx context.Context, userID int, userRole string, courseID, lessonID int) ([]*models.LessonRevision, error)
+	GetRevision(ctx context.Context, userID int, userRole string, courseID, lessonID, number int) (*models.LessonRevision, error)
+	DiffRevisions(ctx context.Context, userID int, userRole string, courseID, lessonID, from, to int) (*models.LessonRevisionDiff, error)
+	RestoreRevision(ctx context.Context, userID int, userRole string, courseID, lessonID, number int) (*models.LessonRevision, error)
+}
+
+type LessonRevisionUseCase struct {
+	revisionService services.LessonRevisionServiceInterface
+	lessonService   services.LessonServiceInterface
+	courseService   services.CourseServiceInterface
+}
+
+func NewLessonRevisionUseCase(
+	revisionService services.LessonRevisionServiceInterface,
+	lessonService services.LessonServiceInterface,
+	courseService services.CourseServiceInterface,
+) *LessonRevisionUseCase {
+	return &LessonRevisionUseCase{
+		revisionService: revisionService,
+		lessonService:   lessonService,
+		courseService:   courseService,
+	}
+}
+
+// ListRevisions возвращает историю правок урока, новые ревизии первыми
+func (u *LessonRevisionUseCase) ListRevisions(ctx context.Context, userID int, userRole string, courseID, lessonID int) ([]*models.LessonRevision, error) {
+	if _, err := u.getLesson(ctx, userID, userRole, courseID, lessonID, courseStaffRoles); err != nil {
+		return nil, err
+	}
+	return u.revisionService.GetLessonRevisions(ctx, lessonID)
+}
+
+// GetRevision возвращает ревизию урока вместе с содержимым
+func (u *LessonRevisionUseCase) GetRevision(ctx context.Context, userID int, userRole string, courseID, lessonID, number int) (*models.LessonRevision, error) {
+	if _, err := u.getLesson(ctx, userID, userRole, courseID, lessonID, courseStaffRoles); err != nil {
+		return nil, err
+	}
+	return u.revisionService.GetRevision(ctx, lessonID, number)
+}
+
+// DiffRevisions сравнивает две ревизии урока: построчный unified diff содержимого
+// и изменения названия и ссылки на видео
+func (u *LessonRevisionUseCase) DiffRevisions(ctx context.Context, userID int, userRole string, courseID, lessonID, from, to int) (*models.LessonRevisionDiff, error) {
+	if from <= 0 || to <= 0 {
+		return nil, errors.New("validation failed: from and to must be revision numbers")
+	}
+	if _, err := u.getLesson(ctx, userID, userRole, courseID, lessonID, courseStaffRoles); err != nil {
+		return nil, err
+	}
+	fromRevision, err := u.revisionService.GetRevision(ctx, lessonID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := u.revisionService.GetRevision(ctx, lessonID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &models.LessonRevisionDiff{LessonID: lessonID, From: from, To: to, Changes: []models.LessonFieldChange{}}
+	if fromRevision.Title != toRevision.Title {
+		diff.Changes = append(diff.Changes, models.LessonFieldChange{Field: "title", From: fromRevision.Title, To: toRevision.Title})
+	}
+	if fromRevision.VideoURL != toRevision.VideoURL {
+		diff.Changes = append(diff.Changes, models.LessonFieldChange{Field: "video_url", From: fromRevision.VideoURL, To: toRevision.VideoURL})
+	}
+
+	a := difflib.SplitLines(fromRevision.Content)
+	b := difflib.SplitLines(toRevision.Content)
+	diff.ContentDiff, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        a,
+		B:        b,
+		FromFile: fmt.Sprintf("revision %d", from),
+		ToFile:   fmt.Sprintf("revision %d", to),
+		Context:  3,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff revisions: %w", err)
+	}
+	// Строки считаем по опкодам, а не по префиксам вывода: строка markdown сама может начинаться с "+" или "-"
+	for _, op := range difflib.NewMatcher(a, b).GetOpCodes() {
+		if op.Tag == 'r' || op.Tag == 'd' {
+			diff.RemovedLines += op.I2 - op.I1
+		}
+		if op.Tag == 'r' || op.Tag == 'i' {
+			diff.AddedLines += op.J2 - op.J1
+		}
+	}
+	return diff, nil
+}
+
+// RestoreRevision возвращает урок к содержимому ревизии. История не переписывается:
+// восстановление сохраняется как новая ревизия со ссылкой на исходную.
+func (u *LessonRevisionUseCase) RestoreRevision(ctx context.Context, userID int, userRole string, courseID, lessonID, number int) (*models.LessonRevision, error) {
+	lesson, err := u.getLesson(ctx, userID, userRole, courseID, lessonID, courseEditorRoles)
+	if err != nil {
+		return nil, err
+	}
+	revision, err := u.revisionService.GetRevision(ctx, lessonID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	if lesson.VideoURL != revision.VideoURL {
+		// длительность относилась к другому видео
+		lesson.VideoDuration = nil
+	}
+	lesson.Title = revision.Title
+	lesson.Content = revision.Content
+	lesson.VideoURL = revision.VideoURL
+	if err := renderLessonContent(lesson); err != nil {
+		return nil, err
+	}
+	return u.revisionService.SaveLesson(ctx, lesson, userID, &revision.Number)
+}
+
+func (u *LessonRevisionUseCase) getLesson(ctx context.Context, userID int, userRole string, courseID, lessonID int, roles []string) (*models.Lesson, error) {
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, roles); err != nil {
+		return nil, err
+	}
+	return getCourseLesson(ctx, u.lessonService, courseID, lessonID)
+}
@@ -0,0 +1,143 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+)
+
+// Mock для LessonRevisionService
+type MockLessonRevisionService struct {
+	mock.Mock
+	services.LessonRevisionServiceInterface
+}
+
+func (m *MockLessonRevisionService) RecordRevision(ctx context.Context, lesson *models.Lesson, authorID int, restoredFrom *int) (*models.LessonRevision, error) {
+	args := m.Called(ctx, lesson, authorID, restoredFrom)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LessonRevision), args.Error(1)
+}
+
+func (m *MockLessonRevisionService) SaveLesson(ctx context.Context, lesson *models.Lesson, authorID int, restoredFrom *int) (*models.LessonRevision, error) {
+	args := m.Called(ctx, lesson, authorID, restoredFrom)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LessonRevision), args.Error(1)
+}
+
+func (m *MockLessonRevisionService) GetRevision(ctx context.Context, lessonID, number int) (*models.LessonRevision, error) {
+	args := m.Called(ctx, lessonID, number)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LessonRevision), args.Error(1)
+}
+
+func (m *MockLessonRevisionService) GetLessonRevisions(ctx context.Context, lessonID int) ([]*models.LessonRevision, error) {
+	args := m.Called(ctx, lessonID)
+	return args.Get(0).([]*models.LessonRevision), args.Error(1)
+}
+
+func (m *MockLessonService) UpdateLesson(ctx context.Context, lesson *models.Lesson) error {
+	args := m.Called(ctx, lesson)
+	return args.Error(0)
+}
+
+func TestDiffRevisions(t *testing.T) {
+	ctx := context.Background()
+	revisionService := new(MockLessonRevisionService)
+	lessonService := new(MockLessonService)
+	courseService := new(MockCourseService)
+	useCase := usecase.NewLessonRevisionUseCase(revisionService, lessonService, courseService)
+
+	courseService.On("GetCourse", ctx, 1).Return(&models.Course{ID: 1}, nil)
+	lessonService.On("GetLessonByID", ctx, 10).Return(&models.Lesson{ID: 10, CourseID: 1}, nil)
+	revisionService.On("GetRevision", ctx, 10, 1).Return(&models.LessonRevision{
+		Number: 1, Title: "Intro", Content: "# Intro\n\nold line\n- item\n",
+	}, nil)
+	revisionService.On("GetRevision", ctx, 10, 2).Return(&models.LessonRevision{
+		Number: 2, Title: "Introduction", Content: "# Intro\n\nnew line\n- item\n- another item\n",
+	}, nil)
+
+	diff, err := useCase.DiffRevisions(ctx, 1, "admin", 1, 10, 1, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.LessonFieldChange{{Field: "title", From: "Intro", To: "Introduction"}}, diff.Changes)
+	assert.Contains(t, diff.ContentDiff, "--- revision 1\n+++ revision 2\n")
+	assert.Contains(t, diff.ContentDiff, "-old line\n+new line\n")
+	assert.Contains(t, diff.ContentDiff, "+- another item\n")
+	assert.Equal(t, 2, diff.AddedLines)
+	assert.Equal(t, 1, diff.RemovedLines)
+}
+
+func TestRestoreRevision(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Restores as a new revision", func(t *testing.T) {
+		revisionService := new(MockLessonRevisionService)
+		lessonService := new(MockLessonService)
+		courseService := new(MockCourseService)
+		useCase := usecase.NewLessonRevisionUseCase(revisionService, lessonService, courseService)
+
+		courseService.On("GetCourse", ctx, 1).Return(&models.Course{ID: 1}, nil)
+		courseService.On("GetStaffMember", ctx, 1, 5).Return(&models.CourseStaff{Role: models.CourseStaffRoleCoTeacher}, nil)
+		lessonService.On("GetLessonByID", ctx, 10).Return(&models.Lesson{ID: 10, CourseID: 1, Title: "Broken", Content: "oops"}, nil)
+		revisionService.On("GetRevision", ctx, 10, 2).Return(&models.LessonRevision{
+			Number: 2, Title: "Intro", Content: "**bold**", VideoURL: "https://video/1",
+		}, nil)
+		// урок и ревизия сохраняются вместе
+		revisionService.On("SaveLesson", ctx, mock.MatchedBy(func(lesson *models.Lesson) bool {
+			return lesson.Title == "Intro" && lesson.Content == "**bold**" && lesson.VideoURL == "https://video/1" &&
+				lesson.ContentHTML == "<p><strong>bold</strong></p>\n"
+		}), 5, intPtr(2)).Return(&models.LessonRevision{Number: 4, RestoredFrom: intPtr(2)}, nil)
+
+		revision, err := useCase.RestoreRevision(ctx, 5, "teacher", 1, 10, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, revision.Number)
+		assert.Equal(t, 2, *revision.RestoredFrom)
+		revisionService.AssertExpectations(t)
+		lessonService.AssertNotCalled(t, "UpdateLesson", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Teaching assistant cannot restore", func(t *testing.T) {
+		revisionService := new(MockLessonRevisionService)
+		lessonService := new(MockLessonService)
+		courseService := new(MockCourseService)
+		useCase := usecase.NewLessonRevisionUseCase(revisionService, lessonService, courseService)
+
+		courseService.On("GetCourse", ctx, 1).Return(&models.Course{ID: 1}, nil)
+		courseService.On("GetStaffMember", ctx, 1, 6).Return(&models.CourseStaff{Role: models.CourseStaffRoleTA}, nil)
+
+		_, err := useCase.RestoreRevision(ctx, 6, "student", 1, 10, 2)
+
+		assert.True(t, errors.Is(err, usecase.ErrPermissionDenied))
+		revisionService.AssertNotCalled(t, "SaveLesson", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestListRevisionsLessonFromOtherCourse(t *testing.T) {
+	ctx := context.Background()
+	revisionService := new(MockLessonRevisionService)
+	lessonService := new(MockLessonService)
+	courseService := new(MockCourseService)
+	useCase := usecase.NewLessonRevisionUseCase(revisionService, lessonService, courseService)
+
+	courseService.On("GetCourse", ctx, 1).Return(&models.Course{ID: 1}, nil)
+	lessonService.On("GetLessonByID", ctx, 20).Return(&models.Lesson{ID: 20, CourseID: 2}, nil)
+
+	_, err := useCase.ListRevisions(ctx, 1, "admin", 1, 20)
+
+	assert.EqualError(t, err, "lesson not found")
+	revisionService.AssertNotCalled(t, "GetLessonRevisions", mock.Anything, mock.Anything)
+}
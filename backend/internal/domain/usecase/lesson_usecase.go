@@ -4,20 +4,31 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+
+	"github.com/go-playground/validator/v10"
 	"gitlab.com/w0ikid/study-platform/internal/domain/models"
 	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+	"gitlab.com/w0ikid/study-platform/pkg/markdown"
 )
 
 type LessonUseCase struct {
 	lessonService services.LessonServiceInterface
 	enrollment services.EnrollmentServiceInterface
 	course services.CourseServiceInterface
+	module services.ModuleServiceInterface
+	version services.CourseVersionServiceInterface
+	video services.VideoProgressServiceInterface
+	release LessonReleaseUseCaseInterface
+	revisions services.LessonRevisionServiceInterface
+	progress LessonProgressUseCaseInterface
 }
 
 func NewLessonUseCase(
-	lessonService services.LessonServiceInterface, enrollment services.EnrollmentServiceInterface, course services.CourseServiceInterface,
+	lessonService services.LessonServiceInterface, enrollment services.EnrollmentServiceInterface, course services.CourseServiceInterface, module services.ModuleServiceInterface, version services.CourseVersionServiceInterface, video services.VideoProgressServiceInterface, release LessonReleaseUseCaseInterface, revisions services.LessonRevisionServiceInterface, progress LessonProgressUseCaseInterface,
 ) *LessonUseCase {
-	return &LessonUseCase{lessonService: lessonService, enrollment: enrollment, course: course}
+	return &LessonUseCase{lessonService: lessonService, enrollment: enrollment, course: course, module: module, version: version, video: video, release: release, revisions: revisions, progress: progress}
 }
 
 type CreateLessonInput struct {
@@ -25,36 +36,55 @@ type CreateLessonInput struct {
 	Content string
 	CourseID int
 	VideoURL string
+	VideoDuration *int
 	UserID int
+	UserRole string
+	ModuleID *int
 }
 
 // CreateLesson создает новый урок
 func (u *LessonUseCase) CreateLesson(ctx context.Context, input CreateLessonInput) (*models.Lesson, error) {
-	isOwner, err := u.IsCourseOwnedByTeacher(ctx, input.CourseID, input.UserID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to verify course ownership: %w", err)
-	}
-	if !isOwner {
-		return nil, errors.New("user is not the owner of this course")
+	if _, err := checkCourseStaff(ctx, u.course, input.UserID, input.UserRole, input.CourseID, courseEditorRoles); err != nil {
+		return nil, err
 	}
 
 	if input.Title == "" {
 		return nil, errors.New("lesson title cannot be empty")
 	}
+	if input.VideoDuration != nil && (*input.VideoDuration < 1 || *input.VideoDuration > 86400) {
+		return nil, errors.New("validation failed: video duration must be between 1 and 86400 seconds")
+	}
+
+	if input.ModuleID != nil {
+		module, err := u.module.GetModule(ctx, *input.ModuleID)
+		if err != nil {
+			return nil, err
+		}
+		if module.CourseID != input.CourseID {
+			return nil, errors.New("module not found in this course")
+		}
+	}
 
 	lesson := &models.Lesson{
 		Title:     input.Title,
 		Content:   input.Content,
 		CourseID:  input.CourseID,
 		VideoURL:  input.VideoURL,
+		VideoDuration: input.VideoDuration,
+		ModuleID:  input.ModuleID,
+	}
+	if err := renderLessonContent(lesson); err != nil {
+		return nil, err
 	}
-	
 
-	
-	lesson, err = u.lessonService.CreateLesson(ctx, lesson)
+	lesson, err := u.lessonService.CreateLesson(ctx, lesson)
 	if err != nil {
 		return nil, err
 	}
+	// Первая ревизия - исходное содержимое урока
+	if _, err := u.revisions.RecordRevision(ctx, lesson, input.UserID, nil); err != nil {
+		return nil, err
+	}
 	// Дополнительная логика (например, отправка уведомлений)
 	return lesson, nil
 }
@@ -71,13 +101,145 @@ func (u *LessonUseCase) GetAllLessons(ctx context.Context, courseID int) ([]*mod
 	return u.lessonService.GetAllLessons(ctx, courseID)
 }
 
-// DeleteLesson удаляет урок по ID
-func (u *LessonUseCase) DeleteLesson(ctx context.Context, id int) error {
-	// Дополнительная логика перед удалением урока (например, проверка прав доступа)
-	return u.lessonService.DeleteLesson(ctx, id)
+// GetLesson возвращает урок так же, как он выглядит в списке уроков курса:
+// студенту - из закреплённой версии, ещё не открытый урок - без содержимого
+func (u *LessonUseCase) GetLesson(ctx context.Context, userID int, userRole string, courseID, lessonID int) (*models.Lesson, error) {
+	lessons, err := u.GetLessonsForStudent(ctx, userID, userRole, courseID)
+	if err != nil {
+		return nil, err
+	}
+	for _, lesson := range lessons {
+		if lesson.ID == lessonID {
+			return lesson, nil
+		}
+	}
+	return nil, errors.New("lesson not found")
+}
+
+// UpdateLesson заменяет название, содержимое и видео урока
+func (u *LessonUseCase) UpdateLesson(ctx context.Context, userID int, userRole string, courseID, lessonID int, input *dto.UpdateLessonRequest) (*models.Lesson, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	lesson, err := u.getEditableLesson(ctx, userID, userRole, courseID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	return u.saveLesson(ctx, userID, lesson, input.Title, input.Content, input.VideoURL, input.VideoDuration)
+}
+
+// PatchLesson меняет только переданные поля урока
+func (u *LessonUseCase) PatchLesson(ctx context.Context, userID int, userRole string, courseID, lessonID int, input *dto.PatchLessonRequest) (*models.Lesson, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	lesson, err := u.getEditableLesson(ctx, userID, userRole, courseID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	title, content, videoURL, videoDuration := lesson.Title, lesson.Content, lesson.VideoURL, lesson.VideoDuration
+	if input.Title != nil {
+		title = *input.Title
+	}
+	if input.Content != nil {
+		content = *input.Content
+	}
+	if input.VideoURL != nil && *input.VideoURL != videoURL {
+		videoURL = *input.VideoURL
+		videoDuration = nil
+	}
+	if input.VideoDuration != nil {
+		videoDuration = input.VideoDuration
+	}
+	return u.saveLesson(ctx, userID, lesson, title, content, videoURL, videoDuration)
+}
+
+// DeleteLesson переносит урок в корзину курса и пересчитывает прогресс студентов:
+// у кого оставшиеся уроки уже пройдены, модуль и курс становятся завершёнными
+func (u *LessonUseCase) DeleteLesson(ctx context.Context, userID int, userRole string, courseID, lessonID int) error {
+	lesson, err := u.getEditableLesson(ctx, userID, userRole, courseID, lessonID)
+	if err != nil {
+		return err
+	}
+	if err := u.lessonService.DeleteLesson(ctx, lesson.ID); err != nil {
+		return err
+	}
+	// урок уже удалён, ошибка пересчёта не должна возвращать его обратно
+	if err := u.progress.RecalculateCourseProgress(ctx, courseID, lesson.ModuleID); err != nil {
+		log.Printf("failed to recalculate progress of course %d after deleting lesson %d: %v", courseID, lesson.ID, err)
+	}
+	return nil
+}
+
+// RestoreLesson возвращает урок из корзины на прежнее место и пересчитывает прогресс студентов:
+// у кого восстановленный урок не пройден, модуль и курс перестают быть завершёнными
+func (u *LessonUseCase) RestoreLesson(ctx context.Context, userID int, userRole string, courseID, lessonID int) (*models.Lesson, error) {
+	if _, err := checkCourseStaff(ctx, u.course, userID, userRole, courseID, courseEditorRoles); err != nil {
+		return nil, err
+	}
+	lesson, err := u.lessonService.RestoreLesson(ctx, courseID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	// урок уже восстановлен, ошибка пересчёта не должна снова убирать его в корзину
+	if err := u.progress.RecalculateCourseProgress(ctx, courseID, lesson.ModuleID); err != nil {
+		log.Printf("failed to recalculate progress of course %d after restoring lesson %d: %v", courseID, lesson.ID, err)
+	}
+	return lesson, nil
+}
+
+// GetDeletedLessons возвращает корзину курса
+func (u *LessonUseCase) GetDeletedLessons(ctx context.Context, userID int, userRole string, courseID int) ([]*models.Lesson, error) {
+	if _, err := checkCourseStaff(ctx, u.course, userID, userRole, courseID, courseEditorRoles); err != nil {
+		return nil, err
+	}
+	return u.lessonService.GetDeletedLessons(ctx, courseID)
+}
+
+func (u *LessonUseCase) getEditableLesson(ctx context.Context, userID int, userRole string, courseID, lessonID int) (*models.Lesson, error) {
+	if _, err := checkCourseStaff(ctx, u.course, userID, userRole, courseID, courseEditorRoles); err != nil {
+		return nil, err
+	}
+	return getCourseLesson(ctx, u.lessonService, courseID, lessonID)
+}
+
+// saveLesson сохраняет урок и его новую ревизию; сохранение без изменений ревизию не создаёт
+// Длительность видео в ревизии не входит: её изменение сохраняется без новой ревизии.
+func (u *LessonUseCase) saveLesson(ctx context.Context, userID int, lesson *models.Lesson, title, content, videoURL string, videoDuration *int) (*models.Lesson, error) {
+	contentChanged := lesson.Title != title || lesson.Content != content || lesson.VideoURL != videoURL
+	if !contentChanged && equalIntPtr(lesson.VideoDuration, videoDuration) {
+		return lesson, nil
+	}
+	lesson.Title = title
+	lesson.Content = content
+	lesson.VideoURL = videoURL
+	lesson.VideoDuration = videoDuration
+	if err := renderLessonContent(lesson); err != nil {
+		return nil, err
+	}
+	if !contentChanged {
+		if err := u.lessonService.UpdateLesson(ctx, lesson); err != nil {
+			return nil, err
+		}
+		return lesson, nil
+	}
+	if _, err := u.revisions.SaveLesson(ctx, lesson, userID, nil); err != nil {
+		return nil, err
+	}
+	return lesson, nil
+}
+
+func equalIntPtr(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
 }
 
-func (u *LessonUseCase) GetLessonsForStudent(ctx context.Context, userID int, courseID int) ([]*models.Lesson, error) {
+// GetLessonsForStudent возвращает уроки из версии курса, закреплённой за студентом.
+// Ещё не открытые уроки отдаются без содержимого, с датой или условием открытия.
+func (u *LessonUseCase) GetLessonsForStudent(ctx context.Context, userID int, userRole string, courseID int) ([]*models.Lesson, error) {
 	// enrolled, err := u.enrollment.IsUserEnrolled(ctx, userID, courseID)
 	// if err != nil {
 	// 	return nil, err
@@ -85,15 +247,72 @@ func (u *LessonUseCase) GetLessonsForStudent(ctx context.Context, userID int, co
 	// if !enrolled {
 	// 	return nil, errors.New("access denied | you are not enrolled in this course")
 	// }
-	
-	return u.lessonService.GetAllLessons(ctx, courseID)
+
+	_, lessons, err := loadCourseContent(ctx, u.version, u.module, u.lessonService, userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	// позиция, с которой продолжить видео урока
+	watched, err := u.video.GetCourseProgress(ctx, userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	positions := resumePositions(watched)
+	for _, lesson := range lessons {
+		if position, ok := positions[lesson.ID]; ok && lesson.VideoURL != "" {
+			lesson.ResumePosition = &position
+		}
+	}
+
+	locks, err := u.release.LockedLessons(ctx, userID, userRole, courseID)
+	if err != nil {
+		return nil, err
+	}
+	for _, lesson := range lessons {
+		if lock, ok := locks[lesson.ID]; ok {
+			lessonTeaser(lesson, lock)
+		}
+	}
+	return lessons, nil
 }
 
-func (uc *LessonUseCase) IsCourseOwnedByTeacher(ctx context.Context, courseID, teacherID int) (bool, error) {
-    course, err := uc.course.GetCourse(ctx, courseID)
-    if err != nil {
-        return false, err
-    }
+// CanEditCourse проверяет, что пользователь может менять уроки курса (владелец, соавтор или администратор)
+func (uc *LessonUseCase) CanEditCourse(ctx context.Context, courseID, userID int, userRole string) error {
+	_, err := checkCourseStaff(ctx, uc.course, userID, userRole, courseID, courseEditorRoles)
+	return err
+}
 
-    return course.TeacherID == teacherID, nil
+// RenderStoredContent строит HTML для уроков, сохранённых до перехода на markdown.
+// Вызывается при запуске; содержимое считается markdown, вставленный HTML проходит через очистку.
+func (u *LessonUseCase) RenderStoredContent(ctx context.Context) (int, error) {
+	rendered := 0
+	for {
+		lessons, err := u.lessonService.GetUnrenderedLessons(ctx, 100)
+		if err != nil {
+			return rendered, err
+		}
+		if len(lessons) == 0 {
+			return rendered, nil
+		}
+		for _, lesson := range lessons {
+			if err := renderLessonContent(lesson); err != nil {
+				return rendered, err
+			}
+			if err := u.lessonService.UpdateLessonHTML(ctx, lesson.ID, lesson.ContentHTML); err != nil {
+				return rendered, err
+			}
+			rendered++
+		}
+	}
+}
+
+// renderLessonContent заполняет ContentHTML очищенным HTML из markdown урока
+func renderLessonContent(lesson *models.Lesson) error {
+	contentHTML, err := markdown.Render(lesson.Content)
+	if err != nil {
+		return err
+	}
+	lesson.ContentHTML = contentHTML
+	return nil
 }
@@ -0,0 +1,262 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+func (m *MockLessonService) CreateLesson(ctx context.Context, lesson *models.Lesson) (*models.Lesson, error) {
+	args := m.Called(ctx, lesson)
+	return lesson, args.Error(0)
+}
+
+func (m *MockLessonService) GetUnrenderedLessons(ctx context.Context, limit int) ([]*models.Lesson, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]*models.Lesson), args.Error(1)
+}
+
+func (m *MockLessonService) UpdateLessonHTML(ctx context.Context, id int, contentHTML string) error {
+	args := m.Called(ctx, id, contentHTML)
+	return args.Error(0)
+}
+
+func TestCreateLesson(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Renders sanitized HTML from markdown", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		lessonService := new(MockLessonService)
+		revisionService := new(MockLessonRevisionService)
+		useCase := usecase.NewLessonUseCase(lessonService, nil, courseService, nil, nil, nil, nil, revisionService, nil)
+
+		courseService.On("GetCourse", ctx, 1).Return(&models.Course{ID: 1}, nil)
+		lessonService.On("CreateLesson", ctx, mock.Anything).Return(nil)
+		revisionService.On("RecordRevision", ctx, mock.Anything, 1, (*int)(nil)).Return(&models.LessonRevision{Number: 1}, nil)
+
+		lesson, err := useCase.CreateLesson(ctx, usecase.CreateLessonInput{
+			Title:    "Intro",
+			Content:  "# Intro\n\n<script>alert(1)</script>\n\n```go\nfmt.Println(1)\n```\n\nEuler: $e^{i\\pi}+1=0$",
+			CourseID: 1,
+			UserID:   1,
+			UserRole: "admin",
+		})
+
+		assert.NoError(t, err)
+		assert.Contains(t, lesson.ContentHTML, "<h1>Intro</h1>")
+		assert.Contains(t, lesson.ContentHTML, `<code class="language-go">`)
+		assert.Contains(t, lesson.ContentHTML, `<span class="math math-inline">e^{i\pi}+1=0</span>`)
+		assert.NotContains(t, lesson.ContentHTML, "<script>")
+		assert.Contains(t, lesson.Content, "<script>")
+		revisionService.AssertExpectations(t)
+	})
+}
+
+func TestRenderStoredContent(t *testing.T) {
+	ctx := context.Background()
+	lessonService := new(MockLessonService)
+	useCase := usecase.NewLessonUseCase(lessonService, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	lessonService.On("GetUnrenderedLessons", ctx, 100).Return([]*models.Lesson{
+		{ID: 1, Content: `<p onclick="steal()">Legacy **html**</p>`},
+		{ID: 2, Content: "| a | b |\n|---|---|\n| 1 | 2 |"},
+	}, nil).Once()
+	lessonService.On("GetUnrenderedLessons", ctx, 100).Return([]*models.Lesson{}, nil).Once()
+	lessonService.On("UpdateLessonHTML", ctx, 1, "<p>Legacy **html**</p>").Return(nil)
+	lessonService.On("UpdateLessonHTML", ctx, 2, mock.MatchedBy(func(html string) bool {
+		return strings.Contains(html, "<table>")
+	})).Return(nil)
+
+	rendered, err := useCase.RenderStoredContent(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, rendered)
+	lessonService.AssertExpectations(t)
+}
+
+func (m *MockLessonService) DeleteLesson(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockLessonService) RestoreLesson(ctx context.Context, courseID, id int) (*models.Lesson, error) {
+	args := m.Called(ctx, courseID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Lesson), args.Error(1)
+}
+
+func (m *MockLessonProgressUseCase) RecalculateCourseProgress(ctx context.Context, courseID int, moduleID *int) error {
+	args := m.Called(ctx, courseID, moduleID)
+	return args.Error(0)
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestPatchLesson(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Changes only given fields and records a revision", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		lessonService := new(MockLessonService)
+		revisionService := new(MockLessonRevisionService)
+		useCase := usecase.NewLessonUseCase(lessonService, nil, courseService, nil, nil, nil, nil, revisionService, nil)
+
+		courseService.On("GetCourse", ctx, 1).Return(&models.Course{ID: 1}, nil)
+		courseService.On("GetStaffMember", ctx, 1, 5).Return(&models.CourseStaff{Role: models.CourseStaffRoleOwner}, nil)
+		lessonService.On("GetLessonByID", ctx, 10).Return(&models.Lesson{ID: 10, CourseID: 1, Title: "Intro", Content: "old", VideoURL: "https://video/1"}, nil)
+		revisionService.On("SaveLesson", ctx, mock.MatchedBy(func(lesson *models.Lesson) bool {
+			return lesson.Title == "Intro" && lesson.Content == "*new*" && lesson.ContentHTML == "<p><em>new</em></p>\n" && lesson.VideoURL == ""
+		}), 5, (*int)(nil)).Return(&models.LessonRevision{Number: 2}, nil)
+
+		lesson, err := useCase.PatchLesson(ctx, 5, "teacher", 1, 10, &dto.PatchLessonRequest{Content: strPtr("*new*"), VideoURL: strPtr("")})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Intro", lesson.Title)
+		revisionService.AssertExpectations(t)
+		lessonService.AssertNotCalled(t, "UpdateLesson", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Saving without changes does not create a revision", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		lessonService := new(MockLessonService)
+		revisionService := new(MockLessonRevisionService)
+		useCase := usecase.NewLessonUseCase(lessonService, nil, courseService, nil, nil, nil, nil, revisionService, nil)
+
+		courseService.On("GetCourse", ctx, 1).Return(&models.Course{ID: 1}, nil)
+		lessonService.On("GetLessonByID", ctx, 10).Return(&models.Lesson{ID: 10, CourseID: 1, Title: "Intro", Content: "old"}, nil)
+
+		_, err := useCase.PatchLesson(ctx, 1, "admin", 1, 10, &dto.PatchLessonRequest{Title: strPtr("Intro")})
+
+		assert.NoError(t, err)
+		lessonService.AssertNotCalled(t, "UpdateLesson", mock.Anything, mock.Anything)
+		revisionService.AssertNotCalled(t, "SaveLesson", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Video duration alone is saved without a revision", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		lessonService := new(MockLessonService)
+		revisionService := new(MockLessonRevisionService)
+		useCase := usecase.NewLessonUseCase(lessonService, nil, courseService, nil, nil, nil, nil, revisionService, nil)
+
+		courseService.On("GetCourse", ctx, 1).Return(&models.Course{ID: 1}, nil)
+		lessonService.On("GetLessonByID", ctx, 10).Return(&models.Lesson{ID: 10, CourseID: 1, Title: "Intro", Content: "old", VideoURL: "https://video/1"}, nil)
+		lessonService.On("UpdateLesson", ctx, mock.MatchedBy(func(lesson *models.Lesson) bool {
+			return *lesson.VideoDuration == 600
+		})).Return(nil).Once()
+
+		_, err := useCase.PatchLesson(ctx, 1, "admin", 1, 10, &dto.PatchLessonRequest{VideoDuration: intPtr(600)})
+
+		assert.NoError(t, err)
+		lessonService.AssertExpectations(t)
+		revisionService.AssertNotCalled(t, "SaveLesson", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invalid video URL", func(t *testing.T) {
+		useCase := usecase.NewLessonUseCase(nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		_, err := useCase.PatchLesson(ctx, 1, "admin", 1, 10, &dto.PatchLessonRequest{VideoURL: strPtr("not a url")})
+
+		assert.ErrorContains(t, err, "validation failed")
+	})
+}
+
+func TestDeleteLesson(t *testing.T) {
+	ctx := context.Background()
+	moduleID := 3
+
+	t.Run("Moves lesson to trash and recalculates progress", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		lessonService := new(MockLessonService)
+		progress := new(MockLessonProgressUseCase)
+		useCase := usecase.NewLessonUseCase(lessonService, nil, courseService, nil, nil, nil, nil, nil, progress)
+
+		courseService.On("GetCourse", ctx, 1).Return(&models.Course{ID: 1}, nil)
+		courseService.On("GetStaffMember", ctx, 1, 5).Return(&models.CourseStaff{Role: models.CourseStaffRoleCoTeacher}, nil)
+		lessonService.On("GetLessonByID", ctx, 10).Return(&models.Lesson{ID: 10, CourseID: 1, ModuleID: &moduleID}, nil)
+		lessonService.On("DeleteLesson", ctx, 10).Return(nil)
+		progress.On("RecalculateCourseProgress", ctx, 1, &moduleID).Return(errors.New("db is down"))
+
+		err := useCase.DeleteLesson(ctx, 5, "teacher", 1, 10)
+
+		assert.NoError(t, err)
+		lessonService.AssertExpectations(t)
+		progress.AssertExpectations(t)
+	})
+
+	t.Run("Teaching assistant cannot delete", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		lessonService := new(MockLessonService)
+		useCase := usecase.NewLessonUseCase(lessonService, nil, courseService, nil, nil, nil, nil, nil, nil)
+
+		courseService.On("GetCourse", ctx, 1).Return(&models.Course{ID: 1}, nil)
+		courseService.On("GetStaffMember", ctx, 1, 6).Return(&models.CourseStaff{Role: models.CourseStaffRoleTA}, nil)
+
+		err := useCase.DeleteLesson(ctx, 6, "student", 1, 10)
+
+		assert.True(t, errors.Is(err, usecase.ErrPermissionDenied))
+		lessonService.AssertNotCalled(t, "DeleteLesson", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Lesson from another course", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		lessonService := new(MockLessonService)
+		useCase := usecase.NewLessonUseCase(lessonService, nil, courseService, nil, nil, nil, nil, nil, nil)
+
+		courseService.On("GetCourse", ctx, 1).Return(&models.Course{ID: 1}, nil)
+		lessonService.On("GetLessonByID", ctx, 20).Return(&models.Lesson{ID: 20, CourseID: 2}, nil)
+
+		err := useCase.DeleteLesson(ctx, 1, "admin", 1, 20)
+
+		assert.EqualError(t, err, "lesson not found")
+		lessonService.AssertNotCalled(t, "DeleteLesson", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRestoreLesson(t *testing.T) {
+	ctx := context.Background()
+	moduleID := 3
+
+	t.Run("Restores lesson and recalculates progress", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		lessonService := new(MockLessonService)
+		progress := new(MockLessonProgressUseCase)
+		useCase := usecase.NewLessonUseCase(lessonService, nil, courseService, nil, nil, nil, nil, nil, progress)
+
+		courseService.On("GetCourse", ctx, 1).Return(&models.Course{ID: 1}, nil)
+		lessonService.On("RestoreLesson", ctx, 1, 10).Return(&models.Lesson{ID: 10, CourseID: 1, ModuleID: &moduleID}, nil)
+		progress.On("RecalculateCourseProgress", ctx, 1, &moduleID).Return(errors.New("db is down"))
+
+		lesson, err := useCase.RestoreLesson(ctx, 1, "admin", 1, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 10, lesson.ID)
+		progress.AssertExpectations(t)
+	})
+
+	t.Run("Lesson not in trash", func(t *testing.T) {
+		courseService := new(MockCourseService)
+		lessonService := new(MockLessonService)
+		progress := new(MockLessonProgressUseCase)
+		useCase := usecase.NewLessonUseCase(lessonService, nil, courseService, nil, nil, nil, nil, nil, progress)
+
+		courseService.On("GetCourse", ctx, 1).Return(&models.Course{ID: 1}, nil)
+		lessonService.On("RestoreLesson", ctx, 1, 10).Return(nil, errors.New("lesson not found"))
+
+		_, err := useCase.RestoreLesson(ctx, 1, "admin", 1, 10)
+
+		assert.EqualError(t, err, "lesson not found")
+		progress.AssertNotCalled(t, "RecalculateCourseProgress", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
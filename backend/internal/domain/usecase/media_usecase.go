@@ -0,0 +1,338 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+	"gitlab.com/w0ikid/study-platform/pkg/storage"
+)
+
+// MediaLimits - ограничения загрузки; QuotaBytes действует на каждого пользователя кроме администраторов
+type MediaLimits struct {
+	MaxUploadBytes int64
+	QuotaBytes     int64
+	URLTTL         time.Duration
+	PublicURL      string // адрес API для постоянных ссылок на публичные файлы
+}
+
+// Разрешённые типы файлов, определяемые по содержимому, а не по имени
+var allowedMediaTypes = []string{
+	"image/", "video/", "audio/",
+	"application/pdf", "application/zip", "application/x-gzip", "application/ogg",
+	"text/plain",
+}
+
+var safeExtension = regexp.MustCompile(`^\.[a-z0-9]{1,10}$`)
+
+type MediaUseCaseInterface interface {
+	Upload(ctx context.Context, userID int, userRole string, input *dto.MediaUploadRequest, filename string, size int64, r io.Reader) (*models.MediaFile, error)
+	GetFile(ctx context.Context, userID int, userRole string, id int) (*models.MediaFile, error)
+	GetMyFiles(ctx context.Context, userID int, userRole string) ([]*models.MediaFile, *models.MediaUsage, error)
+	DeleteFile(ctx context.Context, userID int, userRole string, id int) error
+	OpenPublic(ctx context.Context, id int) (*models.MediaFile, io.ReadCloser, error)
+	OpenSigned(ctx context.Context, key string, expires int64, signature string) (*models.MediaFile, io.ReadCloser, error)
+	SetCourseImage(ctx context.Context, userID int, userRole string, courseID int, input *dto.CourseImageRequest) (*models.Course, error)
+	PublicFileURL(id int) string
+}
+
+type MediaUseCase struct {
+	mediaService  services.MediaServiceInterface
+	courseService services.CourseServiceInterface
+	enrollment    EnrollmentUseCaseInterface
+	store         storage.BlobStore
+	limits        MediaLimits
+}
+
+func NewMediaUseCase(
+	mediaService services.MediaServiceInterface,
+	courseService services.CourseServiceInterface,
+	enrollment EnrollmentUseCaseInterface,
+	store storage.BlobStore,
+	limits MediaLimits,
+) *MediaUseCase {
+	return &MediaUseCase{
+		mediaService:  mediaService,
+		courseService: courseService,
+		enrollment:    enrollment,
+		store:         store,
+		limits:        limits,
+	}
+}
+
+// Upload сохраняет файл в хранилище. Тип определяется по первым байтам содержимого,
+// размер проверяется по лимиту на файл и квоте владельца.
+func (u *MediaUseCase) Upload(ctx context.Context, userID int, userRole string, input *dto.MediaUploadRequest, filename string, size int64, r io.Reader) (*models.MediaFile, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if size <= 0 {
+		return nil, errors.New("validation failed: file is empty")
+	}
+	if u.limits.MaxUploadBytes > 0 && size > u.limits.MaxUploadBytes {
+		return nil, fmt.Errorf("%w: maximum size is %d bytes", ErrFileTooLarge, u.limits.MaxUploadBytes)
+	}
+	if input.CourseID != nil {
+		if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, *input.CourseID, courseEditorRoles); err != nil {
+			return nil, err
+		}
+	}
+
+	quota := u.limits.QuotaBytes
+	if userRole == "admin" {
+		quota = 0
+	}
+	if quota > 0 {
+		used, err := u.mediaService.GetUsedBytes(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if used+size > quota {
+			return nil, fmt.Errorf("%w: %d of %d bytes used", ErrQuotaExceeded, used, quota)
+		}
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	head = head[:n]
+	contentType := http.DetectContentType(head)
+	if !isAllowedMediaType(contentType) {
+		return nil, fmt.Errorf("validation failed: file type %s is not allowed", contentType)
+	}
+
+	key, err := mediaStorageKey(userID, filename)
+	if err != nil {
+		return nil, err
+	}
+	body := io.LimitReader(io.MultiReader(bytes.NewReader(head), r), size)
+	if err := u.store.Put(ctx, key, body, size, contentType); err != nil {
+		return nil, err
+	}
+
+	file := &models.MediaFile{
+		OwnerID:     userID,
+		CourseID:    input.CourseID,
+		Filename:    cleanFilename(filename),
+		ContentType: contentType,
+		Size:        size,
+		Public:      input.Public,
+		StorageKey:  key,
+	}
+	created, err := u.mediaService.CreateFile(ctx, file, quota)
+	if err != nil || !created {
+		if deleteErr := u.store.Delete(ctx, key); deleteErr != nil {
+			log.Printf("media: failed to remove orphaned blob %s: %v", key, deleteErr)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return nil, ErrQuotaExceeded
+	}
+
+	return u.withURL(ctx, file)
+}
+
+// GetFile возвращает файл с временной ссылкой на скачивание
+func (u *MediaUseCase) GetFile(ctx context.Context, userID int, userRole string, id int) (*models.MediaFile, error) {
+	file, err := u.mediaService.GetFile(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.checkRead(ctx, userID, userRole, file); err != nil {
+		return nil, err
+	}
+	return u.withURL(ctx, file)
+}
+
+// GetMyFiles возвращает файлы пользователя и занятое ими место
+func (u *MediaUseCase) GetMyFiles(ctx context.Context, userID int, userRole string) ([]*models.MediaFile, *models.MediaUsage, error) {
+	files, err := u.mediaService.GetOwnerFiles(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	used, err := u.mediaService.GetUsedBytes(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	usage := &models.MediaUsage{UsedBytes: used, QuotaBytes: u.limits.QuotaBytes}
+	if userRole == "admin" {
+		usage.QuotaBytes = 0
+	}
+	return files, usage, nil
+}
+
+// DeleteFile удаляет файл; удалить может владелец, администратор или редактор курса файла
+func (u *MediaUseCase) DeleteFile(ctx context.Context, userID int, userRole string, id int) error {
+	file, err := u.mediaService.GetFile(ctx, id)
+	if err != nil {
+		return err
+	}
+	if file.OwnerID != userID && userRole != "admin" {
+		if file.CourseID == nil {
+			return fmt.Errorf("%w: only the owner can delete this file", ErrPermissionDenied)
+		}
+		if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, *file.CourseID, courseEditorRoles); err != nil {
+			return err
+		}
+	}
+
+	if err := u.mediaService.DeleteFile(ctx, id); err != nil {
+		return err
+	}
+	if err := u.store.Delete(ctx, file.StorageKey); err != nil {
+		log.Printf("media: failed to remove blob %s: %v", file.StorageKey, err)
+	}
+	return nil
+}
+
+// OpenPublic открывает содержимое публичного файла
+func (u *MediaUseCase) OpenPublic(ctx context.Context, id int) (*models.MediaFile, io.ReadCloser, error) {
+	file, err := u.mediaService.GetFile(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !file.Public {
+		return nil, nil, errors.New("media file not found")
+	}
+	content, err := u.store.Get(ctx, file.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, content, nil
+}
+
+// OpenSigned открывает файл по подписанной ссылке, которую обслуживает само приложение (локальное хранилище)
+func (u *MediaUseCase) OpenSigned(ctx context.Context, key string, expires int64, signature string) (*models.MediaFile, io.ReadCloser, error) {
+	verifier, ok := u.store.(storage.SignatureVerifier)
+	if !ok {
+		return nil, nil, errors.New("media file not found")
+	}
+	if err := verifier.Verify(key, expires, signature); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrPermissionDenied, err)
+	}
+
+	file, err := u.mediaService.GetFileByStorageKey(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if file == nil {
+		return nil, nil, errors.New("media file not found")
+	}
+	content, err := u.store.Get(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, content, nil
+}
+
+// SetCourseImage делает загруженное публичное изображение обложкой курса
+func (u *MediaUseCase) SetCourseImage(ctx context.Context, userID int, userRole string, courseID int, input *dto.CourseImageRequest) (*models.Course, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	course, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseEditorRoles)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := u.mediaService.GetFile(ctx, input.MediaID)
+	if err != nil {
+		return nil, err
+	}
+	if file.OwnerID != userID && userRole != "admin" {
+		return nil, fmt.Errorf("%w: you can only use your own uploads", ErrPermissionDenied)
+	}
+	if !file.IsImage() || !file.Public {
+		return nil, errors.New("validation failed: course image must be a public image")
+	}
+
+	course.ImageUrl = u.PublicFileURL(file.ID)
+	if err := u.courseService.UpdateCourse(ctx, course); err != nil {
+		return nil, err
+	}
+	return course, nil
+}
+
+// PublicFileURL - постоянная ссылка на публичный файл
+func (u *MediaUseCase) PublicFileURL(id int) string {
+	return fmt.Sprintf("%s/api/media/%d/content", strings.TrimRight(u.limits.PublicURL, "/"), id)
+}
+
+// checkRead разрешает чтение публичных файлов, владельцу, администратору и участникам курса файла
+func (u *MediaUseCase) checkRead(ctx context.Context, userID int, userRole string, file *models.MediaFile) error {
+	if file.Public || file.OwnerID == userID || userRole == "admin" {
+		return nil
+	}
+	if file.CourseID != nil {
+		access, err := u.enrollment.HasCourseAccess(ctx, userID, *file.CourseID)
+		if err != nil {
+			return err
+		}
+		if access {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: you do not have access to this file", ErrPermissionDenied)
+}
+
+func (u *MediaUseCase) withURL(ctx context.Context, file *models.MediaFile) (*models.MediaFile, error) {
+	url, err := u.store.SignedURL(ctx, file.StorageKey, file.Filename, u.limits.URLTTL)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(u.limits.URLTTL)
+	file.URL = url
+	file.URLExpiresAt = &expiresAt
+	return file, nil
+}
+
+func isAllowedMediaType(contentType string) bool {
+	for _, allowed := range allowedMediaTypes {
+		if strings.HasPrefix(contentType, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// mediaStorageKey - случайный ключ в каталоге владельца; из имени файла берётся только расширение
+func mediaStorageKey(userID int, filename string) (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to generate storage key: %w", err)
+	}
+	ext := strings.ToLower(filepath.Ext(filename))
+	if !safeExtension.MatchString(ext) {
+		ext = ""
+	}
+	return fmt.Sprintf("%d/%s%s", userID, hex.EncodeToString(id), ext), nil
+}
+
+func cleanFilename(filename string) string {
+	name := strings.TrimSpace(filepath.Base(strings.ReplaceAll(filename, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		name = "file"
+	}
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+	}
+	return name
+}
@@ -0,0 +1,175 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+// Mock для MediaService
+type MockMediaService struct {
+	mock.Mock
+	services.MediaServiceInterface
+}
+
+func (m *MockMediaService) CreateFile(ctx context.Context, file *models.MediaFile, quotaBytes int64) (bool, error) {
+	args := m.Called(ctx, file, quotaBytes)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMediaService) GetFile(ctx context.Context, id int) (*models.MediaFile, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MediaFile), args.Error(1)
+}
+
+func (m *MockMediaService) GetUsedBytes(ctx context.Context, ownerID int) (int64, error) {
+	args := m.Called(ctx, ownerID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// memoryBlobStore - хранилище в памяти для тестов
+type memoryBlobStore struct {
+	blobs map[string][]byte
+}
+
+func newMemoryBlobStore() *memoryBlobStore {
+	return &memoryBlobStore{blobs: map[string][]byte{}}
+}
+
+func (s *memoryBlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.blobs[key] = data
+	return nil
+}
+
+func (s *memoryBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(s.blobs[key])), nil
+}
+
+func (s *memoryBlobStore) Delete(ctx context.Context, key string) error {
+	delete(s.blobs, key)
+	return nil
+}
+
+func (s *memoryBlobStore) SignedURL(ctx context.Context, key, filename string, ttl time.Duration) (string, error) {
+	return "https://files.example.com/" + key + "?signed", nil
+}
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func testMediaLimits() usecase.MediaLimits {
+	return usecase.MediaLimits{MaxUploadBytes: 1 << 20, QuotaBytes: 100, URLTTL: time.Minute, PublicURL: "http://api.example.com"}
+}
+
+func TestUploadMedia(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Stores file with sniffed content type", func(t *testing.T) {
+		mediaService := new(MockMediaService)
+		store := newMemoryBlobStore()
+		useCase := usecase.NewMediaUseCase(mediaService, nil, nil, store, testMediaLimits())
+
+		mediaService.On("GetUsedBytes", ctx, 7).Return(int64(10), nil)
+		mediaService.On("CreateFile", ctx, mock.Anything, int64(100)).Return(true, nil)
+
+		file, err := useCase.Upload(ctx, 7, "teacher", &dto.MediaUploadRequest{}, "../../cover.PNG", int64(len(pngHeader)), bytes.NewReader(pngHeader))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "image/png", file.ContentType)
+		assert.Equal(t, "cover.PNG", file.Filename)
+		assert.True(t, strings.HasPrefix(file.StorageKey, "7/"))
+		assert.True(t, strings.HasSuffix(file.StorageKey, ".png"))
+		assert.Equal(t, pngHeader, store.blobs[file.StorageKey])
+		assert.NotEmpty(t, file.URL)
+	})
+
+	t.Run("Rejects HTML disguised as an image", func(t *testing.T) {
+		mediaService := new(MockMediaService)
+		store := newMemoryBlobStore()
+		useCase := usecase.NewMediaUseCase(mediaService, nil, nil, store, testMediaLimits())
+		body := "<html><script>alert(1)</script></html>"
+
+		mediaService.On("GetUsedBytes", ctx, 7).Return(int64(0), nil)
+
+		_, err := useCase.Upload(ctx, 7, "teacher", &dto.MediaUploadRequest{}, "cat.png", int64(len(body)), strings.NewReader(body))
+
+		assert.ErrorContains(t, err, "validation failed")
+		assert.Empty(t, store.blobs)
+	})
+
+	t.Run("Quota exceeded", func(t *testing.T) {
+		mediaService := new(MockMediaService)
+		useCase := usecase.NewMediaUseCase(mediaService, nil, nil, newMemoryBlobStore(), testMediaLimits())
+
+		mediaService.On("GetUsedBytes", ctx, 7).Return(int64(95), nil)
+
+		_, err := useCase.Upload(ctx, 7, "teacher", &dto.MediaUploadRequest{}, "cover.png", int64(len(pngHeader)), bytes.NewReader(pngHeader))
+
+		assert.ErrorIs(t, err, usecase.ErrQuotaExceeded)
+	})
+
+	t.Run("Concurrent upload filled the quota", func(t *testing.T) {
+		mediaService := new(MockMediaService)
+		store := newMemoryBlobStore()
+		useCase := usecase.NewMediaUseCase(mediaService, nil, nil, store, testMediaLimits())
+
+		mediaService.On("GetUsedBytes", ctx, 7).Return(int64(0), nil)
+		mediaService.On("CreateFile", ctx, mock.Anything, int64(100)).Return(false, nil)
+
+		_, err := useCase.Upload(ctx, 7, "teacher", &dto.MediaUploadRequest{}, "cover.png", int64(len(pngHeader)), bytes.NewReader(pngHeader))
+
+		assert.ErrorIs(t, err, usecase.ErrQuotaExceeded)
+		assert.Empty(t, store.blobs)
+	})
+}
+
+func TestGetMediaFile(t *testing.T) {
+	ctx := context.Background()
+	courseID := 10
+	courseFile := &models.MediaFile{ID: 1, OwnerID: 2, CourseID: &courseID, Filename: "notes.pdf", StorageKey: "2/abc.pdf"}
+
+	t.Run("Course file requires course access", func(t *testing.T) {
+		mediaService := new(MockMediaService)
+		enrollment := new(MockEnrollmentUseCase)
+		useCase := usecase.NewMediaUseCase(mediaService, nil, enrollment, newMemoryBlobStore(), testMediaLimits())
+
+		mediaService.On("GetFile", ctx, 1).Return(courseFile, nil)
+		enrollment.On("HasCourseAccess", ctx, 7, courseID).Return(false, nil)
+
+		_, err := useCase.GetFile(ctx, 7, "student", 1)
+
+		assert.ErrorIs(t, err, usecase.ErrPermissionDenied)
+	})
+
+	t.Run("Enrolled student gets a signed URL", func(t *testing.T) {
+		mediaService := new(MockMediaService)
+		enrollment := new(MockEnrollmentUseCase)
+		useCase := usecase.NewMediaUseCase(mediaService, nil, enrollment, newMemoryBlobStore(), testMediaLimits())
+
+		mediaService.On("GetFile", ctx, 1).Return(courseFile, nil)
+		enrollment.On("HasCourseAccess", ctx, 8, courseID).Return(true, nil)
+
+		file, err := useCase.GetFile(ctx, 8, "student", 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "https://files.example.com/2/abc.pdf?signed", file.URL)
+		assert.NotNil(t, file.URLExpiresAt)
+	})
+}
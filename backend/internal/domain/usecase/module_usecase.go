@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+type ModuleUseCaseInterface interface {
+	GetModules(ctx context.Context, userID, courseID int) ([]*models.Module, error)
+	CreateModule(ctx context.Context, userID int, userRole string, courseID int, input *dto.ModuleRequest) (*models.Module, error)
+	UpdateModule(ctx context.Context, userID int, userRole string, courseID, moduleID int, input *dto.ModuleRequest) (*models.Module, error)
+	DeleteModule(ctx context.Context, userID int, userRole string, courseID, moduleID int) error
+	MoveLesson(ctx context.Context, userID int, userRole string, courseID, lessonID int, input *dto.MoveLessonRequest) (*models.Lesson, error)
+}
+
+type ModuleUseCase struct {
+	moduleService services.ModuleServiceInterface
+	lessonService services.LessonServiceInterface
+	courseService services.CourseServiceInterface
+	versionService services.CourseVersionServiceInterface
+}
+
+func NewModuleUseCase(
+	moduleService services.ModuleServiceInterface,
+	lessonService services.LessonServiceInterface,
+	courseService services.CourseServiceInterface,
+	versionService services.CourseVersionServiceInterface,
+) *ModuleUseCase {
+	return &ModuleUseCase{
+		moduleService:  moduleService,
+		lessonService:  lessonService,
+		courseService:  courseService,
+		versionService: versionService,
+	}
+}
+
+// GetModules возвращает модули курса с уроками. Уроки без модуля попадают в неявный модуль по умолчанию.
+// Студент видит модули из закреплённой за ним версии курса.
+func (u *ModuleUseCase) GetModules(ctx context.Context, userID, courseID int) ([]*models.Module, error) {
+	if _, err := u.courseService.GetCourse(ctx, courseID); err != nil {
+		return nil, err
+	}
+
+	modules, lessons, err := loadCourseContent(ctx, u.versionService, u.moduleService, u.lessonService, userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return GroupLessonsByModule(courseID, modules, lessons), nil
+}
+
+// CreateModule добавляет модуль в курс
+func (u *ModuleUseCase) CreateModule(ctx context.Context, userID int, userRole string, courseID int, input *dto.ModuleRequest) (*models.Module, error) {
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseEditorRoles); err != nil {
+		return nil, err
+	}
+
+	module, err := u.moduleService.CreateModule(ctx, &models.Module{
+		CourseID:    courseID,
+		Title:       input.Title,
+		Description: input.Description,
+		Position:    input.Position,
+	})
+	if err != nil {
+		return nil, err
+	}
+	module.Lessons = []*models.Lesson{}
+	return module, nil
+}
+
+// UpdateModule меняет название, описание и позицию модуля
+func (u *ModuleUseCase) UpdateModule(ctx context.Context, userID int, userRole string, courseID, moduleID int, input *dto.ModuleRequest) (*models.Module, error) {
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	module, err := u.getCourseModule(ctx, userID, userRole, courseID, moduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	module.Title = input.Title
+	module.Description = input.Description
+	if input.Position > 0 {
+		module.Position = input.Position
+	}
+
+	if err := u.moduleService.UpdateModule(ctx, module); err != nil {
+		return nil, err
+	}
+	return module, nil
+}
+
+// DeleteModule удаляет модуль, его уроки переносятся в модуль по умолчанию
+func (u *ModuleUseCase) DeleteModule(ctx context.Context, userID int, userRole string, courseID, moduleID int) error {
+	if _, err := u.getCourseModule(ctx, userID, userRole, courseID, moduleID); err != nil {
+		return err
+	}
+	return u.moduleService.DeleteModule(ctx, moduleID)
+}
+
+// MoveLesson переносит урок в другой модуль курса и/или на другую позицию
+func (u *ModuleUseCase) MoveLesson(ctx context.Context, userID int, userRole string, courseID, lessonID int, input *dto.MoveLessonRequest) (*models.Lesson, error) {
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseEditorRoles); err != nil {
+		return nil, err
+	}
+
+	lesson, err := u.lessonService.GetLessonByID(ctx, lessonID)
+	if err != nil {
+		return nil, errors.New("lesson not found")
+	}
+	if lesson.CourseID != courseID {
+		return nil, errors.New("lesson does not belong to the course")
+	}
+
+	moduleID := input.ModuleID
+	if moduleID != nil && *moduleID == 0 {
+		moduleID = nil
+	}
+	if moduleID != nil {
+		module, err := u.moduleService.GetModule(ctx, *moduleID)
+		if err != nil {
+			return nil, err
+		}
+		if module.CourseID != courseID {
+			return nil, errors.New("module does not belong to the course")
+		}
+	}
+
+	if err := u.moduleService.MoveLesson(ctx, lessonID, moduleID, input.Position); err != nil {
+		return nil, err
+	}
+	return u.lessonService.GetLessonByID(ctx, lessonID)
+}
+
+// getCourseModule проверяет права на редактирование курса и то, что модуль принадлежит курсу
+func (u *ModuleUseCase) getCourseModule(ctx context.Context, userID int, userRole string, courseID, moduleID int) (*models.Module, error) {
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseEditorRoles); err != nil {
+		return nil, err
+	}
+
+	module, err := u.moduleService.GetModule(ctx, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	if module.CourseID != courseID {
+		return nil, errors.New("module not found in this course")
+	}
+	return module, nil
+}
+
+// GroupLessonsByModule раскладывает уроки по модулям. Неявный модуль по умолчанию (ID 0)
+// идёт первым и добавляется, только если в нём есть уроки или у курса нет других модулей.
+func GroupLessonsByModule(courseID int, modules []*models.Module, lessons []*models.Lesson) []*models.Module {
+	defaultModule := &models.Module{
+		CourseID:  courseID,
+		Title:     models.DefaultModuleTitle,
+		IsDefault: true,
+		Lessons:   []*models.Lesson{},
+	}
+
+	byID := make(map[int]*models.Module, len(modules))
+	for _, module := range modules {
+		module.Lessons = []*models.Lesson{}
+		byID[module.ID] = module
+	}
+
+	for _, lesson := range lessons {
+		module := defaultModule
+		if lesson.ModuleID != nil {
+			if m, ok := byID[*lesson.ModuleID]; ok {
+				module = m
+			}
+		}
+		module.Lessons = append(module.Lessons, lesson)
+	}
+
+	result := make([]*models.Module, 0, len(modules)+1)
+	if len(defaultModule.Lessons) > 0 || len(modules) == 0 {
+		result = append(result, defaultModule)
+	}
+	return append(result, modules...)
+}
@@ -0,0 +1,245 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+)
+
+// Mock для ModuleService
+type MockModuleService struct {
+	mock.Mock
+	services.ModuleServiceInterface
+}
+
+func (m *MockModuleService) GetModulesByCourse(ctx context.Context, courseID int) ([]*models.Module, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]*models.Module), args.Error(1)
+}
+
+func (m *MockModuleService) GetCompletions(ctx context.Context, userID, courseID int) (map[int]time.Time, error) {
+	args := m.Called(ctx, userID, courseID)
+	return args.Get(0).(map[int]time.Time), args.Error(1)
+}
+
+// Mock для LessonProgressService
+type MockLessonProgressService struct {
+	mock.Mock
+	services.LessonProgressServiceInterface
+}
+
+func (m *MockLessonProgressService) GetProgressByCourse(ctx context.Context, userID, courseID int) ([]*models.LessonProgress, error) {
+	args := m.Called(ctx, userID, courseID)
+	return args.Get(0).([]*models.LessonProgress), args.Error(1)
+}
+
+func TestGroupLessonsByModule(t *testing.T) {
+	moduleID := 3
+	modules := []*models.Module{{ID: moduleID, CourseID: 1, Title: "Basics", Position: 1}}
+	lessons := []*models.Lesson{
+		{ID: 10, CourseID: 1, Title: "Intro"},
+		{ID: 11, CourseID: 1, Title: "Types", ModuleID: &moduleID},
+	}
+
+	grouped := usecase.GroupLessonsByModule(1, modules, lessons)
+
+	assert.Len(t, grouped, 2)
+	assert.True(t, grouped[0].IsDefault)
+	assert.Equal(t, 10, grouped[0].Lessons[0].ID)
+	assert.Equal(t, moduleID, grouped[1].ID)
+	assert.Equal(t, 11, grouped[1].Lessons[0].ID)
+
+	// без уроков вне модулей модуль по умолчанию не показывается
+	grouped = usecase.GroupLessonsByModule(1, modules, lessons[1:])
+	assert.Len(t, grouped, 1)
+	assert.Equal(t, moduleID, grouped[0].ID)
+}
+
+func TestGetCourseProgressByModule(t *testing.T) {
+	ctx := context.Background()
+	basics, advanced := 1, 2
+	completedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	lessonService := new(MockLessonService)
+	moduleService := new(MockModuleService)
+	progressService := new(MockLessonProgressService)
+	versionService := new(MockCourseVersionService)
+	useCase := usecase.NewLessonProgressUseCase(progressService, lessonService, nil, nil, nil, moduleService, versionService)
+
+	versionService.On("GetPinnedVersion", ctx, 7, 5).Return(nil, nil)
+	lessonService.On("GetAllLessons", ctx, 5).Return([]*models.Lesson{
+		{ID: 10, CourseID: 5, ModuleID: &basics},
+		{ID: 11, CourseID: 5, ModuleID: &basics},
+		{ID: 12, CourseID: 5, ModuleID: &advanced},
+		{ID: 13, CourseID: 5, ModuleID: &advanced},
+	}, nil)
+	moduleService.On("GetModulesByCourse", ctx, 5).Return([]*models.Module{
+		{ID: basics, CourseID: 5, Title: "Basics", Position: 1},
+		{ID: advanced, CourseID: 5, Title: "Advanced", Position: 2},
+	}, nil)
+	moduleService.On("GetCompletions", ctx, 7, 5).Return(map[int]time.Time{basics: completedAt}, nil)
+	progressService.On("GetProgressByCourse", ctx, 7, 5).Return([]*models.LessonProgress{
+		{LessonID: 10, IsCompleted: true},
+		{LessonID: 11, IsCompleted: true},
+		{LessonID: 12, IsCompleted: true},
+	}, nil)
+
+	progress, err := useCase.GetCourseProgress(ctx, 7, 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, progress.CompletedLessons)
+	assert.Equal(t, 4, progress.TotalLessons)
+	assert.Equal(t, 75.0, progress.Progress)
+	assert.Len(t, progress.Modules, 2)
+	assert.Equal(t, 100.0, progress.Modules[0].Progress)
+	assert.Equal(t, &completedAt, progress.Modules[0].CompletedAt)
+	assert.Equal(t, 50.0, progress.Modules[1].Progress)
+	assert.Nil(t, progress.Modules[1].CompletedAt)
+}
+
+func (m *MockModuleService) RecordCompletion(ctx context.Context, userID, courseID, moduleID int) (bool, error) {
+	args := m.Called(ctx, userID, courseID, moduleID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockModuleService) DeleteCompletions(ctx context.Context, courseID, moduleID int, userIDs []int) error {
+	args := m.Called(ctx, courseID, moduleID, userIDs)
+	return args.Error(0)
+}
+
+func (m *MockLessonProgressService) GetCompletedByCourse(ctx context.Context, courseID int) ([]*models.LessonProgress, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]*models.LessonProgress), args.Error(1)
+}
+
+func (m *MockCourseVersionService) GetVersions(ctx context.Context, courseID int) ([]*models.CourseVersion, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]*models.CourseVersion), args.Error(1)
+}
+
+func (m *MockEnrollmentService) MarkAsCompleted(ctx context.Context, userID, courseID int) error {
+	args := m.Called(ctx, userID, courseID)
+	return args.Error(0)
+}
+
+func (m *MockEnrollmentService) MarkAsActive(ctx context.Context, userID, courseID int) error {
+	args := m.Called(ctx, userID, courseID)
+	return args.Error(0)
+}
+
+func TestRecalculateCourseProgressAfterLessonDeleted(t *testing.T) {
+	ctx := context.Background()
+	advanced := 2
+
+	lessonService := new(MockLessonService)
+	moduleService := new(MockModuleService)
+	progressService := new(MockLessonProgressService)
+	versionService := new(MockCourseVersionService)
+	enrollmentService := new(MockEnrollmentService)
+	useCase := usecase.NewLessonProgressUseCase(progressService, lessonService, enrollmentService, nil, nil, moduleService, versionService)
+
+	var events []models.ModuleCompletedEvent
+	useCase.OnModuleCompleted(func(ctx context.Context, event models.ModuleCompletedEvent) {
+		events = append(events, event)
+	})
+
+	// урок 13 удалён: студент 7 прошёл всё остальное, студент 8 - нет,
+	// студент 10 уже завершил курс, студент 11 отчислен
+	versionService.On("GetVersions", ctx, 5).Return([]*models.CourseVersion{}, nil)
+	enrollmentService.On("GetEnrollmentsByCourse", ctx, 5).Return([]*models.Enrollment{
+		{UserID: 7, CourseID: 5, Status: "active"},
+		{UserID: 8, CourseID: 5, Status: "active"},
+		{UserID: 10, CourseID: 5, Status: "completed"},
+		{UserID: 11, CourseID: 5, Status: "dropped"},
+	}, nil)
+	lessonService.On("GetAllLessons", ctx, 5).Return([]*models.Lesson{{ID: 12, CourseID: 5, ModuleID: &advanced}}, nil)
+	progressService.On("GetCompletedByCourse", ctx, 5).Return([]*models.LessonProgress{
+		{UserID: 7, LessonID: 12, IsCompleted: true},
+		{UserID: 8, LessonID: 13, IsCompleted: true},
+		{UserID: 10, LessonID: 12, IsCompleted: true},
+		{UserID: 11, LessonID: 12, IsCompleted: true},
+	}, nil).Once()
+	moduleService.On("RecordCompletion", ctx, 7, 5, advanced).Return(true, nil)
+	moduleService.On("RecordCompletion", ctx, 10, 5, advanced).Return(false, nil)
+	moduleService.On("DeleteCompletions", ctx, 5, advanced, []int{8}).Return(nil).Once()
+	enrollmentService.On("MarkAsCompleted", ctx, 7, 5).Return(nil)
+
+	err := useCase.RecalculateCourseProgress(ctx, 5, &advanced)
+
+	assert.NoError(t, err)
+	enrollmentService.AssertExpectations(t)
+	moduleService.AssertExpectations(t)
+	progressService.AssertExpectations(t)
+	enrollmentService.AssertNotCalled(t, "MarkAsCompleted", ctx, 8, 5)
+	enrollmentService.AssertNotCalled(t, "MarkAsCompleted", ctx, 10, 5)
+	enrollmentService.AssertNotCalled(t, "MarkAsActive", mock.Anything, mock.Anything, mock.Anything)
+	moduleService.AssertNotCalled(t, "RecordCompletion", ctx, 11, 5, advanced)
+	progressService.AssertNotCalled(t, "GetProgressByCourse", mock.Anything, mock.Anything, mock.Anything)
+	assert.Len(t, events, 1)
+	assert.Equal(t, 7, events[0].UserID)
+}
+
+func TestRecalculateCourseProgressSkipsPublishedCourse(t *testing.T) {
+	ctx := context.Background()
+	advanced := 2
+
+	versionService := new(MockCourseVersionService)
+	enrollmentService := new(MockEnrollmentService)
+	progressService := new(MockLessonProgressService)
+	useCase := usecase.NewLessonProgressUseCase(progressService, nil, enrollmentService, nil, nil, nil, versionService)
+
+	// студенты опубликованного курса видят уроки версии, правка черновика их не касается
+	versionService.On("GetVersions", ctx, 5).Return([]*models.CourseVersion{{ID: 1, CourseID: 5, Version: 1}}, nil)
+
+	err := useCase.RecalculateCourseProgress(ctx, 5, &advanced)
+
+	assert.NoError(t, err)
+	enrollmentService.AssertNotCalled(t, "GetEnrollmentsByCourse", mock.Anything, mock.Anything)
+	progressService.AssertNotCalled(t, "GetCompletedByCourse", mock.Anything, mock.Anything)
+}
+
+func TestRecalculateCourseProgressAfterLessonRestored(t *testing.T) {
+	ctx := context.Background()
+	advanced := 2
+
+	lessonService := new(MockLessonService)
+	moduleService := new(MockModuleService)
+	progressService := new(MockLessonProgressService)
+	versionService := new(MockCourseVersionService)
+	enrollmentService := new(MockEnrollmentService)
+	useCase := usecase.NewLessonProgressUseCase(progressService, lessonService, enrollmentService, nil, nil, moduleService, versionService)
+
+	// урок 13 восстановлен: студент 7 завершил курс без него, студент 8 проходил его до удаления
+	versionService.On("GetVersions", ctx, 5).Return([]*models.CourseVersion{}, nil)
+	enrollmentService.On("GetEnrollmentsByCourse", ctx, 5).Return([]*models.Enrollment{
+		{UserID: 7, CourseID: 5, Status: "completed"},
+		{UserID: 8, CourseID: 5, Status: "completed"},
+	}, nil)
+	lessonService.On("GetAllLessons", ctx, 5).Return([]*models.Lesson{
+		{ID: 12, CourseID: 5, ModuleID: &advanced},
+		{ID: 13, CourseID: 5, ModuleID: &advanced},
+	}, nil)
+	progressService.On("GetCompletedByCourse", ctx, 5).Return([]*models.LessonProgress{
+		{UserID: 7, LessonID: 12, IsCompleted: true},
+		{UserID: 8, LessonID: 12, IsCompleted: true},
+		{UserID: 8, LessonID: 13, IsCompleted: true},
+	}, nil)
+	moduleService.On("DeleteCompletions", ctx, 5, advanced, []int{7}).Return(nil)
+	moduleService.On("RecordCompletion", ctx, 8, 5, advanced).Return(false, nil)
+	enrollmentService.On("MarkAsActive", ctx, 7, 5).Return(nil)
+
+	err := useCase.RecalculateCourseProgress(ctx, 5, &advanced)
+
+	assert.NoError(t, err)
+	moduleService.AssertExpectations(t)
+	enrollmentService.AssertExpectations(t)
+	enrollmentService.AssertNotCalled(t, "MarkAsActive", ctx, 8, 5)
+	enrollmentService.AssertNotCalled(t, "MarkAsCompleted", mock.Anything, mock.Anything, mock.Anything)
+}
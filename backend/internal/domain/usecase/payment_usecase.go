@@ -0,0 +1,392 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services/pdfgen"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+	"gitlab.com/w0ikid/study-platform/pkg/payment"
+)
+
+// defaultCurrency - валюта курса, если преподаватель её не указал
+const defaultCurrency = "USD"
+
+type PaymentUseCase struct {
+	orderService  services.OrderServiceInterface
+	couponService services.CouponServiceInterface
+	courseService services.CourseServiceInterface
+	userService   services.UserServiceInterface
+	enrollment    EnrollmentUseCaseInterface
+	provider      payment.PaymentProvider
+}
+
+func NewPaymentUseCase(
+	orderService services.OrderServiceInterface,
+	couponService services.CouponServiceInterface,
+	courseService services.CourseServiceInterface,
+	userService services.UserServiceInterface,
+	enrollment EnrollmentUseCaseInterface,
+	provider payment.PaymentProvider,
+) *PaymentUseCase {
+	return &PaymentUseCase{
+		orderService:  orderService,
+		couponService: couponService,
+		courseService: courseService,
+		userService:   userService,
+		enrollment:    enrollment,
+		provider:      provider,
+	}
+}
+
+// SetPrice задаёт цену курса; цена 0 делает курс бесплатным
+func (u *PaymentUseCase) SetPrice(ctx context.Context, userID int, userRole string, courseID int, input *dto.CoursePriceRequest) (*models.Course, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	course, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseEditorRoles)
+	if err != nil {
+		return nil, err
+	}
+
+	course.PriceCents = input.PriceCents
+	course.Currency = strings.ToUpper(input.Currency)
+	if course.Currency == "" {
+		course.Currency = defaultCurrency
+	}
+	if err := u.courseService.UpdatePrice(ctx, course); err != nil {
+		return nil, err
+	}
+	return course, nil
+}
+
+// CreateOrder оформляет покупку курса. Заказ ждёт подтверждения оплаты вебхуком провайдера;
+// если купон покрывает всю цену, студент записывается сразу. Пока на курс есть ожидающий
+// оплаты заказ, повторный запрос с тем же купоном возвращает его, с другим купоном - отклоняется.
+func (u *PaymentUseCase) CreateOrder(ctx context.Context, userID, courseID int, input *dto.CreateOrderRequest) (*models.Order, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	course, err := u.enrollment.CheckEnrollment(ctx, userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if !course.IsPaid() {
+		return nil, errors.New("course is free, enroll directly")
+	}
+
+	order := &models.Order{
+		UserID:      userID,
+		CourseID:    courseID,
+		CourseName:  course.Name,
+		AmountCents: course.PriceCents,
+		Currency:    course.Currency,
+		Status:      models.OrderStatusPending,
+		Provider:    u.provider.Name(),
+	}
+
+	if code := strings.TrimSpace(input.CouponCode); code != "" {
+		coupon, err := u.couponService.GetCouponByCode(ctx, code)
+		if err != nil {
+			return nil, err
+		}
+		if coupon == nil {
+			return nil, errors.New("coupon not found")
+		}
+		if reason := coupon.UnavailableReason(courseID, time.Now()); reason != "" {
+			return nil, errors.New(reason)
+		}
+		order.CouponID = &coupon.ID
+		order.CouponCode = &coupon.Code
+		order.DiscountCents = coupon.Discount(course.PriceCents)
+	}
+	order.TotalCents = order.AmountCents - order.DiscountCents
+
+	pending, err := u.orderService.CreateOrder(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		return reusePendingOrder(pending, order)
+	}
+
+	if order.TotalCents == 0 {
+		return u.completeOrder(ctx, order)
+	}
+
+	created, err := u.provider.CreatePayment(ctx, payment.PaymentRequest{
+		OrderID:     order.ID,
+		AmountCents: order.TotalCents,
+		Currency:    order.Currency,
+		Description: course.Name,
+	})
+	if err != nil {
+		u.orderService.MarkFailed(ctx, order.ID)
+		return nil, fmt.Errorf("failed to create payment: %w", err)
+	}
+	order.PaymentID = &created.ID
+	order.CheckoutURL = &created.CheckoutURL
+	if err := u.orderService.SetPayment(ctx, order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// reusePendingOrder возвращает уже оформленный заказ вместо нового, если он оплачивается тем же купоном
+func reusePendingOrder(pending, requested *models.Order) (*models.Order, error) {
+	if pending.CheckoutURL == nil {
+		return nil, errors.New("validation failed: an order for this course is already being processed")
+	}
+	if !sameCoupon(pending.CouponID, requested.CouponID) {
+		return nil, errors.New("validation failed: an order for this course with a different coupon is awaiting payment")
+	}
+	return pending, nil
+}
+
+func sameCoupon(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// HandleWebhook обрабатывает уведомление провайдера о платеже. Повторные уведомления
+// по уже обработанному заказу ничего не меняют.
+func (u *PaymentUseCase) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	event, err := u.provider.ParseWebhook(payload, signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPermissionDenied, err)
+	}
+
+	order, err := u.orderService.GetOrderByPayment(ctx, u.provider.Name(), event.PaymentID)
+	if err != nil {
+		return err
+	}
+	if order == nil {
+		return errors.New("order not found")
+	}
+
+	switch event.Type {
+	case payment.EventPaymentSucceeded:
+		if order.Status != models.OrderStatusPending {
+			return nil
+		}
+		if event.AmountCents != order.TotalCents || !strings.EqualFold(event.Currency, order.Currency) {
+			return fmt.Errorf("payment amount mismatch for order %d", order.ID)
+		}
+		_, err := u.completeOrder(ctx, order)
+		return err
+	case payment.EventPaymentFailed:
+		_, err := u.orderService.MarkFailed(ctx, order.ID)
+		return err
+	case payment.EventPaymentRefunded:
+		// возврат, оформленный на стороне провайдера
+		return u.revokeOrder(ctx, order)
+	default:
+		return fmt.Errorf("unsupported webhook event %q", event.Type)
+	}
+}
+
+// completeOrder отмечает заказ оплаченным и записывает студента. Если записать
+// не удалось (например, закончились места) или купон уже исчерпан, деньги возвращаются.
+func (u *PaymentUseCase) completeOrder(ctx context.Context, order *models.Order) (*models.Order, error) {
+	paid, err := u.orderService.MarkPaid(ctx, order.ID)
+	if errors.Is(err, models.ErrCouponLimitReached) {
+		log.Printf("payment: coupon of order %d is used up, refunding", order.ID)
+		if err := u.refundPayment(ctx, order); err != nil {
+			return nil, err
+		}
+		if _, err := u.orderService.MarkFailed(ctx, order.ID); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("order %d refunded: %w", order.ID, models.ErrCouponLimitReached)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !paid {
+		return u.orderService.GetOrder(ctx, order.ID)
+	}
+
+	if _, err := u.enrollment.EnrollPaid(ctx, order.UserID, order.CourseID); err != nil {
+		log.Printf("payment: failed to enroll user %d by order %d, refunding: %v", order.UserID, order.ID, err)
+		if refundErr := u.refund(ctx, order); refundErr != nil {
+			return nil, refundErr
+		}
+		return nil, fmt.Errorf("order %d refunded: %w", order.ID, err)
+	}
+	return u.orderService.GetOrder(ctx, order.ID)
+}
+
+// RefundOrder возвращает деньги за оплаченный заказ и отзывает запись на курс
+func (u *PaymentUseCase) RefundOrder(ctx context.Context, orderID int) (*models.Order, error) {
+	order, err := u.orderService.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != models.OrderStatusPaid {
+		return nil, errors.New("only paid orders can be refunded")
+	}
+
+	if err := u.refund(ctx, order); err != nil {
+		return nil, err
+	}
+	if err := u.revokeEnrollment(ctx, order); err != nil {
+		return nil, err
+	}
+	return u.orderService.GetOrder(ctx, orderID)
+}
+
+// refund возвращает деньги у провайдера и отмечает заказ возвращённым
+func (u *PaymentUseCase) refund(ctx context.Context, order *models.Order) error {
+	if err := u.refundPayment(ctx, order); err != nil {
+		return err
+	}
+	_, err := u.orderService.MarkRefunded(ctx, order.ID)
+	return err
+}
+
+// refundPayment возвращает деньги у провайдера; бесплатный заказ возвращать нечего
+func (u *PaymentUseCase) refundPayment(ctx context.Context, order *models.Order) error {
+	if order.TotalCents > 0 && order.PaymentID != nil {
+		if err := u.provider.Refund(ctx, *order.PaymentID, order.TotalCents); err != nil {
+			return fmt.Errorf("failed to refund payment: %w", err)
+		}
+	}
+	return nil
+}
+
+// revokeOrder отмечает возврат, пришедший от провайдера, и отзывает запись на курс
+func (u *PaymentUseCase) revokeOrder(ctx context.Context, order *models.Order) error {
+	refunded, err := u.orderService.MarkRefunded(ctx, order.ID)
+	if err != nil || !refunded {
+		return err
+	}
+	return u.revokeEnrollment(ctx, order)
+}
+
+func (u *PaymentUseCase) revokeEnrollment(ctx context.Context, order *models.Order) error {
+	enrolled, err := u.enrollment.IsUserEnrolled(ctx, order.UserID, order.CourseID)
+	if err != nil || !enrolled {
+		return err
+	}
+	return u.enrollment.DropCourse(ctx, order.UserID, order.CourseID)
+}
+
+// GetOrder возвращает заказ его владельцу или администратору
+func (u *PaymentUseCase) GetOrder(ctx context.Context, userID int, userRole string, orderID int) (*models.Order, error) {
+	order, err := u.orderService.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID && userRole != "admin" {
+		return nil, fmt.Errorf("%w: order belongs to another user", ErrPermissionDenied)
+	}
+	return order, nil
+}
+
+// GetMyOrders возвращает заказы пользователя
+func (u *PaymentUseCase) GetMyOrders(ctx context.Context, userID int) ([]*models.Order, error) {
+	return u.orderService.GetUserOrders(ctx, userID)
+}
+
+// GetInvoice формирует PDF-счёт по оплаченному (в том числе позже возвращённому) заказу
+func (u *PaymentUseCase) GetInvoice(ctx context.Context, userID int, userRole string, orderID int) ([]byte, error) {
+	order, err := u.GetOrder(ctx, userID, userRole, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.PaidAt == nil {
+		return nil, errors.New("order is not paid")
+	}
+
+	user, err := u.userService.GetUser(ctx, order.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return pdfgen.GenerateInvoicePDF(order, user)
+}
+
+// CreateCoupon создаёт купон. Купон на все курсы может создать только администратор,
+// купон курса - его владелец или соавтор.
+func (u *PaymentUseCase) CreateCoupon(ctx context.Context, userID int, userRole string, input *dto.CouponRequest) (*models.Coupon, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if input.Type == models.CouponTypePercent && input.Value > 100 {
+		return nil, errors.New("validation failed: percent discount cannot exceed 100")
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("validation failed: expires_at must be in the future")
+	}
+	if err := u.checkCouponAccess(ctx, userID, userRole, input.CourseID); err != nil {
+		return nil, err
+	}
+
+	existing, err := u.couponService.GetCouponByCode(ctx, input.Code)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("coupon code already exists")
+	}
+
+	coupon := &models.Coupon{
+		Code:      strings.ToUpper(input.Code),
+		Type:      input.Type,
+		Value:     input.Value,
+		CourseID:  input.CourseID,
+		ExpiresAt: input.ExpiresAt,
+		MaxUses:   input.MaxUses,
+		CreatedBy: userID,
+	}
+	if err := u.couponService.CreateCoupon(ctx, coupon); err != nil {
+		return nil, err
+	}
+	return coupon, nil
+}
+
+// GetCoupons возвращает все купоны (только для администратора)
+func (u *PaymentUseCase) GetCoupons(ctx context.Context) ([]*models.Coupon, error) {
+	return u.couponService.GetAllCoupons(ctx)
+}
+
+// GetCourseCoupons возвращает купоны курса его редакторам
+func (u *PaymentUseCase) GetCourseCoupons(ctx context.Context, userID int, userRole string, courseID int) ([]*models.Coupon, error) {
+	if err := u.checkCouponAccess(ctx, userID, userRole, &courseID); err != nil {
+		return nil, err
+	}
+	return u.couponService.GetCourseCoupons(ctx, courseID)
+}
+
+// DeleteCoupon удаляет купон; уже оформленные заказы сохраняют скидку
+func (u *PaymentUseCase) DeleteCoupon(ctx context.Context, userID int, userRole string, couponID int) error {
+	coupon, err := u.couponService.GetCoupon(ctx, couponID)
+	if err != nil {
+		return err
+	}
+	if err := u.checkCouponAccess(ctx, userID, userRole, coupon.CourseID); err != nil {
+		return err
+	}
+	return u.couponService.DeleteCoupon(ctx, couponID)
+}
+
+func (u *PaymentUseCase) checkCouponAccess(ctx context.Context, userID int, userRole string, courseID *int) error {
+	if courseID == nil {
+		if userRole != "admin" {
+			return fmt.Errorf("%w: only admins can manage site-wide coupons", ErrPermissionDenied)
+		}
+		return nil
+	}
+	_, err := checkCourseStaff(ctx, u.courseService, userID, userRole, *courseID, courseEditorRoles)
+	return err
+}
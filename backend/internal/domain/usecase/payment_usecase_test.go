@@ -0,0 +1,277 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+	"gitlab.com/w0ikid/study-platform/pkg/payment"
+)
+
+// Mock для OrderService
+type MockOrderService struct {
+	mock.Mock
+	services.OrderServiceInterface
+}
+
+func (m *MockOrderService) CreateOrder(ctx context.Context, order *models.Order) (*models.Order, error) {
+	args := m.Called(ctx, order)
+	if args.Get(0) == nil {
+		order.ID = 1
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Order), args.Error(1)
+}
+
+func (m *MockOrderService) GetOrder(ctx context.Context, id int) (*models.Order, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Order), args.Error(1)
+}
+
+func (m *MockOrderService) GetOrderByPayment(ctx context.Context, provider, paymentID string) (*models.Order, error) {
+	args := m.Called(ctx, provider, paymentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Order), args.Error(1)
+}
+
+func (m *MockOrderService) SetPayment(ctx context.Context, order *models.Order) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+func (m *MockOrderService) MarkPaid(ctx context.Context, id int) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockOrderService) MarkFailed(ctx context.Context, id int) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockOrderService) MarkRefunded(ctx context.Context, id int) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+// Mock для CouponService
+type MockCouponService struct {
+	mock.Mock
+	services.CouponServiceInterface
+}
+
+func (m *MockCouponService) GetCouponByCode(ctx context.Context, code string) (*models.Coupon, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Coupon), args.Error(1)
+}
+
+func (m *MockEnrollmentUseCase) CheckEnrollment(ctx context.Context, userID, courseID int) (*models.Course, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Course), args.Error(1)
+}
+
+func (m *MockEnrollmentUseCase) EnrollPaid(ctx context.Context, userID, courseID int) (*models.EnrollmentResult, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EnrollmentResult), args.Error(1)
+}
+
+func TestCreateOrder(t *testing.T) {
+	ctx := context.Background()
+	course := &models.Course{ID: 1, Name: "Go 101", PriceCents: 2000, Currency: "USD"}
+
+	t.Run("Applies percent coupon and creates payment", func(t *testing.T) {
+		orderService := new(MockOrderService)
+		couponService := new(MockCouponService)
+		enrollment := new(MockEnrollmentUseCase)
+		useCase := usecase.NewPaymentUseCase(orderService, couponService, nil, nil, enrollment, payment.NewLocalProvider("secret", "http://localhost:4200"))
+
+		enrollment.On("CheckEnrollment", ctx, 7, 1).Return(course, nil)
+		couponService.On("GetCouponByCode", ctx, "SPRING").Return(&models.Coupon{ID: 3, Code: "SPRING", Type: models.CouponTypePercent, Value: 25}, nil)
+		orderService.On("CreateOrder", ctx, mock.Anything).Return(nil, nil)
+		orderService.On("SetPayment", ctx, mock.Anything).Return(nil)
+
+		order, err := useCase.CreateOrder(ctx, 7, 1, &dto.CreateOrderRequest{CouponCode: "SPRING"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 500, order.DiscountCents)
+		assert.Equal(t, 1500, order.TotalCents)
+		assert.Equal(t, models.OrderStatusPending, order.Status)
+		assert.Contains(t, *order.CheckoutURL, "http://localhost:4200/checkout/")
+		enrollment.AssertNotCalled(t, "EnrollPaid", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Expired coupon", func(t *testing.T) {
+		couponService := new(MockCouponService)
+		enrollment := new(MockEnrollmentUseCase)
+		useCase := usecase.NewPaymentUseCase(new(MockOrderService), couponService, nil, nil, enrollment, payment.NewLocalProvider("secret", ""))
+
+		expired := time.Now().Add(-time.Hour)
+		enrollment.On("CheckEnrollment", ctx, 7, 1).Return(course, nil)
+		couponService.On("GetCouponByCode", ctx, "OLD").Return(&models.Coupon{ID: 3, Code: "OLD", Type: models.CouponTypeFixed, Value: 100, ExpiresAt: &expired}, nil)
+
+		_, err := useCase.CreateOrder(ctx, 7, 1, &dto.CreateOrderRequest{CouponCode: "OLD"})
+
+		assert.EqualError(t, err, "coupon has expired")
+	})
+
+	t.Run("Coupon covering the full price enrolls immediately", func(t *testing.T) {
+		orderService := new(MockOrderService)
+		couponService := new(MockCouponService)
+		enrollment := new(MockEnrollmentUseCase)
+		useCase := usecase.NewPaymentUseCase(orderService, couponService, nil, nil, enrollment, payment.NewLocalProvider("secret", ""))
+
+		enrollment.On("CheckEnrollment", ctx, 7, 1).Return(course, nil)
+		couponService.On("GetCouponByCode", ctx, "FREE").Return(&models.Coupon{ID: 4, Code: "FREE", Type: models.CouponTypeFixed, Value: 5000}, nil)
+		orderService.On("CreateOrder", ctx, mock.Anything).Return(nil, nil)
+		orderService.On("MarkPaid", ctx, 1).Return(true, nil)
+		enrollment.On("EnrollPaid", ctx, 7, 1).Return(&models.EnrollmentResult{Status: models.EnrollmentResultEnrolled}, nil)
+		orderService.On("GetOrder", ctx, 1).Return(&models.Order{ID: 1, Status: models.OrderStatusPaid, TotalCents: 0}, nil)
+
+		order, err := useCase.CreateOrder(ctx, 7, 1, &dto.CreateOrderRequest{CouponCode: "FREE"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.OrderStatusPaid, order.Status)
+		orderService.AssertNotCalled(t, "SetPayment", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Pending order with the same coupon is reused", func(t *testing.T) {
+		orderService := new(MockOrderService)
+		couponService := new(MockCouponService)
+		enrollment := new(MockEnrollmentUseCase)
+		useCase := usecase.NewPaymentUseCase(orderService, couponService, nil, nil, enrollment, payment.NewLocalProvider("secret", ""))
+
+		couponID, checkoutURL := 3, "http://localhost:4200/checkout/pay_1"
+		pending := &models.Order{ID: 5, CouponID: &couponID, Status: models.OrderStatusPending, CheckoutURL: &checkoutURL}
+		enrollment.On("CheckEnrollment", ctx, 7, 1).Return(course, nil)
+		couponService.On("GetCouponByCode", ctx, "SPRING").Return(&models.Coupon{ID: 3, Code: "SPRING", Type: models.CouponTypePercent, Value: 25}, nil)
+		orderService.On("CreateOrder", ctx, mock.Anything).Return(pending, nil)
+
+		order, err := useCase.CreateOrder(ctx, 7, 1, &dto.CreateOrderRequest{CouponCode: "SPRING"})
+
+		assert.NoError(t, err)
+		assert.Same(t, pending, order)
+		orderService.AssertNotCalled(t, "SetPayment", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Pending order with another coupon is rejected", func(t *testing.T) {
+		orderService := new(MockOrderService)
+		enrollment := new(MockEnrollmentUseCase)
+		useCase := usecase.NewPaymentUseCase(orderService, new(MockCouponService), nil, nil, enrollment, payment.NewLocalProvider("secret", ""))
+
+		couponID, checkoutURL := 3, "http://localhost:4200/checkout/pay_1"
+		pending := &models.Order{ID: 5, CouponID: &couponID, Status: models.OrderStatusPending, CheckoutURL: &checkoutURL}
+		enrollment.On("CheckEnrollment", ctx, 7, 1).Return(course, nil)
+		orderService.On("CreateOrder", ctx, mock.Anything).Return(pending, nil)
+
+		_, err := useCase.CreateOrder(ctx, 7, 1, &dto.CreateOrderRequest{})
+
+		assert.EqualError(t, err, "validation failed: an order for this course with a different coupon is awaiting payment")
+		orderService.AssertNotCalled(t, "SetPayment", mock.Anything, mock.Anything)
+	})
+}
+
+func TestHandleWebhook(t *testing.T) {
+	ctx := context.Background()
+	provider := payment.NewLocalProvider("secret", "")
+	payload := []byte(`{"type":"payment.succeeded","payment_id":"local_1","amount_cents":1500,"currency":"USD"}`)
+	paymentID := "local_1"
+	pending := func() *models.Order {
+		return &models.Order{ID: 1, UserID: 7, CourseID: 1, TotalCents: 1500, Currency: "USD", Status: models.OrderStatusPending, PaymentID: &paymentID}
+	}
+
+	t.Run("Invalid signature", func(t *testing.T) {
+		orderService := new(MockOrderService)
+		useCase := usecase.NewPaymentUseCase(orderService, nil, nil, nil, nil, provider)
+
+		err := useCase.HandleWebhook(ctx, payload, "deadbeef")
+
+		assert.ErrorIs(t, err, usecase.ErrPermissionDenied)
+		orderService.AssertNotCalled(t, "GetOrderByPayment", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Successful payment enrolls the student", func(t *testing.T) {
+		orderService := new(MockOrderService)
+		enrollment := new(MockEnrollmentUseCase)
+		useCase := usecase.NewPaymentUseCase(orderService, nil, nil, nil, enrollment, provider)
+
+		orderService.On("GetOrderByPayment", ctx, "local", "local_1").Return(pending(), nil)
+		orderService.On("MarkPaid", ctx, 1).Return(true, nil)
+		enrollment.On("EnrollPaid", ctx, 7, 1).Return(&models.EnrollmentResult{Status: models.EnrollmentResultEnrolled}, nil)
+		orderService.On("GetOrder", ctx, 1).Return(&models.Order{ID: 1, Status: models.OrderStatusPaid}, nil)
+
+		err := useCase.HandleWebhook(ctx, payload, provider.Sign(payload))
+
+		assert.NoError(t, err)
+		enrollment.AssertExpectations(t)
+	})
+
+	t.Run("Repeated webhook is ignored", func(t *testing.T) {
+		orderService := new(MockOrderService)
+		enrollment := new(MockEnrollmentUseCase)
+		useCase := usecase.NewPaymentUseCase(orderService, nil, nil, nil, enrollment, provider)
+
+		paid := pending()
+		paid.Status = models.OrderStatusPaid
+		orderService.On("GetOrderByPayment", ctx, "local", "local_1").Return(paid, nil)
+
+		err := useCase.HandleWebhook(ctx, payload, provider.Sign(payload))
+
+		assert.NoError(t, err)
+		orderService.AssertNotCalled(t, "MarkPaid", mock.Anything, mock.Anything)
+		enrollment.AssertNotCalled(t, "EnrollPaid", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Full course refunds the payment", func(t *testing.T) {
+		orderService := new(MockOrderService)
+		enrollment := new(MockEnrollmentUseCase)
+		useCase := usecase.NewPaymentUseCase(orderService, nil, nil, nil, enrollment, provider)
+
+		orderService.On("GetOrderByPayment", ctx, "local", "local_1").Return(pending(), nil)
+		orderService.On("MarkPaid", ctx, 1).Return(true, nil)
+		enrollment.On("EnrollPaid", ctx, 7, 1).Return(nil, usecase.ErrCourseFull)
+		orderService.On("MarkRefunded", ctx, 1).Return(true, nil)
+
+		err := useCase.HandleWebhook(ctx, payload, provider.Sign(payload))
+
+		assert.ErrorIs(t, err, usecase.ErrCourseFull)
+		orderService.AssertExpectations(t)
+	})
+
+	t.Run("Used up coupon refunds the payment", func(t *testing.T) {
+		orderService := new(MockOrderService)
+		enrollment := new(MockEnrollmentUseCase)
+		useCase := usecase.NewPaymentUseCase(orderService, nil, nil, nil, enrollment, provider)
+
+		orderService.On("GetOrderByPayment", ctx, "local", "local_1").Return(pending(), nil)
+		orderService.On("MarkPaid", ctx, 1).Return(false, models.ErrCouponLimitReached)
+		orderService.On("MarkFailed", ctx, 1).Return(true, nil)
+
+		err := useCase.HandleWebhook(ctx, payload, provider.Sign(payload))
+
+		assert.ErrorIs(t, err, models.ErrCouponLimitReached)
+		orderService.AssertExpectations(t)
+		orderService.AssertNotCalled(t, "MarkRefunded", mock.Anything, mock.Anything)
+		enrollment.AssertNotCalled(t, "EnrollPaid", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+)
+
+// Веса составляющих оценки рекомендации, в сумме 1
+const (
+	recommendationWeightCoEnrollment = 0.5
+	recommendationWeightCategory     = 0.2
+	recommendationWeightTags         = 0.15
+	recommendationWeightLevel        = 0.1
+	recommendationWeightRating       = 0.05
+)
+
+const (
+	defaultRecommendationLimit = 10
+	maxRecommendationLimit     = 50
+)
+
+type RecommendationUseCase struct {
+	recommendationService services.RecommendationServiceInterface
+	courseService         services.CourseServiceInterface
+	enrollmentService     services.EnrollmentServiceInterface
+	userService           services.UserServiceInterface
+}
+
+func NewRecommendationUseCase(
+	recommendationService services.RecommendationServiceInterface,
+	courseService services.CourseServiceInterface,
+	enrollmentService services.EnrollmentServiceInterface,
+	userService services.UserServiceInterface,
+) *RecommendationUseCase {
+	return &RecommendationUseCase{
+		recommendationService: recommendationService,
+		courseService:         courseService,
+		enrollmentService:     enrollmentService,
+		userService:           userService,
+	}
+}
+
+// GetRecommendations подбирает курсы каталога, на которые пользователь ещё не записан.
+// Оценка складывается из похожести на его курсы по совместным записям студентов,
+// совпадения категории и тегов, соответствия уровня курса User.Level и рейтинга.
+func (u *RecommendationUseCase) GetRecommendations(ctx context.Context, userID, limit int) ([]*models.RecommendedCourse, error) {
+	if limit <= 0 {
+		limit = defaultRecommendationLimit
+	}
+	if limit > maxRecommendationLimit {
+		limit = maxRecommendationLimit
+	}
+
+	user, err := u.userService.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	enrollments, err := u.enrollmentService.GetEnrollmentsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	catalog, err := u.courseService.GetCatalog(ctx, models.CourseFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	coursesByID := make(map[int]*models.Course, len(catalog))
+	for i := range catalog {
+		coursesByID[catalog[i].ID] = &catalog[i]
+	}
+
+	// профиль интересов - категории и теги курсов, на которые пользователь записан
+	enrolled := map[int]bool{}
+	categories := map[int]bool{}
+	tags := map[string]bool{}
+	var enrolledIDs []int
+	for _, enrollment := range enrollments {
+		enrolled[enrollment.CourseID] = true
+		enrolledIDs = append(enrolledIDs, enrollment.CourseID)
+
+		course, ok := coursesByID[enrollment.CourseID]
+		if !ok {
+			if course, err = u.courseService.GetCourse(ctx, enrollment.CourseID); err != nil {
+				continue
+			}
+		}
+		if course.CategoryID != nil {
+			categories[*course.CategoryID] = true
+		}
+		for _, tag := range course.Tags {
+			tags[tag] = true
+		}
+	}
+
+	similarities, err := u.recommendationService.GetSimilarCourses(ctx, enrolledIDs)
+	if err != nil {
+		return nil, err
+	}
+	coEnrollment := map[int]float64{}
+	maxCoEnrollment := 0.0
+	for _, similarity := range similarities {
+		coEnrollment[similarity.SimilarCourseID] += similarity.Score
+		if coEnrollment[similarity.SimilarCourseID] > maxCoEnrollment {
+			maxCoEnrollment = coEnrollment[similarity.SimilarCourseID]
+		}
+	}
+
+	var recommendations []*models.RecommendedCourse
+	for i := range catalog {
+		course := &catalog[i]
+		if enrolled[course.ID] || course.TeacherID == userID || course.Status == models.CourseStatusInactive || course.MinUserLevel > user.Level {
+			continue
+		}
+
+		recommendation := &models.RecommendedCourse{Course: *course, Reasons: []string{}}
+		if maxCoEnrollment > 0 && coEnrollment[course.ID] > 0 {
+			recommendation.Score += recommendationWeightCoEnrollment * coEnrollment[course.ID] / maxCoEnrollment
+			recommendation.Reasons = append(recommendation.Reasons, models.RecommendationReasonCoEnrollment)
+		}
+		if course.CategoryID != nil && categories[*course.CategoryID] {
+			recommendation.Score += recommendationWeightCategory
+			recommendation.Reasons = append(recommendation.Reasons, models.RecommendationReasonCategory)
+		}
+		if overlap := tagOverlap(course.Tags, tags); overlap > 0 {
+			recommendation.Score += recommendationWeightTags * overlap
+			recommendation.Reasons = append(recommendation.Reasons, models.RecommendationReasonTags)
+		}
+		if match := levelMatch(course.Level, user.Level); match > 0 {
+			recommendation.Score += recommendationWeightLevel * match
+			recommendation.Reasons = append(recommendation.Reasons, models.RecommendationReasonLevel)
+		}
+		if course.RatingCount > 0 {
+			recommendation.Score += recommendationWeightRating * course.RatingAverage / 5
+			if course.RatingAverage >= 4 {
+				recommendation.Reasons = append(recommendation.Reasons, models.RecommendationReasonRating)
+			}
+		}
+		recommendations = append(recommendations, recommendation)
+	}
+
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].EnrolledCount > recommendations[j].EnrolledCount
+	})
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	if recommendations == nil {
+		recommendations = []*models.RecommendedCourse{}
+	}
+	return recommendations, nil
+}
+
+// RecomputeSimilarities пересчитывает похожесть курсов по совместным записям
+func (u *RecommendationUseCase) RecomputeSimilarities(ctx context.Context) error {
+	pairs, err := u.recommendationService.RecomputeSimilarities(ctx)
+	if err != nil {
+		return err
+	}
+	log.Printf("recommendations: computed %d course similarities", pairs)
+	return nil
+}
+
+// RunSimilarityJob периодически пересчитывает похожесть курсов, пока не отменён ctx
+func (u *RecommendationUseCase) RunSimilarityJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := u.RecomputeSimilarities(ctx); err != nil {
+			log.Printf("recommendations: similarity job failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tagOverlap - доля тегов курса, встречающихся в курсах пользователя
+func tagOverlap(courseTags []string, userTags map[string]bool) float64 {
+	if len(courseTags) == 0 {
+		return 0
+	}
+	matched := 0
+	for _, tag := range courseTags {
+		if userTags[tag] {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(courseTags))
+}
+
+// levelMatch: 1 - курс уровня пользователя, 0.5 - на уровень выше, иначе 0
+func levelMatch(courseLevel, userLevel int) float64 {
+	switch courseLevel - userLevel {
+	case 0:
+		return 1
+	case 1:
+		return 0.5
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,102 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+)
+
+// Mock для RecommendationService
+type MockRecommendationService struct {
+	mock.Mock
+	services.RecommendationServiceInterface
+}
+
+func (m *MockRecommendationService) GetSimilarCourses(ctx context.Context, courseIDs []int) ([]*models.CourseSimilarity, error) {
+	args := m.Called(ctx, courseIDs)
+	return args.Get(0).([]*models.CourseSimilarity), args.Error(1)
+}
+
+func (m *MockCourseService) GetCatalog(ctx context.Context, filter models.CourseFilter) ([]models.Course, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]models.Course), args.Error(1)
+}
+
+func (m *MockEnrollmentService) GetEnrollmentsByUser(ctx context.Context, userID int) ([]*models.Enrollment, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]*models.Enrollment), args.Error(1)
+}
+
+func TestGetRecommendations(t *testing.T) {
+	ctx := context.Background()
+	golang, databases := 1, 2
+
+	catalog := []models.Course{
+		{ID: 1, Name: "Go basics", CategoryID: &golang, Level: models.CourseLevelBeginner, Tags: []string{"go"}},
+		{ID: 2, Name: "Advanced Go", CategoryID: &golang, Level: models.CourseLevelIntermediate, Tags: []string{"go", "concurrency"}},
+		{ID: 3, Name: "PostgreSQL", CategoryID: &databases, Level: models.CourseLevelBeginner, Tags: []string{"sql"}},
+		{ID: 4, Name: "Kubernetes", Level: models.CourseLevelAdvanced, Tags: []string{"devops"}, MinUserLevel: 2},
+		{ID: 5, Name: "Legacy", Status: models.CourseStatusInactive, CategoryID: &golang},
+		{ID: 6, Name: "My own course", TeacherID: 7, CategoryID: &golang},
+	}
+
+	t.Run("Blends co-enrollment with category, tags and level", func(t *testing.T) {
+		recommendationService := new(MockRecommendationService)
+		courseService := new(MockCourseService)
+		enrollmentService := new(MockEnrollmentService)
+		userService := new(MockUserService)
+		useCase := usecase.NewRecommendationUseCase(recommendationService, courseService, enrollmentService, userService)
+
+		userService.On("GetUser", ctx, 7).Return(&models.User{ID: 7, Level: 0}, nil)
+		enrollmentService.On("GetEnrollmentsByUser", ctx, 7).Return([]*models.Enrollment{{UserID: 7, CourseID: 1}}, nil)
+		courseService.On("GetCatalog", ctx, models.CourseFilter{}).Return(catalog, nil)
+		recommendationService.On("GetSimilarCourses", ctx, []int{1}).Return([]*models.CourseSimilarity{
+			{CourseID: 1, SimilarCourseID: 3, Score: 0.6},
+			{CourseID: 1, SimilarCourseID: 2, Score: 0.3},
+		}, nil)
+
+		courses, err := useCase.GetRecommendations(ctx, 7, 0)
+
+		assert.NoError(t, err)
+		// enrolled, inactive, own and too-advanced courses are excluded
+		assert.Len(t, courses, 2)
+		// strongest co-enrollment outweighs the category and tag match
+		assert.Equal(t, 3, courses[0].ID)
+		assert.InDelta(t, 0.6, courses[0].Score, 0.001)
+		assert.Equal(t, []string{models.RecommendationReasonCoEnrollment, models.RecommendationReasonLevel}, courses[0].Reasons)
+		assert.Equal(t, 2, courses[1].ID)
+		assert.InDelta(t, 0.575, courses[1].Score, 0.001)
+		assert.Equal(t, []string{
+			models.RecommendationReasonCoEnrollment,
+			models.RecommendationReasonCategory,
+			models.RecommendationReasonTags,
+			models.RecommendationReasonLevel,
+		}, courses[1].Reasons)
+	})
+
+	t.Run("New user gets level-matched courses", func(t *testing.T) {
+		recommendationService := new(MockRecommendationService)
+		courseService := new(MockCourseService)
+		enrollmentService := new(MockEnrollmentService)
+		userService := new(MockUserService)
+		useCase := usecase.NewRecommendationUseCase(recommendationService, courseService, enrollmentService, userService)
+
+		userService.On("GetUser", ctx, 8).Return(&models.User{ID: 8, Level: 0}, nil)
+		enrollmentService.On("GetEnrollmentsByUser", ctx, 8).Return([]*models.Enrollment{}, nil)
+		courseService.On("GetCatalog", ctx, models.CourseFilter{}).Return(catalog, nil)
+		recommendationService.On("GetSimilarCourses", ctx, []int(nil)).Return([]*models.CourseSimilarity{}, nil)
+
+		courses, err := useCase.GetRecommendations(ctx, 8, 2)
+
+		assert.NoError(t, err)
+		assert.Len(t, courses, 2)
+		assert.Equal(t, 1, courses[0].ID)
+		assert.Equal(t, 3, courses[1].ID)
+	})
+}
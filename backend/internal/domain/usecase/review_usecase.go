@@ -0,0 +1,236 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+type ReviewUseCaseInterface interface {
+	GetCourseReviews(ctx context.Context, userRole string, courseID int) ([]*models.Review, error)
+	CreateReview(ctx context.Context, userID, courseID int, input *dto.ReviewRequest) (*models.Review, error)
+	UpdateReview(ctx context.Context, userID, courseID, reviewID int, input *dto.ReviewRequest) (*models.Review, error)
+	DeleteReview(ctx context.Context, userID int, userRole string, courseID, reviewID int) error
+	ReplyToReview(ctx context.Context, userID int, userRole string, courseID, reviewID int, input *dto.ReviewReplyRequest) (*models.Review, error)
+	ReportReview(ctx context.Context, userID, courseID, reviewID int, input *dto.ReviewReportRequest) error
+	SetReviewStatus(ctx context.Context, courseID, reviewID int, input *dto.ReviewStatusRequest) (*models.Review, error)
+	GetReportedReviews(ctx context.Context) ([]*models.Review, error)
+}
+
+type ReviewUseCase struct {
+	reviewService         services.ReviewServiceInterface
+	courseService         services.CourseServiceInterface
+	enrollmentService     services.EnrollmentServiceInterface
+	lessonService         services.LessonServiceInterface
+	lessonProgressService services.LessonProgressServiceInterface
+	minProgress           float64 // минимальный прогресс по курсу (%), после которого можно оставить отзыв
+}
+
+func NewReviewUseCase(
+	reviewService services.ReviewServiceInterface,
+	courseService services.CourseServiceInterface,
+	enrollmentService services.EnrollmentServiceInterface,
+	lessonService services.LessonServiceInterface,
+	lessonProgressService services.LessonProgressServiceInterface,
+	minProgress int,
+) *ReviewUseCase {
+	return &ReviewUseCase{
+		reviewService:         reviewService,
+		courseService:         courseService,
+		enrollmentService:     enrollmentService,
+		lessonService:         lessonService,
+		lessonProgressService: lessonProgressService,
+		minProgress:           float64(minProgress),
+	}
+}
+
+// GetCourseReviews возвращает отзывы курса; скрытые модератором видит только администратор
+func (u *ReviewUseCase) GetCourseReviews(ctx context.Context, userRole string, courseID int) ([]*models.Review, error) {
+	if _, err := u.courseService.GetCourse(ctx, courseID); err != nil {
+		return nil, err
+	}
+	return u.reviewService.GetCourseReviews(ctx, courseID, userRole == "admin")
+}
+
+// CreateReview оставляет отзыв студента о курсе. Отзыв может оставить только записанный
+// на курс студент, один раз; дальше отзыв редактируется через UpdateReview.
+func (u *ReviewUseCase) CreateReview(ctx context.Context, userID, courseID int, input *dto.ReviewRequest) (*models.Review, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	enrolled, err := u.enrollmentService.IsUserEnrolled(ctx, userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if !enrolled {
+		return nil, fmt.Errorf("%w: only enrolled students can review this course", ErrPermissionDenied)
+	}
+
+	if err := u.checkProgress(ctx, userID, courseID); err != nil {
+		return nil, err
+	}
+
+	existing, err := u.reviewService.GetUserReview(ctx, courseID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("you have already reviewed this course, edit your review instead")
+	}
+
+	review := &models.Review{
+		CourseID: courseID,
+		UserID:   userID,
+		Rating:   input.Rating,
+		Body:     strings.TrimSpace(input.Body),
+		Status:   models.ReviewStatusVisible,
+	}
+	if err := u.reviewService.CreateReview(ctx, review); err != nil {
+		return nil, err
+	}
+	return u.reviewService.GetReview(ctx, review.ID)
+}
+
+// checkProgress проверяет, что студент прошёл достаточную часть курса
+func (u *ReviewUseCase) checkProgress(ctx context.Context, userID, courseID int) error {
+	if u.minProgress <= 0 {
+		return nil
+	}
+
+	lessons, err := u.lessonService.GetAllLessons(ctx, courseID)
+	if err != nil {
+		return err
+	}
+	progresses, err := u.lessonProgressService.GetProgressByCourse(ctx, userID, courseID)
+	if err != nil {
+		return err
+	}
+
+	completed := completedLessonIDs(progresses)
+	done := 0
+	for _, lesson := range lessons {
+		if completed[lesson.ID] {
+			done++
+		}
+	}
+	if progress := percent(done, len(lessons)); progress < u.minProgress {
+		return fmt.Errorf("%w: complete at least %.0f%% of the course to leave a review (current progress %.0f%%)",
+			ErrPermissionDenied, u.minProgress, progress)
+	}
+	return nil
+}
+
+// UpdateReview меняет оценку и текст своего отзыва
+func (u *ReviewUseCase) UpdateReview(ctx context.Context, userID, courseID, reviewID int, input *dto.ReviewRequest) (*models.Review, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	review, err := u.getCourseReview(ctx, courseID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID != userID {
+		return nil, fmt.Errorf("%w: only the author can edit a review", ErrPermissionDenied)
+	}
+
+	review.Rating = input.Rating
+	review.Body = strings.TrimSpace(input.Body)
+	if err := u.reviewService.UpdateReview(ctx, review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// DeleteReview удаляет отзыв: автор или администратор
+func (u *ReviewUseCase) DeleteReview(ctx context.Context, userID int, userRole string, courseID, reviewID int) error {
+	review, err := u.getCourseReview(ctx, courseID, reviewID)
+	if err != nil {
+		return err
+	}
+	if review.UserID != userID && userRole != "admin" {
+		return fmt.Errorf("%w: only the author or an admin can delete a review", ErrPermissionDenied)
+	}
+	return u.reviewService.DeleteReview(ctx, reviewID)
+}
+
+// ReplyToReview сохраняет ответ команды курса на отзыв, пустой ответ удаляет его
+func (u *ReviewUseCase) ReplyToReview(ctx context.Context, userID int, userRole string, courseID, reviewID int, input *dto.ReviewReplyRequest) (*models.Review, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseStaffRoles); err != nil {
+		return nil, err
+	}
+	if _, err := u.getCourseReview(ctx, courseID, reviewID); err != nil {
+		return nil, err
+	}
+
+	var reply *string
+	if text := strings.TrimSpace(input.Reply); text != "" {
+		reply = &text
+	}
+	if err := u.reviewService.SetReply(ctx, reviewID, userID, reply); err != nil {
+		return nil, err
+	}
+	return u.reviewService.GetReview(ctx, reviewID)
+}
+
+// ReportReview отправляет жалобу на отзыв модераторам
+func (u *ReviewUseCase) ReportReview(ctx context.Context, userID, courseID, reviewID int, input *dto.ReviewReportRequest) error {
+	if err := validator.New().Struct(input); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	review, err := u.getCourseReview(ctx, courseID, reviewID)
+	if err != nil {
+		return err
+	}
+	if review.UserID == userID {
+		return errors.New("you cannot report your own review")
+	}
+
+	return u.reviewService.ReportReview(ctx, &models.ReviewReport{
+		ReviewID: reviewID,
+		UserID:   userID,
+		Reason:   strings.TrimSpace(input.Reason),
+	})
+}
+
+// SetReviewStatus скрывает или возвращает отзыв (модерация администратором)
+func (u *ReviewUseCase) SetReviewStatus(ctx context.Context, courseID, reviewID int, input *dto.ReviewStatusRequest) (*models.Review, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if _, err := u.getCourseReview(ctx, courseID, reviewID); err != nil {
+		return nil, err
+	}
+
+	if err := u.reviewService.SetStatus(ctx, reviewID, input.Status); err != nil {
+		return nil, err
+	}
+	return u.reviewService.GetReview(ctx, reviewID)
+}
+
+// GetReportedReviews возвращает очередь модерации: отзывы с жалобами
+func (u *ReviewUseCase) GetReportedReviews(ctx context.Context) ([]*models.Review, error) {
+	return u.reviewService.GetReportedReviews(ctx)
+}
+
+func (u *ReviewUseCase) getCourseReview(ctx context.Context, courseID, reviewID int) (*models.Review, error) {
+	review, err := u.reviewService.GetReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.CourseID != courseID {
+		return nil, errors.New("review not found in this course")
+	}
+	return review, nil
+}
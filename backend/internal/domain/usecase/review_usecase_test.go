@@ -0,0 +1,138 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+// Mock для ReviewService
+type MockReviewService struct {
+	mock.Mock
+	services.ReviewServiceInterface
+}
+
+func (m *MockReviewService) CreateReview(ctx context.Context, review *models.Review) error {
+	args := m.Called(ctx, review)
+	return args.Error(0)
+}
+
+func (m *MockReviewService) GetReview(ctx context.Context, id int) (*models.Review, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Review), args.Error(1)
+}
+
+func (m *MockReviewService) GetUserReview(ctx context.Context, courseID, userID int) (*models.Review, error) {
+	args := m.Called(ctx, courseID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Review), args.Error(1)
+}
+
+func (m *MockReviewService) UpdateReview(ctx context.Context, review *models.Review) error {
+	args := m.Called(ctx, review)
+	return args.Error(0)
+}
+
+func TestCreateReview(t *testing.T) {
+	ctx := context.Background()
+	input := &dto.ReviewRequest{Rating: 5, Body: "Great course"}
+
+	t.Run("Not enrolled", func(t *testing.T) {
+		enrollmentService := new(MockEnrollmentService)
+		reviewService := new(MockReviewService)
+		useCase := usecase.NewReviewUseCase(reviewService, nil, enrollmentService, nil, nil, 0)
+
+		enrollmentService.On("IsUserEnrolled", ctx, 7, 1).Return(false, nil)
+
+		_, err := useCase.CreateReview(ctx, 7, 1, input)
+
+		assert.ErrorIs(t, err, usecase.ErrPermissionDenied)
+		reviewService.AssertNotCalled(t, "CreateReview", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Not enough progress", func(t *testing.T) {
+		enrollmentService := new(MockEnrollmentService)
+		lessonService := new(MockLessonService)
+		progressService := new(MockLessonProgressService)
+		useCase := usecase.NewReviewUseCase(new(MockReviewService), nil, enrollmentService, lessonService, progressService, 50)
+
+		enrollmentService.On("IsUserEnrolled", ctx, 7, 1).Return(true, nil)
+		lessonService.On("GetAllLessons", ctx, 1).Return([]*models.Lesson{{ID: 1}, {ID: 2}, {ID: 3}}, nil)
+		progressService.On("GetProgressByCourse", ctx, 7, 1).Return([]*models.LessonProgress{{LessonID: 1, IsCompleted: true}}, nil)
+
+		_, err := useCase.CreateReview(ctx, 7, 1, input)
+
+		assert.ErrorIs(t, err, usecase.ErrPermissionDenied)
+		assert.Contains(t, err.Error(), "complete at least 50%")
+	})
+
+	t.Run("Already reviewed", func(t *testing.T) {
+		enrollmentService := new(MockEnrollmentService)
+		reviewService := new(MockReviewService)
+		useCase := usecase.NewReviewUseCase(reviewService, nil, enrollmentService, nil, nil, 0)
+
+		enrollmentService.On("IsUserEnrolled", ctx, 7, 1).Return(true, nil)
+		reviewService.On("GetUserReview", ctx, 1, 7).Return(&models.Review{ID: 3, CourseID: 1, UserID: 7}, nil)
+
+		_, err := useCase.CreateReview(ctx, 7, 1, input)
+
+		assert.EqualError(t, err, "you have already reviewed this course, edit your review instead")
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		enrollmentService := new(MockEnrollmentService)
+		reviewService := new(MockReviewService)
+		useCase := usecase.NewReviewUseCase(reviewService, nil, enrollmentService, nil, nil, 0)
+
+		enrollmentService.On("IsUserEnrolled", ctx, 7, 1).Return(true, nil)
+		reviewService.On("GetUserReview", ctx, 1, 7).Return(nil, nil)
+		reviewService.On("CreateReview", ctx, mock.MatchedBy(func(review *models.Review) bool {
+			return review.Rating == 5 && review.Status == models.ReviewStatusVisible
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.Review).ID = 3
+		}).Return(nil)
+		reviewService.On("GetReview", ctx, 3).Return(&models.Review{ID: 3, CourseID: 1, UserID: 7, Rating: 5}, nil)
+
+		review, err := useCase.CreateReview(ctx, 7, 1, input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, review.ID)
+	})
+
+	t.Run("Invalid rating", func(t *testing.T) {
+		useCase := usecase.NewReviewUseCase(nil, nil, nil, nil, nil, 0)
+
+		_, err := useCase.CreateReview(ctx, 7, 1, &dto.ReviewRequest{Rating: 6})
+
+		assert.ErrorContains(t, err, "validation failed")
+	})
+}
+
+func TestUpdateReviewOnlyAuthor(t *testing.T) {
+	ctx := context.Background()
+	reviewService := new(MockReviewService)
+	useCase := usecase.NewReviewUseCase(reviewService, nil, nil, nil, nil, 0)
+
+	reviewService.On("GetReview", ctx, 3).Return(&models.Review{ID: 3, CourseID: 1, UserID: 7, Rating: 2}, nil)
+
+	_, err := useCase.UpdateReview(ctx, 8, 1, 3, &dto.ReviewRequest{Rating: 1})
+	assert.ErrorIs(t, err, usecase.ErrPermissionDenied)
+
+	reviewService.On("UpdateReview", ctx, mock.Anything).Return(nil)
+	review, err := useCase.UpdateReview(ctx, 7, 1, 3, &dto.ReviewRequest{Rating: 4, Body: " better now "})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, review.Rating)
+	assert.Equal(t, "better now", review.Body)
+}
@@ -0,0 +1,393 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+	"gitlab.com/w0ikid/study-platform/pkg/mailer"
+)
+
+const (
+	maxImportRows             = 5000
+	temporaryPasswordLength   = 12
+	temporaryPasswordAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz23456789"
+)
+
+type UserImportUseCaseInterface interface {
+	ImportUsers(ctx context.Context, r io.Reader, dryRun, sendInvites bool) (*models.UserImportReport, error)
+}
+
+type UserImportUseCase struct {
+	userService   services.UserServiceInterface
+	courseService services.CourseServiceInterface
+	groupService  services.GroupServiceInterface
+	enrollment    EnrollmentUseCaseInterface
+	mailer        mailer.Mailer
+	appURL        string
+}
+
+func NewUserImportUseCase(
+	userService services.UserServiceInterface,
+	courseService services.CourseServiceInterface,
+	groupService services.GroupServiceInterface,
+	enrollment EnrollmentUseCaseInterface,
+	mailer mailer.Mailer,
+	appURL string,
+) *UserImportUseCase {
+	return &UserImportUseCase{
+		userService:   userService,
+		courseService: courseService,
+		groupService:  groupService,
+		enrollment:    enrollment,
+		mailer:        mailer,
+		appURL:        appURL,
+	}
+}
+
+// ImportUserCSVRow - строка CSV после разбора, ещё не проверенная
+type ImportUserCSVRow struct {
+	Line   int
+	Row    dto.ImportUserRow
+	Errors []string
+}
+
+// ParseUserImportCSV разбирает CSV с заголовком. Обязательные колонки: username, email, role;
+// необязательные: name, surname, group (ID группы), course (ID курса).
+func ParseUserImportCSV(r io.Reader) ([]*ImportUserCSVRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("validation failed: csv file is empty")
+		}
+		return nil, fmt.Errorf("validation failed: invalid csv header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"username", "email", "role"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("validation failed: missing required column %q", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []*ImportUserCSVRow
+	line := 1
+	for {
+		record, err := reader.Read()
+		line++
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		row := &ImportUserCSVRow{Line: line}
+		rows = append(rows, row)
+		if len(rows) > maxImportRows {
+			return nil, fmt.Errorf("validation failed: csv file has more than %d rows", maxImportRows)
+		}
+		if err != nil {
+			row.Errors = append(row.Errors, fmt.Sprintf("invalid csv row: %v", err))
+			continue
+		}
+
+		row.Row = dto.ImportUserRow{
+			Username: field(record, "username"),
+			Name:     field(record, "name"),
+			Surname:  field(record, "surname"),
+			Email:    strings.ToLower(field(record, "email")),
+			Role:     strings.ToLower(field(record, "role")),
+		}
+		if value := field(record, "group"); value != "" {
+			id, err := strconv.Atoi(value)
+			if err != nil {
+				row.Errors = append(row.Errors, "group: must be a group ID")
+			}
+			row.Row.GroupID = id
+		}
+		if value := field(record, "course"); value != "" {
+			id, err := strconv.Atoi(value)
+			if err != nil {
+				row.Errors = append(row.Errors, "course: must be a course ID")
+			}
+			row.Row.CourseID = id
+		}
+	}
+
+	return rows, nil
+}
+
+// ImportUsers проверяет строки CSV и, если ошибок нет и это не dry-run, создаёт
+// все учётные записи одной транзакцией. Пароли генерируются автоматически и либо
+// возвращаются в отчёте, либо отправляются пользователям в письме-приглашении.
+func (u *UserImportUseCase) ImportUsers(ctx context.Context, r io.Reader, dryRun, sendInvites bool) (*models.UserImportReport, error) {
+	rows, err := ParseUserImportCSV(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.validateRows(ctx, rows); err != nil {
+		return nil, err
+	}
+
+	report := &models.UserImportReport{
+		DryRun: dryRun,
+		Total:  len(rows),
+		Rows:   make([]*models.UserImportRowResult, 0, len(rows)),
+	}
+	for _, row := range rows {
+		report.Rows = append(report.Rows, &models.UserImportRowResult{
+			Line:     row.Line,
+			Username: row.Row.Username,
+			Email:    row.Row.Email,
+			Errors:   row.Errors,
+		})
+		if len(row.Errors) == 0 {
+			report.Valid++
+		} else {
+			report.Invalid++
+		}
+	}
+
+	if dryRun || report.Invalid > 0 || len(rows) == 0 {
+		return report, nil
+	}
+
+	entries := make([]*models.UserImportEntry, 0, len(rows))
+	passwords := make([]string, 0, len(rows))
+	for _, row := range rows {
+		password, err := generateTemporaryPassword()
+		if err != nil {
+			return nil, err
+		}
+		passwords = append(passwords, password)
+		entries = append(entries, &models.UserImportEntry{
+			User: &models.User{
+				Username: row.Row.Username,
+				Name:     row.Row.Name,
+				Surname:  row.Row.Surname,
+				Email:    row.Row.Email,
+				Password: password,
+				Role:     row.Row.Role,
+			},
+			CourseID: row.Row.CourseID,
+			GroupID:  row.Row.GroupID,
+		})
+	}
+
+	if err := u.userService.ImportUsers(ctx, entries); err != nil {
+		return nil, err
+	}
+	report.Created = len(entries)
+
+	groupCourses := make(map[int][]int)
+	for i, entry := range entries {
+		report.Rows[i].UserID = entry.User.ID
+		report.Rows[i].Enrollments = u.enrollImported(ctx, entry, groupCourses)
+	}
+
+	// без настроенной почты приглашение никуда не уйдёт, поэтому пароли отдаются администратору
+	delivers := mailer.Delivers(u.mailer)
+	for i, entry := range entries {
+		result := report.Rows[i]
+		if !sendInvites {
+			result.TemporaryPassword = passwords[i]
+			continue
+		}
+		if !delivers {
+			result.InviteError = "email delivery is not configured"
+			result.TemporaryPassword = passwords[i]
+			continue
+		}
+
+		if err := u.mailer.Send(entry.User.Email, "Your EduApp account", u.inviteBody(entry.User, passwords[i])); err != nil {
+			// аккаунт уже создан, поэтому пароль отдаём администратору
+			result.InviteError = err.Error()
+			result.TemporaryPassword = passwords[i]
+			continue
+		}
+		result.InviteSent = true
+	}
+
+	return report, nil
+}
+
+// enrollImported зачисляет созданного пользователя на курс строки и курсы его группы так же,
+// как администратор через AdminEnrollStudent: с проверкой мест и пререквизитов. Аккаунт уже
+// создан, поэтому отказ в зачислении не отменяет импорт, а попадает в отчёт.
+// groupCourses - курсы уже загруженных групп.
+func (u *UserImportUseCase) enrollImported(ctx context.Context, entry *models.UserImportEntry, groupCourses map[int][]int) []*models.UserImportEnrollment {
+	var courseIDs []int
+	if entry.CourseID != 0 {
+		courseIDs = append(courseIDs, entry.CourseID)
+	}
+	if entry.GroupID != 0 {
+		if _, loaded := groupCourses[entry.GroupID]; !loaded {
+			courses, err := u.groupService.GetCourses(ctx, entry.GroupID)
+			if err != nil {
+				log.Printf("user import: failed to get courses of group %d: %v", entry.GroupID, err)
+			}
+			groupCourses[entry.GroupID] = []int{}
+			for _, course := range courses {
+				groupCourses[entry.GroupID] = append(groupCourses[entry.GroupID], course.CourseID)
+			}
+		}
+		for _, courseID := range groupCourses[entry.GroupID] {
+			if !slices.Contains(courseIDs, courseID) {
+				courseIDs = append(courseIDs, courseID)
+			}
+		}
+	}
+
+	var enrollments []*models.UserImportEnrollment
+	for _, courseID := range courseIDs {
+		enrollment := &models.UserImportEnrollment{CourseID: courseID}
+		if err := u.enrollment.AdminEnrollStudent(ctx, entry.User.ID, courseID, false); err != nil {
+			enrollment.Error = err.Error()
+		} else {
+			enrollment.Enrolled = true
+		}
+		enrollments = append(enrollments, enrollment)
+	}
+	return enrollments
+}
+
+// validateRows дописывает в строки ошибки валидации, дубликаты и ссылки на несуществующие курсы и группы
+func (u *UserImportUseCase) validateRows(ctx context.Context, rows []*ImportUserCSVRow) error {
+	validate := validator.New()
+
+	usernames := make(map[string]int)
+	emails := make(map[string]int)
+	for _, row := range rows {
+		if err := validate.Struct(row.Row); err != nil {
+			var validationErrors validator.ValidationErrors
+			if errors.As(err, &validationErrors) {
+				for _, fe := range validationErrors {
+					row.Errors = append(row.Errors, fmt.Sprintf("%s: failed on '%s'", strings.ToLower(fe.Field()), fe.Tag()))
+				}
+			} else {
+				row.Errors = append(row.Errors, err.Error())
+			}
+		}
+
+		if row.Row.Username != "" {
+			if line, ok := usernames[row.Row.Username]; ok {
+				row.Errors = append(row.Errors, fmt.Sprintf("username: duplicates line %d", line))
+			} else {
+				usernames[row.Row.Username] = row.Line
+			}
+		}
+		if row.Row.Email != "" {
+			if line, ok := emails[row.Row.Email]; ok {
+				row.Errors = append(row.Errors, fmt.Sprintf("email: duplicates line %d", line))
+			} else {
+				emails[row.Row.Email] = row.Line
+			}
+		}
+	}
+
+	if len(usernames) > 0 || len(emails) > 0 {
+		existing, err := u.userService.FindExistingUsers(ctx, mapKeys(usernames), mapKeys(emails))
+		if err != nil {
+			return err
+		}
+		takenUsernames := make(map[string]bool)
+		takenEmails := make(map[string]bool)
+		for _, user := range existing {
+			takenUsernames[user.Username] = true
+			takenEmails[strings.ToLower(user.Email)] = true
+		}
+		for _, row := range rows {
+			if takenUsernames[row.Row.Username] {
+				row.Errors = append(row.Errors, "username: already taken")
+			}
+			if takenEmails[row.Row.Email] {
+				row.Errors = append(row.Errors, "email: already taken")
+			}
+		}
+	}
+
+	courses := make(map[int]bool)
+	groups := make(map[int]bool)
+	for _, row := range rows {
+		if id := row.Row.CourseID; id > 0 {
+			if _, checked := courses[id]; !checked {
+				_, err := u.courseService.GetCourse(ctx, id)
+				courses[id] = err == nil
+			}
+			if !courses[id] {
+				row.Errors = append(row.Errors, fmt.Sprintf("course: course %d not found", id))
+			}
+		}
+		if id := row.Row.GroupID; id > 0 {
+			if _, checked := groups[id]; !checked {
+				_, err := u.groupService.GetGroup(ctx, id)
+				groups[id] = err == nil
+			}
+			if !groups[id] {
+				row.Errors = append(row.Errors, fmt.Sprintf("group: group %d not found", id))
+			}
+		}
+	}
+
+	return nil
+}
+
+func (u *UserImportUseCase) inviteBody(user *models.User, password string) string {
+	return fmt.Sprintf(`Hello %s,
+
+An account has been created for you on EduApp.
+
+Username: %s
+Email: %s
+Temporary password: %s
+
+Please sign in at %s and change your password.
+`, user.Username, user.Username, user.Email, password, u.appURL)
+}
+
+func generateTemporaryPassword() (string, error) {
+	alphabet := big.NewInt(int64(len(temporaryPasswordAlphabet)))
+	password := make([]byte, temporaryPasswordLength)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, alphabet)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate password: %w", err)
+		}
+		password[i] = temporaryPasswordAlphabet[n.Int64()]
+	}
+	return string(password), nil
+}
+
+func mapKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,196 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/pkg/mailer"
+)
+
+func (m *MockUserService) FindExistingUsers(ctx context.Context, usernames, emails []string) ([]*models.User, error) {
+	args := m.Called(ctx, usernames, emails)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.User), args.Error(1)
+}
+
+func (m *MockUserService) ImportUsers(ctx context.Context, entries []*models.UserImportEntry) error {
+	args := m.Called(ctx, entries)
+	return args.Error(0)
+}
+
+// Mock для Mailer
+type MockMailer struct {
+	mock.Mock
+}
+
+func (m *MockMailer) Send(to, subject, body string) error {
+	args := m.Called(to, subject, body)
+	return args.Error(0)
+}
+
+func TestParseUserImportCSV(t *testing.T) {
+	t.Run("Parses rows with optional columns", func(t *testing.T) {
+		csv := "Username,Email,Role,Name,Surname,Group,Course\n" +
+			"alice,Alice@Example.com,Student,Alice,Smith,3,7\n" +
+			"bob,bob@example.com,teacher,,,,\n"
+
+		rows, err := usecase.ParseUserImportCSV(strings.NewReader(csv))
+
+		assert.NoError(t, err)
+		assert.Len(t, rows, 2)
+		assert.Equal(t, 2, rows[0].Line)
+		assert.Equal(t, "alice@example.com", rows[0].Row.Email)
+		assert.Equal(t, "student", rows[0].Row.Role)
+		assert.Equal(t, 3, rows[0].Row.GroupID)
+		assert.Equal(t, 7, rows[0].Row.CourseID)
+		assert.Empty(t, rows[0].Errors)
+		assert.Equal(t, 0, rows[1].Row.CourseID)
+	})
+
+	t.Run("Missing required column", func(t *testing.T) {
+		_, err := usecase.ParseUserImportCSV(strings.NewReader("username,email\nalice,a@example.com\n"))
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "role")
+	})
+
+	t.Run("Invalid course ID", func(t *testing.T) {
+		rows, err := usecase.ParseUserImportCSV(strings.NewReader("username,email,role,course\nalice,a@example.com,student,abc\n"))
+
+		assert.NoError(t, err)
+		assert.Contains(t, rows[0].Errors, "course: must be a course ID")
+	})
+}
+
+func (m *MockGroupService) GetCourses(ctx context.Context, groupID int) ([]*models.GroupCourse, error) {
+	args := m.Called(ctx, groupID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.GroupCourse), args.Error(1)
+}
+
+func (m *MockEnrollmentUseCase) AdminEnrollStudent(ctx context.Context, userID, courseID int, overridePrerequisites bool) error {
+	args := m.Called(ctx, userID, courseID, overridePrerequisites)
+	return args.Error(0)
+}
+
+func TestImportUsers(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Dry run reports per-row errors", func(t *testing.T) {
+		userService := new(MockUserService)
+		useCase := usecase.NewUserImportUseCase(userService, nil, nil, nil, nil, "http://localhost")
+
+		csv := "username,email,role\n" +
+			"alice,alice@example.com,student\n" +
+			"bob,not-an-email,student\n" +
+			"carol,alice@example.com,wizard\n" +
+			"taken,taken@example.com,student\n"
+
+		userService.On("FindExistingUsers", ctx, mock.Anything, mock.Anything).
+			Return([]*models.User{{Username: "taken", Email: "other@example.com"}}, nil).Once()
+
+		report, err := useCase.ImportUsers(ctx, strings.NewReader(csv), true, false)
+
+		assert.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 4, report.Total)
+		assert.Equal(t, 1, report.Valid)
+		assert.Equal(t, 3, report.Invalid)
+		assert.Empty(t, report.Rows[0].Errors)
+		assert.Contains(t, report.Rows[1].Errors, "email: failed on 'email'")
+		assert.Contains(t, report.Rows[2].Errors, "role: failed on 'oneof'")
+		assert.Contains(t, report.Rows[2].Errors, "email: duplicates line 2")
+		assert.Contains(t, report.Rows[3].Errors, "username: already taken")
+		userService.AssertNotCalled(t, "ImportUsers", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Creates users and sends invites", func(t *testing.T) {
+		userService := new(MockUserService)
+		mail := new(MockMailer)
+		useCase := usecase.NewUserImportUseCase(userService, nil, nil, nil, mail, "http://localhost")
+
+		csv := "username,email,role\nalice,alice@example.com,student\nbob,bob@example.com,student\n"
+
+		userService.On("FindExistingUsers", ctx, mock.Anything, mock.Anything).Return([]*models.User{}, nil).Once()
+		userService.On("ImportUsers", ctx, mock.MatchedBy(func(entries []*models.UserImportEntry) bool {
+			return len(entries) == 2 && len(entries[0].User.Password) == 12
+		})).Run(func(args mock.Arguments) {
+			for i, entry := range args.Get(1).([]*models.UserImportEntry) {
+				entry.User.ID = i + 1
+			}
+		}).Return(nil).Once()
+		mail.On("Send", "alice@example.com", mock.Anything, mock.Anything).Return(nil).Once()
+		mail.On("Send", "bob@example.com", mock.Anything, mock.Anything).Return(errors.New("smtp down")).Once()
+
+		report, err := useCase.ImportUsers(ctx, strings.NewReader(csv), false, true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.Created)
+		assert.Equal(t, 1, report.Rows[0].UserID)
+		assert.True(t, report.Rows[0].InviteSent)
+		assert.Empty(t, report.Rows[0].TemporaryPassword)
+		assert.False(t, report.Rows[1].InviteSent)
+		assert.NotEmpty(t, report.Rows[1].TemporaryPassword)
+		userService.AssertExpectations(t)
+		mail.AssertExpectations(t)
+	})
+
+	t.Run("Returns passwords when email delivery is not configured", func(t *testing.T) {
+		userService := new(MockUserService)
+		useCase := usecase.NewUserImportUseCase(userService, nil, nil, nil, mailer.NewLogMailer(), "http://localhost")
+
+		csv := "username,email,role\nalice,alice@example.com,student\n"
+
+		userService.On("FindExistingUsers", ctx, mock.Anything, mock.Anything).Return([]*models.User{}, nil).Once()
+		userService.On("ImportUsers", ctx, mock.Anything).Return(nil).Once()
+
+		report, err := useCase.ImportUsers(ctx, strings.NewReader(csv), false, true)
+
+		assert.NoError(t, err)
+		assert.False(t, report.Rows[0].InviteSent)
+		assert.Equal(t, "email delivery is not configured", report.Rows[0].InviteError)
+		assert.NotEmpty(t, report.Rows[0].TemporaryPassword)
+	})
+
+	t.Run("Enrolls through the enrollment rules", func(t *testing.T) {
+		userService := new(MockUserService)
+		courseService := new(MockCourseService)
+		groupService := new(MockGroupService)
+		enrollment := new(MockEnrollmentUseCase)
+		useCase := usecase.NewUserImportUseCase(userService, courseService, groupService, enrollment, nil, "http://localhost")
+
+		csv := "username,email,role,course,group\nalice,alice@example.com,student,5,3\n"
+
+		userService.On("FindExistingUsers", ctx, mock.Anything, mock.Anything).Return([]*models.User{}, nil).Once()
+		courseService.On("GetCourse", ctx, 5).Return(&models.Course{ID: 5}, nil)
+		groupService.On("GetGroup", ctx, 3).Return(&models.Group{ID: 3}, nil)
+		userService.On("ImportUsers", ctx, mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(1).([]*models.UserImportEntry)[0].User.ID = 42
+		}).Return(nil).Once()
+		groupService.On("GetCourses", ctx, 3).Return([]*models.GroupCourse{{GroupID: 3, CourseID: 5}, {GroupID: 3, CourseID: 6}}, nil).Once()
+		enrollment.On("AdminEnrollStudent", ctx, 42, 5, false).Return(nil).Once()
+		enrollment.On("AdminEnrollStudent", ctx, 42, 6, false).Return(usecase.ErrCourseFull).Once()
+
+		report, err := useCase.ImportUsers(ctx, strings.NewReader(csv), false, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, []*models.UserImportEnrollment{
+			{CourseID: 5, Enrolled: true},
+			{CourseID: 6, Error: usecase.ErrCourseFull.Error()},
+		}, report.Rows[0].Enrollments)
+		enrollment.AssertExpectations(t)
+		groupService.AssertExpectations(t)
+	})
+}
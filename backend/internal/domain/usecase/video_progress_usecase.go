@@ -0,0 +1,276 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+// Границы проверки heartbeat. Отрезок не может быть длиннее, чем успело бы проиграться
+// на максимальной скорости с прошлого heartbeat, а всё засчитанное - чем успело бы
+// проиграться с первого heartbeat; лишнее не засчитывается.
+const (
+	videoMaxPlaybackRate = 2  // максимальная скорость воспроизведения в проигрывателе
+	videoHeartbeatSlack  = 5  // секунд на задержки сети; в общем ограничении даётся один раз
+	videoFirstSegmentMax = 30 // максимальный отрезок в первом heartbeat, когда прошлого ещё нет
+	videoSeekTolerance   = 3  // скачок вперёд меньше этого не считается перемоткой
+	videoResumeTail      = 5  // если осталось меньше, просмотр продолжается с начала
+	videoDurationSlack   = 2  // расхождение длительности от проигрывателя с известной длительностью видео
+)
+
+type VideoProgressUseCaseInterface interface {
+	Heartbeat(ctx context.Context, userID, courseID, lessonID int, input *dto.VideoHeartbeatRequest) (*models.VideoProgress, error)
+	GetProgress(ctx context.Context, userID, courseID, lessonID int) (*models.VideoProgress, error)
+	GetLessonReport(ctx context.Context, userID int, userRole string, courseID, lessonID int) ([]*models.VideoProgress, error)
+	CheckWatched(ctx context.Context, userID int, lesson *models.Lesson) error
+}
+
+type VideoProgressUseCase struct {
+	videoService      services.VideoProgressServiceInterface
+	lessonService     services.LessonServiceInterface
+	courseService     services.CourseServiceInterface
+	progress          LessonProgressUseCaseInterface
+	completionPercent int
+}
+
+// NewVideoProgressUseCase - completionPercent: доля различных просмотренных секунд видео,
+// после которой урок завершается автоматически; 0 отключает проверку просмотра
+func NewVideoProgressUseCase(
+	videoService services.VideoProgressServiceInterface,
+	lessonService services.LessonServiceInterface,
+	courseService services.CourseServiceInterface,
+	progress LessonProgressUseCaseInterface,
+	completionPercent int,
+) *VideoProgressUseCase {
+	return &VideoProgressUseCase{
+		videoService:      videoService,
+		lessonService:     lessonService,
+		courseService:     courseService,
+		progress:          progress,
+		completionPercent: completionPercent,
+	}
+}
+
+// Heartbeat учитывает отрезок, проигранный с прошлого heartbeat, и сохраняет позицию.
+// Когда просмотрено не меньше completionPercent видео, урок завершается через LessonProgressUseCase.
+func (u *VideoProgressUseCase) Heartbeat(ctx context.Context, userID, courseID, lessonID int, input *dto.VideoHeartbeatRequest) (*models.VideoProgress, error) {
+	if err := validator.New().Struct(input); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	lesson, err := getCourseLesson(ctx, u.lessonService, courseID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if lesson.VideoURL == "" {
+		return nil, errors.New("validation failed: lesson has no video")
+	}
+
+	now := time.Now()
+	progress, err := u.videoService.UpdateProgress(ctx, userID, lessonID, courseID, func(progress *models.VideoProgress) error {
+		duration, err := videoDuration(lesson, progress, input)
+		if err != nil {
+			return err
+		}
+		applyHeartbeat(progress, input, duration, now)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u.withPercent(progress)
+	if progress.CompletedAt == nil && u.completionPercent > 0 && progress.WatchedPercent >= float64(u.completionPercent) {
+		err := u.progress.MarkLessonCompleted(ctx, userID, lessonID, courseID)
+		switch {
+		case errors.Is(err, ErrLessonRequirementsNotMet):
+			// остальные условия урока ещё не выполнены, просмотр засчитается при следующем heartbeat
+		case err != nil && !errors.Is(err, ErrLessonAlreadyCompleted):
+			return nil, err
+		default:
+			if err := u.videoService.MarkCompleted(ctx, userID, lessonID); err != nil {
+				return nil, err
+			}
+			progress.CompletedAt = &now
+		}
+	}
+	progress.LessonCompleted = progress.CompletedAt != nil
+	return progress, nil
+}
+
+// GetProgress возвращает прогресс просмотра видео урока текущим студентом
+func (u *VideoProgressUseCase) GetProgress(ctx context.Context, userID, courseID, lessonID int) (*models.VideoProgress, error) {
+	if _, err := getCourseLesson(ctx, u.lessonService, courseID, lessonID); err != nil {
+		return nil, err
+	}
+	progress, err := u.videoService.GetProgress(ctx, userID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if progress == nil {
+		progress = &models.VideoProgress{UserID: userID, LessonID: lessonID, CourseID: courseID, Intervals: []models.WatchInterval{}}
+	}
+	u.withPercent(progress)
+	progress.LessonCompleted = progress.CompletedAt != nil
+	return progress, nil
+}
+
+// GetLessonReport - просмотр видео урока всеми студентами с перемотками и отброшенными отрезками, для команды курса
+func (u *VideoProgressUseCase) GetLessonReport(ctx context.Context, userID int, userRole string, courseID, lessonID int) ([]*models.VideoProgress, error) {
+	if _, err := checkCourseStaff(ctx, u.courseService, userID, userRole, courseID, courseStaffRoles); err != nil {
+		return nil, err
+	}
+	if _, err := getCourseLesson(ctx, u.lessonService, courseID, lessonID); err != nil {
+		return nil, err
+	}
+	report, err := u.videoService.GetLessonProgress(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	for _, progress := range report {
+		u.withPercent(progress)
+		progress.LessonCompleted = progress.CompletedAt != nil
+	}
+	return report, nil
+}
+
+// CheckWatched - условие завершения урока с видео для LessonProgressUseCase:
+// студент должен просмотреть не меньше completionPercent различных секунд видео
+func (u *VideoProgressUseCase) CheckWatched(ctx context.Context, userID int, lesson *models.Lesson) error {
+	if u.completionPercent <= 0 || lesson.VideoURL == "" {
+		return nil
+	}
+	progress, err := u.videoService.GetProgress(ctx, userID, lesson.ID)
+	if err != nil {
+		return err
+	}
+	watched := 0.0
+	if progress != nil {
+		if lesson.VideoDuration != nil {
+			progress.Duration = *lesson.VideoDuration
+		}
+		u.withPercent(progress)
+		watched = progress.WatchedPercent
+	}
+	if watched < float64(u.completionPercent) {
+		return fmt.Errorf("%w: watch at least %d%% of the video (watched %.0f%%)", ErrLessonRequirementsNotMet, u.completionPercent, watched)
+	}
+	return nil
+}
+
+// resumePositions возвращает для уроков курса секунду, с которой студент продолжит просмотр
+func resumePositions(progresses []*models.VideoProgress) map[int]int {
+	positions := make(map[int]int, len(progresses))
+	for _, progress := range progresses {
+		position := progress.Position
+		if progress.Duration > 0 && position >= progress.Duration-videoResumeTail {
+			position = 0
+		}
+		positions[progress.LessonID] = position
+	}
+	return positions
+}
+
+func (u *VideoProgressUseCase) withPercent(progress *models.VideoProgress) {
+	if progress.Duration > 0 {
+		progress.WatchedPercent = math.Round(float64(progress.WatchedSeconds)*1000/float64(progress.Duration)) / 10
+	}
+}
+
+// videoDuration возвращает длительность, от которой считается доля просмотра: заданную в уроке,
+// а если её нет - зафиксированную первым heartbeat студента. Длительность от клиента не может
+// её изменить, иначе короткая длительность завершила бы урок без просмотра видео.
+func videoDuration(lesson *models.Lesson, progress *models.VideoProgress, input *dto.VideoHeartbeatRequest) (int, error) {
+	reported := int(math.Ceil(input.Duration))
+	known := progress.Duration
+	if lesson.VideoDuration != nil {
+		known = *lesson.VideoDuration
+	}
+	if known == 0 {
+		return reported, nil
+	}
+	if reported < known-videoDurationSlack || reported > known+videoDurationSlack {
+		return 0, fmt.Errorf("validation failed: video duration %d does not match lesson video duration %d", reported, known)
+	}
+	return known, nil
+}
+
+// applyHeartbeat переносит отрезок heartbeat в прогресс: отбрасывает то, что не могло
+// проиграться за прошедшее время, считает перемотки вперёд и объединяет отрезки
+func applyHeartbeat(progress *models.VideoProgress, input *dto.VideoHeartbeatRequest, duration int, now time.Time) {
+	from := min(int(input.From), duration)
+	to := min(int(input.To), duration)
+
+	if progress.FirstHeartbeatAt == nil {
+		// прогресс, сохранённый до появления first_heartbeat_at, отсчитывается от прошлого heartbeat
+		progress.FirstHeartbeatAt = &now
+		if progress.LastHeartbeatAt != nil {
+			progress.FirstHeartbeatAt = progress.LastHeartbeatAt
+		}
+	}
+
+	allowed := videoFirstSegmentMax
+	if progress.LastHeartbeatAt != nil {
+		allowed = int(now.Sub(*progress.LastHeartbeatAt).Seconds()*videoMaxPlaybackRate) + videoHeartbeatSlack
+		// запас на задержки не накапливается: частые heartbeat не дают засчитать больше,
+		// чем проигралось бы с первого heartbeat
+		budget := videoFirstSegmentMax + int(now.Sub(*progress.FirstHeartbeatAt).Seconds()*videoMaxPlaybackRate) + videoHeartbeatSlack
+		allowed = max(min(allowed, budget-progress.CreditedSeconds), 0)
+		if from > progress.Position+videoSeekTolerance {
+			progress.SkipCount++
+			progress.SkippedSeconds += from - progress.Position
+		}
+	}
+	if to-from > allowed {
+		progress.RejectedSeconds += to - from - allowed
+		to = from + allowed
+	}
+
+	progress.Duration = duration
+	if to > from {
+		progress.CreditedSeconds += to - from
+		progress.Intervals = mergeWatchInterval(progress.Intervals, models.WatchInterval{Start: from, End: to})
+	}
+	if progress.Intervals == nil {
+		progress.Intervals = []models.WatchInterval{}
+	}
+	progress.WatchedSeconds = watchedSeconds(progress.Intervals, duration)
+	progress.Position = to
+	progress.LastHeartbeatAt = &now
+}
+
+// mergeWatchInterval добавляет отрезок и склеивает пересекающиеся и соседние отрезки
+func mergeWatchInterval(intervals []models.WatchInterval, next models.WatchInterval) []models.WatchInterval {
+	all := append(append([]models.WatchInterval{}, intervals...), next)
+	sort.Slice(all, func(i, j int) bool { return all[i].Start < all[j].Start })
+
+	merged := []models.WatchInterval{all[0]}
+	for _, interval := range all[1:] {
+		last := &merged[len(merged)-1]
+		if interval.Start <= last.End {
+			last.End = max(last.End, interval.End)
+			continue
+		}
+		merged = append(merged, interval)
+	}
+	return merged
+}
+
+// watchedSeconds - число различных просмотренных секунд в пределах длительности видео
+func watchedSeconds(intervals []models.WatchInterval, duration int) int {
+	total := 0
+	for _, interval := range intervals {
+		end := min(interval.End, duration)
+		if end > interval.Start {
+			total += end - interval.Start
+		}
+	}
+	return total
+}
@@ -0,0 +1,220 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"gitlab.com/w0ikid/study-platform/internal/domain/models"
+	"gitlab.com/w0ikid/study-platform/internal/domain/services"
+	"gitlab.com/w0ikid/study-platform/internal/domain/usecase"
+	"gitlab.com/w0ikid/study-platform/internal/dto"
+)
+
+// Mock для VideoProgressService
+type MockVideoProgressService struct {
+	mock.Mock
+	services.VideoProgressServiceInterface
+}
+
+func (m *MockVideoProgressService) GetProgress(ctx context.Context, userID, lessonID int) (*models.VideoProgress, error) {
+	args := m.Called(ctx, userID, lessonID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.VideoProgress), args.Error(1)
+}
+
+// UpdateProgress применяет apply к прогрессу из Return (или к пустому, если его нет), как репозиторий под блокировкой
+func (m *MockVideoProgressService) UpdateProgress(ctx context.Context, userID, lessonID, courseID int, apply func(progress *models.VideoProgress) error) (*models.VideoProgress, error) {
+	args := m.Called(ctx, userID, lessonID, courseID)
+	progress := &models.VideoProgress{UserID: userID, LessonID: lessonID, CourseID: courseID, Intervals: []models.WatchInterval{}}
+	if args.Get(0) != nil {
+		progress = args.Get(0).(*models.VideoProgress)
+	}
+	if err := apply(progress); err != nil {
+		return nil, err
+	}
+	return progress, args.Error(1)
+}
+
+func (m *MockVideoProgressService) MarkCompleted(ctx context.Context, userID, lessonID int) error {
+	args := m.Called(ctx, userID, lessonID)
+	return args.Error(0)
+}
+
+func ago(d time.Duration) *time.Time {
+	t := time.Now().Add(-d)
+	return &t
+}
+
+func TestHeartbeatAutoCompletesLessonAfterThreshold(t *testing.T) {
+	ctx := context.Background()
+	videoService := new(MockVideoProgressService)
+	lessonService := new(MockLessonService)
+	progress := new(MockLessonProgressUseCase)
+	useCase := usecase.NewVideoProgressUseCase(videoService, lessonService, nil, progress, 90)
+
+	lessonService.On("GetLessonByID", ctx, 20).Return(&models.Lesson{ID: 20, CourseID: 10, VideoURL: "https://video.example.com/1"}, nil)
+	videoService.On("UpdateProgress", ctx, 5, 20, 10).Return(&models.VideoProgress{
+		UserID: 5, LessonID: 20, CourseID: 10, Position: 85, Duration: 100,
+		Intervals: []models.WatchInterval{{Start: 0, End: 40}, {Start: 42, End: 85}}, WatchedSeconds: 83,
+		LastHeartbeatAt: ago(10 * time.Second),
+	}, nil)
+	progress.On("MarkLessonCompleted", ctx, 5, 20, 10).Return(nil).Once()
+	videoService.On("MarkCompleted", ctx, 5, 20).Return(nil).Once()
+
+	result, err := useCase.Heartbeat(ctx, 5, 10, 20, &dto.VideoHeartbeatRequest{From: 85, To: 95.6, Duration: 100})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.WatchInterval{{Start: 0, End: 40}, {Start: 42, End: 95}}, result.Intervals)
+	assert.Equal(t, 93, result.WatchedSeconds)
+	assert.Equal(t, 93.0, result.WatchedPercent)
+	assert.Equal(t, 95, result.Position)
+	assert.Zero(t, result.SkipCount)
+	assert.True(t, result.LessonCompleted)
+	progress.AssertExpectations(t)
+	videoService.AssertExpectations(t)
+}
+
+func TestHeartbeatDetectsSkipAndRejectsImpossibleSegment(t *testing.T) {
+	ctx := context.Background()
+	videoService := new(MockVideoProgressService)
+	lessonService := new(MockLessonService)
+	progress := new(MockLessonProgressUseCase)
+	useCase := usecase.NewVideoProgressUseCase(videoService, lessonService, nil, progress, 90)
+
+	lessonService.On("GetLessonByID", ctx, 20).Return(&models.Lesson{ID: 20, CourseID: 10, VideoURL: "https://video.example.com/1"}, nil)
+	videoService.On("UpdateProgress", ctx, 5, 20, 10).Return(&models.VideoProgress{
+		UserID: 5, LessonID: 20, CourseID: 10, Position: 10, Duration: 100,
+		Intervals: []models.WatchInterval{{Start: 0, End: 10}}, WatchedSeconds: 10,
+		LastHeartbeatAt: ago(5 * time.Second),
+	}, nil)
+
+	// перемотка на 60-ю секунду и заявленные 40 секунд просмотра за 5 секунд
+	result, err := useCase.Heartbeat(ctx, 5, 10, 20, &dto.VideoHeartbeatRequest{From: 60, To: 100, Duration: 100})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.SkipCount)
+	assert.Equal(t, 50, result.SkippedSeconds)
+	assert.Equal(t, 25, result.RejectedSeconds)
+	assert.Equal(t, []models.WatchInterval{{Start: 0, End: 10}, {Start: 60, End: 75}}, result.Intervals)
+	assert.Equal(t, 25, result.WatchedSeconds)
+	assert.False(t, result.LessonCompleted)
+	progress.AssertNotCalled(t, "MarkLessonCompleted", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHeartbeatRejectsTinyDurationForLessonWithKnownDuration(t *testing.T) {
+	ctx := context.Background()
+	videoService := new(MockVideoProgressService)
+	lessonService := new(MockLessonService)
+	progress := new(MockLessonProgressUseCase)
+	useCase := usecase.NewVideoProgressUseCase(videoService, lessonService, nil, progress, 90)
+
+	lessonService.On("GetLessonByID", ctx, 20).Return(&models.Lesson{ID: 20, CourseID: 10, VideoURL: "https://video.example.com/1", VideoDuration: intPtr(600)}, nil)
+	videoService.On("UpdateProgress", ctx, 5, 20, 10).Return(nil, nil)
+
+	// первая секунда видео, выданная за всё видео
+	_, err := useCase.Heartbeat(ctx, 5, 10, 20, &dto.VideoHeartbeatRequest{From: 0, To: 1, Duration: 1})
+
+	assert.EqualError(t, err, "validation failed: video duration 1 does not match lesson video duration 600")
+	progress.AssertNotCalled(t, "MarkLessonCompleted", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHeartbeatKeepsDurationOfFirstHeartbeat(t *testing.T) {
+	ctx := context.Background()
+	videoService := new(MockVideoProgressService)
+	lessonService := new(MockLessonService)
+	progress := new(MockLessonProgressUseCase)
+	useCase := usecase.NewVideoProgressUseCase(videoService, lessonService, nil, progress, 90)
+
+	lessonService.On("GetLessonByID", ctx, 20).Return(&models.Lesson{ID: 20, CourseID: 10, VideoURL: "https://video.example.com/1"}, nil)
+	videoService.On("UpdateProgress", ctx, 5, 20, 10).Return(&models.VideoProgress{
+		UserID: 5, LessonID: 20, CourseID: 10, Position: 40, Duration: 100,
+		Intervals: []models.WatchInterval{{Start: 0, End: 40}}, WatchedSeconds: 40,
+		LastHeartbeatAt: ago(10 * time.Second),
+	}, nil)
+
+	// сокращённая длительность сделала бы 40 просмотренных секунд всем видео
+	_, err := useCase.Heartbeat(ctx, 5, 10, 20, &dto.VideoHeartbeatRequest{From: 40, To: 44, Duration: 44})
+	assert.EqualError(t, err, "validation failed: video duration 44 does not match lesson video duration 100")
+	progress.AssertNotCalled(t, "MarkLessonCompleted", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	// дробная длительность от проигрывателя в пределах погрешности принимается
+	result, err := useCase.Heartbeat(ctx, 5, 10, 20, &dto.VideoHeartbeatRequest{From: 40, To: 50, Duration: 100.4})
+	assert.NoError(t, err)
+	assert.Equal(t, 100, result.Duration)
+	assert.Equal(t, 50.0, result.WatchedPercent)
+	assert.False(t, result.LessonCompleted)
+}
+
+func TestHeartbeatRequiresVideoLesson(t *testing.T) {
+	ctx := context.Background()
+	lessonService := new(MockLessonService)
+	useCase := usecase.NewVideoProgressUseCase(new(MockVideoProgressService), lessonService, nil, nil, 90)
+
+	lessonService.On("GetLessonByID", ctx, 20).Return(&models.Lesson{ID: 20, CourseID: 10}, nil)
+
+	_, err := useCase.Heartbeat(ctx, 5, 10, 20, &dto.VideoHeartbeatRequest{From: 0, To: 10, Duration: 100})
+
+	assert.EqualError(t, err, "validation failed: lesson has no video")
+}
+
+func TestCheckWatchedRequiresThreshold(t *testing.T) {
+	ctx := context.Background()
+	videoService := new(MockVideoProgressService)
+	useCase := usecase.NewVideoProgressUseCase(videoService, nil, nil, nil, 90)
+
+	videoService.On("GetProgress", ctx, 5, 20).Return(&models.VideoProgress{Duration: 200, WatchedSeconds: 100}, nil)
+	videoService.On("GetProgress", ctx, 5, 21).Return(nil, nil)
+
+	err := useCase.CheckWatched(ctx, 5, &models.Lesson{ID: 20, VideoURL: "https://video.example.com/1"})
+	assert.True(t, errors.Is(err, usecase.ErrLessonRequirementsNotMet))
+	assert.ErrorContains(t, err, "watch at least 90% of the video (watched 50%)")
+
+	err = useCase.CheckWatched(ctx, 5, &models.Lesson{ID: 21, VideoURL: "https://video.example.com/2"})
+	assert.True(t, errors.Is(err, usecase.ErrLessonRequirementsNotMet))
+
+	assert.NoError(t, useCase.CheckWatched(ctx, 5, &models.Lesson{ID: 22}))
+}
+
+func TestCheckWatchedUsesLessonVideoDuration(t *testing.T) {
+	ctx := context.Background()
+	videoService := new(MockVideoProgressService)
+	useCase := usecase.NewVideoProgressUseCase(videoService, nil, nil, nil, 90)
+
+	// длительность зафиксирована коротким первым heartbeat до того, как её задала команда курса
+	videoService.On("GetProgress", ctx, 5, 20).Return(&models.VideoProgress{Duration: 1, WatchedSeconds: 1}, nil)
+
+	err := useCase.CheckWatched(ctx, 5, &models.Lesson{ID: 20, VideoURL: "https://video.example.com/1", VideoDuration: intPtr(600)})
+	assert.True(t, errors.Is(err, usecase.ErrLessonRequirementsNotMet))
+	assert.ErrorContains(t, err, "watched 0%")
+}
+
+func TestHeartbeatSlackDoesNotAccumulate(t *testing.T) {
+	ctx := context.Background()
+	videoService := new(MockVideoProgressService)
+	lessonService := new(MockLessonService)
+	progress := new(MockLessonProgressUseCase)
+	useCase := usecase.NewVideoProgressUseCase(videoService, lessonService, nil, progress, 90)
+
+	lessonService.On("GetLessonByID", ctx, 20).Return(&models.Lesson{ID: 20, CourseID: 10, VideoURL: "https://video.example.com/1"}, nil)
+	// за минуту с первого heartbeat засчитано 150 секунд: 30 в первом отрезке и 120 на двойной скорости
+	videoService.On("UpdateProgress", ctx, 5, 20, 10).Return(&models.VideoProgress{
+		UserID: 5, LessonID: 20, CourseID: 10, Position: 150, Duration: 600,
+		Intervals: []models.WatchInterval{{Start: 0, End: 150}}, WatchedSeconds: 150, CreditedSeconds: 150,
+		FirstHeartbeatAt: ago(60 * time.Second), LastHeartbeatAt: ago(10 * time.Second),
+	}, nil)
+
+	// 25 секунд укладываются в интервал с прошлого heartbeat, но от общего запаса осталось только 5
+	result, err := useCase.Heartbeat(ctx, 5, 10, 20, &dto.VideoHeartbeatRequest{From: 150, To: 175, Duration: 600})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 20, result.RejectedSeconds)
+	assert.Equal(t, []models.WatchInterval{{Start: 0, End: 155}}, result.Intervals)
+	assert.Equal(t, 155, result.CreditedSeconds)
+}
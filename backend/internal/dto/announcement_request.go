@@ -0,0 +1,12 @@
+package dto
+
+import "time"
+
+// AnnouncementRequest - без publish_at объявление публикуется сразу
+type AnnouncementRequest struct {
+	Title     string     `json:"title" validate:"required,max=200"`
+	Body      string     `json:"body" validate:"required,max=20000"`
+	Pinned    bool       `json:"pinned"`
+	SendEmail bool       `json:"send_email"`
+	PublishAt *time.Time `json:"publish_at"`
+}
@@ -0,0 +1,44 @@
+package dto
+
+import "time"
+
+// AssignmentRequest - задание урока целиком; рубрика заменяется переданной.
+// При непустой рубрике сумма max_points критериев должна равняться max_score.
+type AssignmentRequest struct {
+	Title              string                       `json:"title" validate:"required,min=1,max=255"`
+	Instructions       string                       `json:"instructions" validate:"max=100000"`
+	DueAt              *time.Time                   `json:"due_at"`
+	AllowedExtensions  []string                     `json:"allowed_extensions" validate:"max=20"`
+	MaxScore           int                          `json:"max_score" validate:"required,min=1,max=1000"`
+	PassingScore       int                          `json:"passing_score" validate:"min=0"`
+	RequirePassing     bool                         `json:"require_passing"`
+	LatePenaltyPercent int                          `json:"late_penalty_percent" validate:"min=0,max=100"`
+	MaxLateDays        *int                         `json:"max_late_days" validate:"omitempty,min=0,max=365"`
+	Criteria           []AssignmentCriterionRequest `json:"criteria" validate:"max=50,dive"`
+}
+
+// AssignmentCriterionRequest - критерий рубрики
+type AssignmentCriterionRequest struct {
+	Title       string `json:"title" validate:"required,min=1,max=255"`
+	Description string `json:"description" validate:"max=2000"`
+	MaxPoints   int    `json:"max_points" validate:"required,min=1"`
+}
+
+// SubmitAssignmentRequest - поля multipart-формы сдачи кроме самого файла
+type SubmitAssignmentRequest struct {
+	Comment string `form:"comment" validate:"max=2000"`
+}
+
+// GradeSubmissionRequest - оценка попытки: по каждому критерию рубрики или общим баллом, если рубрики нет
+type GradeSubmissionRequest struct {
+	Score    *int                    `json:"score" validate:"omitempty,min=0"`
+	Criteria []CriterionScoreRequest `json:"criteria" validate:"dive"`
+	Feedback string                  `json:"feedback" validate:"max=10000"`
+}
+
+// CriterionScoreRequest - баллы по одному критерию рубрики
+type CriterionScoreRequest struct {
+	CriterionID int    `json:"criterion_id" validate:"required,gt=0"`
+	Points      int    `json:"points" validate:"min=0"`
+	Comment     string `json:"comment" validate:"max=2000"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+type CreateCategoryRequest struct {
+	Name        string `json:"name" validate:"required,max=64"`
+	Description string `json:"description"`
+	ParentID    *int   `json:"parent_id,omitempty"`
+}
@@ -1,7 +1,32 @@
 package dto
 
+import "time"
+
 type CreateCourseRequest struct {
 	Name string `json:"name" validate:"required"`
 	Description string `json:"description" validate:"required"`
 	ImageUrl string `json:"image_url"`
+	CategoryID      *int   `json:"category_id,omitempty"`
+	Level           string `json:"level" validate:"omitempty,oneof=beginner intermediate advanced"`
+	DurationMinutes int    `json:"duration_minutes" validate:"min=0"`
+	Language        string `json:"language" validate:"max=32"`
+	Status          string `json:"status" validate:"omitempty,oneof=active inactive draft"`
+}
+
+type CourseTagsRequest struct {
+	Tags []string `json:"tags" validate:"required,min=1,dive,required,max=32"`
+}
+
+type CloneCourseRequest struct {
+	Name               string `json:"name" validate:"max=255"` // по умолчанию "<название> (copy)"
+	IncludeEnrollments bool   `json:"include_enrollments"`     // по умолчанию студенты не переносятся
+}
+
+// CourseScheduleRequest заменяет расписание курса целиком: пустые поля снимают ограничение
+type CourseScheduleRequest struct {
+	StartsAt           *time.Time `json:"starts_at"`
+	EndsAt             *time.Time `json:"ends_at"`
+	EnrollmentOpensAt  *time.Time `json:"enrollment_opens_at"`
+	EnrollmentClosesAt *time.Time `json:"enrollment_closes_at"`
+	Capacity           *int       `json:"capacity" validate:"omitempty,min=1"`
 }
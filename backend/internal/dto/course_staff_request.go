@@ -0,0 +1,10 @@
+package dto
+
+type CourseStaffRequest struct {
+	UserID int    `json:"user_id" validate:"required"`
+	Role   string `json:"role" validate:"required,oneof=co_teacher ta"`
+}
+
+type TransferOwnershipRequest struct {
+	UserID int `json:"user_id" validate:"required"`
+}
@@ -0,0 +1,5 @@
+package dto
+
+type PublishVersionRequest struct {
+	Notes string `json:"notes" validate:"max=2000"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+// DiscussionThreadRequest - без lesson_id тема относится ко всему курсу
+type DiscussionThreadRequest struct {
+	Title    string `json:"title" validate:"required,max=200"`
+	Body     string `json:"body" validate:"required,max=20000"`
+	LessonID *int   `json:"lesson_id" validate:"omitempty,min=1"`
+}
+
+// DiscussionPostRequest - parent_id задаёт ответ, к которому вкладывается новый
+type DiscussionPostRequest struct {
+	Body     string `json:"body" validate:"required,max=20000"`
+	ParentID *int   `json:"parent_id" validate:"omitempty,min=1"`
+}
+
+// AcceptedAnswerRequest - post_id: null снимает отметку
+type AcceptedAnswerRequest struct {
+	PostID *int `json:"post_id" validate:"omitempty,min=1"`
+}
@@ -0,0 +1,15 @@
+package dto
+
+type CreateGroupRequest struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+}
+
+type GroupMemberRequest struct {
+	UserID int    `json:"user_id" validate:"required"`
+	Role   string `json:"role" validate:"omitempty,oneof=owner member"`
+}
+
+type GroupCourseRequest struct {
+	CourseID int `json:"course_id" validate:"required"`
+}
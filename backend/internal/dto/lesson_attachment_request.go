@@ -0,0 +1,13 @@
+package dto
+
+// LessonAttachmentUploadRequest - поля multipart-формы вложения кроме самого файла; без title используется имя файла
+type LessonAttachmentUploadRequest struct {
+	Title     string `form:"title" validate:"max=255"`
+	Watermark bool   `form:"watermark"`
+}
+
+// UpdateLessonAttachmentRequest - изменяются только переданные поля
+type UpdateLessonAttachmentRequest struct {
+	Title     *string `json:"title" validate:"omitempty,min=1,max=255"`
+	Watermark *bool   `json:"watermark"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+import "encoding/json"
+
+// LessonBlockRequest - data проверяется JSON-схемой типа блока; без required блок обязателен
+type LessonBlockRequest struct {
+	Type     string          `json:"type" validate:"required,oneof=text video image code callout file quiz"`
+	Data     json.RawMessage `json:"data" validate:"required"`
+	Required *bool           `json:"required"`
+}
+
+type ReorderLessonBlocksRequest struct {
+	BlockIDs []int `json:"block_ids" validate:"required,min=1,unique,dive,min=1"`
+}
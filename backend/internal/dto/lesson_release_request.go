@@ -0,0 +1,11 @@
+package dto
+
+import "time"
+
+// LessonReleaseRuleRequest - правило открытия урока; заполняется только поле выбранного типа
+type LessonReleaseRuleRequest struct {
+	Type                string     `json:"type" validate:"required,oneof=date enrollment_days after_lesson"`
+	ReleaseAt           *time.Time `json:"release_at"`
+	DaysAfterEnrollment *int       `json:"days_after_enrollment" validate:"omitempty,min=0,max=3650"`
+	AfterLessonID       *int       `json:"after_lesson_id" validate:"omitempty,gt=0"`
+}
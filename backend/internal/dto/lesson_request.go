@@ -4,4 +4,24 @@ type CreateLessonRequest struct {
 	Title    string `json:"title" validate:"required"`
 	Content  string `json:"content" validate:"required"`
 	VideoURL string `json:"video_url,omitempty" validate:"omitempty,url"`
-}
\ No newline at end of file
+	// VideoDuration - длительность видео в секундах; без неё длительность фиксирует первый heartbeat студента
+	VideoDuration *int `json:"video_duration,omitempty" validate:"omitempty,min=1,max=86400"`
+	ModuleID      *int `json:"module_id,omitempty"`
+}
+
+// UpdateLessonRequest - полная замена содержимого урока; модуль и позиция меняются через move
+type UpdateLessonRequest struct {
+	Title         string `json:"title" validate:"required,max=255"`
+	Content       string `json:"content" validate:"required"`
+	VideoURL      string `json:"video_url,omitempty" validate:"omitempty,url"`
+	VideoDuration *int   `json:"video_duration,omitempty" validate:"omitempty,min=1,max=86400"`
+}
+
+// PatchLessonRequest - изменяются только переданные поля; пустой video_url убирает видео.
+// Новое видео без video_duration сбрасывает длительность прежнего.
+type PatchLessonRequest struct {
+	Title         *string `json:"title" validate:"omitempty,min=1,max=255"`
+	Content       *string `json:"content" validate:"omitempty,min=1"`
+	VideoURL      *string `json:"video_url" validate:"omitempty,len=0|url"`
+	VideoDuration *int    `json:"video_duration" validate:"omitempty,min=1,max=86400"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+// MediaUploadRequest - поля multipart-формы загрузки кроме самого файла.
+// С course_id файл доступен участникам курса, с public=true - всем.
+type MediaUploadRequest struct {
+	CourseID *int `form:"course_id" validate:"omitempty,min=1"`
+	Public   bool `form:"public"`
+}
+
+// CourseImageRequest - обложка курса из загруженного публичного изображения
+type CourseImageRequest struct {
+	MediaID int `json:"media_id" validate:"required,min=1"`
+}
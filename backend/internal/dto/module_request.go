@@ -0,0 +1,12 @@
+package dto
+
+type ModuleRequest struct {
+	Title       string `json:"title" validate:"required,max=255"`
+	Description string `json:"description"`
+	Position    int    `json:"position" validate:"min=0"` // 0 - в конец курса
+}
+
+type MoveLessonRequest struct {
+	ModuleID *int `json:"module_id"`                   // nil или 0 - модуль по умолчанию
+	Position int  `json:"position" validate:"min=0"` // 0 - в конец модуля
+}
@@ -0,0 +1,23 @@
+package dto
+
+import "time"
+
+// CoursePriceRequest - цена 0 делает курс бесплатным
+type CoursePriceRequest struct {
+	PriceCents int    `json:"price_cents" validate:"min=0"`
+	Currency   string `json:"currency" validate:"omitempty,len=3,alpha"`
+}
+
+type CreateOrderRequest struct {
+	CouponCode string `json:"coupon_code" validate:"max=64"`
+}
+
+// CouponRequest - без course_id купон действует на все курсы (создаёт только администратор)
+type CouponRequest struct {
+	Code      string     `json:"code" validate:"required,min=3,max=64,alphanum"`
+	Type      string     `json:"type" validate:"required,oneof=percent fixed"`
+	Value     int        `json:"value" validate:"required,min=1"`
+	CourseID  *int       `json:"course_id" validate:"omitempty,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	MaxUses   *int       `json:"max_uses" validate:"omitempty,min=1"`
+}
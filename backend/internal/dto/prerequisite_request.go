@@ -0,0 +1,11 @@
+package dto
+
+type SetPrerequisitesRequest struct {
+	CourseIDs    []int `json:"course_ids" validate:"dive,min=1"`
+	MinUserLevel int   `json:"min_user_level" validate:"min=0"`
+}
+
+type AdminEnrollRequest struct {
+	UserID                int  `json:"user_id" validate:"required"`
+	OverridePrerequisites bool `json:"override_prerequisites"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+type ReviewRequest struct {
+	Rating int    `json:"rating" validate:"required,min=1,max=5"`
+	Body   string `json:"body" validate:"max=5000"`
+}
+
+// ReviewReplyRequest - пустой reply удаляет ответ
+type ReviewReplyRequest struct {
+	Reply string `json:"reply" validate:"max=5000"`
+}
+
+type ReviewReportRequest struct {
+	Reason string `json:"reason" validate:"required,max=1000"`
+}
+
+type ReviewStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=visible hidden"`
+}
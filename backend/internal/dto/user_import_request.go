@@ -0,0 +1,12 @@
+package dto
+
+// ImportUserRow - одна строка CSV-файла импорта пользователей
+type ImportUserRow struct {
+	Username string `validate:"required,max=64"`
+	Name     string `validate:"max=52"`
+	Surname  string `validate:"max=52"`
+	Email    string `validate:"required,email"`
+	Role     string `validate:"required,oneof=student teacher admin"`
+	GroupID  int    `validate:"omitempty,min=1"`
+	CourseID int    `validate:"omitempty,min=1"`
+}
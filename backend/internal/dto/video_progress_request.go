@@ -0,0 +1,9 @@
+package dto
+
+// VideoHeartbeatRequest - отрезок видео, проигранный с прошлого heartbeat, в секундах.
+// После перемотки без воспроизведения from и to совпадают.
+type VideoHeartbeatRequest struct {
+	From     float64 `json:"from" validate:"min=0"`
+	To       float64 `json:"to" validate:"gtefield=From"`
+	Duration float64 `json:"duration" validate:"required,gt=0,max=86400"`
+}
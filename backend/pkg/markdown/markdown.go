@@ -0,0 +1,61 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+// Renderer переводит markdown в HTML и очищает результат по белому списку тегов и атрибутов,
+// поэтому вставленный в markdown HTML не может выполнить скрипт у студента
+type Renderer struct {
+	markdown goldmark.Markdown
+	policy   *bluemonday.Policy
+}
+
+// NewRenderer поддерживает CommonMark, таблицы, зачёркивание, списки задач, автоссылки,
+// блоки кода с языком (class="language-go") и формулы $...$ и $$...$$
+func NewRenderer() *Renderer {
+	md := goldmark.New(
+		goldmark.WithExtensions(
+			extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute)),
+			extension.Strikethrough,
+			extension.TaskList,
+			extension.Linkify,
+			Math,
+		),
+		// сырой HTML пропускается дальше, его чистит policy
+		goldmark.WithRendererOptions(html.WithUnsafe()),
+	)
+	return &Renderer{markdown: md, policy: newPolicy()}
+}
+
+// Render возвращает очищенный HTML для markdown
+func (r *Renderer) Render(source string) (string, error) {
+	var buf bytes.Buffer
+	if err := r.markdown.Convert([]byte(source), &buf); err != nil {
+		return "", fmt.Errorf("failed to render markdown: %w", err)
+	}
+	return r.policy.Sanitize(buf.String()), nil
+}
+
+var defaultRenderer = NewRenderer()
+
+// Render переводит markdown в очищенный HTML рендерером по умолчанию
+func Render(source string) (string, error) {
+	return defaultRenderer.Render(source)
+}
+
+func newPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^math math-(inline|display)$`)).OnElements("span")
+	policy.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	policy.AllowAttrs("checked", "disabled").OnElements("input")
+	return policy
+}
@@ -0,0 +1,89 @@
+package markdown_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/w0ikid/study-platform/pkg/markdown"
+)
+
+func TestRenderSanitizesHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{"Script block", "<script>alert(1)</script>", ""},
+		{"Inline script", "hi <script>alert(1)</script> there", "<p>hi  there</p>\n"},
+		{"javascript link in markdown", "[click](javascript:alert(1))", "<p>click</p>\n"},
+		{"javascript link in HTML", `<a href="javascript:alert(1)">x</a>`, "<p>x</p>\n"},
+		{"onerror handler", `<img src=x onerror="alert(1)">`, `<img src="x">`},
+		{"Event handler and style", `<p style="color:red" onclick="x()">p</p>`, "<p>p</p>"},
+		{"iframe", `<iframe src="https://evil"></iframe>`, ""},
+		{"Non-checkbox input", `<input type="text" value="x">`, ""},
+		{"Safe link", "[docs](https://example.com)", "<p><a href=\"https://example.com\" rel=\"nofollow\">docs</a></p>\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html, err := markdown.Render(tt.source)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, html)
+		})
+	}
+}
+
+func TestRenderMath(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{"Inline formula", "$x^2$", "<p><span class=\"math math-inline\">x^2</span></p>\n"},
+		{"Inline formula followed by text", "$x$ y", "<p><span class=\"math math-inline\">x</span> y</p>\n"},
+		{"Display formula", "$$x = 1$$", "<p><span class=\"math math-display\">x = 1</span></p>\n"},
+		{"Formula is escaped", "$a < b$", "<p><span class=\"math math-inline\">a &lt; b</span></p>\n"},
+		{"Space after opening $", "$ x$", "<p>$ x$</p>\n"},
+		{"Space before closing $", "$x $", "<p>$x $</p>\n"},
+		{"Digit after closing $", "$x$5", "<p>$x$5</p>\n"},
+		{"Prices", "$5 and $10", "<p>$5 and $10</p>\n"},
+		{"Unclosed $$", "$$x = 1", "<p>$$x = 1</p>\n"},
+		{"Empty $$", "a $$ b", "<p>a $$ b</p>\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html, err := markdown.Render(tt.source)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, html)
+		})
+	}
+}
+
+func TestRenderAllowedClasses(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{"Code block language", "```go\nfmt.Println()\n```", "<pre><code class=\"language-go\">fmt.Println()\n</code></pre>\n"},
+		{"Language class in HTML", `<code class="language-go">x</code>`, "<p><code class=\"language-go\">x</code></p>\n"},
+		{"Other code class", `<code class="foo">x</code>`, "<p><code>x</code></p>\n"},
+		{"Code block language with quotes", "```evil\" onclick=\"x\nq\n```", "<pre><code>q\n</code></pre>\n"},
+		{"Math class in HTML", `<span class="math math-inline">x</span>`, "<p><span class=\"math math-inline\">x</span></p>\n"},
+		{"Other span class", `<span class="danger">x</span>`, "<p><span>x</span></p>\n"},
+		{"Task list", "- [x] done\n- [ ] todo", "<ul>\n<li><input checked=\"\" disabled=\"\" type=\"checkbox\"> done</li>\n<li><input disabled=\"\" type=\"checkbox\"> todo</li>\n</ul>\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html, err := markdown.Render(tt.source)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, html)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package markdown
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// KindMath - узел формулы в дереве markdown
+var KindMath = ast.NewNodeKind("Math")
+
+// MathNode - формула TeX; отображается на клиенте (KaTeX/MathJax) по классу math-inline или math-display
+type MathNode struct {
+	ast.BaseInline
+	Display bool
+	Value   []byte
+}
+
+func (n *MathNode) Kind() ast.NodeKind {
+	return KindMath
+}
+
+func (n *MathNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Value": string(n.Value)}, nil)
+}
+
+type mathParser struct{}
+
+func (p *mathParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+// Parse разбирает $...$ и $$...$$ в пределах строки. Как в pandoc, у строчной формулы
+// не должно быть пробелов у границ и цифры сразу после закрывающего $, чтобы "$5 и $10" остались текстом.
+func (p *mathParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	delimiter := []byte("$")
+	if bytes.HasPrefix(line, []byte("$$")) {
+		delimiter = []byte("$$")
+	}
+
+	rest := line[len(delimiter):]
+	end := bytes.Index(rest, delimiter)
+	if end <= 0 {
+		return nil
+	}
+	value := rest[:end]
+	if len(delimiter) == 1 {
+		if isSpace(value[0]) || isSpace(value[len(value)-1]) {
+			return nil
+		}
+		if after := len(delimiter) + end + 1; after < len(line) && line[after] >= '0' && line[after] <= '9' {
+			return nil
+		}
+	}
+
+	block.Advance(2*len(delimiter) + end)
+	return &MathNode{Display: len(delimiter) == 2, Value: append([]byte(nil), value...)}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+type mathRenderer struct{}
+
+func (r *mathRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindMath, r.render)
+}
+
+func (r *mathRenderer) render(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	math := node.(*MathNode)
+	class := "math math-inline"
+	if math.Display {
+		class = "math math-display"
+	}
+	_, _ = w.WriteString(`<span class="` + class + `">`)
+	_, _ = w.Write(util.EscapeHTML(math.Value))
+	_, _ = w.WriteString("</span>")
+	return ast.WalkSkipChildren, nil
+}
+
+type mathExtension struct{}
+
+// Math - расширение goldmark для формул
+var Math goldmark.Extender = &mathExtension{}
+
+func (e *mathExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithInlineParsers(util.Prioritized(&mathParser{}, 500)))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(&mathRenderer{}, 500)))
+}